@@ -0,0 +1,11 @@
+package constant
+
+const (
+	SearchModeFullText = "fulltext"
+	SearchModeFuzzy    = "fuzzy"
+)
+
+const (
+	BookAvailable   = "available"
+	BookUnavailable = "unavailable"
+)
@@ -0,0 +1,17 @@
+package constant
+
+const (
+	CopyStatusAvailable = "AVAILABLE"
+	CopyStatusOnLoan    = "ON_LOAN"
+	CopyStatusOnHold    = "ON_HOLD"
+	CopyStatusRetired   = "RETIRED"
+	CopyStatusLost      = "LOST"
+)
+
+const (
+	CopyConditionNew     = "NEW"
+	CopyConditionGood    = "GOOD"
+	CopyConditionFair    = "FAIR"
+	CopyConditionPoor    = "POOR"
+	CopyConditionDamaged = "DAMAGED"
+)
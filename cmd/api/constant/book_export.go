@@ -0,0 +1,7 @@
+package constant
+
+const (
+	BookExportFormatCSV   = "csv"
+	BookExportFormatJSONL = "jsonl"
+	BookExportFormatXLSX  = "xlsx"
+)
@@ -0,0 +1,16 @@
+package constant
+
+// BookImportListSeparator separates the values of the list columns of an import or export file, e.g. subjects
+const BookImportListSeparator = ";"
+
+const (
+	BookImportModeAll   = "all"
+	BookImportModeBatch = "batch"
+)
+
+const (
+	BookImportCreated  = "created"
+	BookImportUpdated  = "updated"
+	BookImportArchived = "archived" // the ISBN is of an archived book, which is left alone
+	BookImportNoStock  = "no stock" // a new book without copies, which is not created
+)
@@ -0,0 +1,31 @@
+package constant
+
+const (
+	BookQueryFieldAny       = "any" // full text
+	BookQueryFieldTitle     = "title"
+	BookQueryFieldAuthor    = "author" // credit line
+	BookQueryFieldISBN      = "isbn"
+	BookQueryFieldSubject   = "subject" // subjects and category names
+	BookQueryFieldPublisher = "publisher"
+	BookQueryFieldLanguage  = "language"
+	BookQueryFieldYear      = "year"
+)
+
+const (
+	BookQueryRelationContains = "contains" // the term appears as is
+	BookQueryRelationExact    = "exact"    // the whole value is the term
+	BookQueryRelationAll      = "all"      // every word of the term appears
+	BookQueryRelationAny      = "any"      // some word of the term appears
+	BookQueryRelationEq       = "eq"
+	BookQueryRelationNe       = "ne"
+	BookQueryRelationLt       = "lt"
+	BookQueryRelationLe       = "le"
+	BookQueryRelationGt       = "gt"
+	BookQueryRelationGe       = "ge"
+)
+
+const (
+	BookQueryAnd = "and"
+	BookQueryOr  = "or"
+	BookQueryNot = "not" // the left and not the right
+)
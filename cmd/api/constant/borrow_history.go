@@ -3,5 +3,11 @@ package constant
 const (
 	BorrowStatusBorrowed = "BORROWED"
 	BorrowStatusReturned = "RETURNED"
+	BorrowStatusOverdue  = "OVERDUE"
+	BorrowStatusLost     = "LOST"
+	BorrowStatusDamaged  = "DAMAGED" // returned damaged, the copy is withdrawn
+)
+	
+const (
+	LoanLimitActiveLoans = "active loans"
 )
-	
\ No newline at end of file
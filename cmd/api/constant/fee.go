@@ -0,0 +1,8 @@
+package constant
+
+const (
+	FeeTypeLateFee     = "LATE_FEE"
+	FeeTypeReplacement = "REPLACEMENT"
+	FeeTypePayment     = "PAYMENT"
+	FeeTypeWaiver      = "WAIVER"
+)
@@ -0,0 +1,9 @@
+package constant
+
+const (
+	HoldStatusWaiting   = "WAITING"
+	HoldStatusReady     = "READY"
+	HoldStatusFulfilled = "FULFILLED"
+	HoldStatusCancelled = "CANCELLED"
+	HoldStatusExpired   = "EXPIRED"
+)
@@ -0,0 +1,6 @@
+package constant
+
+const (
+	MARCFormatMARC21  = "marc21" // ISO 2709 exchange format
+	MARCFormatMARCXML = "marcxml"
+)
@@ -0,0 +1,66 @@
+package entity
+
+import "time"
+
+// Author is a model for author table, a person credited on books
+type Author struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	Name      string     `gorm:"type:varchar(255);not null" json:"name"`
+	NameKey   string     `gorm:"type:varchar(255);not null;uniqueIndex" json:"-"` // the name folded by search.NameKey, so spellings of one name do not make two authors
+	CreatedAt *time.Time `gorm:"default:now()" json:"createdAt"`
+	UpdatedAt *time.Time `gorm:"default:now()" json:"updatedAt"`
+
+	Books []BookAuthor `gorm:"foreignKey:AuthorID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT" json:"-"`
+}
+
+// BookAuthor is a model for book author table, crediting an author on a book
+type BookAuthor struct {
+	BookID   uint `gorm:"primaryKey;autoIncrement:false" json:"bookId"`
+	AuthorID uint `gorm:"primaryKey;autoIncrement:false;index" json:"authorId"`
+	Position int  `gorm:"not null;default:0" json:"position"` // order the authors are credited in
+}
+
+// AuthorCreateRequest is a request for creating an author
+type AuthorCreateRequest struct {
+	Name string `json:"name" validate:"required,max=255"`
+}
+
+// AuthorUpdateRequest is a request for renaming an author
+type AuthorUpdateRequest struct {
+	ID   uint   `json:"-"`
+	Name string `json:"name" validate:"required,max=255"`
+}
+
+// ListAuthorRequest is a request for listing authors
+type ListAuthorRequest struct {
+	Page   int     `form:"page" validate:"required,min=1"`
+	Size   int     `form:"size" validate:"required,min=1"`
+	Search *string `form:"search"` // part of the name
+}
+
+// ListAuthorResult is a page of authors
+type ListAuthorResult struct {
+	Authors    []AuthorResponse
+	Pagination Pagination
+}
+
+// AuthorResponse represents a response for author
+type AuthorResponse struct {
+	ID        uint       `json:"id"`
+	Name      string     `json:"name"`
+	BookCount int64      `json:"bookCount"` // books in the catalog crediting the author
+	CreatedAt *time.Time `json:"createdAt"`
+	UpdatedAt *time.Time `json:"updatedAt"`
+}
+
+// AuthorDetailResponse represents an author with the books crediting them
+type AuthorDetailResponse struct {
+	AuthorResponse
+	Books []BookResponse `json:"books"`
+}
+
+// BookAuthorResponse represents an author credited on a book
+type BookAuthorResponse struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
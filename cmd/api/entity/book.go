@@ -6,38 +6,124 @@ import "time"
 type Book struct {
 	ID     uint 		`gorm:"primaryKey" json:"id"`
 	Title  string 		`gorm:"not null" json:"title"`
-	Author string		`gorm:"not null" json:"author"`
+	Author string		`gorm:"not null" json:"author"` // credit line, kept in sync with the linked authors
 	Price  float64		`gorm:"not null" json:"price"`
-	Stock  uint			`gorm:"not null" json:"stock"`
+	Stock  uint			`gorm:"not null" json:"stock"` // number of available copies, kept in sync with book_copies
+	ISBN      *string	`gorm:"type:varchar(13);uniqueIndex" json:"isbn"` // ISBN-13, converted from an ISBN-10 when given one
+	Publisher string	`gorm:"type:varchar(255);not null;default:''" json:"publisher"`
+	PublicationYear *int	`json:"publicationYear"`
+	Edition   string	`gorm:"type:varchar(100);not null;default:''" json:"edition"`
+	Language  string	`gorm:"type:varchar(35);not null;default:''" json:"language"` // BCP 47 tag such as en or th
+	PageCount *uint		`json:"pageCount"`
 	CreatedAt *time.Time	`gorm:"default:now()" json:"createdAt"`
 	UpdatedAt *time.Time	`gorm:"default:now()" json:"updatedAt"`
 	DeletedAt *time.Time	`gorm:"index" json:"deletedAt"`
+	SearchVector string	`gorm:"->;type:tsvector;index:idx_books_search_vector,type:gin" json:"-"` // maintained by the repository on create and update
+	SearchVersion int	`gorm:"->;not null;default:0" json:"-"` // analysis version the search vector was built with
+	CoverKey  *string	`gorm:"type:varchar(255)" json:"-"` // blob storage key of the cover image
+	CoverThumbnailKey *string	`gorm:"type:varchar(255)" json:"-"`
 
 	BorrowHistories []BorrowHistory `gorm:"foreignKey:BookID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"-"`	
+	Holds           []Hold          `gorm:"foreignKey:BookID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	Copies          []BookCopy      `gorm:"foreignKey:BookID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	Authors         []BookAuthor    `gorm:"foreignKey:BookID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	Categories      []BookCategory  `gorm:"foreignKey:BookID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	Subjects        []BookSubject   `gorm:"foreignKey:BookID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
 }
 
 // BookCreateRequest is a request for creating a book
 type BookCreateRequest struct {
 	Title  string 	`json:"title" validate:"required"`
-	Author string	`json:"author" validate:"required"`
+	Author string	`json:"author" validate:"required_without=AuthorIDs"` // credit line, split into authors at ;, & and "and"
+	AuthorIDs []uint `json:"authorIds" validate:"omitempty,dive,min=1"` // credited authors in order, used instead of author when given
 	Price  float64	`json:"price" validate:"required"`
-	Stock  uint		`json:"stock" validate:"required,min=1"`
+	Stock  uint		`json:"stock" validate:"required,min=1"` // number of copies to add
+	ISBN   string	`json:"isbn" validate:"omitempty,bookisbn"` // ISBN-10 or ISBN-13, hyphens allowed
+	Publisher string	`json:"publisher" validate:"max=255"`
+	PublicationYear *int	`json:"publicationYear" validate:"omitempty,min=1,max=9999"`
+	Edition   string	`json:"edition" validate:"max=100"`
+	Language  string	`json:"language" validate:"omitempty,bcp47_language_tag"`
+	PageCount *uint		`json:"pageCount" validate:"omitempty,min=1"`
+	CategoryIDs []uint `json:"categoryIds" validate:"omitempty,dive,min=1"`
+	Subjects  []string `json:"subjects" validate:"omitempty,dive,required,max=100"`
 }
 
 // ListBookRequest is a request for listing books
 type ListBookRequest struct {
-	Page   int 		`form:"page" validate:"required,min=1"`
+	Page   int 		`form:"page" validate:"required_without=Cursor,omitempty,min=1"`
 	Size   int 		`form:"size" validate:"required,min=1"`
-	Search *string 	`form:"search"`
+	Cursor *string 	`form:"cursor"` // pages by cursor instead of number, empty for the first page
+	Search *string 	`form:"search"` // words, "phrases", prefix*, -excluded and OR
+	Mode   string 	`form:"mode" validate:"omitempty,oneof=fulltext fuzzy"` // defaults to fulltext
+	Author    *string  `form:"author"` // any spelling of a credited author's name
+	AuthorID  *uint    `form:"authorId"`
+	CategoryID *uint   `form:"categoryId"` // includes the subcategories
+	Subject   *string  `form:"subject"`
+	Available *bool    `form:"available"`
+	MinPrice  *float64 `form:"minPrice" validate:"omitempty,min=0"`
+	MaxPrice  *float64 `form:"maxPrice" validate:"omitempty,min=0"`
+	Language  *string  `form:"language" validate:"omitempty,bcp47_language_tag"` // BCP 47 tag such as en or th
+	MinYear   *int     `form:"minYear" validate:"omitempty,min=1,max=9999"` // earliest publication year
+	MaxYear   *int     `form:"maxYear" validate:"omitempty,min=1,max=9999"` // latest publication year
+	Sort      []string `form:"sort" validate:"dive,oneof=title -title author -author createdAt -createdAt popularity -popularity"` // a leading - sorts descending
+	Facets    bool     `form:"facets"` // also count the matches by author, category, subject, availability, price range, language and decade
+}
+
+// ListBookResult is a page of books with a suggestion for a misspelled search
+type ListBookResult struct {
+	Books      []BookResponse
+	Pagination Pagination
+	Suggestion *string
+	Facets     *BookFacets
+}
+
+// BookFacets counts the books matching a listing along each filter, ignoring that filter's own value
+type BookFacets struct {
+	Authors      []FacetCount      `json:"authors"`
+	Categories   []FacetCount      `json:"categories"`
+	Subjects     []FacetCount      `json:"subjects"`
+	Availability []FacetCount      `json:"availability"`
+	PriceRanges  []PriceRangeFacet `json:"priceRanges"`
+	Languages    []FacetCount      `json:"languages"`
+	Decades      []YearRangeFacet  `json:"decades"`
+}
+
+// FacetCount is how many books share a filter value
+type FacetCount struct {
+	ID    uint   `json:"id,omitempty"` // the author or category of an author or category facet
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// PriceRangeFacet is how many books are priced from Min up to, but not including, Max
+type PriceRangeFacet struct {
+	Min   float64  `json:"min"`
+	Max   *float64 `json:"max,omitempty"`
+	Count int64    `json:"count"`
+}
+
+// YearRangeFacet is how many books were published from Min through Max
+type YearRangeFacet struct {
+	Min   int   `json:"min"`
+	Max   int   `json:"max"`
+	Count int64 `json:"count"`
 }
 
 // BookUpdateRequest is a request for updating a book
 type BookUpdateRequest struct {
 	ID     uint 		`json:"id" validate:"required"`
 	Title  string 		`json:"title" validate:"required"`
-	Author string		`json:"author" validate:"required"`
+	Author string		`json:"author" validate:"required_without=AuthorIDs"` // credit line, split into authors at ;, & and "and"
+	AuthorIDs []uint	`json:"authorIds" validate:"omitempty,dive,min=1"` // credited authors in order, used instead of author when given
 	Price  float64		`json:"price" validate:"required"`
-	Stock  uint			`json:"stock" validate:"required,min=1"`
+	ISBN   string		`json:"isbn" validate:"omitempty,bookisbn"` // ISBN-10 or ISBN-13, hyphens allowed
+	Publisher string	`json:"publisher" validate:"max=255"`
+	PublicationYear *int	`json:"publicationYear" validate:"omitempty,min=1,max=9999"`
+	Edition   string	`json:"edition" validate:"max=100"`
+	Language  string	`json:"language" validate:"omitempty,bcp47_language_tag"`
+	PageCount *uint		`json:"pageCount" validate:"omitempty,min=1"`
+	CategoryIDs []uint	`json:"categoryIds" validate:"omitempty,dive,min=1"` // left as they are when missing, cleared when empty
+	Subjects  []string	`json:"subjects" validate:"omitempty,dive,required,max=100"` // left as they are when missing, cleared when empty
 }
 
 // BookResponse represents a response for book
@@ -47,7 +133,36 @@ type BookResponse struct {
 	Author string		`json:"author"`
 	Price  float64		`json:"price"`
 	Stock  uint			`json:"stock"`
+	ISBN      *string	`json:"isbn"`
+	Publisher string	`json:"publisher"`
+	PublicationYear *int	`json:"publicationYear"`
+	Edition   string	`json:"edition"`
+	Language  string	`json:"language"`
+	PageCount *uint		`json:"pageCount"`
 	CreatedAt *time.Time	`json:"createdAt"`
 	UpdatedAt *time.Time	`json:"updatedAt"`
+	DeletedAt *time.Time	`json:"deletedAt,omitempty"` // set when the book is archived
+	CoverKey  *string	`json:"-"`
+	CoverThumbnailKey *string	`json:"-"`
+	CoverURL  *string	`gorm:"-" json:"coverUrl"` // filled in by the service from the cover key
+	CoverThumbnailURL *string	`gorm:"-" json:"coverThumbnailUrl"`
+	Authors   []BookAuthorResponse `gorm:"-" json:"authors"`
+	Categories []BookCategoryResponse `gorm:"-" json:"categories"`
+	Subjects  []string	`gorm:"-" json:"subjects"`
 }
 
+
+// BookCover is where the cover of a book and its thumbnail are kept in blob storage, neither when it has none
+type BookCover struct {
+	CoverKey          *string
+	CoverThumbnailKey *string
+}
+
+// BookCoverResponse represents a response for an uploaded cover
+type BookCoverResponse struct {
+	CoverURL          string `json:"coverUrl"`
+	CoverThumbnailURL string `json:"coverThumbnailUrl"`
+	ContentType       string `json:"contentType"`
+	Width             int    `json:"width"`
+	Height            int    `json:"height"`
+}
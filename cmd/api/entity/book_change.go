@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// ListBookChangeRequest is a request for the books changed in a time range, archived books included,
+// in the order they last changed
+type ListBookChangeRequest struct {
+	From       *time.Time // changed at or after
+	Before     *time.Time // changed before
+	CategoryID *uint      // includes the subcategories
+	Size       int
+	Cursor     string // where the page after another continues, empty for the first page
+}
+
+// ListBookChangeResult is a page of changed books with the cursor of the page after it when there is one
+type ListBookChangeResult struct {
+	Books []BookResponse
+	Next  *string
+	Total *int64 // counted for the first page only
+}
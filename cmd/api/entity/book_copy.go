@@ -0,0 +1,46 @@
+package entity
+
+import "time"
+
+// BookCopy is a model for book copy table, a physical item of a book
+type BookCopy struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	BookID        uint       `gorm:"not null;index" json:"bookId"`
+	Barcode       string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"barcode"`
+	ShelfLocation string     `gorm:"type:varchar(100)" json:"shelfLocation"`
+	Condition     string     `gorm:"type:varchar(20);not null;default:GOOD" json:"condition"` // "new", "good", "fair", "poor", "damaged"
+	Status        string     `gorm:"type:varchar(20);not null;index" json:"status"`           // "available", "on_loan", "on_hold", "retired"
+	CreatedAt     *time.Time `gorm:"default:now()" json:"createdAt"`
+	UpdatedAt     *time.Time `gorm:"default:now()" json:"updatedAt"`
+
+	BorrowHistories []BorrowHistory `gorm:"foreignKey:CopyID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"-"`
+	Holds           []Hold          `gorm:"foreignKey:CopyID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"-"`
+}
+
+// BookCopyCreateRequest is a request for adding a copy of a book
+type BookCopyCreateRequest struct {
+	BookID        uint   `json:"-"`
+	Barcode       string `json:"barcode" validate:"required,max=64"`
+	ShelfLocation string `json:"shelfLocation" validate:"max=100"`
+	Condition     string `json:"condition" validate:"omitempty,oneof=NEW GOOD FAIR POOR DAMAGED"`
+}
+
+// BookCopyUpdateRequest is a request for updating a copy of a book
+type BookCopyUpdateRequest struct {
+	ID            uint   `json:"-"`
+	ShelfLocation string `json:"shelfLocation" validate:"max=100"`
+	Condition     string `json:"condition" validate:"required,oneof=NEW GOOD FAIR POOR DAMAGED"`
+}
+
+// BookCopyResponse represents a response for book copy
+type BookCopyResponse struct {
+	ID            uint       `json:"id"`
+	BookID        uint       `json:"bookId"`
+	BookTitle     string     `json:"bookTitle"`
+	Barcode       string     `json:"barcode"`
+	ShelfLocation string     `json:"shelfLocation"`
+	Condition     string     `json:"condition"`
+	Status        string     `json:"status"`
+	CreatedAt     *time.Time `json:"createdAt"`
+	UpdatedAt     *time.Time `json:"updatedAt"`
+}
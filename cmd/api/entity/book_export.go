@@ -0,0 +1,23 @@
+package entity
+
+// BookExportRequest is a request for exporting the books matching a listing, a batch at a time
+// rather than by page
+type BookExportRequest struct {
+	ListBookRequest
+	Format string `form:"format" validate:"required,oneof=csv jsonl xlsx"`
+}
+
+// BookAvailability counts the copies of a book by what is happening to them
+type BookAvailability struct {
+	BookID    uint  `json:"-"`
+	Copies    int64 `json:"copies"` // copies in circulation, leaving out retired and lost ones
+	Available int64 `json:"available"`
+	OnLoan    int64 `json:"onLoan"`
+	OnHold    int64 `json:"onHold"`
+}
+
+// BookExport is a book of an export with the availability of its copies
+type BookExport struct {
+	BookResponse
+	Availability BookAvailability `json:"availability"`
+}
@@ -0,0 +1,42 @@
+package entity
+
+// BookImportRequest tells how to run an import of the catalog
+type BookImportRequest struct {
+	DryRun    bool   `form:"dryRun"`                                        // check and write every row, then roll it all back
+	Mode      string `form:"mode" validate:"omitempty,oneof=all batch"`     // all runs in one transaction, batch in one per batch
+	BatchSize int    `form:"batchSize" validate:"omitempty,min=1,max=1000"` // rows per transaction in batch mode, defaults to 100
+}
+
+// BookImportRow is a row read from an import file, with the problems found in it
+type BookImportRow struct {
+	Line    int
+	Book    BookCreateRequest
+	Columns []string // lower-cased JSON fields the file gives, the only ones written over a book with the same ISBN
+	Errors  []string
+}
+
+// BookImportItem is a checked row ready to be written
+type BookImportItem struct {
+	Line        int
+	Book        Book
+	Columns     []string
+	Authors     []Author
+	CategoryIDs []uint
+	Subjects    []string
+}
+
+// BookImportRowError lists the problems of a row that was not imported
+type BookImportRowError struct {
+	Line   int      `json:"line"`
+	Errors []string `json:"errors"`
+}
+
+// BookImportReport sums up an import
+type BookImportReport struct {
+	DryRun  bool                 `json:"dryRun"`
+	Rows    int                  `json:"rows"`
+	Created int                  `json:"created"`
+	Updated int                  `json:"updated"`
+	Skipped int                  `json:"skipped"` // rows left out because they or another row of their batch failed, or their book is archived
+	Errors  []BookImportRowError `json:"errors"`
+}
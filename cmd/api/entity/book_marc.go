@@ -0,0 +1,12 @@
+package entity
+
+// BookMARCRequest picks how MARC records are written or read
+type BookMARCRequest struct {
+	Format string `form:"format" validate:"omitempty,oneof=marc21 marcxml"` // marc21, the ISO 2709 exchange format, by default
+}
+
+// BookMARCImportRequest tells how to run an import of MARC records
+type BookMARCImportRequest struct {
+	BookImportRequest
+	BookMARCRequest
+}
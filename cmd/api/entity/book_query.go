@@ -0,0 +1,26 @@
+package entity
+
+// BookQuery is a tree of conditions on books: a field compared to a term, or two queries joined by an operator.
+// Terms mask any characters with * and a single one with ?, and a backslash makes the next character literal.
+type BookQuery struct {
+	Field    string
+	Relation string
+	Term     string
+
+	Operator string
+	Left     *BookQuery
+	Right    *BookQuery
+}
+
+// SearchBookRequest is a request for the books matching a query, archived books left out, in the order they were added
+type SearchBookRequest struct {
+	Query  BookQuery
+	Offset int
+	Limit  int
+}
+
+// SearchBookResult is a slice of the books matching a query with how many match in all
+type SearchBookResult struct {
+	Books []BookResponse
+	Total int64
+}
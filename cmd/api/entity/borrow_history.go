@@ -4,14 +4,19 @@ import "time"
 
 // BorrowHistory is a model for borrow history table
 type BorrowHistory struct {
-	ID         uint       `gorm:"primaryKey" json:"id"`
-	BookID     uint       `gorm:"not null" json:"bookId"`
-	UserID     uint       `gorm:"not null" json:"userId"`
-	BorrowedAt *time.Time `gorm:"default:null" json:"borrowedAt"`
-	ReturnedAt *time.Time `gorm:"default:null" json:"returnedAt,omitempty"`
-	Status     string     `gorm:"type:varchar(20);not null" json:"status"` //  "borrowed", "returned"
-	CreatedAt  *time.Time `gorm:"default:now()" json:"createdAt"`
-	UpdatedAt  *time.Time `gorm:"default:now()" json:"updatedAt"`
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	BookID       uint       `gorm:"not null" json:"bookId"`
+	CopyID       *uint      `gorm:"index" json:"copyId"`
+	UserID       uint       `gorm:"not null" json:"userId"`
+	BorrowedAt   *time.Time `gorm:"default:null" json:"borrowedAt"`
+	DueAt        *time.Time `gorm:"default:null;index" json:"dueAt"`
+	RenewalCount uint       `gorm:"not null;default:0" json:"renewalCount"`
+	FineRate     *float64   `gorm:"type:numeric(12,2);default:null" json:"fineRate,omitempty"` // late fee per day, taken from the borrower's loan policy
+	ReturnedAt   *time.Time `gorm:"default:null" json:"returnedAt,omitempty"`
+	LostAt       *time.Time `gorm:"default:null" json:"lostAt,omitempty"` // late fees stop here, even when the book is found later
+	Status       string     `gorm:"type:varchar(20);not null" json:"status"` //  "borrowed", "returned", "overdue", "lost", "damaged"
+	CreatedAt    *time.Time `gorm:"default:now()" json:"createdAt"`
+	UpdatedAt    *time.Time `gorm:"default:now()" json:"updatedAt"`
 }
 
 // BorrowBookRequest is a request for borrow a book
@@ -23,18 +28,98 @@ type BorrowBookRequest struct {
 // ReturnBookRequest is a request for return a book
 type ReturnBookRequest struct {
 	HistoryID uint `json:"historyId" validate:"required"`
-	BookID 	  uint `json:"bookId" validate:"required"`
+	BookID    uint `json:"bookId" validate:"required"`
 	UserID    uint `json:"-"`
 }
 
+// RenewBookRequest is a request for renew a borrowed book
+type RenewBookRequest struct {
+	HistoryID uint `json:"historyId" validate:"required"`
+	BookID    uint `json:"bookId" validate:"required"`
+	UserID    uint `json:"-"`
+}
+
+// LostBookRequest is a request for mark a borrowed book as lost
+type LostBookRequest struct {
+	HistoryID         uint `json:"-"`
+	ChargeReplacement bool `json:"chargeReplacement"`
+	StaffID           uint `json:"-"`
+}
+
+// DamagedBookRequest is a request for return a borrowed book damaged
+type DamagedBookRequest struct {
+	HistoryID         uint `json:"-"`
+	ChargeReplacement bool `json:"chargeReplacement"`
+	StaffID           uint `json:"-"`
+}
+
+// FoundBookRequest is a request for reverse a lost book that turned up
+type FoundBookRequest struct {
+	HistoryID uint `json:"-"`
+	StaffID   uint `json:"-"`
+}
+
 // BorrowHistoryResponse represents the response for borrow history
 type BorrowHistoryResponse struct {
-	ID         uint       `json:"id"`
-	BookID     uint       `json:"bookId"`
-	UserID     uint       `json:"userId"`
-	BorrowedAt time.Time  `json:"borrowedAt"`
-	ReturnedAt *time.Time `json:"returnedAt,omitempty"`
-	Status     string     `json:"status"`
-	CreatedAt  *time.Time `json:"createdAt"`
-	UpdatedAt  *time.Time `json:"updatedAt"`
+	ID           uint       `json:"id"`
+	BookID       uint       `json:"bookId"`
+	CopyID       *uint      `json:"copyId"`
+	UserID       uint       `json:"userId"`
+	BorrowedAt   time.Time  `json:"borrowedAt"`
+	DueAt        *time.Time `json:"dueAt"`
+	RenewalCount uint       `json:"renewalCount"`
+	ReturnedAt   *time.Time `json:"returnedAt,omitempty"`
+	LostAt       *time.Time `json:"lostAt,omitempty"`
+	Status       string     `json:"status"`
+	CreatedAt    *time.Time `json:"createdAt"`
+	UpdatedAt    *time.Time `json:"updatedAt"`
+}
+
+// ListOverdueBorrowRequest is a request for listing overdue borrows
+type ListOverdueBorrowRequest struct {
+	Page   int     `form:"page" validate:"required_without=Cursor,omitempty,min=1"`
+	Size   int     `form:"size" validate:"required,min=1"`
+	Cursor *string `form:"cursor"` // pages by cursor instead of number, empty for the first page
+	UserID *uint   `form:"userId"`
+	BookID *uint   `form:"bookId"`
+}
+
+// ListOverdueBorrowResult is a page of overdue borrows
+type ListOverdueBorrowResult struct {
+	Histories  []BorrowHistoryResponse
+	Pagination Pagination
+}
+
+// ListUserBorrowHistoryRequest is a request for listing borrow history of a user
+type ListUserBorrowHistoryRequest struct {
+	Page   int        `form:"page" validate:"required_without=Cursor,omitempty,min=1"`
+	Size   int        `form:"size" validate:"required,min=1"`
+	Cursor *string    `form:"cursor"` // pages by cursor instead of number, empty for the first page
+	Status string     `form:"status" validate:"omitempty,oneof=BORROWED RETURNED OVERDUE LOST DAMAGED"`
+	From   *time.Time `form:"from" time_format:"2006-01-02"`
+	To     *time.Time `form:"to" time_format:"2006-01-02"`
+	UserID uint       `form:"-"`
+}
+
+// ListUserBorrowHistoryResult is a page of the borrow history of a user
+type ListUserBorrowHistoryResult struct {
+	Histories  []UserLoanResponse
+	Pagination Pagination
+}
+
+// UserLoanResponse represents a borrow history of a user with its book details
+type UserLoanResponse struct {
+	ID           uint       `json:"id"`
+	BookID       uint       `json:"bookId"`
+	BookTitle    string     `json:"bookTitle"`
+	BookAuthor   string     `json:"bookAuthor"`
+	CopyID       *uint      `json:"copyId"`
+	UserID       uint       `json:"userId"`
+	BorrowedAt   time.Time  `json:"borrowedAt"`
+	DueAt        *time.Time `json:"dueAt"`
+	RenewalCount uint       `json:"renewalCount"`
+	ReturnedAt   *time.Time `json:"returnedAt,omitempty"`
+	Status       string     `json:"status"`
+	CreatedAt    *time.Time `json:"createdAt"`
+	UpdatedAt    *time.Time `json:"updatedAt"`
 }
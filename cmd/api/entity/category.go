@@ -0,0 +1,67 @@
+package entity
+
+import "time"
+
+// Category is a model for category table, a node of the classification tree such as a Dewey class or a genre
+type Category struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	Name      string     `gorm:"type:varchar(255);not null" json:"name"`
+	Code      string     `gorm:"type:varchar(50);not null;default:''" json:"code"` // class number or other notation, such as 823.914
+	ParentID  *uint      `gorm:"index" json:"parentId"`
+	CreatedAt *time.Time `gorm:"default:now()" json:"createdAt"`
+	UpdatedAt *time.Time `gorm:"default:now()" json:"updatedAt"`
+
+	Children []Category     `gorm:"foreignKey:ParentID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT" json:"-"`
+	Books    []BookCategory `gorm:"foreignKey:CategoryID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT" json:"-"`
+}
+
+// BookCategory is a model for book category table, filing a book under a category
+type BookCategory struct {
+	BookID     uint `gorm:"primaryKey;autoIncrement:false" json:"bookId"`
+	CategoryID uint `gorm:"primaryKey;autoIncrement:false;index" json:"categoryId"`
+}
+
+// BookSubject is a model for book subject table, a free subject heading tagged on a book
+type BookSubject struct {
+	BookID uint   `gorm:"primaryKey;autoIncrement:false" json:"bookId"`
+	Name   string `gorm:"primaryKey;type:varchar(100)" json:"name"`
+}
+
+// CategoryCreateRequest is a request for creating a category
+type CategoryCreateRequest struct {
+	Name     string `json:"name" validate:"required,max=255"`
+	Code     string `json:"code" validate:"max=50"`
+	ParentID *uint  `json:"parentId" validate:"omitempty,min=1"` // none for a top level category
+}
+
+// CategoryUpdateRequest is a request for renaming or moving a category
+type CategoryUpdateRequest struct {
+	ID       uint   `json:"-"`
+	Name     string `json:"name" validate:"required,max=255"`
+	Code     string `json:"code" validate:"max=50"`
+	ParentID *uint  `json:"parentId" validate:"omitempty,min=1"` // none for a top level category
+}
+
+// CategoryResponse represents a category with its subcategories
+type CategoryResponse struct {
+	ID        uint               `json:"id"`
+	Name      string             `json:"name"`
+	Code      string             `json:"code"`
+	ParentID  *uint              `json:"parentId"`
+	CreatedAt *time.Time         `json:"createdAt"`
+	UpdatedAt *time.Time         `json:"updatedAt"`
+	Children  []CategoryResponse `gorm:"-" json:"children"`
+}
+
+// BookCategoryResponse represents a category a book is filed under
+type BookCategoryResponse struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	Code string `json:"code"`
+}
+
+// ListLatestBookRequest is a request for listing the latest books
+type ListLatestBookRequest struct {
+	CategoryID *uint   `form:"categoryId"` // includes the subcategories
+	Subject    *string `form:"subject"`
+}
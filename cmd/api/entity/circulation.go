@@ -0,0 +1,33 @@
+package entity
+
+import "time"
+
+// CheckoutRequest is a request for lend a copy to a patron at the circulation desk
+type CheckoutRequest struct {
+	UserID     uint   `json:"userId" validate:"required_without=CardNumber"`
+	CardNumber string `json:"cardNumber" validate:"required_without=UserID,max=32"`
+	Barcode    string `json:"barcode" validate:"required,max=64"`
+}
+
+// CheckinRequest is a request for take back a copy at the circulation desk,
+// the book ID is enough when only one of its copies is on loan
+type CheckinRequest struct {
+	Barcode string `json:"barcode" validate:"required_without=BookID,max=64"`
+	BookID  uint   `json:"bookId" validate:"required_without=Barcode"`
+}
+
+// CirculationReceiptResponse represents the receipt of a checkout or check-in
+type CirculationReceiptResponse struct {
+	HistoryID  uint       `json:"historyId"`
+	UserID     uint       `json:"userId"`
+	UserName   string     `json:"userName"`
+	BookID     uint       `json:"bookId"`
+	BookTitle  string     `json:"bookTitle"`
+	CopyID     uint       `json:"copyId"`
+	Barcode    string     `json:"barcode"`
+	BorrowedAt *time.Time `json:"borrowedAt"`
+	DueAt      *time.Time `json:"dueAt"`
+	ReturnedAt *time.Time `json:"returnedAt,omitempty"`
+	Status     string     `json:"status"`
+	CopyStatus string     `json:"copyStatus"` // ON_HOLD tells the desk to put the copy on the hold shelf
+}
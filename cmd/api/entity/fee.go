@@ -0,0 +1,63 @@
+package entity
+
+import "time"
+
+// FeeTransaction is a model for the append-only fee ledger table
+type FeeTransaction struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	UserID          uint       `gorm:"not null;index" json:"userId"`
+	BorrowHistoryID *uint      `gorm:"index" json:"borrowHistoryId,omitempty"`
+	Type            string     `gorm:"type:varchar(20);not null" json:"type"`     // "late_fee", "replacement", "payment", "waiver"
+	Amount          float64    `gorm:"type:numeric(12,2);not null" json:"amount"` // charges are positive, payments and waivers are negative
+	Note            string     `gorm:"type:text" json:"note"`
+	CreatedBy       *uint      `gorm:"default:null" json:"createdBy,omitempty"`
+	CreatedAt       *time.Time `gorm:"default:now()" json:"createdAt"`
+}
+
+// FeePaymentRequest is a request for record a fee payment
+type FeePaymentRequest struct {
+	UserID          uint    `json:"-"`
+	BorrowHistoryID *uint   `json:"borrowHistoryId"`
+	Amount          float64 `json:"amount" validate:"required,gt=0"`
+	Note            string  `json:"note"`
+	StaffID         uint    `json:"-"`
+}
+
+// FeeWaiverRequest is a request for waive fees
+type FeeWaiverRequest struct {
+	UserID          uint    `json:"-"`
+	BorrowHistoryID *uint   `json:"borrowHistoryId"`
+	Amount          float64 `json:"amount" validate:"required,gt=0"`
+	Note            string  `json:"note" validate:"required"`
+	StaffID         uint    `json:"-"`
+}
+
+// ReplacementChargeRequest is a request for charge the replacement cost of a borrowed book
+type ReplacementChargeRequest struct {
+	BorrowHistoryID uint `json:"borrowHistoryId" validate:"required"`
+	StaffID         uint `json:"-"`
+}
+
+// FeeTransactionResponse represents the response for fee transaction
+type FeeTransactionResponse struct {
+	ID              uint       `json:"id"`
+	UserID          uint       `json:"userId"`
+	BorrowHistoryID *uint      `json:"borrowHistoryId,omitempty"`
+	Type            string     `json:"type"`
+	Amount          float64    `json:"amount"`
+	Note            string     `json:"note"`
+	CreatedBy       *uint      `json:"createdBy,omitempty"`
+	CreatedAt       *time.Time `json:"createdAt"`
+}
+
+// FeeBalanceResponse represents the fee balance of a user
+type FeeBalanceResponse struct {
+	UserID       uint                     `json:"userId"`
+	Balance      float64                  `json:"balance"`
+	Transactions []FeeTransactionResponse `json:"transactions"`
+}
+
+// AccrueLateFeesResponse represents the result of a late fee accrual
+type AccrueLateFeesResponse struct {
+	Charged int64 `json:"charged"`
+}
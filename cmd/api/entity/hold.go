@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+// Hold is a model for hold table
+type Hold struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	BookID    uint       `gorm:"not null;index" json:"bookId"`
+	UserID    uint       `gorm:"not null;index" json:"userId"`
+	CopyID    *uint      `gorm:"index" json:"copyId,omitempty"`                 // the copy set aside once the hold is ready
+	Status    string     `gorm:"type:varchar(20);not null;index" json:"status"` // "waiting", "ready", "fulfilled", "cancelled", "expired"
+	ReadyAt   *time.Time `gorm:"default:null" json:"readyAt,omitempty"`
+	ExpiresAt *time.Time `gorm:"default:null" json:"expiresAt,omitempty"`
+	CreatedAt *time.Time `gorm:"default:now()" json:"createdAt"`
+	UpdatedAt *time.Time `gorm:"default:now()" json:"updatedAt"`
+}
+
+// PlaceHoldRequest is a request for place a hold on a book
+type PlaceHoldRequest struct {
+	BookID uint `json:"bookId" validate:"required"`
+	UserID uint `json:"-"`
+}
+
+// CancelHoldRequest is a request for cancel a hold
+type CancelHoldRequest struct {
+	HoldID uint `json:"-"`
+	UserID uint `json:"-"`
+}
+
+// HoldResponse represents the response for hold
+type HoldResponse struct {
+	ID            uint       `json:"id"`
+	BookID        uint       `json:"bookId"`
+	BookTitle     string     `json:"bookTitle"`
+	UserID        uint       `json:"userId"`
+	Status        string     `json:"status"`
+	QueuePosition int        `json:"queuePosition"` // 0 once a copy is ready for pickup
+	ReadyAt       *time.Time `json:"readyAt,omitempty"`
+	ExpiresAt     *time.Time `json:"expiresAt,omitempty"`
+	CreatedAt     *time.Time `json:"createdAt"`
+	UpdatedAt     *time.Time `json:"updatedAt"`
+}
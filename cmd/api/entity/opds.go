@@ -0,0 +1,13 @@
+package entity
+
+// OPDSBooksRequest is a request for a page of an OPDS acquisition feed
+type OPDSBooksRequest struct {
+	Query      string `form:"q"`          // searched like the search of a book listing
+	CategoryID *uint  `form:"categoryId"` // includes the subcategories
+	Page       int    `form:"page" validate:"omitempty,min=1"`
+}
+
+// OPDSCategoriesRequest is a request for an OPDS navigation feed of categories
+type OPDSCategoriesRequest struct {
+	ParentID *uint `form:"parentId"` // the top of the tree when missing
+}
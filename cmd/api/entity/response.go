@@ -1,10 +1,25 @@
 package entity
 
 type ResponseData struct {
-	Data interface{} `json:"data"`
+	Data       interface{} `json:"data"`
+	Pagination *Pagination `json:"pagination,omitempty"`
+	Suggestion *string     `json:"suggestion,omitempty"` // did you mean, for searches with a likely typo
+	Facets     interface{} `json:"facets,omitempty"`
+}
+
+// Pagination tells where a page sits in a listing. Pages by number are counted,
+// pages by cursor are not and only link forward.
+type Pagination struct {
+	Page       int     `json:"page,omitempty"`
+	Size       int     `json:"size"`
+	Total      *int64  `json:"total,omitempty"`
+	TotalPages *int64  `json:"totalPages,omitempty"`
+	NextCursor *string `json:"nextCursor,omitempty"` // pass as cursor to get the page after this one
+	Next       *string `json:"next,omitempty"`
+	Prev       *string `json:"prev,omitempty"`
 }
 
 type ResponseError struct {
 	Error string `json:"error"`
 	Code  int    `json:"code"`
-}
\ No newline at end of file
+}
@@ -11,11 +11,13 @@ type User struct {
 	Username 	string    	`gorm:"unique;not null" json:"username"`
 	Password 	string    	`gorm:"not null" json:"password"`
 	Role 		string 		`gorm:"not null" json:"role"`
+	CardNumber 	*string 	`gorm:"type:varchar(32);uniqueIndex" json:"cardNumber"` // library card, scanned at the circulation desk
 	CreatedAt 	*time.Time 	`gorm:"default:now()" json:"createdAt"`
 	UpdatedAt 	*time.Time 	`gorm:"default:now()" json:"updatedAt"`
 	DeletedAt 	*time.Time 	`gorm:"default:null" json:"deletedAt"`
 
 	BorrowHistories []BorrowHistory `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"-"`
+	Holds           []Hold          `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
 }
 
 // UserLoginRequest is a request for log in
@@ -43,10 +45,18 @@ type UserUpdateRequest struct {
 	Name 		string 		`json:"name" binding:"required"`
 }
 
+// UserCardRequest is a request for assign a library card to a user
+type UserCardRequest struct {
+	ID 			uint 		`json:"-"`
+	CardNumber 	string 		`json:"cardNumber" validate:"required,max=32"`
+}
+
 // UserResponse represents a response for user
 type UserResponse struct {
 	ID        uint      `json:"id"`
 	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	CardNumber *string  `json:"cardNumber,omitempty"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	"go-library-service/cmd/api/middleware"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// CreateAuthor creates an author
+// @Summary Create an author
+// @Description Create an author, refused when an author already has the name under any spelling
+// @Tags management authors
+// @Accept  json
+// @Produce  json
+// @Param   author  body      entity.AuthorCreateRequest  true  "Author"
+// @Success 201 {object} entity.ResponseData{data=entity.AuthorResponse}
+// @Failure 400 {object} entity.ResponseError
+// @Failure 409 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/authors [post]
+func (h *Handler) CreateAuthor(c *gin.Context) {
+	var req entity.AuthorCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.CreateAuthor]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid request", Code: http.StatusBadRequest})
+		return
+	}
+
+	if err := h.deps.Validator.Struct(req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.CreateAuthor]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: err.Error(), Code: http.StatusBadRequest})
+		return
+	}
+
+	author, err := h.deps.Service.CreateAuthor(req)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapConflict) {
+			c.JSON(http.StatusConflict, entity.ResponseError{Error: "author already exists", Code: http.StatusConflict})
+			return
+		}
+
+		log.Error(errors.Wrap(err, "[Handler.CreateAuthor]: unable to create author"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to create author", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatusJSON(http.StatusCreated, entity.ResponseData{Data: author})
+}
+
+// ListAuthors lists authors
+// @Summary List authors
+// @Description Get a list of authors by name with the number of books crediting them
+// @Tags management authors
+// @Accept  json
+// @Produce  json
+// @Param   page    query     int     true   "Page number"
+// @Param   size    query     int     true   "Number of items per page"
+// @Param   search  query     string  false  "Part of the author name"
+// @Success 200 {object} entity.ResponseData{data=[]entity.AuthorResponse}
+// @Failure 400 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/authors [get]
+func (h *Handler) ListAuthors(c *gin.Context) {
+	var req entity.ListAuthorRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ListAuthors]: unable to bind query"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "unable to bind query", Code: http.StatusBadRequest})
+		return
+	}
+
+	if err := h.deps.Validator.Struct(req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ListAuthors]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: err.Error(), Code: http.StatusBadRequest})
+		return
+	}
+
+	result, err := h.deps.Service.ListAuthors(req)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ListAuthors]: unable to list authors"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to list authors", Code: http.StatusInternalServerError})
+		return
+	}
+
+	linkPages(c, &result.Pagination)
+	c.AbortWithStatusJSON(http.StatusOK, entity.ResponseData{Data: result.Authors, Pagination: &result.Pagination})
+}
+
+// GetAuthor gets an author
+// @Summary Get an author
+// @Description Get an author with the books crediting them
+// @Tags authors
+// @Accept  json
+// @Produce  json
+// @Param   id   path      int  true  "Author ID"
+// @Success 200 {object} entity.ResponseData{data=entity.AuthorDetailResponse}
+// @Failure 400 {object} entity.ResponseError
+// @Failure 404 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /authors/{id} [get]
+func (h *Handler) GetAuthor(c *gin.Context) {
+	authorID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.GetAuthor]: unable to convert author id"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid author id", Code: http.StatusBadRequest})
+		return
+	}
+
+	author, err := h.deps.Service.GetAuthor(uint(authorID))
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			c.JSON(http.StatusNotFound, entity.ResponseError{Error: "author not found", Code: http.StatusNotFound})
+			return
+		}
+
+		log.Error(errors.Wrap(err, "[Handler.GetAuthor]: unable to get author"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to get author", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatusJSON(http.StatusOK, entity.ResponseData{Data: author})
+}
+
+// UpdateAuthor renames an author
+// @Summary Rename an author
+// @Description Rename an author, the author line of their books follows the new name
+// @Tags management authors
+// @Accept  json
+// @Produce  json
+// @Param   id      path      int  true  "Author ID"
+// @Param   author  body      entity.AuthorUpdateRequest  true  "Author"
+// @Success 200 {object} entity.ResponseData
+// @Failure 400 {object} entity.ResponseError
+// @Failure 404 {object} entity.ResponseError
+// @Failure 409 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/authors/{id} [put]
+func (h *Handler) UpdateAuthor(c *gin.Context) {
+	authorID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.UpdateAuthor]: unable to convert author id"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid author id", Code: http.StatusBadRequest})
+		return
+	}
+
+	var req entity.AuthorUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.UpdateAuthor]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid request", Code: http.StatusBadRequest})
+		return
+	}
+
+	if err := h.deps.Validator.Struct(req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.UpdateAuthor]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: err.Error(), Code: http.StatusBadRequest})
+		return
+	}
+
+	req.ID = uint(authorID)
+
+	if err := h.deps.Service.UpdateAuthor(req); err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			c.JSON(http.StatusNotFound, entity.ResponseError{Error: "author not found", Code: http.StatusNotFound})
+			return
+		}
+
+		if errors.Is(err, errmap.ErrmapConflict) {
+			c.JSON(http.StatusConflict, entity.ResponseError{Error: "author already exists", Code: http.StatusConflict})
+			return
+		}
+
+		log.Error(errors.Wrap(err, "[Handler.UpdateAuthor]: unable to update author"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to update author", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatus(http.StatusOK)
+}
+
+// DeleteAuthor deletes an author
+// @Summary Delete an author
+// @Description Delete an author, refused while any book credits them
+// @Tags management authors
+// @Accept  json
+// @Produce  json
+// @Param   id   path      int  true  "Author ID"
+// @Success 200 {object} entity.ResponseData
+// @Failure 400 {object} entity.ResponseError
+// @Failure 404 {object} entity.ResponseError
+// @Failure 409 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/authors/{id} [delete]
+func (h *Handler) DeleteAuthor(c *gin.Context) {
+	authorID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.DeleteAuthor]: unable to convert author id"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid author id", Code: http.StatusBadRequest})
+		return
+	}
+
+	if err := h.deps.Service.DeleteAuthor(uint(authorID)); err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			c.JSON(http.StatusNotFound, entity.ResponseError{Error: "author not found", Code: http.StatusNotFound})
+			return
+		}
+
+		if errors.Is(err, errmap.ErrmapConflict) {
+			c.JSON(http.StatusConflict, entity.ResponseError{Error: "author is credited on books", Code: http.StatusConflict})
+			return
+		}
+
+		log.Error(errors.Wrap(err, "[Handler.DeleteAuthor]: unable to delete author"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to delete author", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatus(http.StatusOK)
+}
+
+// RegisterAuthorRoutes registers author routes
+func RegisterAuthorRoutes(router *gin.RouterGroup, handler *Handler) {
+	authorRoutes := router.Group("/authors")
+	{
+		authorRoutes.Use(middleware.AuthMiddleware())
+		authorRoutes.Use(middleware.RoleMiddleware(constant.UserTypeUser, constant.UserTypeStaff))
+
+		authorRoutes.GET("/:id", handler.GetAuthor)
+	}
+
+	managementAuthorRoutes := router.Group("/management/authors")
+	{
+		managementAuthorRoutes.Use(middleware.AuthMiddleware())
+		managementAuthorRoutes.Use(middleware.RoleMiddleware(constant.UserTypeStaff))
+
+		managementAuthorRoutes.POST("", handler.CreateAuthor)
+		managementAuthorRoutes.GET("", handler.ListAuthors)
+		managementAuthorRoutes.PUT("/:id", handler.UpdateAuthor)
+		managementAuthorRoutes.DELETE("/:id", handler.DeleteAuthor)
+	}
+}
@@ -0,0 +1,215 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	"go-library-service/cmd/api/handler"
+	"go-library-service/cmd/api/handler/mock"
+	"go-library-service/cmd/api/middleware"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Author Handler", func() {
+	var (
+		h           *handler.Handler
+		serviceMock *mock.MockService
+		r           *gin.Engine
+		ctrl        *gomock.Controller
+		testToken   string
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		serviceMock = mock.NewMockService(ctrl)
+		h = handler.NewHandler(&handler.Dependencies{
+			Service:   serviceMock,
+			Validator: validator.New(),
+		}, &handler.Config{})
+
+		gin.SetMode(gin.TestMode)
+		r = gin.Default()
+		handler.RegisterAuthorRoutes(r.Group("/api"), h)
+
+		var err error
+		testToken, err = middleware.GenerateToken(uint(1), constant.UserTypeStaff)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("CreateAuthor", func() {
+		It("should create an author", func() {
+			jsonValue, _ := json.Marshal(entity.AuthorCreateRequest{Name: "Neil Gaiman"})
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/authors", bytes.NewBuffer(jsonValue))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().
+				CreateAuthor(entity.AuthorCreateRequest{Name: "Neil Gaiman"}).
+				Return(&entity.AuthorResponse{ID: 1, Name: "Neil Gaiman"}, nil)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.CreateAuthor(c)
+
+			Expect(w.Code).To(Equal(http.StatusCreated))
+		})
+
+		It("should return error for a blank name", func() {
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/authors", bytes.NewBufferString(`{"name": ""}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.CreateAuthor(c)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return conflict when the author exists", func() {
+			jsonValue, _ := json.Marshal(entity.AuthorCreateRequest{Name: "Gaiman, Neil"})
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/authors", bytes.NewBuffer(jsonValue))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().CreateAuthor(gomock.Any()).Return(nil, errmap.ErrmapConflict)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.CreateAuthor(c)
+
+			Expect(w.Code).To(Equal(http.StatusConflict))
+		})
+	})
+
+	Context("ListAuthors", func() {
+		It("should return a page of authors with links", func() {
+			req, _ := http.NewRequest(http.MethodGet, "/api/management/authors?page=1&size=1", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			total, totalPages := int64(2), int64(2)
+			serviceMock.EXPECT().
+				ListAuthors(entity.ListAuthorRequest{Page: 1, Size: 1}).
+				Return(&entity.ListAuthorResult{
+					Authors:    []entity.AuthorResponse{{ID: 1, Name: "Neil Gaiman", BookCount: 2}},
+					Pagination: entity.Pagination{Page: 1, Size: 1, Total: &total, TotalPages: &totalPages},
+				}, nil)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.ListAuthors(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var response struct {
+				Data       []entity.AuthorResponse `json:"data"`
+				Pagination entity.Pagination       `json:"pagination"`
+			}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Data[0].BookCount).To(Equal(int64(2)))
+			Expect(*response.Pagination.Next).To(Equal("/api/management/authors?page=2&size=1"))
+		})
+	})
+
+	Context("GetAuthor", func() {
+		It("should return the author with their books", func() {
+			req, _ := http.NewRequest(http.MethodGet, "/api/authors/1", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().GetAuthor(uint(1)).Return(&entity.AuthorDetailResponse{
+				AuthorResponse: entity.AuthorResponse{ID: 1, Name: "Neil Gaiman", BookCount: 1},
+				Books:          []entity.BookResponse{{ID: 5, Title: "Good Omens"}},
+			}, nil)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "1"})
+			c.Request = req
+
+			h.GetAuthor(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var response map[string]map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response["data"]["name"]).To(Equal("Neil Gaiman"))
+			Expect(response["data"]["books"]).To(HaveLen(1))
+		})
+
+		It("should return not found for an unknown author", func() {
+			req, _ := http.NewRequest(http.MethodGet, "/api/authors/9", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().GetAuthor(uint(9)).Return(nil, errmap.ErrmapNotFound)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "9"})
+			c.Request = req
+
+			h.GetAuthor(c)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("UpdateAuthor", func() {
+		It("should rename the author", func() {
+			jsonValue, _ := json.Marshal(entity.AuthorUpdateRequest{Name: "Neil Gaiman"})
+			req, _ := http.NewRequest(http.MethodPut, "/api/management/authors/1", bytes.NewBuffer(jsonValue))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().UpdateAuthor(entity.AuthorUpdateRequest{ID: 1, Name: "Neil Gaiman"}).Return(nil)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "1"})
+			c.Request = req
+
+			h.UpdateAuthor(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+		})
+	})
+
+	Context("DeleteAuthor", func() {
+		It("should return conflict while books credit the author", func() {
+			req, _ := http.NewRequest(http.MethodDelete, "/api/management/authors/1", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().DeleteAuthor(uint(1)).Return(errmap.ErrmapConflict)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "1"})
+			c.Request = req
+
+			h.DeleteAuthor(c)
+
+			Expect(w.Code).To(Equal(http.StatusConflict))
+		})
+	})
+})
@@ -15,13 +15,14 @@ import (
 
 // CreateBook creates a new book
 // @Summary Create a new book
-// @Description Create a new book
+// @Description Create a new book, crediting the authors given by ID or else the names in its author line
 // @Tags management books
 // @Accept  json
 // @Produce  json
 // @Param   book  body      entity.BookCreateRequest  true  "Create book"
 // @Success 201 {object} entity.ResponseData
 // @Failure 400 {object} entity.ResponseError
+// @Failure 409 {object} entity.ResponseError
 // @Failure 500 {object} entity.ResponseError
 // @Security BearerAuth
 // @Router /management/books [post]
@@ -41,6 +42,26 @@ func (h *Handler) CreateBook(c *gin.Context) {
 
 	err := h.deps.Service.CreateBook(req)
 	if err != nil {
+		if errors.Is(err, errmap.ErrmapUnknownAuthor) {
+			c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "unknown author id", Code: http.StatusBadRequest})
+			return
+		}
+
+		if errors.Is(err, errmap.ErrmapUnknownCategory) {
+			c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "unknown category id", Code: http.StatusBadRequest})
+			return
+		}
+
+		if errors.Is(err, errmap.ErrmapInvalidISBN) {
+			c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid isbn", Code: http.StatusBadRequest})
+			return
+		}
+
+		if errors.Is(err, errmap.ErrmapConflict) {
+			c.JSON(http.StatusConflict, entity.ResponseError{Error: "isbn already exists", Code: http.StatusConflict})
+			return
+		}
+
 		log.Error(errors.Wrap(err, "[Handler.CreateBook]: unable to create book"))
 		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "Unable to create book", Code: http.StatusInternalServerError})
 		return
@@ -55,10 +76,24 @@ func (h *Handler) CreateBook(c *gin.Context) {
 // @Tags books
 // @Accept  json
 // @Produce  json
-// @Param   page      query     int     false  "Page number"
+// @Param   page      query     int     false  "Page number, required unless paging by cursor"
 // @Param   size	  query     int     false  "Number of items per page"
-// @Param   search    query     string  false  "Search query"
-// @Success 200 {object} entity.ResponseData{data=[]entity.BookResponse}
+// @Param   cursor    query     string  false  "Cursor of the page to get, empty for the first page, pages by cursor are not counted"
+// @Param   search    query     string  false  "Search title and author, supports quoted phrases, prefix*, -excluded and OR"
+// @Param   mode      query     string  false  "Search mode, fuzzy tolerates typos in title and author" Enums(fulltext, fuzzy)
+// @Param   author    query     string  false  "Only books crediting this author, under any spelling of the name"
+// @Param   authorId  query     int     false  "Only books crediting the author with this ID"
+// @Param   categoryId query    int     false  "Only books filed under this category or its subcategories"
+// @Param   subject   query     string  false  "Only books tagged with this subject"
+// @Param   available query     bool    false  "Only books with a copy on the shelf, or only books without one"
+// @Param   minPrice  query     number  false  "Lowest price"
+// @Param   maxPrice  query     number  false  "Highest price"
+// @Param   language  query     string  false  "Only books in this language, as a BCP 47 tag such as en or th"
+// @Param   minYear   query     int     false  "Earliest publication year"
+// @Param   maxYear   query     int     false  "Latest publication year"
+// @Param   sort      query     []string false "Sort fields in order, a leading - sorts descending" collectionFormat(multi) Enums(title, -title, author, -author, createdAt, -createdAt, popularity, -popularity)
+// @Param   facets    query     bool    false  "Also count the matches by author, category, subject, availability, price range, language and decade"
+// @Success 200 {object} entity.ResponseData{data=[]entity.BookResponse,facets=entity.BookFacets}
 // @Failure 400 {object} entity.ResponseError
 // @Failure 500 {object} entity.ResponseError
 // @Security BearerAuth
@@ -77,14 +112,35 @@ func (h *Handler) ListBook(c *gin.Context) {
 		return
 	}
 
-	books, err := h.deps.Service.ListBook(req)
+	if req.MinPrice != nil && req.MaxPrice != nil && *req.MaxPrice < *req.MinPrice {
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "maxPrice must not be less than minPrice", Code: http.StatusBadRequest})
+		return
+	}
+
+	if req.MinYear != nil && req.MaxYear != nil && *req.MaxYear < *req.MinYear {
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "maxYear must not be less than minYear", Code: http.StatusBadRequest})
+		return
+	}
+
+	result, err := h.deps.Service.ListBook(req)
 	if err != nil {
+		if errors.Is(err, errmap.ErrmapInvalidCursor) {
+			c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid cursor", Code: http.StatusBadRequest})
+			return
+		}
 		log.Error(errors.Wrap(err, "[Handler.ListBook]: unable to list books"))
 		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "Unable to list books", Code: http.StatusInternalServerError})
 		return
 	}
 
-	c.AbortWithStatusJSON(http.StatusOK, entity.ResponseData{Data: books})
+	linkPages(c, &result.Pagination)
+	response := entity.ResponseData{Data: result.Books, Pagination: &result.Pagination, Suggestion: result.Suggestion}
+	// A nil *BookFacets would still be written out as null
+	if result.Facets != nil {
+		response.Facets = result.Facets
+	}
+
+	c.AbortWithStatusJSON(http.StatusOK, response)
 }
 
 // GetBookByID gets a book by id
@@ -118,6 +174,40 @@ func (h *Handler) GetBookByID(c *gin.Context) {
 	c.AbortWithStatusJSON(http.StatusOK, entity.ResponseData{Data: book})
 }
 
+// GetBookByISBN gets a book by isbn
+// @Summary Get a book by ISBN
+// @Description Get a book by its ISBN-10 or ISBN-13, hyphens and spaces allowed
+// @Tags books
+// @Accept  json
+// @Produce  json
+// @Param   isbn  path      string  true  "ISBN-10 or ISBN-13"
+// @Success 200 {object} entity.ResponseData{data=entity.BookResponse}
+// @Failure 400 {object} entity.ResponseError
+// @Failure 404 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /books/isbn/{isbn} [get]
+func (h *Handler) GetBookByISBN(c *gin.Context) {
+	book, err := h.deps.Service.GetBookByISBN(c.Param("isbn"))
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapInvalidISBN) {
+			c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid isbn", Code: http.StatusBadRequest})
+			return
+		}
+
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			c.JSON(http.StatusNotFound, entity.ResponseError{Error: "book not found", Code: http.StatusNotFound})
+			return
+		}
+
+		log.Error(errors.Wrap(err, "[Handler.GetBookByISBN]: unable to get book"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to get book", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatusJSON(http.StatusOK, entity.ResponseData{Data: book})
+}
+
 // UpdateBook updates a book
 // @Summary Update a book
 // @Description Update a book by ID
@@ -129,6 +219,7 @@ func (h *Handler) GetBookByID(c *gin.Context) {
 // @Success 200 {object} entity.ResponseData
 // @Failure 400 {object} entity.ResponseError
 // @Failure 404 {object} entity.ResponseError
+// @Failure 409 {object} entity.ResponseError
 // @Failure 500 {object} entity.ResponseError
 // @Security BearerAuth
 // @Router /management/books/{id} [put]
@@ -157,6 +248,26 @@ func (h *Handler) UpdateBook(c *gin.Context) {
 			return
 		}
 
+		if errors.Is(err, errmap.ErrmapUnknownAuthor) {
+			c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "unknown author id", Code: http.StatusBadRequest})
+			return
+		}
+
+		if errors.Is(err, errmap.ErrmapUnknownCategory) {
+			c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "unknown category id", Code: http.StatusBadRequest})
+			return
+		}
+
+		if errors.Is(err, errmap.ErrmapInvalidISBN) {
+			c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid isbn", Code: http.StatusBadRequest})
+			return
+		}
+
+		if errors.Is(err, errmap.ErrmapConflict) {
+			c.JSON(http.StatusConflict, entity.ResponseError{Error: "isbn already exists", Code: http.StatusConflict})
+			return
+		}
+
 		log.Error(errors.Wrap(err, "[Handler.UpdateBook]: unable to update book"))
 		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to update book", Code: http.StatusInternalServerError})
 		return
@@ -165,19 +276,110 @@ func (h *Handler) UpdateBook(c *gin.Context) {
 	c.AbortWithStatus(http.StatusOK)
 }
 
+// ArchiveBook archives a book
+// @Summary Archive a book
+// @Description Withdraw a book from the catalog, refused while any copy is on loan
+// @Tags management books
+// @Accept  json
+// @Produce  json
+// @Param   id   path      int  true  "Book ID"
+// @Success 200 {object} entity.ResponseData
+// @Failure 400 {object} entity.ResponseError
+// @Failure 404 {object} entity.ResponseError
+// @Failure 409 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/books/{id} [delete]
+func (h *Handler) ArchiveBook(c *gin.Context) {
+	bookID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ArchiveBook]: invalid book id"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid book id", Code: http.StatusBadRequest})
+		return
+	}
+
+	if err := h.deps.Service.ArchiveBook(uint(bookID)); err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			c.JSON(http.StatusNotFound, entity.ResponseError{Error: "book not found", Code: http.StatusNotFound})
+			return
+		}
+		if errors.Is(err, errmap.ErrmapActiveLoans) {
+			c.JSON(http.StatusConflict, entity.ResponseError{Error: "book has active loans", Code: http.StatusConflict})
+			return
+		}
+		if errors.Is(err, errmap.ErrmapConflict) {
+			c.JSON(http.StatusConflict, entity.ResponseError{Error: "book is already archived", Code: http.StatusConflict})
+			return
+		}
+		log.Error(errors.Wrap(err, "[Handler.ArchiveBook]: unable to archive book"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to archive book", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatus(http.StatusOK)
+}
+
+// RestoreBook restores an archived book
+// @Summary Restore an archived book
+// @Description Put an archived book back in the catalog
+// @Tags management books
+// @Accept  json
+// @Produce  json
+// @Param   id   path      int  true  "Book ID"
+// @Success 200 {object} entity.ResponseData
+// @Failure 400 {object} entity.ResponseError
+// @Failure 404 {object} entity.ResponseError
+// @Failure 409 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/books/{id}/restore [post]
+func (h *Handler) RestoreBook(c *gin.Context) {
+	bookID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.RestoreBook]: invalid book id"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid book id", Code: http.StatusBadRequest})
+		return
+	}
+
+	if err := h.deps.Service.RestoreBook(uint(bookID)); err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			c.JSON(http.StatusNotFound, entity.ResponseError{Error: "book not found", Code: http.StatusNotFound})
+			return
+		}
+		if errors.Is(err, errmap.ErrmapConflict) {
+			c.JSON(http.StatusConflict, entity.ResponseError{Error: "book is not archived", Code: http.StatusConflict})
+			return
+		}
+		log.Error(errors.Wrap(err, "[Handler.RestoreBook]: unable to restore book"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to restore book", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatus(http.StatusOK)
+}
+
 // ListLatestBooks lists latest books
 // @Summary List latest books
-// @Description Get a list of latest books
+// @Description Get a list of latest books, optionally in a category or with a subject
 // @Tags books
 // @Accept  json
 // @Produce  json
+// @Param   categoryId  query     int     false  "Only books filed under this category or its subcategories"
+// @Param   subject     query     string  false  "Only books tagged with this subject"
 // @Success 200 {object} entity.ResponseData{data=[]entity.BookResponse}
 // @Failure 400 {object} entity.ResponseError
 // @Failure 500 {object} entity.ResponseError
 // @Security BearerAuth
 // @Router /books/latest [get]
 func (h *Handler) ListLatestBooks(c *gin.Context) {
-	books, err := h.deps.Service.ListLatestBooks()
+	var req entity.ListLatestBookRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ListLatestBooks]: unable to bind query"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "unable to bind query", Code: http.StatusBadRequest})
+		return
+	}
+
+	books, err := h.deps.Service.ListLatestBooks(req)
 	if err != nil {
 		log.Error(errors.Wrap(err, "[Handler.ListLatestBooks]: unable to list latest books"))
 		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to list latest books", Code: http.StatusInternalServerError})
@@ -198,6 +400,7 @@ func (h *Handler) ListLatestBooks(c *gin.Context) {
 // @Success 200 {object} entity.ResponseData
 // @Failure 400 {object} entity.ResponseError
 // @Failure 404 {object} entity.ResponseError
+// @Failure 403 {object} entity.ResponseError
 // @Failure 409 {object} entity.ResponseError
 // @Failure 500 {object} entity.ResponseError
 // @Security BearerAuth
@@ -230,6 +433,11 @@ func (h *Handler) BorrowBook(c *gin.Context) {
 			c.JSON(http.StatusConflict, entity.ResponseError{Error: "out of stock", Code: http.StatusConflict})
 			return
 		}
+		var limitErr *errmap.LoanLimitError
+		if errors.As(err, &limitErr) {
+			c.JSON(http.StatusForbidden, entity.ResponseError{Error: limitErr.Error(), Code: http.StatusForbidden})
+			return
+		}
 		log.Error(errors.Wrap(err, "[Handler.BorrowBook]: unable to borrow book"))
 		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to borrow book", Code: http.StatusInternalServerError})
 		return
@@ -289,6 +497,69 @@ func (h *Handler) ReturnBook(c *gin.Context) {
 	c.AbortWithStatus(http.StatusOK)
 }
 
+// RenewBook renews a borrowed book
+// @Summary Renew a borrowed book
+// @Description Extend the due date of an active borrow
+// @Tags books
+// @Accept  json
+// @Produce  json
+// @Param   id   path      int  true  "Book ID"
+// @Param   renew  body      entity.RenewBookRequest  true  "Renew book"
+// @Success 200 {object} entity.ResponseData{data=entity.BorrowHistoryResponse}
+// @Failure 400 {object} entity.ResponseError
+// @Failure 404 {object} entity.ResponseError
+// @Failure 409 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /books/{id}/renew [post]
+func (h *Handler) RenewBook(c *gin.Context) {
+	userID := h.getJWTInfo(c)
+
+	var req entity.RenewBookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.RenewBook]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid request", Code: http.StatusBadRequest})
+		return
+	}
+
+	if err := h.deps.Validator.Struct(req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.RenewBook]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: err.Error(), Code: http.StatusBadRequest})
+		return
+	}
+
+	req.UserID = userID
+
+	history, err := h.deps.Service.RenewBook(req)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			c.JSON(http.StatusNotFound, entity.ResponseError{Error: "borrow history not found", Code: http.StatusNotFound})
+			return
+		}
+
+		if errors.Is(err, errmap.ErrmapRenewalLimit) {
+			c.JSON(http.StatusConflict, entity.ResponseError{Error: "renewal limit reached", Code: http.StatusConflict})
+			return
+		}
+
+		if errors.Is(err, errmap.ErrmapHoldPending) {
+			c.JSON(http.StatusConflict, entity.ResponseError{Error: "book is reserved by another user", Code: http.StatusConflict})
+			return
+		}
+
+		if errors.Is(err, errmap.ErrmapConflict) {
+			c.JSON(http.StatusConflict, entity.ResponseError{Error: "book is not borrowed", Code: http.StatusConflict})
+			return
+		}
+
+		log.Error(errors.Wrap(err, "[Handler.RenewBook]: unable to renew book"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to renew book", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatusJSON(http.StatusOK, entity.ResponseData{Data: history})
+}
+
 // GetBookBorrowHistory gets a book borrow history
 // @Summary Get borrow history for a book
 // @Description Get the borrow history for book
@@ -328,9 +599,11 @@ func RegisterBookRoutes(router *gin.RouterGroup, handler *Handler) {
 		bookRoutes.Use(middleware.RoleMiddleware(constant.UserTypeUser, constant.UserTypeStaff))
 
 		bookRoutes.GET("", handler.ListBook)
+		bookRoutes.GET("/isbn/:isbn", handler.GetBookByISBN)
 		bookRoutes.GET("/:id", handler.GetBookByID)
 		bookRoutes.POST("/:id/borrow", handler.BorrowBook)
 		bookRoutes.POST("/:id/return", handler.ReturnBook)
+		bookRoutes.POST("/:id/renew", handler.RenewBook)
 	}
 
 	managementBookRoutes := router.Group("/management/books")
@@ -339,7 +612,16 @@ func RegisterBookRoutes(router *gin.RouterGroup, handler *Handler) {
 		managementBookRoutes.Use(middleware.RoleMiddleware(constant.UserTypeStaff))
 		
 		managementBookRoutes.POST("", handler.CreateBook)
+		managementBookRoutes.POST("/import", handler.ImportBooks)
+		managementBookRoutes.POST("/import/marc", handler.ImportMARCBooks)
+		managementBookRoutes.GET("/export", handler.ExportBooks)
+		managementBookRoutes.GET("/export/marc", handler.ExportMARCBooks)
+		managementBookRoutes.GET("/:id/marc", handler.GetBookMARC)
 		managementBookRoutes.PUT("/:id", handler.UpdateBook)
+		managementBookRoutes.DELETE("/:id", handler.ArchiveBook)
+		managementBookRoutes.POST("/:id/restore", handler.RestoreBook)
+		managementBookRoutes.PUT("/:id/cover", handler.UploadBookCover)
+		managementBookRoutes.DELETE("/:id/cover", handler.DeleteBookCover)
 		managementBookRoutes.GET("/:id/history", handler.GetBookBorrowHistory)
 	}
 	
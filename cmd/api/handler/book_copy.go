@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	"go-library-service/cmd/api/middleware"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// AddBookCopy adds a copy of a book
+// @Summary Add a book copy
+// @Description Add a physical copy of a book, it goes to the next waiting hold if there is one
+// @Tags management copies
+// @Accept  json
+// @Produce  json
+// @Param   id    path      int  true  "Book ID"
+// @Param   copy  body      entity.BookCopyCreateRequest  true  "Book copy"
+// @Success 201 {object} entity.ResponseData{data=entity.BookCopyResponse}
+// @Failure 400 {object} entity.ResponseError
+// @Failure 404 {object} entity.ResponseError
+// @Failure 409 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/books/{id}/copies [post]
+func (h *Handler) AddBookCopy(c *gin.Context) {
+	bookID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.AddBookCopy]: unable to convert book id"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid book id", Code: http.StatusBadRequest})
+		return
+	}
+
+	var req entity.BookCopyCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.AddBookCopy]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid request", Code: http.StatusBadRequest})
+		return
+	}
+
+	if err := h.deps.Validator.Struct(req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.AddBookCopy]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: err.Error(), Code: http.StatusBadRequest})
+		return
+	}
+
+	req.BookID = uint(bookID)
+
+	bookCopy, err := h.deps.Service.AddBookCopy(req)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			c.JSON(http.StatusNotFound, entity.ResponseError{Error: "book not found", Code: http.StatusNotFound})
+			return
+		}
+
+		if errors.Is(err, errmap.ErrmapConflict) {
+			c.JSON(http.StatusConflict, entity.ResponseError{Error: "barcode already exists", Code: http.StatusConflict})
+			return
+		}
+
+		log.Error(errors.Wrap(err, "[Handler.AddBookCopy]: unable to add book copy"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to add book copy", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatusJSON(http.StatusCreated, entity.ResponseData{Data: bookCopy})
+}
+
+// ListBookCopies lists copies of a book
+// @Summary List book copies
+// @Description Get the physical copies of a book with their status
+// @Tags management copies
+// @Accept  json
+// @Produce  json
+// @Param   id   path      int  true  "Book ID"
+// @Success 200 {object} entity.ResponseData{data=[]entity.BookCopyResponse}
+// @Failure 400 {object} entity.ResponseError
+// @Failure 404 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/books/{id}/copies [get]
+func (h *Handler) ListBookCopies(c *gin.Context) {
+	bookID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ListBookCopies]: unable to convert book id"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid book id", Code: http.StatusBadRequest})
+		return
+	}
+
+	copies, err := h.deps.Service.ListBookCopies(uint(bookID))
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			c.JSON(http.StatusNotFound, entity.ResponseError{Error: "book not found", Code: http.StatusNotFound})
+			return
+		}
+
+		log.Error(errors.Wrap(err, "[Handler.ListBookCopies]: unable to list book copies"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to list book copies", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatusJSON(http.StatusOK, entity.ResponseData{Data: copies})
+}
+
+// GetBookCopyByBarcode looks up a copy by barcode
+// @Summary Get a book copy by barcode
+// @Description Look up a physical copy by its barcode
+// @Tags management copies
+// @Accept  json
+// @Produce  json
+// @Param   barcode   path      string  true  "Barcode"
+// @Success 200 {object} entity.ResponseData{data=entity.BookCopyResponse}
+// @Failure 404 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/copies/barcode/{barcode} [get]
+func (h *Handler) GetBookCopyByBarcode(c *gin.Context) {
+	bookCopy, err := h.deps.Service.GetBookCopyByBarcode(c.Param("barcode"))
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			c.JSON(http.StatusNotFound, entity.ResponseError{Error: "book copy not found", Code: http.StatusNotFound})
+			return
+		}
+
+		log.Error(errors.Wrap(err, "[Handler.GetBookCopyByBarcode]: unable to get book copy"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to get book copy", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatusJSON(http.StatusOK, entity.ResponseData{Data: bookCopy})
+}
+
+// UpdateBookCopy updates a copy
+// @Summary Update a book copy
+// @Description Update the shelf location and condition of a copy
+// @Tags management copies
+// @Accept  json
+// @Produce  json
+// @Param   id    path      int  true  "Copy ID"
+// @Param   copy  body      entity.BookCopyUpdateRequest  true  "Book copy"
+// @Success 200 {object} entity.ResponseData
+// @Failure 400 {object} entity.ResponseError
+// @Failure 404 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/copies/{id} [put]
+func (h *Handler) UpdateBookCopy(c *gin.Context) {
+	copyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.UpdateBookCopy]: unable to convert copy id"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid copy id", Code: http.StatusBadRequest})
+		return
+	}
+
+	var req entity.BookCopyUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.UpdateBookCopy]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid request", Code: http.StatusBadRequest})
+		return
+	}
+
+	if err := h.deps.Validator.Struct(req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.UpdateBookCopy]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: err.Error(), Code: http.StatusBadRequest})
+		return
+	}
+
+	req.ID = uint(copyID)
+
+	if err := h.deps.Service.UpdateBookCopy(req); err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			c.JSON(http.StatusNotFound, entity.ResponseError{Error: "book copy not found", Code: http.StatusNotFound})
+			return
+		}
+
+		log.Error(errors.Wrap(err, "[Handler.UpdateBookCopy]: unable to update book copy"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to update book copy", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatus(http.StatusOK)
+}
+
+// RetireBookCopy retires a copy
+// @Summary Retire a book copy
+// @Description Take a copy that is on the shelf out of circulation
+// @Tags management copies
+// @Accept  json
+// @Produce  json
+// @Param   id   path      int  true  "Copy ID"
+// @Success 200 {object} entity.ResponseData
+// @Failure 400 {object} entity.ResponseError
+// @Failure 404 {object} entity.ResponseError
+// @Failure 409 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/copies/{id} [delete]
+func (h *Handler) RetireBookCopy(c *gin.Context) {
+	copyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.RetireBookCopy]: unable to convert copy id"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid copy id", Code: http.StatusBadRequest})
+		return
+	}
+
+	if err := h.deps.Service.RetireBookCopy(uint(copyID)); err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			c.JSON(http.StatusNotFound, entity.ResponseError{Error: "book copy not found", Code: http.StatusNotFound})
+			return
+		}
+
+		if errors.Is(err, errmap.ErrmapConflict) {
+			c.JSON(http.StatusConflict, entity.ResponseError{Error: "book copy is not available", Code: http.StatusConflict})
+			return
+		}
+
+		log.Error(errors.Wrap(err, "[Handler.RetireBookCopy]: unable to retire book copy"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to retire book copy", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatus(http.StatusOK)
+}
+
+// RegisterBookCopyRoutes registers book copy routes
+func RegisterBookCopyRoutes(router *gin.RouterGroup, handler *Handler) {
+	managementBookCopyRoutes := router.Group("/management/books/:id/copies")
+	{
+		managementBookCopyRoutes.Use(middleware.AuthMiddleware())
+		managementBookCopyRoutes.Use(middleware.RoleMiddleware(constant.UserTypeStaff))
+
+		managementBookCopyRoutes.POST("", handler.AddBookCopy)
+		managementBookCopyRoutes.GET("", handler.ListBookCopies)
+	}
+
+	managementCopyRoutes := router.Group("/management/copies")
+	{
+		managementCopyRoutes.Use(middleware.AuthMiddleware())
+		managementCopyRoutes.Use(middleware.RoleMiddleware(constant.UserTypeStaff))
+
+		managementCopyRoutes.GET("/barcode/:barcode", handler.GetBookCopyByBarcode)
+		managementCopyRoutes.PUT("/:id", handler.UpdateBookCopy)
+		managementCopyRoutes.DELETE("/:id", handler.RetireBookCopy)
+	}
+}
@@ -0,0 +1,181 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	"go-library-service/cmd/api/handler"
+	"go-library-service/cmd/api/handler/mock"
+	"go-library-service/cmd/api/middleware"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Book Copy Handler", func() {
+	var (
+		h           *handler.Handler
+		serviceMock *mock.MockService
+		r           *gin.Engine
+		ctrl        *gomock.Controller
+		testToken   string
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		serviceMock = mock.NewMockService(ctrl)
+		h = handler.NewHandler(&handler.Dependencies{
+			Service:   serviceMock,
+			Validator: validator.New(),
+		}, &handler.Config{})
+
+		gin.SetMode(gin.TestMode)
+		r = gin.Default()
+		handler.RegisterBookCopyRoutes(r.Group("/api"), h)
+
+		var err error
+		testToken, err = middleware.GenerateToken(uint(1), constant.UserTypeStaff)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("AddBookCopy", func() {
+		It("should add a copy successfully", func() {
+			jsonValue, _ := json.Marshal(entity.BookCopyCreateRequest{Barcode: "0001", ShelfLocation: "A-1"})
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/books/1/copies", bytes.NewBuffer(jsonValue))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().
+				AddBookCopy(entity.BookCopyCreateRequest{BookID: 1, Barcode: "0001", ShelfLocation: "A-1"}).
+				Return(&entity.BookCopyResponse{ID: 1, BookID: 1, Barcode: "0001", Status: constant.CopyStatusAvailable}, nil)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "1"})
+			c.Request = req
+
+			h.AddBookCopy(c)
+
+			Expect(w.Code).To(Equal(http.StatusCreated))
+		})
+
+		It("should return error for an invalid condition", func() {
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/books/1/copies", bytes.NewBufferString(`{"barcode": "0001", "condition": "BROKEN"}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "1"})
+			c.Request = req
+
+			h.AddBookCopy(c)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return conflict when barcode already exists", func() {
+			jsonValue, _ := json.Marshal(entity.BookCopyCreateRequest{Barcode: "0001"})
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/books/1/copies", bytes.NewBuffer(jsonValue))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().AddBookCopy(gomock.Any()).Return(nil, errmap.ErrmapConflict)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "1"})
+			c.Request = req
+
+			h.AddBookCopy(c)
+
+			Expect(w.Code).To(Equal(http.StatusConflict))
+		})
+	})
+
+	Context("GetBookCopyByBarcode", func() {
+		It("should return the copy", func() {
+			req, _ := http.NewRequest(http.MethodGet, "/api/management/copies/barcode/0001", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().
+				GetBookCopyByBarcode("0001").
+				Return(&entity.BookCopyResponse{ID: 1, BookID: 1, BookTitle: "Test Book", Barcode: "0001"}, nil)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "barcode", Value: "0001"})
+			c.Request = req
+
+			h.GetBookCopyByBarcode(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var response map[string]map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response["data"]["bookTitle"]).To(Equal("Test Book"))
+		})
+
+		It("should return not found for an unknown barcode", func() {
+			req, _ := http.NewRequest(http.MethodGet, "/api/management/copies/barcode/unknown", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().GetBookCopyByBarcode("unknown").Return(nil, errmap.ErrmapNotFound)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "barcode", Value: "unknown"})
+			c.Request = req
+
+			h.GetBookCopyByBarcode(c)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("RetireBookCopy", func() {
+		It("should retire a copy", func() {
+			req, _ := http.NewRequest(http.MethodDelete, "/api/management/copies/1", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().RetireBookCopy(uint(1)).Return(nil)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "1"})
+			c.Request = req
+
+			h.RetireBookCopy(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+		})
+
+		It("should return conflict when copy is on loan", func() {
+			req, _ := http.NewRequest(http.MethodDelete, "/api/management/copies/1", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().RetireBookCopy(uint(1)).Return(errmap.ErrmapConflict)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "1"})
+			c.Request = req
+
+			h.RetireBookCopy(c)
+
+			Expect(w.Code).To(Equal(http.StatusConflict))
+		})
+	})
+})
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-library-service/cmd/api/entity"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// UploadBookCover sets the cover of a book
+// @Summary Upload a book cover
+// @Description Set the cover of a book from a JPEG, PNG or GIF image, replacing the cover it had. The type is told from the content of the file rather than its name. A JPEG thumbnail of at most 200x300 is made alongside it, and both are returned in coverUrl and coverThumbnailUrl of the book.
+// @Tags management books
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param   id    path      int   true  "Book ID"
+// @Param   file  formData  file  true  "Cover image"
+// @Success 200 {object} entity.ResponseData{data=entity.BookCoverResponse}
+// @Failure 400 {object} entity.ResponseError
+// @Failure 404 {object} entity.ResponseError
+// @Failure 413 {object} entity.ResponseError
+// @Failure 415 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/books/{id}/cover [put]
+func (h *Handler) UploadBookCover(c *gin.Context) {
+	bookID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.UploadBookCover]: invalid book id"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid book id", Code: http.StatusBadRequest})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.UploadBookCover]: unable to get file"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "missing file", Code: http.StatusBadRequest})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.UploadBookCover]: unable to open file"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to read file", Code: http.StatusInternalServerError})
+		return
+	}
+	defer file.Close()
+
+	cover, err := h.deps.Service.UploadBookCover(uint(bookID), file)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			c.JSON(http.StatusNotFound, entity.ResponseError{Error: "book not found", Code: http.StatusNotFound})
+			return
+		}
+		if errors.Is(err, errmap.ErrmapCoverTooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, entity.ResponseError{Error: err.Error(), Code: http.StatusRequestEntityTooLarge})
+			return
+		}
+		if errors.Is(err, errmap.ErrmapUnsupportedCover) {
+			c.JSON(http.StatusUnsupportedMediaType, entity.ResponseError{Error: "cover must be a JPEG, PNG or GIF image", Code: http.StatusUnsupportedMediaType})
+			return
+		}
+		if errors.Is(err, errmap.ErrmapInvalidCover) {
+			c.JSON(http.StatusBadRequest, entity.ResponseError{Error: err.Error(), Code: http.StatusBadRequest})
+			return
+		}
+		log.Error(errors.Wrap(err, "[Handler.UploadBookCover]: unable to upload cover"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to upload cover", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatusJSON(http.StatusOK, entity.ResponseData{Data: cover})
+}
+
+// DeleteBookCover removes the cover of a book
+// @Summary Delete a book cover
+// @Description Remove the cover of a book and its thumbnail
+// @Tags management books
+// @Accept  json
+// @Produce  json
+// @Param   id   path      int  true  "Book ID"
+// @Success 200 {object} entity.ResponseData
+// @Failure 400 {object} entity.ResponseError
+// @Failure 404 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/books/{id}/cover [delete]
+func (h *Handler) DeleteBookCover(c *gin.Context) {
+	bookID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.DeleteBookCover]: invalid book id"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid book id", Code: http.StatusBadRequest})
+		return
+	}
+
+	if err := h.deps.Service.DeleteBookCover(uint(bookID)); err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			c.JSON(http.StatusNotFound, entity.ResponseError{Error: "book or cover not found", Code: http.StatusNotFound})
+			return
+		}
+		log.Error(errors.Wrap(err, "[Handler.DeleteBookCover]: unable to delete cover"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to delete cover", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatus(http.StatusOK)
+}
@@ -0,0 +1,167 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	"go-library-service/cmd/api/handler"
+	"go-library-service/cmd/api/handler/mock"
+	"go-library-service/cmd/api/middleware"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Book Cover Handler", func() {
+	var (
+		h           *handler.Handler
+		serviceMock *mock.MockService
+		r           *gin.Engine
+		ctrl        *gomock.Controller
+		testToken   string
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		serviceMock = mock.NewMockService(ctrl)
+		h = handler.NewHandler(&handler.Dependencies{
+			Service:   serviceMock,
+			Validator: validator.New(),
+		}, &handler.Config{})
+
+		gin.SetMode(gin.TestMode)
+		r = gin.Default()
+		handler.RegisterBookRoutes(r.Group("/api"), h)
+
+		var err error
+		testToken, err = middleware.GenerateToken(uint(1), constant.UserTypeStaff)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	uploadRequest := func(url string, data []byte) *http.Request {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile("file", "cover.png")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = part.Write(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(writer.Close()).To(Succeed())
+
+		req, _ := http.NewRequest(http.MethodPut, url, body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+testToken)
+		return req
+	}
+
+	Context("UploadBookCover", func() {
+		It("should hand the uploaded file to the service and return the cover", func() {
+			cover := &entity.BookCoverResponse{
+				CoverURL:          "http://localhost:8080/covers/covers/1/a.png",
+				CoverThumbnailURL: "http://localhost:8080/covers/covers/1/a-thumb.jpg",
+				ContentType:       "image/png",
+				Width:             600,
+				Height:            900,
+			}
+			serviceMock.EXPECT().UploadBookCover(uint(1), gomock.Any()).DoAndReturn(func(_ uint, file io.Reader) (*entity.BookCoverResponse, error) {
+				data, err := io.ReadAll(file)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(data)).To(Equal("image data"))
+				return cover, nil
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, uploadRequest("/api/management/books/1/cover", []byte("image data")))
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var res struct {
+				Data entity.BookCoverResponse `json:"data"`
+			}
+			Expect(json.Unmarshal(w.Body.Bytes(), &res)).To(Succeed())
+			Expect(res.Data).To(Equal(*cover))
+		})
+
+		It("should return bad request without a file", func() {
+			req, _ := http.NewRequest(http.MethodPut, "/api/management/books/1/cover", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return bad request for an invalid book id", func() {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, uploadRequest("/api/management/books/abc/cover", []byte("image data")))
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		DescribeTable("should map the errors of the service to statuses",
+			func(err error, status int) {
+				serviceMock.EXPECT().UploadBookCover(uint(1), gomock.Any()).Return(nil, err)
+
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, uploadRequest("/api/management/books/1/cover", []byte("image data")))
+
+				Expect(w.Code).To(Equal(status))
+			},
+			Entry("missing book", errmap.ErrmapNotFound, http.StatusNotFound),
+			Entry("cover too large", errmap.ErrmapCoverTooLarge, http.StatusRequestEntityTooLarge),
+			Entry("unsupported type", errmap.ErrmapUnsupportedCover, http.StatusUnsupportedMediaType),
+			Entry("invalid image", errmap.ErrmapInvalidCover, http.StatusBadRequest),
+			Entry("storage failure", errors.New("storage down"), http.StatusInternalServerError),
+		)
+
+		It("should refuse users who are not staff", func() {
+			token, err := middleware.GenerateToken(uint(2), constant.UserTypeUser)
+			Expect(err).NotTo(HaveOccurred())
+			req := uploadRequest("/api/management/books/1/cover", []byte("image data"))
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+		})
+	})
+
+	Context("DeleteBookCover", func() {
+		It("should delete the cover", func() {
+			serviceMock.EXPECT().DeleteBookCover(uint(1)).Return(nil)
+
+			req, _ := http.NewRequest(http.MethodDelete, "/api/management/books/1/cover", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+		})
+
+		It("should return not found for a book without a cover", func() {
+			serviceMock.EXPECT().DeleteBookCover(uint(1)).Return(errmap.ErrmapNotFound)
+
+			req, _ := http.NewRequest(http.MethodDelete, "/api/management/books/1/cover", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+})
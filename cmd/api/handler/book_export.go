@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	"go-library-service/internal/xlsx"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// bookExportColumns head the columns of a CSV or Excel export, in the order of bookExportValues
+var bookExportColumns = []interface{}{
+	"id", "title", "author", "isbn", "publisher", "publicationYear", "edition", "language", "pageCount", "price",
+	"categories", "subjects", "copies", "available", "onLoan", "onHold", "createdAt", "updatedAt",
+}
+
+// bookExport writes books in one of the export formats
+type bookExport struct {
+	contentType string
+	extension   string
+	write       func(book entity.BookExport) error
+	flush       func() error
+	close       func() error
+}
+
+// newBookExport creates an export writing books in a format, starting with the column names
+// when the format has them
+func newBookExport(format string, w io.Writer) (bookExport, error) {
+	switch format {
+	case constant.BookExportFormatJSONL:
+		buffered := bufio.NewWriter(w)
+		encoder := json.NewEncoder(buffered)
+		return bookExport{
+			contentType: "application/x-ndjson",
+			extension:   "jsonl",
+			write:       func(book entity.BookExport) error { return encoder.Encode(book) },
+			flush:       buffered.Flush,
+			close:       buffered.Flush,
+		}, nil
+
+	case constant.BookExportFormatXLSX:
+		writer := xlsx.NewWriter(w, "Books")
+		export := bookExport{
+			contentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+			extension:   "xlsx",
+			write:       func(book entity.BookExport) error { return writer.Write(bookExportValues(book)...) },
+			flush:       writer.Flush,
+			close:       writer.Close,
+		}
+		return export, writer.Write(bookExportColumns...)
+
+	default:
+		writer := csv.NewWriter(w)
+		flush := func() error {
+			writer.Flush()
+			return writer.Error()
+		}
+		export := bookExport{
+			contentType: "text/csv; charset=utf-8",
+			extension:   "csv",
+			write:       func(book entity.BookExport) error { return writer.Write(csvRecord(bookExportValues(book))) },
+			flush:       flush,
+			close:       flush,
+		}
+		return export, writer.Write(csvRecord(bookExportColumns))
+	}
+}
+
+// bookExportValues lists the values of a book under bookExportColumns, nil for what it does not have
+func bookExportValues(book entity.BookExport) []interface{} {
+	values := make([]interface{}, 0, len(bookExportColumns))
+	values = append(values, book.ID, book.Title, book.Author)
+
+	if book.ISBN != nil {
+		values = append(values, *book.ISBN)
+	} else {
+		values = append(values, nil)
+	}
+	values = append(values, book.Publisher)
+	if book.PublicationYear != nil {
+		values = append(values, *book.PublicationYear)
+	} else {
+		values = append(values, nil)
+	}
+	values = append(values, book.Edition, book.Language)
+	if book.PageCount != nil {
+		values = append(values, *book.PageCount)
+	} else {
+		values = append(values, nil)
+	}
+	values = append(values, book.Price)
+
+	categories := make([]string, len(book.Categories))
+	for i, category := range book.Categories {
+		categories[i] = category.Name
+	}
+	values = append(values,
+		strings.Join(categories, constant.BookImportListSeparator),
+		strings.Join(book.Subjects, constant.BookImportListSeparator),
+		book.Availability.Copies, book.Availability.Available, book.Availability.OnLoan, book.Availability.OnHold)
+
+	for _, at := range []*time.Time{book.CreatedAt, book.UpdatedAt} {
+		if at != nil {
+			values = append(values, at.UTC())
+		} else {
+			values = append(values, nil)
+		}
+	}
+	return values
+}
+
+// csvRecord writes values as the fields of a CSV record. Text that a spreadsheet would run as a formula,
+// or that starts with a tab or carriage return some spreadsheets skip before one, is prefixed with a quote,
+// so a title like =HYPERLINK(...) is shown rather than evaluated.
+func csvRecord(values []interface{}) []string {
+	record := make([]string, len(values))
+	for i, value := range values {
+		switch v := value.(type) {
+		case nil:
+		case string:
+			if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+				v = "'" + v
+			}
+			record[i] = v
+		case float64:
+			record[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		case time.Time:
+			record[i] = v.Format(time.RFC3339)
+		default:
+			record[i] = fmt.Sprint(v)
+		}
+	}
+	return record
+}
+
+// ExportBooks exports the books matching a listing
+// @Summary Export books
+// @Description Stream the books matching the filters of a listing, with the availability of their copies, as CSV, JSON Lines or an Excel workbook. Books come in the order of the listing and the whole result is exported rather than a page. Lists of categories and subjects are separated by semicolons in CSV and Excel.
+// @Tags management books
+// @Produce  text/csv
+// @Produce  application/x-ndjson
+// @Produce  application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param   format    query     string  true   "Export format" Enums(csv, jsonl, xlsx)
+// @Param   search    query     string  false  "Search title and author, supports quoted phrases, prefix*, -excluded and OR"
+// @Param   mode      query     string  false  "Search mode, fuzzy tolerates typos in title and author" Enums(fulltext, fuzzy)
+// @Param   author    query     string  false  "Only books crediting this author, under any spelling of the name"
+// @Param   authorId  query     int     false  "Only books crediting the author with this ID"
+// @Param   categoryId query    int     false  "Only books filed under this category or its subcategories"
+// @Param   subject   query     string  false  "Only books tagged with this subject"
+// @Param   available query     bool    false  "Only books with a copy on the shelf, or only books without one"
+// @Param   minPrice  query     number  false  "Lowest price"
+// @Param   maxPrice  query     number  false  "Highest price"
+// @Param   language  query     string  false  "Only books in this language, as a BCP 47 tag such as en or th"
+// @Param   minYear   query     int     false  "Earliest publication year"
+// @Param   maxYear   query     int     false  "Latest publication year"
+// @Param   sort      query     []string false "Sort fields in order, a leading - sorts descending" collectionFormat(multi) Enums(title, -title, author, -author, createdAt, -createdAt, popularity, -popularity)
+// @Success 200 {file} file
+// @Failure 400 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/books/export [get]
+func (h *Handler) ExportBooks(c *gin.Context) {
+	var req entity.BookExportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ExportBooks]: unable to bind query"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "unable to bind query", Code: http.StatusBadRequest})
+		return
+	}
+
+	// An export runs through every match, so it takes no page
+	if err := h.deps.Validator.StructExcept(req, "ListBookRequest.Page", "ListBookRequest.Size"); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ExportBooks]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: err.Error(), Code: http.StatusBadRequest})
+		return
+	}
+
+	export, err := newBookExport(req.Format, c.Writer)
+	if err == nil {
+		c.Header("Content-Type", export.contentType)
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="books.%s"`, export.extension))
+		c.Status(http.StatusOK)
+
+		err = h.deps.Service.ExportBooks(req.ListBookRequest, func(books []entity.BookExport) error {
+			for _, book := range books {
+				if err := export.write(book); err != nil {
+					return err
+				}
+			}
+			if err := export.flush(); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+			return nil
+		})
+	}
+	if err == nil {
+		err = export.close()
+	}
+
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ExportBooks]: unable to export books"))
+		if !c.Writer.Written() {
+			c.Header("Content-Disposition", "")
+			c.Header("Content-Type", "")
+			c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to export books", Code: http.StatusInternalServerError})
+			return
+		}
+	}
+	c.Abort()
+}
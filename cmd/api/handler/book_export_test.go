@@ -0,0 +1,219 @@
+package handler_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	"go-library-service/cmd/api/handler"
+	"go-library-service/cmd/api/handler/mock"
+	"go-library-service/cmd/api/middleware"
+	"go-library-service/cmd/api/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Book Export Handler", func() {
+	var (
+		h           *handler.Handler
+		serviceMock *mock.MockService
+		r           *gin.Engine
+		ctrl        *gomock.Controller
+		testToken   string
+		dune        entity.BookExport
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		serviceMock = mock.NewMockService(ctrl)
+		validate := validator.New()
+		Expect(service.RegisterValidations(validate)).To(Succeed())
+		h = handler.NewHandler(&handler.Dependencies{
+			Service:   serviceMock,
+			Validator: validate,
+		}, &handler.Config{})
+
+		gin.SetMode(gin.TestMode)
+		r = gin.Default()
+		handler.RegisterBookRoutes(r.Group("/api"), h)
+
+		var err error
+		testToken, err = middleware.GenerateToken(uint(1), constant.UserTypeStaff)
+		Expect(err).NotTo(HaveOccurred())
+
+		isbn, year := "9780441172719", 1965
+		createdAt := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+		dune = entity.BookExport{
+			BookResponse: entity.BookResponse{
+				ID:              1,
+				Title:           "Dune",
+				Author:          "Frank Herbert",
+				Price:           12.5,
+				Stock:           1,
+				ISBN:            &isbn,
+				PublicationYear: &year,
+				Categories:      []entity.BookCategoryResponse{{ID: 4, Name: "Fiction"}, {ID: 7, Name: "Science fiction"}},
+				Subjects:        []string{"Space opera", "Ecology"},
+				CreatedAt:       &createdAt,
+			},
+			Availability: entity.BookAvailability{BookID: 1, Copies: 3, Available: 1, OnLoan: 1, OnHold: 1},
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	exportRequest := func(url string) *http.Request {
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		req.Header.Set("Authorization", "Bearer "+testToken)
+		return req
+	}
+
+	Context("ExportBooks", func() {
+		It("should stream the matching books as CSV", func() {
+			available := true
+			serviceMock.EXPECT().
+				ExportBooks(entity.ListBookRequest{Available: &available, Sort: []string{"-title"}}, gomock.Any()).
+				DoAndReturn(func(_ entity.ListBookRequest, fn func(books []entity.BookExport) error) error {
+					Expect(fn([]entity.BookExport{dune})).To(Succeed())
+					return fn([]entity.BookExport{{BookResponse: entity.BookResponse{ID: 2, Title: "Neuromancer, a novel", Author: "William Gibson"}}})
+				})
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = exportRequest("/api/management/books/export?format=csv&available=true&sort=-title")
+
+			h.ExportBooks(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Header().Get("Content-Type")).To(Equal("text/csv; charset=utf-8"))
+			Expect(w.Header().Get("Content-Disposition")).To(Equal(`attachment; filename="books.csv"`))
+
+			records, err := csv.NewReader(w.Body).ReadAll()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(Equal([][]string{
+				{"id", "title", "author", "isbn", "publisher", "publicationYear", "edition", "language", "pageCount", "price",
+					"categories", "subjects", "copies", "available", "onLoan", "onHold", "createdAt", "updatedAt"},
+				{"1", "Dune", "Frank Herbert", "9780441172719", "", "1965", "", "", "", "12.5",
+					"Fiction;Science fiction", "Space opera;Ecology", "3", "1", "1", "1", "2026-01-01T08:00:00Z", ""},
+				{"2", "Neuromancer, a novel", "William Gibson", "", "", "", "", "", "", "0",
+					"", "", "0", "0", "0", "0", "", ""},
+			}))
+		})
+
+		It("should quote text a spreadsheet would run as a formula", func() {
+			serviceMock.EXPECT().
+				ExportBooks(entity.ListBookRequest{}, gomock.Any()).
+				DoAndReturn(func(_ entity.ListBookRequest, fn func(books []entity.BookExport) error) error {
+					return fn([]entity.BookExport{{BookResponse: entity.BookResponse{ID: 3, Title: `=HYPERLINK("http://example.com","Dune")`,
+						Author: "@Herbert", Publisher: "+Ace", Edition: "-1st", Language: "\t=1+1", Subjects: []string{"\r=2+2", "Ecology"}}}})
+				})
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = exportRequest("/api/management/books/export?format=csv")
+
+			h.ExportBooks(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			records, err := csv.NewReader(w.Body).ReadAll()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records[1]).To(Equal([]string{"3", `'=HYPERLINK("http://example.com","Dune")`, "'@Herbert", "", "'+Ace", "", "'-1st", "'\t=1+1", "", "0",
+				"", "'\r=2+2;Ecology", "0", "0", "0", "0", "", ""}))
+		})
+
+		It("should write a JSON object a line", func() {
+			serviceMock.EXPECT().
+				ExportBooks(entity.ListBookRequest{}, gomock.Any()).
+				DoAndReturn(func(_ entity.ListBookRequest, fn func(books []entity.BookExport) error) error {
+					return fn([]entity.BookExport{dune, dune})
+				})
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = exportRequest("/api/management/books/export?format=jsonl")
+
+			h.ExportBooks(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			lines := strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n")
+			Expect(lines).To(HaveLen(2))
+
+			var book map[string]interface{}
+			Expect(json.Unmarshal([]byte(lines[0]), &book)).To(Succeed())
+			Expect(book["title"]).To(Equal("Dune"))
+			Expect(book["availability"]).To(Equal(map[string]interface{}{"copies": 3.0, "available": 1.0, "onLoan": 1.0, "onHold": 1.0}))
+		})
+
+		It("should write an Excel workbook", func() {
+			serviceMock.EXPECT().
+				ExportBooks(entity.ListBookRequest{}, gomock.Any()).
+				DoAndReturn(func(_ entity.ListBookRequest, fn func(books []entity.BookExport) error) error {
+					return fn([]entity.BookExport{dune})
+				})
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = exportRequest("/api/management/books/export?format=xlsx")
+
+			h.ExportBooks(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Header().Get("Content-Disposition")).To(Equal(`attachment; filename="books.xlsx"`))
+
+			workbook, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+			Expect(err).NotTo(HaveOccurred())
+			var parts []string
+			for _, file := range workbook.File {
+				parts = append(parts, file.Name)
+			}
+			Expect(parts).To(ContainElement("xl/worksheets/sheet1.xml"))
+		})
+
+		It("should return error without a format", func() {
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = exportRequest("/api/management/books/export")
+
+			h.ExportBooks(c)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return error for an unknown sort", func() {
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = exportRequest("/api/management/books/export?format=csv&sort=price")
+
+			h.ExportBooks(c)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return error when nothing could be exported", func() {
+			serviceMock.EXPECT().ExportBooks(gomock.Any(), gomock.Any()).Return(errors.New("connection refused"))
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = exportRequest("/api/management/books/export?format=csv")
+
+			h.ExportBooks(c)
+
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+			Expect(w.Header().Get("Content-Disposition")).To(BeEmpty())
+		})
+	})
+})
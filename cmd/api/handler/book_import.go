@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"net/http"
+
+	"go-library-service/cmd/api/entity"
+	"go-library-service/cmd/api/service"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// ImportBooks imports books from a CSV file
+// @Summary Import books
+// @Description Create or update books from a CSV file with a header line naming the columns: title, author, authorIds, price, stock, isbn, publisher, publicationYear, edition, language, pageCount, categoryIds and subjects, lists separated by semicolons. Rows are checked like a new book, except that only a row creating a book needs a stock. A row with the ISBN of a book in the catalog updates only the columns of the file on that book and leaves its copies alone. A row with the ISBN of an archived book is reported and not written. Every row is written in one transaction unless mode is batch, and a transaction is written only when all its rows are sound. A dry run checks and writes everything, then rolls it back.
+// @Tags management books
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param   file       formData  file    true   "CSV file"
+// @Param   dryRun     query     bool    false  "Report what the import would do without keeping it"
+// @Param   mode       query     string  false  "One transaction for all rows or one per batch" Enums(all, batch)
+// @Param   batchSize  query     int     false  "Rows per transaction in batch mode, defaults to 100"
+// @Success 200 {object} entity.ResponseData{data=entity.BookImportReport}
+// @Failure 400 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/books/import [post]
+func (h *Handler) ImportBooks(c *gin.Context) {
+	var req entity.BookImportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ImportBooks]: unable to bind query"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "unable to bind query", Code: http.StatusBadRequest})
+		return
+	}
+
+	if err := h.deps.Validator.Struct(req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ImportBooks]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: err.Error(), Code: http.StatusBadRequest})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ImportBooks]: unable to get file"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "missing file", Code: http.StatusBadRequest})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ImportBooks]: unable to open file"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to read file", Code: http.StatusInternalServerError})
+		return
+	}
+	defer file.Close()
+
+	rows, err := service.ReadBookImport(file, h.deps.Validator)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapInvalidImport) {
+			c.JSON(http.StatusBadRequest, entity.ResponseError{Error: err.Error(), Code: http.StatusBadRequest})
+			return
+		}
+
+		log.Error(errors.Wrap(err, "[Handler.ImportBooks]: unable to read file"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to read file", Code: http.StatusInternalServerError})
+		return
+	}
+
+	report, err := h.deps.Service.ImportBooks(rows, req)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ImportBooks]: unable to import books"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to import books", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatusJSON(http.StatusOK, entity.ResponseData{Data: report})
+}
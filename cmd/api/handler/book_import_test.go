@@ -0,0 +1,132 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	"go-library-service/cmd/api/handler"
+	"go-library-service/cmd/api/handler/mock"
+	"go-library-service/cmd/api/middleware"
+	"go-library-service/cmd/api/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Book Import Handler", func() {
+	var (
+		h           *handler.Handler
+		serviceMock *mock.MockService
+		r           *gin.Engine
+		ctrl        *gomock.Controller
+		validate    *validator.Validate
+		testToken   string
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		serviceMock = mock.NewMockService(ctrl)
+		validate = validator.New()
+		Expect(service.RegisterValidations(validate)).To(Succeed())
+		h = handler.NewHandler(&handler.Dependencies{
+			Service:   serviceMock,
+			Validator: validate,
+		}, &handler.Config{})
+
+		gin.SetMode(gin.TestMode)
+		r = gin.Default()
+		handler.RegisterBookRoutes(r.Group("/api"), h)
+
+		var err error
+		testToken, err = middleware.GenerateToken(uint(1), constant.UserTypeStaff)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	importRequest := func(url, csv string) *http.Request {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile("file", "books.csv")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = part.Write([]byte(csv))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(writer.Close()).To(Succeed())
+
+		req, _ := http.NewRequest(http.MethodPost, url, body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+testToken)
+		return req
+	}
+
+	Context("ImportBooks", func() {
+		It("should import the rows of the file", func() {
+			req := importRequest("/api/management/books/import?dryRun=true&mode=batch&batchSize=50",
+				"title,author,price,stock\nDune,Frank Herbert,12.5,2\n")
+
+			serviceMock.EXPECT().
+				ImportBooks(gomock.Len(1), entity.BookImportRequest{DryRun: true, Mode: constant.BookImportModeBatch, BatchSize: 50}).
+				Return(&entity.BookImportReport{DryRun: true, Rows: 1, Created: 1, Errors: []entity.BookImportRowError{}}, nil)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.ImportBooks(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var response struct {
+				Data entity.BookImportReport `json:"data"`
+			}
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.Data.Created).To(Equal(1))
+		})
+
+		It("should return error without a file", func() {
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/books/import", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.ImportBooks(c)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return error for an unknown mode", func() {
+			req := importRequest("/api/management/books/import?mode=each", "title\nDune\n")
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.ImportBooks(c)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return error for a file that cannot be read", func() {
+			req := importRequest("/api/management/books/import", "title,shelf\nDune,A1\n")
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.ImportBooks(c)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+})
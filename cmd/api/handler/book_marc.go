@@ -0,0 +1,297 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	"go-library-service/cmd/api/service"
+	errmap "go-library-service/internal/error_map"
+	"go-library-service/internal/marc"
+	"go-library-service/internal/search"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// bookMARCLeader is the leader of an exported book: a new record of a printed monograph in Unicode,
+// catalogued at minimal level without ISBD punctuation. The writer fills in the lengths.
+const bookMARCLeader = "00000nam a22000007c 4500"
+
+// marcExport writes MARC records in one of the formats
+type marcExport struct {
+	contentType string
+	extension   string
+	write       func(record marc.Record) error
+	close       func() error
+}
+
+// newMARCExport creates an export writing records in a format, MARC 21 exchange records by default
+func newMARCExport(format string, w io.Writer) marcExport {
+	if format == constant.MARCFormatMARCXML {
+		writer := marc.NewXMLWriter(w)
+		return marcExport{contentType: "application/marcxml+xml", extension: "xml", write: writer.Write, close: writer.Close}
+	}
+
+	writer := marc.NewWriter(w)
+	return marcExport{contentType: "application/marc", extension: "mrc", write: writer.Write, close: func() error { return nil }}
+}
+
+// bookMARCRecord writes a book as a bibliographic record
+func bookMARCRecord(book entity.BookResponse) marc.Record {
+	record := marc.Record{Leader: bookMARCLeader}
+	record.AddControlField("001", strconv.FormatUint(uint64(book.ID), 10))
+	if book.UpdatedAt != nil {
+		record.AddControlField("005", book.UpdatedAt.UTC().Format("20060102150405.0"))
+	}
+
+	entered, dateType, year := "      ", 'n', "uuuu"
+	if book.CreatedAt != nil {
+		entered = book.CreatedAt.UTC().Format("060102")
+	}
+	if book.PublicationYear != nil {
+		dateType, year = 's', fmt.Sprintf("%04d", *book.PublicationYear)
+	}
+	record.AddControlField("008", fmt.Sprintf("%s%c%s%-4s%-3s%-17s%s d", entered, dateType, year, "", "xx", "", marc.LanguageCode(book.Language)))
+
+	price := ""
+	if book.Price > 0 {
+		price = strconv.FormatFloat(book.Price, 'f', 2, 64)
+	}
+	isbn := ""
+	if book.ISBN != nil {
+		isbn = *book.ISBN
+	}
+	record.AddDataField("020", ' ', ' ', marc.Subfield{Code: 'a', Value: isbn}, marc.Subfield{Code: 'c', Value: price})
+
+	var names []string
+	for _, author := range book.Authors {
+		names = append(names, author.Name)
+	}
+	if len(names) == 0 {
+		names = search.SplitNames(book.Author)
+	}
+
+	titleAdded := byte('0')
+	if len(names) > 0 {
+		record.AddDataField("100", '1', ' ', marc.Subfield{Code: 'a', Value: names[0]})
+		titleAdded = '1'
+	}
+	record.AddDataField("245", titleAdded, '0', marc.Subfield{Code: 'a', Value: book.Title}, marc.Subfield{Code: 'c', Value: book.Author})
+	record.AddDataField("250", ' ', ' ', marc.Subfield{Code: 'a', Value: book.Edition})
+
+	publicationYear := ""
+	if book.PublicationYear != nil {
+		publicationYear = strconv.Itoa(*book.PublicationYear)
+	}
+	record.AddDataField("264", ' ', '1', marc.Subfield{Code: 'b', Value: book.Publisher}, marc.Subfield{Code: 'c', Value: publicationYear})
+
+	if book.PageCount != nil {
+		record.AddDataField("300", ' ', ' ', marc.Subfield{Code: 'a', Value: fmt.Sprintf("%d pages", *book.PageCount)})
+	}
+
+	for _, subject := range book.Subjects {
+		record.AddDataField("653", ' ', ' ', marc.Subfield{Code: 'a', Value: subject})
+	}
+
+	if len(names) > 1 {
+		for _, name := range names[1:] {
+			record.AddDataField("700", '1', ' ', marc.Subfield{Code: 'a', Value: name})
+		}
+	}
+
+	return record
+}
+
+// ImportMARCBooks imports books from MARC records
+// @Summary Import books from MARC
+// @Description Create or update books from MARC 21 records, as an ISO 2709 exchange file or MARCXML. Title, authors, ISBN, price, publisher, year, edition, language, pages and subjects are read from the usual fields, and a new book gets one copy, priced at 0 when its record has no price. Rows of the report are numbered by record. Otherwise the import runs like the CSV one.
+// @Tags management books
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param   file       formData  file    true   "MARC file"
+// @Param   format     query     string  false  "File format, marc21 by default" Enums(marc21, marcxml)
+// @Param   dryRun     query     bool    false  "Report what the import would do without keeping it"
+// @Param   mode       query     string  false  "One transaction for all records or one per batch" Enums(all, batch)
+// @Param   batchSize  query     int     false  "Records per transaction in batch mode, defaults to 100"
+// @Success 200 {object} entity.ResponseData{data=entity.BookImportReport}
+// @Failure 400 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/books/import/marc [post]
+func (h *Handler) ImportMARCBooks(c *gin.Context) {
+	var req entity.BookMARCImportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ImportMARCBooks]: unable to bind query"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "unable to bind query", Code: http.StatusBadRequest})
+		return
+	}
+
+	if err := h.deps.Validator.Struct(req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ImportMARCBooks]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: err.Error(), Code: http.StatusBadRequest})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ImportMARCBooks]: unable to get file"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "missing file", Code: http.StatusBadRequest})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ImportMARCBooks]: unable to open file"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to read file", Code: http.StatusInternalServerError})
+		return
+	}
+	defer file.Close()
+
+	rows, err := service.ReadMARCBookImport(file, req.Format, h.deps.Validator)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapInvalidImport) {
+			c.JSON(http.StatusBadRequest, entity.ResponseError{Error: err.Error(), Code: http.StatusBadRequest})
+			return
+		}
+
+		log.Error(errors.Wrap(err, "[Handler.ImportMARCBooks]: unable to read file"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to read file", Code: http.StatusInternalServerError})
+		return
+	}
+
+	report, err := h.deps.Service.ImportBooks(rows, req.BookImportRequest)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ImportMARCBooks]: unable to import books"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to import books", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatusJSON(http.StatusOK, entity.ResponseData{Data: report})
+}
+
+// GetBookMARC gets the MARC record of a book
+// @Summary Get the MARC record of a book
+// @Description Get a book as a MARC 21 bibliographic record, as an ISO 2709 exchange file or MARCXML
+// @Tags management books
+// @Produce  application/marc
+// @Produce  application/marcxml+xml
+// @Param   id      path      int     true   "Book ID"
+// @Param   format  query     string  false  "Record format, marc21 by default" Enums(marc21, marcxml)
+// @Success 200 {file} file
+// @Failure 400 {object} entity.ResponseError
+// @Failure 404 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/books/{id}/marc [get]
+func (h *Handler) GetBookMARC(c *gin.Context) {
+	bookID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.GetBookMARC]: unable to convert book id"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid book id", Code: http.StatusBadRequest})
+		return
+	}
+
+	var req entity.BookMARCRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.GetBookMARC]: unable to bind query"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "unable to bind query", Code: http.StatusBadRequest})
+		return
+	}
+
+	if err := h.deps.Validator.Struct(req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.GetBookMARC]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: err.Error(), Code: http.StatusBadRequest})
+		return
+	}
+
+	book, err := h.deps.Service.GetBookByID(uint(bookID))
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			c.JSON(http.StatusNotFound, entity.ResponseError{Error: "book not found", Code: http.StatusNotFound})
+			return
+		}
+
+		log.Error(errors.Wrap(err, "[Handler.GetBookMARC]: unable to get book"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to get book", Code: http.StatusInternalServerError})
+		return
+	}
+
+	var record bytes.Buffer
+	export := newMARCExport(req.Format, &record)
+	if err := export.write(bookMARCRecord(*book)); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.GetBookMARC]: unable to write record"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to write record", Code: http.StatusInternalServerError})
+		return
+	}
+
+	if err := export.close(); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.GetBookMARC]: unable to write record"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to write record", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="book-%d.%s"`, bookID, export.extension))
+	c.Data(http.StatusOK, export.contentType, record.Bytes())
+}
+
+// ExportMARCBooks exports the catalog as MARC records
+// @Summary Export the catalog as MARC
+// @Description Stream every book that is not archived as MARC 21 bibliographic records, as an ISO 2709 exchange file or a MARCXML collection
+// @Tags management books
+// @Produce  application/marc
+// @Produce  application/marcxml+xml
+// @Param   format  query     string  false  "Record format, marc21 by default" Enums(marc21, marcxml)
+// @Success 200 {file} file
+// @Failure 400 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/books/export/marc [get]
+func (h *Handler) ExportMARCBooks(c *gin.Context) {
+	var req entity.BookMARCRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ExportMARCBooks]: unable to bind query"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "unable to bind query", Code: http.StatusBadRequest})
+		return
+	}
+
+	if err := h.deps.Validator.Struct(req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ExportMARCBooks]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: err.Error(), Code: http.StatusBadRequest})
+		return
+	}
+
+	export := newMARCExport(req.Format, c.Writer)
+	c.Header("Content-Type", export.contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="catalog.%s"`, export.extension))
+	c.Status(http.StatusOK)
+
+	err := h.deps.Service.ExportBooks(entity.ListBookRequest{}, func(books []entity.BookExport) error {
+		for _, book := range books {
+			if err := export.write(bookMARCRecord(book.BookResponse)); err != nil {
+				return err
+			}
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err == nil {
+		err = export.close()
+	}
+
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ExportMARCBooks]: unable to export books"))
+		if !c.Writer.Written() {
+			c.Header("Content-Disposition", "")
+			c.Header("Content-Type", "")
+			c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to export books", Code: http.StatusInternalServerError})
+			return
+		}
+	}
+	c.Abort()
+}
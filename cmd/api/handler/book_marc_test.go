@@ -0,0 +1,310 @@
+package handler_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"time"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	"go-library-service/cmd/api/handler"
+	"go-library-service/cmd/api/handler/mock"
+	"go-library-service/cmd/api/middleware"
+	"go-library-service/cmd/api/service"
+	errmap "go-library-service/internal/error_map"
+	"go-library-service/internal/marc"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Book MARC Handler", func() {
+	var (
+		h           *handler.Handler
+		serviceMock *mock.MockService
+		r           *gin.Engine
+		ctrl        *gomock.Controller
+		testToken   string
+		dune        *entity.BookResponse
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		serviceMock = mock.NewMockService(ctrl)
+		validate := validator.New()
+		Expect(service.RegisterValidations(validate)).To(Succeed())
+		h = handler.NewHandler(&handler.Dependencies{
+			Service:   serviceMock,
+			Validator: validate,
+		}, &handler.Config{})
+
+		gin.SetMode(gin.TestMode)
+		r = gin.Default()
+		handler.RegisterBookRoutes(r.Group("/api"), h)
+
+		var err error
+		testToken, err = middleware.GenerateToken(uint(1), constant.UserTypeStaff)
+		Expect(err).NotTo(HaveOccurred())
+
+		isbn, year, pages := "9780441172719", 1965, uint(412)
+		createdAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		dune = &entity.BookResponse{
+			ID:              1,
+			Title:           "Dune",
+			Author:          "Frank Herbert",
+			Price:           12.5,
+			ISBN:            &isbn,
+			Publisher:       "Chilton Books",
+			PublicationYear: &year,
+			Edition:         "1st ed.",
+			Language:        "en",
+			PageCount:       &pages,
+			Subjects:        []string{"Space opera", "Ecology"},
+			CreatedAt:       &createdAt,
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	importRequest := func(url, path string) *http.Request {
+		file, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile("file", "books")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = part.Write(file)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(writer.Close()).To(Succeed())
+
+		req, _ := http.NewRequest(http.MethodPost, url, body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+testToken)
+		return req
+	}
+
+	Context("ImportMARCBooks", func() {
+		It("should import the records of an exchange file", func() {
+			req := importRequest("/api/management/books/import/marc?dryRun=true", "../../../internal/marc/testdata/books.mrc")
+
+			serviceMock.EXPECT().
+				ImportBooks(gomock.Any(), entity.BookImportRequest{DryRun: true}).
+				DoAndReturn(func(rows []entity.BookImportRow, _ entity.BookImportRequest) (*entity.BookImportReport, error) {
+					year, pages := 1965, uint(412)
+					Expect(rows).To(HaveLen(2))
+					Expect(rows[0]).To(Equal(entity.BookImportRow{Line: 1, Book: entity.BookCreateRequest{
+						Title:           "Dune",
+						Author:          "Frank Herbert",
+						Price:           12.5,
+						Stock:           1,
+						ISBN:            "9780441172719",
+						Publisher:       "Chilton Books",
+						PublicationYear: &year,
+						Edition:         "1st ed",
+						Language:        "en",
+						PageCount:       &pages,
+						Subjects:        []string{"Space opera", "Ecology"},
+					}, Columns: []string{"title", "author", "price", "isbn", "publisher", "publicationyear", "edition", "language", "pagecount", "subjects"}}))
+
+					Expect(rows[1].Line).To(Equal(2))
+					Expect(rows[1].Errors).To(BeEmpty())
+					Expect(rows[1].Book.Title).To(Equal("ความน่าจะเป็น"))
+					Expect(rows[1].Book.Author).To(Equal("ปราบดา หยุ่น; Mui Poopoksakul"))
+					Expect(rows[1].Book.Price).To(Equal(350.0))
+					Expect(rows[1].Book.Language).To(Equal("th"))
+					Expect(*rows[1].Book.PageCount).To(Equal(uint(220)))
+					Expect(rows[1].Book.Subjects).To(Equal([]string{"Thai fiction"}))
+					return &entity.BookImportReport{DryRun: true, Rows: 2, Created: 2, Errors: []entity.BookImportRowError{}}, nil
+				})
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.ImportMARCBooks(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+		})
+
+		It("should read a MARCXML record with ISBD punctuation", func() {
+			req := importRequest("/api/management/books/import/marc?format=marcxml", "testdata/books.xml")
+
+			serviceMock.EXPECT().
+				ImportBooks(gomock.Any(), entity.BookImportRequest{}).
+				DoAndReturn(func(rows []entity.BookImportRow, _ entity.BookImportRequest) (*entity.BookImportReport, error) {
+					year, pages := 1984, uint(271)
+					Expect(rows).To(Equal([]entity.BookImportRow{{Line: 1, Book: entity.BookCreateRequest{
+						Title:           "Neuromancer : a novel",
+						Author:          "Gibson, William",
+						Price:           2.95,
+						Stock:           1,
+						ISBN:            "0441569595",
+						Publisher:       "Ace Books",
+						PublicationYear: &year,
+						Edition:         "Ace ed",
+						Language:        "en",
+						PageCount:       &pages,
+						Subjects:        []string{"Cyberpunk"},
+					}, Columns: []string{"title", "author", "price", "isbn", "publisher", "publicationyear", "edition", "language", "pagecount", "subjects"}}}))
+					return &entity.BookImportReport{Rows: 1, Created: 1, Errors: []entity.BookImportRowError{}}, nil
+				})
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.ImportMARCBooks(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+		})
+
+		It("should return error for a file that is not MARC", func() {
+			req := importRequest("/api/management/books/import/marc", "testdata/books.xml")
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.ImportMARCBooks(c)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return error for an unknown format", func() {
+			req := importRequest("/api/management/books/import/marc?format=unimarc", "testdata/books.xml")
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.ImportMARCBooks(c)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Context("GetBookMARC", func() {
+		It("should write the record of a book", func() {
+			req, _ := http.NewRequest(http.MethodGet, "/api/management/books/1/marc", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().GetBookByID(uint(1)).Return(dune, nil)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "1"})
+			c.Request = req
+
+			h.GetBookMARC(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Header().Get("Content-Type")).To(Equal("application/marc"))
+			Expect(w.Header().Get("Content-Disposition")).To(Equal(`attachment; filename="book-1.mrc"`))
+
+			record, err := marc.NewReader(w.Body).Read()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(record.ControlField("001")).To(Equal("1"))
+			Expect(record.ControlField("008")).To(Equal("260101s1965    xx                  eng d"))
+			Expect(record.DataFields("020")[0].Subfield('c')).To(Equal("12.50"))
+			Expect(record.DataFields("264")[0].Indicator2).To(Equal(byte('1')))
+			Expect(record.DataFields("300")[0].Subfield('a')).To(Equal("412 pages"))
+			Expect(record.DataFields("653")).To(HaveLen(2))
+		})
+
+		It("should write MARCXML when asked", func() {
+			req, _ := http.NewRequest(http.MethodGet, "/api/management/books/1/marc?format=marcxml", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().GetBookByID(uint(1)).Return(dune, nil)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "1"})
+			c.Request = req
+
+			h.GetBookMARC(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Header().Get("Content-Type")).To(Equal("application/marcxml+xml"))
+
+			record, err := marc.NewXMLReader(w.Body).Read()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(record.DataFields("245")[0].Subfield('a')).To(Equal("Dune"))
+		})
+
+		It("should return not found when book doesn't exist", func() {
+			req, _ := http.NewRequest(http.MethodGet, "/api/management/books/999/marc", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().GetBookByID(uint(999)).Return(nil, errmap.ErrmapNotFound)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "999"})
+			c.Request = req
+
+			h.GetBookMARC(c)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("ExportMARCBooks", func() {
+		It("should stream every book of the catalog", func() {
+			req, _ := http.NewRequest(http.MethodGet, "/api/management/books/export/marc?format=marcxml", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().
+				ExportBooks(entity.ListBookRequest{}, gomock.Any()).
+				DoAndReturn(func(_ entity.ListBookRequest, fn func(books []entity.BookExport) error) error {
+					Expect(fn([]entity.BookExport{{BookResponse: *dune}})).To(Succeed())
+					return fn([]entity.BookExport{{BookResponse: entity.BookResponse{ID: 2, Title: "Neuromancer", Author: "William Gibson"}}})
+				})
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.ExportMARCBooks(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Header().Get("Content-Disposition")).To(Equal(`attachment; filename="catalog.xml"`))
+
+			reader := marc.NewXMLReader(w.Body)
+			for _, id := range []string{"1", "2"} {
+				record, err := reader.Read()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(record.ControlField("001")).To(Equal(id))
+			}
+			_, err := reader.Read()
+			Expect(err).To(Equal(io.EOF))
+		})
+
+		It("should return error when nothing could be exported", func() {
+			req, _ := http.NewRequest(http.MethodGet, "/api/management/books/export/marc", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().ExportBooks(gomock.Any(), gomock.Any()).Return(errors.New("connection refused"))
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.ExportMARCBooks(c)
+
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+			Expect(w.Header().Get("Content-Disposition")).To(BeEmpty())
+		})
+	})
+})
@@ -13,6 +13,7 @@ import (
 	"go-library-service/cmd/api/handler"
 	"go-library-service/cmd/api/handler/mock"
 	"go-library-service/cmd/api/middleware"
+	"go-library-service/cmd/api/service"
 	errmap "go-library-service/internal/error_map"
 
 	"github.com/gin-gonic/gin"
@@ -35,9 +36,11 @@ var _ = Describe("Book Handler", func() {
 	BeforeEach(func() {
 		ctrl = gomock.NewController(GinkgoT())
 		serviceMock = mock.NewMockService(ctrl)
+		validate := validator.New()
+		Expect(service.RegisterValidations(validate)).To(Succeed())
 		h = handler.NewHandler(&handler.Dependencies{
 			Service:   serviceMock,
-			Validator: validator.New(),
+			Validator: validate,
 		}, &handler.Config{})
 
 		gin.SetMode(gin.TestMode)
@@ -147,6 +150,55 @@ var _ = Describe("Book Handler", func() {
 
 			Expect(w.Code).To(Equal(http.StatusInternalServerError))
 		})
+
+		It("should return error for an isbn with a bad check digit", func() {
+			reqBody := entity.BookCreateRequest{
+				Title:  "Test Book",
+				Author: "Test Author",
+				Price:  19.99,
+				Stock:  5,
+				ISBN:   "978-0-306-40615-6",
+			}
+			jsonValue, _ := json.Marshal(reqBody)
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/books", bytes.NewBuffer(jsonValue))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.CreateBook(c)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return conflict when another book has the isbn", func() {
+			reqBody := entity.BookCreateRequest{
+				Title:    "Test Book",
+				Author:   "Test Author",
+				Price:    19.99,
+				Stock:    5,
+				ISBN:     "0-306-40615-2",
+				Language: "th",
+			}
+			jsonValue, _ := json.Marshal(reqBody)
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/books", bytes.NewBuffer(jsonValue))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().
+				CreateBook(gomock.Any()).
+				Return(errmap.ErrmapConflict)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.CreateBook(c)
+
+			Expect(w.Code).To(Equal(http.StatusConflict))
+		})
 	})
 
 	Context("ListBook", func() {
@@ -157,7 +209,7 @@ var _ = Describe("Book Handler", func() {
             expectedBooks := []entity.BookResponse{*testBook}
             serviceMock.EXPECT().
                 ListBook(gomock.Any()).
-                Return(expectedBooks, nil)
+                Return(&entity.ListBookResult{Books: expectedBooks}, nil)
 
             w := httptest.NewRecorder()
             c := gin.CreateTestContextOnly(w, r)
@@ -185,6 +237,231 @@ var _ = Describe("Book Handler", func() {
             Expect(w.Code).To(Equal(http.StatusBadRequest))
         })
 
+        It("should return the suggestion of a fuzzy search", func() {
+            req, _ := http.NewRequest(http.MethodGet, "/api/books?page=1&size=10&search=rowlnig&mode=fuzzy", nil)
+            req.Header.Set("Authorization", "Bearer "+testToken)
+
+            search := "rowlnig"
+            suggestion := "J.K. Rowling"
+            serviceMock.EXPECT().
+                ListBook(entity.ListBookRequest{Page: 1, Size: 10, Search: &search, Mode: constant.SearchModeFuzzy}).
+                Return(&entity.ListBookResult{Books: []entity.BookResponse{*testBook}, Suggestion: &suggestion}, nil)
+
+            w := httptest.NewRecorder()
+            c := gin.CreateTestContextOnly(w, r)
+            c.Request = req
+
+            h.ListBook(c)
+
+            Expect(w.Code).To(Equal(http.StatusOK))
+            var response map[string]interface{}
+            err := json.Unmarshal(w.Body.Bytes(), &response)
+            Expect(err).NotTo(HaveOccurred())
+            Expect(response["suggestion"]).To(Equal(suggestion))
+        })
+
+        It("should pass filters, sort and facets to the service", func() {
+            req, _ := http.NewRequest(http.MethodGet, "/api/books?page=1&size=10&author=Frank+Herbert&available=true&minPrice=100&maxPrice=500&language=th&minYear=1960&maxYear=1969&sort=-popularity&sort=title&facets=true", nil)
+            req.Header.Set("Authorization", "Bearer "+testToken)
+
+            author := "Frank Herbert"
+            available := true
+            minPrice, maxPrice := 100.0, 500.0
+            language := "th"
+            minYear, maxYear := 1960, 1969
+            facets := &entity.BookFacets{Authors: []entity.FacetCount{{Value: author, Count: 1}}}
+            serviceMock.EXPECT().
+                ListBook(entity.ListBookRequest{
+                    Page:      1,
+                    Size:      10,
+                    Author:    &author,
+                    Available: &available,
+                    MinPrice:  &minPrice,
+                    MaxPrice:  &maxPrice,
+                    Language:  &language,
+                    MinYear:   &minYear,
+                    MaxYear:   &maxYear,
+                    Sort:      []string{"-popularity", "title"},
+                    Facets:    true,
+                }).
+                Return(&entity.ListBookResult{Books: []entity.BookResponse{*testBook}, Facets: facets}, nil)
+
+            w := httptest.NewRecorder()
+            c := gin.CreateTestContextOnly(w, r)
+            c.Request = req
+
+            h.ListBook(c)
+
+            Expect(w.Code).To(Equal(http.StatusOK))
+            var response struct {
+                Facets entity.BookFacets `json:"facets"`
+            }
+            err := json.Unmarshal(w.Body.Bytes(), &response)
+            Expect(err).NotTo(HaveOccurred())
+            Expect(response.Facets.Authors).To(Equal(facets.Authors))
+        })
+
+        It("should leave facets out unless asked", func() {
+            req, _ := http.NewRequest(http.MethodGet, "/api/books?page=1&size=10", nil)
+            req.Header.Set("Authorization", "Bearer "+testToken)
+
+            serviceMock.EXPECT().
+                ListBook(gomock.Any()).
+                Return(&entity.ListBookResult{Books: []entity.BookResponse{*testBook}}, nil)
+
+            w := httptest.NewRecorder()
+            c := gin.CreateTestContextOnly(w, r)
+            c.Request = req
+
+            h.ListBook(c)
+
+            Expect(w.Code).To(Equal(http.StatusOK))
+            Expect(w.Body.String()).NotTo(ContainSubstring("facets"))
+        })
+
+        It("should link to the pages around a page by number", func() {
+            req, _ := http.NewRequest(http.MethodGet, "/api/books?page=2&size=10&sort=title", nil)
+            req.Header.Set("Authorization", "Bearer "+testToken)
+
+            total, totalPages := int64(25), int64(3)
+            serviceMock.EXPECT().
+                ListBook(gomock.Any()).
+                Return(&entity.ListBookResult{
+                    Books:      []entity.BookResponse{*testBook},
+                    Pagination: entity.Pagination{Page: 2, Size: 10, Total: &total, TotalPages: &totalPages},
+                }, nil)
+
+            w := httptest.NewRecorder()
+            c := gin.CreateTestContextOnly(w, r)
+            c.Request = req
+
+            h.ListBook(c)
+
+            Expect(w.Code).To(Equal(http.StatusOK))
+            var response struct {
+                Pagination entity.Pagination `json:"pagination"`
+            }
+            err := json.Unmarshal(w.Body.Bytes(), &response)
+            Expect(err).NotTo(HaveOccurred())
+            Expect(*response.Pagination.Total).To(Equal(total))
+            Expect(*response.Pagination.Next).To(Equal("/api/books?page=3&size=10&sort=title"))
+            Expect(*response.Pagination.Prev).To(Equal("/api/books?page=1&size=10&sort=title"))
+        })
+
+        It("should page by cursor without a page number", func() {
+            req, _ := http.NewRequest(http.MethodGet, "/api/books?size=10&cursor=", nil)
+            req.Header.Set("Authorization", "Bearer "+testToken)
+
+            nextCursor := "abc"
+            serviceMock.EXPECT().
+                ListBook(gomock.Any()).
+                DoAndReturn(func(req entity.ListBookRequest) (*entity.ListBookResult, error) {
+                    Expect(*req.Cursor).To(BeEmpty())
+                    return &entity.ListBookResult{
+                        Books:      []entity.BookResponse{*testBook},
+                        Pagination: entity.Pagination{Size: 10, NextCursor: &nextCursor},
+                    }, nil
+                })
+
+            w := httptest.NewRecorder()
+            c := gin.CreateTestContextOnly(w, r)
+            c.Request = req
+
+            h.ListBook(c)
+
+            Expect(w.Code).To(Equal(http.StatusOK))
+            var response struct {
+                Pagination entity.Pagination `json:"pagination"`
+            }
+            err := json.Unmarshal(w.Body.Bytes(), &response)
+            Expect(err).NotTo(HaveOccurred())
+            Expect(*response.Pagination.Next).To(Equal("/api/books?cursor=abc&size=10"))
+            Expect(response.Pagination.Prev).To(BeNil())
+            Expect(response.Pagination.Total).To(BeNil())
+        })
+
+        It("should return error for a cursor that cannot be followed", func() {
+            req, _ := http.NewRequest(http.MethodGet, "/api/books?size=10&cursor=stale", nil)
+            req.Header.Set("Authorization", "Bearer "+testToken)
+
+            serviceMock.EXPECT().
+                ListBook(gomock.Any()).
+                Return(nil, errmap.ErrmapInvalidCursor)
+
+            w := httptest.NewRecorder()
+            c := gin.CreateTestContextOnly(w, r)
+            c.Request = req
+
+            h.ListBook(c)
+
+            Expect(w.Code).To(Equal(http.StatusBadRequest))
+        })
+
+        It("should return error without a page or a cursor", func() {
+            req, _ := http.NewRequest(http.MethodGet, "/api/books?size=10", nil)
+            req.Header.Set("Authorization", "Bearer "+testToken)
+
+            w := httptest.NewRecorder()
+            c := gin.CreateTestContextOnly(w, r)
+            c.Request = req
+
+            h.ListBook(c)
+
+            Expect(w.Code).To(Equal(http.StatusBadRequest))
+        })
+
+        It("should return error for an unknown sort field", func() {
+            req, _ := http.NewRequest(http.MethodGet, "/api/books?page=1&size=10&sort=price", nil)
+            req.Header.Set("Authorization", "Bearer "+testToken)
+
+            w := httptest.NewRecorder()
+            c := gin.CreateTestContextOnly(w, r)
+            c.Request = req
+
+            h.ListBook(c)
+
+            Expect(w.Code).To(Equal(http.StatusBadRequest))
+        })
+
+        It("should return error when maxPrice is below minPrice", func() {
+            req, _ := http.NewRequest(http.MethodGet, "/api/books?page=1&size=10&minPrice=500&maxPrice=100", nil)
+            req.Header.Set("Authorization", "Bearer "+testToken)
+
+            w := httptest.NewRecorder()
+            c := gin.CreateTestContextOnly(w, r)
+            c.Request = req
+
+            h.ListBook(c)
+
+            Expect(w.Code).To(Equal(http.StatusBadRequest))
+        })
+
+        It("should return error when maxYear is below minYear", func() {
+            req, _ := http.NewRequest(http.MethodGet, "/api/books?page=1&size=10&minYear=1990&maxYear=1980", nil)
+            req.Header.Set("Authorization", "Bearer "+testToken)
+
+            w := httptest.NewRecorder()
+            c := gin.CreateTestContextOnly(w, r)
+            c.Request = req
+
+            h.ListBook(c)
+
+            Expect(w.Code).To(Equal(http.StatusBadRequest))
+        })
+
+        It("should return error for an unknown search mode", func() {
+            req, _ := http.NewRequest(http.MethodGet, "/api/books?page=1&size=10&search=dune&mode=regex", nil)
+            req.Header.Set("Authorization", "Bearer "+testToken)
+
+            w := httptest.NewRecorder()
+            c := gin.CreateTestContextOnly(w, r)
+            c.Request = req
+
+            h.ListBook(c)
+
+            Expect(w.Code).To(Equal(http.StatusBadRequest))
+        })
+
         It("should return error when service fails", func() {
             req, _ := http.NewRequest(http.MethodGet, "/api/books?page=1&size=10", nil)
             req.Header.Set("Authorization", "Bearer "+testToken)
@@ -259,6 +536,62 @@ var _ = Describe("Book Handler", func() {
         })
     })
 
+    Context("GetBookByISBN", func() {
+        It("should get book by ISBN successfully", func() {
+            req, _ := http.NewRequest(http.MethodGet, "/api/books/isbn/0-306-40615-2", nil)
+            req.Header.Set("Authorization", "Bearer "+testToken)
+
+            serviceMock.EXPECT().
+                GetBookByISBN("0-306-40615-2").
+                Return(testBook, nil)
+
+            w := httptest.NewRecorder()
+            c := gin.CreateTestContextOnly(w, r)
+            c.Params = append(c.Params, gin.Param{Key: "isbn", Value: "0-306-40615-2"})
+            c.Request = req
+
+            h.GetBookByISBN(c)
+
+            Expect(w.Code).To(Equal(http.StatusOK))
+        })
+
+        It("should return error for an invalid isbn", func() {
+            req, _ := http.NewRequest(http.MethodGet, "/api/books/isbn/12345", nil)
+            req.Header.Set("Authorization", "Bearer "+testToken)
+
+            serviceMock.EXPECT().
+                GetBookByISBN("12345").
+                Return(nil, errmap.ErrmapInvalidISBN)
+
+            w := httptest.NewRecorder()
+            c := gin.CreateTestContextOnly(w, r)
+            c.Params = append(c.Params, gin.Param{Key: "isbn", Value: "12345"})
+            c.Request = req
+
+            h.GetBookByISBN(c)
+
+            Expect(w.Code).To(Equal(http.StatusBadRequest))
+        })
+
+        It("should return not found when no book has the isbn", func() {
+            req, _ := http.NewRequest(http.MethodGet, "/api/books/isbn/9780306406157", nil)
+            req.Header.Set("Authorization", "Bearer "+testToken)
+
+            serviceMock.EXPECT().
+                GetBookByISBN("9780306406157").
+                Return(nil, errmap.ErrmapNotFound)
+
+            w := httptest.NewRecorder()
+            c := gin.CreateTestContextOnly(w, r)
+            c.Params = append(c.Params, gin.Param{Key: "isbn", Value: "9780306406157"})
+            c.Request = req
+
+            h.GetBookByISBN(c)
+
+            Expect(w.Code).To(Equal(http.StatusNotFound))
+        })
+    })
+
     Context("UpdateBook", func() {
         It("should update book successfully", func() {
             reqBody := entity.BookUpdateRequest{
@@ -266,7 +599,6 @@ var _ = Describe("Book Handler", func() {
                 Title:  "Updated Book",
                 Author: "Updated Author",
                 Price:  29.99,
-                Stock:  10,
             }
             jsonValue, _ := json.Marshal(reqBody)
             req, _ := http.NewRequest(http.MethodPut, "/api/management/books/1", bytes.NewBuffer(jsonValue))
@@ -309,7 +641,6 @@ var _ = Describe("Book Handler", func() {
                 Title:  "Book",
                 Author: "Author",
                 Price:  9.99,
-                Stock:  1,
             }
             jsonValue, _ := json.Marshal(reqBody)
             req, _ := http.NewRequest(http.MethodPut, "/api/management/books/999", bytes.NewBuffer(jsonValue))
@@ -329,31 +660,76 @@ var _ = Describe("Book Handler", func() {
 
             Expect(w.Code).To(Equal(http.StatusNotFound))
         })
+    })
 
-        It("should return bad request for invalid stock", func() {
-            reqBody := entity.BookUpdateRequest{
-                ID:     1,
-                Title:  "Test Book",
-                Author: "Test Author",
-                Price:  19.99,
-                Stock:  0,
-            }
-			
-            jsonValue, _ := json.Marshal(reqBody)
-            req, _ := http.NewRequest(http.MethodPut, "/api/management/books/1", bytes.NewBuffer(jsonValue))
-            req.Header.Set("Content-Type", "application/json")
-            req.Header.Set("Authorization", "Bearer "+testToken)
+	Context("ArchiveBook", func() {
+		It("should archive a book", func() {
+			req, _ := http.NewRequest(http.MethodDelete, "/api/management/books/1", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
 
-            w := httptest.NewRecorder()
-            c := gin.CreateTestContextOnly(w, r)
-            c.Params = append(c.Params, gin.Param{Key: "id", Value: "1"})
-            c.Request = req
+			serviceMock.EXPECT().ArchiveBook(uint(1)).Return(nil)
 
-            h.UpdateBook(c)
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "1"})
+			c.Request = req
 
-            Expect(w.Code).To(Equal(http.StatusBadRequest))
-        })
-    })
+			h.ArchiveBook(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+		})
+
+		It("should return conflict when the book has active loans", func() {
+			req, _ := http.NewRequest(http.MethodDelete, "/api/management/books/1", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().ArchiveBook(uint(1)).Return(errmap.ErrmapActiveLoans)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "1"})
+			c.Request = req
+
+			h.ArchiveBook(c)
+
+			Expect(w.Code).To(Equal(http.StatusConflict))
+			Expect(w.Body.String()).To(ContainSubstring("book has active loans"))
+		})
+	})
+
+	Context("RestoreBook", func() {
+		It("should restore an archived book", func() {
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/books/1/restore", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().RestoreBook(uint(1)).Return(nil)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "1"})
+			c.Request = req
+
+			h.RestoreBook(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+		})
+
+		It("should return not found for an unknown book", func() {
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/books/999/restore", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().RestoreBook(uint(999)).Return(errmap.ErrmapNotFound)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "999"})
+			c.Request = req
+
+			h.RestoreBook(c)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+	})
 
 	Context("ListLatestBooks", func() {
         It("should list latest books successfully", func() {
@@ -362,7 +738,7 @@ var _ = Describe("Book Handler", func() {
 
             expectedBooks := []entity.BookResponse{*testBook}
             serviceMock.EXPECT().
-                ListLatestBooks().
+                ListLatestBooks(entity.ListLatestBookRequest{}).
                 Return(expectedBooks, nil)
 
             w := httptest.NewRecorder()
@@ -377,12 +753,29 @@ var _ = Describe("Book Handler", func() {
             Expect(err).NotTo(HaveOccurred())
         })
 
+        It("should narrow latest books to a category and subject", func() {
+            req, _ := http.NewRequest(http.MethodGet, "/api/books/latest?categoryId=4&subject=Space+opera", nil)
+
+            categoryID, subject := uint(4), "Space opera"
+            serviceMock.EXPECT().
+                ListLatestBooks(entity.ListLatestBookRequest{CategoryID: &categoryID, Subject: &subject}).
+                Return([]entity.BookResponse{*testBook}, nil)
+
+            w := httptest.NewRecorder()
+            c := gin.CreateTestContextOnly(w, r)
+            c.Request = req
+
+            h.ListLatestBooks(c)
+
+            Expect(w.Code).To(Equal(http.StatusOK))
+        })
+
         It("should return error when service fails", func() {
             req, _ := http.NewRequest(http.MethodGet, "/api/books/latest", nil)
             req.Header.Set("Authorization", "Bearer "+testToken)
 
             serviceMock.EXPECT().
-                ListLatestBooks().
+                ListLatestBooks(entity.ListLatestBookRequest{}).
                 Return(nil, errors.New("internal server error"))
 
             w := httptest.NewRecorder()
@@ -501,6 +894,33 @@ var _ = Describe("Book Handler", func() {
         })
     })
 
+    Context("BorrowBook loan policy", func() {
+        It("should return forbidden with the reached limit", func() {
+            jsonValue, _ := json.Marshal(entity.BorrowBookRequest{BookID: 1})
+            req, _ := http.NewRequest(http.MethodPost, "/api/books/1/borrow", bytes.NewBuffer(jsonValue))
+            req.Header.Set("Content-Type", "application/json")
+            req.Header.Set("Authorization", "Bearer "+testToken)
+
+            serviceMock.EXPECT().
+                BorrowBook(gomock.Any()).
+                Return(nil, &errmap.LoanLimitError{Limit: constant.LoanLimitActiveLoans, Max: 5})
+
+            w := httptest.NewRecorder()
+            c := gin.CreateTestContextOnly(w, r)
+            c.Params = append(c.Params, gin.Param{Key: "id", Value: "1"})
+            c.Request = req
+            c.Set("userID", uint(1))
+
+            h.BorrowBook(c)
+
+            Expect(w.Code).To(Equal(http.StatusForbidden))
+            var response entity.ResponseError
+            err := json.Unmarshal(w.Body.Bytes(), &response)
+            Expect(err).NotTo(HaveOccurred())
+            Expect(response.Error).To(Equal("limit of 5 active loans reached"))
+        })
+    })
+
     Context("ReturnBook", func() {
         It("should return book successfully", func() {
             reqBody := entity.ReturnBookRequest{
@@ -571,6 +991,105 @@ var _ = Describe("Book Handler", func() {
         })
     })
 
+    Context("RenewBook", func() {
+        It("should renew book successfully", func() {
+            reqBody := entity.RenewBookRequest{
+                BookID:    1,
+                HistoryID: 1,
+            }
+            jsonValue, _ := json.Marshal(reqBody)
+            req, _ := http.NewRequest(http.MethodPost, "/api/books/1/renew", bytes.NewBuffer(jsonValue))
+            req.Header.Set("Content-Type", "application/json")
+            req.Header.Set("Authorization", "Bearer "+testToken)
+
+            dueAt := time.Now().Add(14 * 24 * time.Hour)
+            serviceMock.EXPECT().
+                RenewBook(entity.RenewBookRequest{BookID: 1, HistoryID: 1, UserID: 1}).
+                Return(&entity.BorrowHistoryResponse{ID: 1, BookID: 1, UserID: 1, DueAt: &dueAt, RenewalCount: 1}, nil)
+
+            w := httptest.NewRecorder()
+            c := gin.CreateTestContextOnly(w, r)
+            c.Params = append(c.Params, gin.Param{Key: "id", Value: "1"})
+            c.Request = req
+            c.Set("userID", uint(1))
+
+            h.RenewBook(c)
+
+            Expect(w.Code).To(Equal(http.StatusOK))
+            var response map[string]map[string]interface{}
+            err := json.Unmarshal(w.Body.Bytes(), &response)
+            Expect(err).NotTo(HaveOccurred())
+            Expect(response["data"]["renewalCount"]).To(BeEquivalentTo(1))
+        })
+
+        It("should return error for invalid request", func() {
+            invalidPayload := `{"bookId": 1}`
+            req, _ := http.NewRequest(http.MethodPost, "/api/books/1/renew", bytes.NewBuffer([]byte(invalidPayload)))
+            req.Header.Set("Content-Type", "application/json")
+            req.Header.Set("Authorization", "Bearer "+testToken)
+
+            w := httptest.NewRecorder()
+            c := gin.CreateTestContextOnly(w, r)
+            c.Params = append(c.Params, gin.Param{Key: "id", Value: "1"})
+            c.Request = req
+            c.Set("userID", uint(1))
+
+            h.RenewBook(c)
+
+            Expect(w.Code).To(Equal(http.StatusBadRequest))
+        })
+
+        It("should return conflict when renewal limit is reached", func() {
+            reqBody := entity.RenewBookRequest{
+                BookID:    1,
+                HistoryID: 1,
+            }
+            jsonValue, _ := json.Marshal(reqBody)
+            req, _ := http.NewRequest(http.MethodPost, "/api/books/1/renew", bytes.NewBuffer(jsonValue))
+            req.Header.Set("Content-Type", "application/json")
+            req.Header.Set("Authorization", "Bearer "+testToken)
+
+            serviceMock.EXPECT().
+                RenewBook(gomock.Any()).
+                Return(nil, errmap.ErrmapRenewalLimit)
+
+            w := httptest.NewRecorder()
+            c := gin.CreateTestContextOnly(w, r)
+            c.Params = append(c.Params, gin.Param{Key: "id", Value: "1"})
+            c.Request = req
+            c.Set("userID", uint(1))
+
+            h.RenewBook(c)
+
+            Expect(w.Code).To(Equal(http.StatusConflict))
+        })
+
+        It("should return not found when borrow record doesn't exist", func() {
+            reqBody := entity.RenewBookRequest{
+                BookID:    1,
+                HistoryID: 999,
+            }
+            jsonValue, _ := json.Marshal(reqBody)
+            req, _ := http.NewRequest(http.MethodPost, "/api/books/1/renew", bytes.NewBuffer(jsonValue))
+            req.Header.Set("Content-Type", "application/json")
+            req.Header.Set("Authorization", "Bearer "+testToken)
+
+            serviceMock.EXPECT().
+                RenewBook(gomock.Any()).
+                Return(nil, errmap.ErrmapNotFound)
+
+            w := httptest.NewRecorder()
+            c := gin.CreateTestContextOnly(w, r)
+            c.Params = append(c.Params, gin.Param{Key: "id", Value: "1"})
+            c.Request = req
+            c.Set("userID", uint(1))
+
+            h.RenewBook(c)
+
+            Expect(w.Code).To(Equal(http.StatusNotFound))
+        })
+    })
+
     Context("GetBookBorrowHistory", func() {
         It("should get book borrow history successfully", func() {
             req, _ := http.NewRequest(http.MethodGet, "/api/management/books/1/history", nil)
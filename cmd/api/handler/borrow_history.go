@@ -0,0 +1,315 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	"go-library-service/cmd/api/middleware"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// ListOverdueBorrows lists overdue borrows
+// @Summary List overdue borrows
+// @Description Get a list of unreturned borrows past their due date with optional filters
+// @Tags management borrows
+// @Accept  json
+// @Produce  json
+// @Param   page      query     int     false  "Page number, required unless paging by cursor"
+// @Param   size      query     int     true   "Number of items per page"
+// @Param   cursor    query     string  false  "Cursor of the page to get, empty for the first page, pages by cursor are not counted"
+// @Param   userId    query     int     false  "Filter by user ID"
+// @Param   bookId    query     int     false  "Filter by book ID"
+// @Success 200 {object} entity.ResponseData{data=[]entity.BorrowHistoryResponse}
+// @Failure 400 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/borrows/overdue [get]
+func (h *Handler) ListOverdueBorrows(c *gin.Context) {
+	var req entity.ListOverdueBorrowRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ListOverdueBorrows]: unable to bind query"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "Unable to bind query", Code: http.StatusBadRequest})
+		return
+	}
+
+	if err := h.deps.Validator.Struct(req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ListOverdueBorrows]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: err.Error(), Code: http.StatusBadRequest})
+		return
+	}
+
+	result, err := h.deps.Service.ListOverdueBorrows(req)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapInvalidCursor) {
+			c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid cursor", Code: http.StatusBadRequest})
+			return
+		}
+		log.Error(errors.Wrap(err, "[Handler.ListOverdueBorrows]: unable to list overdue borrows"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to list overdue borrows", Code: http.StatusInternalServerError})
+		return
+	}
+
+	linkPages(c, &result.Pagination)
+	c.AbortWithStatusJSON(http.StatusOK, entity.ResponseData{Data: result.Histories, Pagination: &result.Pagination})
+}
+
+// ListMyLoans lists active loans of the current user
+// @Summary List my loans
+// @Description Get the books the current user has not returned yet
+// @Tags borrows
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} entity.ResponseData{data=[]entity.UserLoanResponse}
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /users/me/loans [get]
+func (h *Handler) ListMyLoans(c *gin.Context) {
+	userID := h.getJWTInfo(c)
+
+	loans, err := h.deps.Service.ListUserLoans(userID)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ListMyLoans]: unable to list loans"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to list loans", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatusJSON(http.StatusOK, entity.ResponseData{Data: loans})
+}
+
+// ListMyBorrowHistory lists borrow history of the current user
+// @Summary List my borrow history
+// @Description Get the borrow history of the current user with optional status and borrow date filters
+// @Tags borrows
+// @Accept  json
+// @Produce  json
+// @Param   page      query     int     false  "Page number, required unless paging by cursor"
+// @Param   size      query     int     true   "Number of items per page"
+// @Param   cursor    query     string  false  "Cursor of the page to get, empty for the first page, pages by cursor are not counted"
+// @Param   status    query     string  false  "Filter by status" Enums(BORROWED, RETURNED, OVERDUE, LOST, DAMAGED)
+// @Param   from      query     string  false  "Borrowed on or after (YYYY-MM-DD)"
+// @Param   to        query     string  false  "Borrowed on or before (YYYY-MM-DD)"
+// @Success 200 {object} entity.ResponseData{data=[]entity.UserLoanResponse}
+// @Failure 400 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /users/me/history [get]
+func (h *Handler) ListMyBorrowHistory(c *gin.Context) {
+	userID := h.getJWTInfo(c)
+
+	var req entity.ListUserBorrowHistoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ListMyBorrowHistory]: unable to bind query"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "Unable to bind query", Code: http.StatusBadRequest})
+		return
+	}
+
+	if err := h.deps.Validator.Struct(req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ListMyBorrowHistory]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: err.Error(), Code: http.StatusBadRequest})
+		return
+	}
+
+	if req.From != nil && req.To != nil && req.To.Before(*req.From) {
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "to must not be before from", Code: http.StatusBadRequest})
+		return
+	}
+
+	req.UserID = userID
+
+	result, err := h.deps.Service.ListUserBorrowHistory(req)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapInvalidCursor) {
+			c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid cursor", Code: http.StatusBadRequest})
+			return
+		}
+		log.Error(errors.Wrap(err, "[Handler.ListMyBorrowHistory]: unable to list borrow history"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to list borrow history", Code: http.StatusInternalServerError})
+		return
+	}
+
+	linkPages(c, &result.Pagination)
+	c.AbortWithStatusJSON(http.StatusOK, entity.ResponseData{Data: result.Histories, Pagination: &result.Pagination})
+}
+
+// MarkBookLost marks a borrowed book as lost
+// @Summary Mark a borrowed book as lost
+// @Description Close an active borrow as lost, the copy is not put back in stock and the patron can be billed its replacement cost
+// @Tags management borrows
+// @Accept  json
+// @Produce  json
+// @Param   id    path      int  true  "Borrow history ID"
+// @Param   lost  body      entity.LostBookRequest  true  "Lost book"
+// @Success 200 {object} entity.ResponseData{data=entity.BorrowHistoryResponse}
+// @Failure 400 {object} entity.ResponseError
+// @Failure 404 {object} entity.ResponseError
+// @Failure 409 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/borrows/{id}/lost [post]
+func (h *Handler) MarkBookLost(c *gin.Context) {
+	staffID := h.getJWTInfo(c)
+
+	historyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.MarkBookLost]: unable to convert history id"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid history id", Code: http.StatusBadRequest})
+		return
+	}
+
+	var req entity.LostBookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.MarkBookLost]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid request", Code: http.StatusBadRequest})
+		return
+	}
+
+	req.HistoryID = uint(historyID)
+	req.StaffID = staffID
+
+	history, err := h.deps.Service.MarkBookLost(req)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			c.JSON(http.StatusNotFound, entity.ResponseError{Error: "borrow history not found", Code: http.StatusNotFound})
+			return
+		}
+
+		if errors.Is(err, errmap.ErrmapConflict) {
+			c.JSON(http.StatusConflict, entity.ResponseError{Error: "book is not on loan", Code: http.StatusConflict})
+			return
+		}
+
+		log.Error(errors.Wrap(err, "[Handler.MarkBookLost]: unable to mark book lost"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to mark book lost", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatusJSON(http.StatusOK, entity.ResponseData{Data: history})
+}
+
+// ReturnBookDamaged returns a borrowed book damaged
+// @Summary Return a damaged book
+// @Description Close an active borrow whose book came back damaged, the copy is withdrawn and the patron can be billed its replacement cost
+// @Tags management borrows
+// @Accept  json
+// @Produce  json
+// @Param   id       path      int  true  "Borrow history ID"
+// @Param   damaged  body      entity.DamagedBookRequest  true  "Damaged book"
+// @Success 200 {object} entity.ResponseData{data=entity.BorrowHistoryResponse}
+// @Failure 400 {object} entity.ResponseError
+// @Failure 404 {object} entity.ResponseError
+// @Failure 409 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/borrows/{id}/damaged [post]
+func (h *Handler) ReturnBookDamaged(c *gin.Context) {
+	staffID := h.getJWTInfo(c)
+
+	historyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ReturnBookDamaged]: unable to convert history id"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid history id", Code: http.StatusBadRequest})
+		return
+	}
+
+	var req entity.DamagedBookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ReturnBookDamaged]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid request", Code: http.StatusBadRequest})
+		return
+	}
+
+	req.HistoryID = uint(historyID)
+	req.StaffID = staffID
+
+	history, err := h.deps.Service.ReturnBookDamaged(req)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			c.JSON(http.StatusNotFound, entity.ResponseError{Error: "borrow history not found", Code: http.StatusNotFound})
+			return
+		}
+
+		if errors.Is(err, errmap.ErrmapConflict) {
+			c.JSON(http.StatusConflict, entity.ResponseError{Error: "book is not on loan", Code: http.StatusConflict})
+			return
+		}
+
+		log.Error(errors.Wrap(err, "[Handler.ReturnBookDamaged]: unable to return book"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to return book", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatusJSON(http.StatusOK, entity.ResponseData{Data: history})
+}
+
+// MarkBookFound reverses a lost book
+// @Summary Mark a lost book as found
+// @Description Return a lost book that turned up, put its copy back in circulation and waive the replacement cost still owed
+// @Tags management borrows
+// @Accept  json
+// @Produce  json
+// @Param   id   path      int  true  "Borrow history ID"
+// @Success 200 {object} entity.ResponseData{data=entity.BorrowHistoryResponse}
+// @Failure 400 {object} entity.ResponseError
+// @Failure 404 {object} entity.ResponseError
+// @Failure 409 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/borrows/{id}/found [post]
+func (h *Handler) MarkBookFound(c *gin.Context) {
+	staffID := h.getJWTInfo(c)
+
+	historyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.MarkBookFound]: unable to convert history id"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid history id", Code: http.StatusBadRequest})
+		return
+	}
+
+	history, err := h.deps.Service.MarkBookFound(entity.FoundBookRequest{HistoryID: uint(historyID), StaffID: staffID})
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			c.JSON(http.StatusNotFound, entity.ResponseError{Error: "borrow history not found", Code: http.StatusNotFound})
+			return
+		}
+
+		if errors.Is(err, errmap.ErrmapConflict) {
+			c.JSON(http.StatusConflict, entity.ResponseError{Error: "book is not lost", Code: http.StatusConflict})
+			return
+		}
+
+		log.Error(errors.Wrap(err, "[Handler.MarkBookFound]: unable to mark book found"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to mark book found", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatusJSON(http.StatusOK, entity.ResponseData{Data: history})
+}
+
+// RegisterBorrowHistoryRoutes registers borrow history routes
+func RegisterBorrowHistoryRoutes(router *gin.RouterGroup, handler *Handler) {
+	managementBorrowRoutes := router.Group("/management/borrows")
+	{
+		managementBorrowRoutes.Use(middleware.AuthMiddleware())
+		managementBorrowRoutes.Use(middleware.RoleMiddleware(constant.UserTypeStaff))
+
+		managementBorrowRoutes.GET("/overdue", handler.ListOverdueBorrows)
+		managementBorrowRoutes.POST("/:id/lost", handler.MarkBookLost)
+		managementBorrowRoutes.POST("/:id/damaged", handler.ReturnBookDamaged)
+		managementBorrowRoutes.POST("/:id/found", handler.MarkBookFound)
+	}
+
+	userBorrowRoutes := router.Group("/users/me")
+	{
+		userBorrowRoutes.Use(middleware.AuthMiddleware())
+		userBorrowRoutes.Use(middleware.RoleMiddleware(constant.UserTypeUser, constant.UserTypeStaff))
+
+		userBorrowRoutes.GET("/loans", handler.ListMyLoans)
+		userBorrowRoutes.GET("/history", handler.ListMyBorrowHistory)
+	}
+}
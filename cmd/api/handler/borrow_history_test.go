@@ -0,0 +1,362 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	"go-library-service/cmd/api/handler"
+	"go-library-service/cmd/api/handler/mock"
+	"go-library-service/cmd/api/middleware"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Borrow History Handler", func() {
+	var (
+		h           *handler.Handler
+		serviceMock *mock.MockService
+		r           *gin.Engine
+		ctrl        *gomock.Controller
+		testToken   string
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		serviceMock = mock.NewMockService(ctrl)
+		h = handler.NewHandler(&handler.Dependencies{
+			Service:   serviceMock,
+			Validator: validator.New(),
+		}, &handler.Config{})
+
+		gin.SetMode(gin.TestMode)
+		r = gin.Default()
+		handler.RegisterBorrowHistoryRoutes(r.Group("/api"), h)
+
+		var err error
+		testToken, err = middleware.GenerateToken(uint(1), constant.UserTypeStaff)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("ListOverdueBorrows", func() {
+		It("should list overdue borrows with filters", func() {
+			req, _ := http.NewRequest(http.MethodGet, "/api/management/borrows/overdue?page=1&size=10&userId=2&bookId=3", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			dueAt := time.Now().Add(-24 * time.Hour)
+			expectedHistories := []entity.BorrowHistoryResponse{
+				{
+					ID:     1,
+					BookID: 3,
+					UserID: 2,
+					DueAt:  &dueAt,
+					Status: constant.BorrowStatusOverdue,
+				},
+			}
+
+			serviceMock.EXPECT().
+				ListOverdueBorrows(gomock.Any()).
+				DoAndReturn(func(req entity.ListOverdueBorrowRequest) (*entity.ListOverdueBorrowResult, error) {
+					Expect(*req.UserID).To(Equal(uint(2)))
+					Expect(*req.BookID).To(Equal(uint(3)))
+					return &entity.ListOverdueBorrowResult{Histories: expectedHistories}, nil
+				})
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.ListOverdueBorrows(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response["data"]).To(HaveLen(1))
+		})
+
+		It("should return error for invalid query params", func() {
+			req, _ := http.NewRequest(http.MethodGet, "/api/management/borrows/overdue?page=0&size=0", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.ListOverdueBorrows(c)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should link to the next page by cursor", func() {
+			req, _ := http.NewRequest(http.MethodGet, "/api/management/borrows/overdue?size=10&cursor=&userId=2", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			nextCursor := "abc"
+			serviceMock.EXPECT().
+				ListOverdueBorrows(gomock.Any()).
+				Return(&entity.ListOverdueBorrowResult{
+					Histories:  []entity.BorrowHistoryResponse{{ID: 1}},
+					Pagination: entity.Pagination{Size: 10, NextCursor: &nextCursor},
+				}, nil)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.ListOverdueBorrows(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var response struct {
+				Pagination entity.Pagination `json:"pagination"`
+			}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*response.Pagination.NextCursor).To(Equal(nextCursor))
+			Expect(*response.Pagination.Next).To(Equal("/api/management/borrows/overdue?cursor=abc&size=10&userId=2"))
+		})
+
+		It("should return error for a cursor that cannot be followed", func() {
+			req, _ := http.NewRequest(http.MethodGet, "/api/management/borrows/overdue?size=10&cursor=stale", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().
+				ListOverdueBorrows(gomock.Any()).
+				Return(nil, errmap.ErrmapInvalidCursor)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.ListOverdueBorrows(c)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return error when service fails", func() {
+			req, _ := http.NewRequest(http.MethodGet, "/api/management/borrows/overdue?page=1&size=10", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().
+				ListOverdueBorrows(gomock.Any()).
+				Return(nil, errors.New("internal server error"))
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.ListOverdueBorrows(c)
+
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+	Context("ListMyLoans", func() {
+		It("should list loans of the current user", func() {
+			req, _ := http.NewRequest(http.MethodGet, "/api/users/me/loans", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			expectedLoans := []entity.UserLoanResponse{
+				{ID: 1, BookID: 3, BookTitle: "Test Book", BookAuthor: "Test Author", UserID: 1, Status: constant.BorrowStatusBorrowed},
+			}
+
+			serviceMock.EXPECT().ListUserLoans(uint(1)).Return(expectedLoans, nil)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+			c.Set("userID", uint(1))
+
+			h.ListMyLoans(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response["data"]).To(HaveLen(1))
+		})
+
+		It("should return error when service fails", func() {
+			req, _ := http.NewRequest(http.MethodGet, "/api/users/me/loans", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().ListUserLoans(uint(1)).Return(nil, errors.New("internal server error"))
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+			c.Set("userID", uint(1))
+
+			h.ListMyLoans(c)
+
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	Context("ListMyBorrowHistory", func() {
+		It("should list borrow history with filters", func() {
+			req, _ := http.NewRequest(http.MethodGet, "/api/users/me/history?page=1&size=10&status=RETURNED&from=2024-01-01&to=2024-01-31", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().
+				ListUserBorrowHistory(gomock.Any()).
+				DoAndReturn(func(req entity.ListUserBorrowHistoryRequest) (*entity.ListUserBorrowHistoryResult, error) {
+					Expect(req.UserID).To(Equal(uint(1)))
+					Expect(req.Status).To(Equal(constant.BorrowStatusReturned))
+					Expect(req.From.Format("2006-01-02")).To(Equal("2024-01-01"))
+					Expect(req.To.Format("2006-01-02")).To(Equal("2024-01-31"))
+					return &entity.ListUserBorrowHistoryResult{Histories: []entity.UserLoanResponse{}}, nil
+				})
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+			c.Set("userID", uint(1))
+
+			h.ListMyBorrowHistory(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+		})
+
+		It("should return error for an invalid status", func() {
+			req, _ := http.NewRequest(http.MethodGet, "/api/users/me/history?page=1&size=10&status=UNKNOWN", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+			c.Set("userID", uint(1))
+
+			h.ListMyBorrowHistory(c)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return error when to is before from", func() {
+			req, _ := http.NewRequest(http.MethodGet, "/api/users/me/history?page=1&size=10&from=2024-02-01&to=2024-01-01", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+			c.Set("userID", uint(1))
+
+			h.ListMyBorrowHistory(c)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+	Context("MarkBookLost", func() {
+		It("should mark a book lost", func() {
+			jsonValue, _ := json.Marshal(entity.LostBookRequest{ChargeReplacement: true})
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/borrows/5/lost", bytes.NewBuffer(jsonValue))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().
+				MarkBookLost(entity.LostBookRequest{HistoryID: 5, ChargeReplacement: true, StaffID: 1}).
+				Return(&entity.BorrowHistoryResponse{ID: 5, Status: constant.BorrowStatusLost}, nil)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "5"})
+			c.Request = req
+			c.Set("userID", uint(1))
+
+			h.MarkBookLost(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+		})
+
+		It("should return conflict when the book is not on loan", func() {
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/borrows/5/lost", bytes.NewBufferString(`{}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().MarkBookLost(gomock.Any()).Return(nil, errmap.ErrmapConflict)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "5"})
+			c.Request = req
+			c.Set("userID", uint(1))
+
+			h.MarkBookLost(c)
+
+			Expect(w.Code).To(Equal(http.StatusConflict))
+		})
+	})
+
+	Context("ReturnBookDamaged", func() {
+		It("should return a damaged book", func() {
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/borrows/5/damaged", bytes.NewBufferString(`{"chargeReplacement": false}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().
+				ReturnBookDamaged(entity.DamagedBookRequest{HistoryID: 5, StaffID: 1}).
+				Return(&entity.BorrowHistoryResponse{ID: 5, Status: constant.BorrowStatusDamaged}, nil)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "5"})
+			c.Request = req
+			c.Set("userID", uint(1))
+
+			h.ReturnBookDamaged(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+		})
+	})
+
+	Context("MarkBookFound", func() {
+		It("should mark a lost book found", func() {
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/borrows/5/found", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().
+				MarkBookFound(entity.FoundBookRequest{HistoryID: 5, StaffID: 1}).
+				Return(&entity.BorrowHistoryResponse{ID: 5, Status: constant.BorrowStatusReturned}, nil)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "5"})
+			c.Request = req
+			c.Set("userID", uint(1))
+
+			h.MarkBookFound(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+		})
+
+		It("should return not found for an unknown borrow", func() {
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/borrows/999/found", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().MarkBookFound(gomock.Any()).Return(nil, errmap.ErrmapNotFound)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "999"})
+			c.Request = req
+			c.Set("userID", uint(1))
+
+			h.MarkBookFound(c)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+})
@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	"go-library-service/cmd/api/middleware"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// CreateCategory creates a category
+// @Summary Create a category
+// @Description Create a category at the top of the tree or under a parent, refused when the parent already has one of the same name
+// @Tags management categories
+// @Accept  json
+// @Produce  json
+// @Param   category  body      entity.CategoryCreateRequest  true  "Category"
+// @Success 201 {object} entity.ResponseData{data=entity.CategoryResponse}
+// @Failure 400 {object} entity.ResponseError
+// @Failure 409 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/categories [post]
+func (h *Handler) CreateCategory(c *gin.Context) {
+	var req entity.CategoryCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.CreateCategory]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid request", Code: http.StatusBadRequest})
+		return
+	}
+
+	if err := h.deps.Validator.Struct(req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.CreateCategory]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: err.Error(), Code: http.StatusBadRequest})
+		return
+	}
+
+	category, err := h.deps.Service.CreateCategory(req)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapUnknownCategory) {
+			c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "unknown parent category id", Code: http.StatusBadRequest})
+			return
+		}
+
+		if errors.Is(err, errmap.ErrmapConflict) {
+			c.JSON(http.StatusConflict, entity.ResponseError{Error: "category already exists", Code: http.StatusConflict})
+			return
+		}
+
+		log.Error(errors.Wrap(err, "[Handler.CreateCategory]: unable to create category"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to create category", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatusJSON(http.StatusCreated, entity.ResponseData{Data: category})
+}
+
+// ListCategories lists the category tree
+// @Summary List categories
+// @Description Get the category tree, each level by name
+// @Tags categories
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} entity.ResponseData{data=[]entity.CategoryResponse}
+// @Failure 500 {object} entity.ResponseError
+// @Router /categories [get]
+func (h *Handler) ListCategories(c *gin.Context) {
+	categories, err := h.deps.Service.ListCategories()
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ListCategories]: unable to list categories"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to list categories", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatusJSON(http.StatusOK, entity.ResponseData{Data: categories})
+}
+
+// UpdateCategory updates a category
+// @Summary Update a category
+// @Description Rename a category or move it under another parent, never under itself or its subcategories
+// @Tags management categories
+// @Accept  json
+// @Produce  json
+// @Param   id        path      int  true  "Category ID"
+// @Param   category  body      entity.CategoryUpdateRequest  true  "Category"
+// @Success 200 {object} entity.ResponseData
+// @Failure 400 {object} entity.ResponseError
+// @Failure 404 {object} entity.ResponseError
+// @Failure 409 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/categories/{id} [put]
+func (h *Handler) UpdateCategory(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.UpdateCategory]: unable to convert category id"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid category id", Code: http.StatusBadRequest})
+		return
+	}
+
+	var req entity.CategoryUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.UpdateCategory]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid request", Code: http.StatusBadRequest})
+		return
+	}
+
+	if err := h.deps.Validator.Struct(req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.UpdateCategory]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: err.Error(), Code: http.StatusBadRequest})
+		return
+	}
+
+	req.ID = uint(categoryID)
+
+	if err := h.deps.Service.UpdateCategory(req); err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			c.JSON(http.StatusNotFound, entity.ResponseError{Error: "category not found", Code: http.StatusNotFound})
+			return
+		}
+
+		if errors.Is(err, errmap.ErrmapUnknownCategory) {
+			c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "unknown parent category id", Code: http.StatusBadRequest})
+			return
+		}
+
+		if errors.Is(err, errmap.ErrmapCategoryCycle) {
+			c.JSON(http.StatusConflict, entity.ResponseError{Error: "category cannot be moved under itself", Code: http.StatusConflict})
+			return
+		}
+
+		if errors.Is(err, errmap.ErrmapConflict) {
+			c.JSON(http.StatusConflict, entity.ResponseError{Error: "category already exists", Code: http.StatusConflict})
+			return
+		}
+
+		log.Error(errors.Wrap(err, "[Handler.UpdateCategory]: unable to update category"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to update category", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatus(http.StatusOK)
+}
+
+// DeleteCategory deletes a category
+// @Summary Delete a category
+// @Description Delete a category, refused while it has subcategories or books filed under it
+// @Tags management categories
+// @Accept  json
+// @Produce  json
+// @Param   id   path      int  true  "Category ID"
+// @Success 200 {object} entity.ResponseData
+// @Failure 400 {object} entity.ResponseError
+// @Failure 404 {object} entity.ResponseError
+// @Failure 409 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/categories/{id} [delete]
+func (h *Handler) DeleteCategory(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.DeleteCategory]: unable to convert category id"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid category id", Code: http.StatusBadRequest})
+		return
+	}
+
+	if err := h.deps.Service.DeleteCategory(uint(categoryID)); err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			c.JSON(http.StatusNotFound, entity.ResponseError{Error: "category not found", Code: http.StatusNotFound})
+			return
+		}
+
+		if errors.Is(err, errmap.ErrmapConflict) {
+			c.JSON(http.StatusConflict, entity.ResponseError{Error: "category has subcategories or books", Code: http.StatusConflict})
+			return
+		}
+
+		log.Error(errors.Wrap(err, "[Handler.DeleteCategory]: unable to delete category"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to delete category", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatus(http.StatusOK)
+}
+
+// RegisterCategoryRoutes registers category routes
+func RegisterCategoryRoutes(router *gin.RouterGroup, handler *Handler) {
+	// The tree is public like the latest books it narrows down
+	categoryRoutes := router.Group("/categories")
+	{
+		categoryRoutes.GET("", handler.ListCategories)
+	}
+
+	managementCategoryRoutes := router.Group("/management/categories")
+	{
+		managementCategoryRoutes.Use(middleware.AuthMiddleware())
+		managementCategoryRoutes.Use(middleware.RoleMiddleware(constant.UserTypeStaff))
+
+		managementCategoryRoutes.POST("", handler.CreateCategory)
+		managementCategoryRoutes.PUT("/:id", handler.UpdateCategory)
+		managementCategoryRoutes.DELETE("/:id", handler.DeleteCategory)
+	}
+}
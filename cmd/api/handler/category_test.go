@@ -0,0 +1,153 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	"go-library-service/cmd/api/handler"
+	"go-library-service/cmd/api/handler/mock"
+	"go-library-service/cmd/api/middleware"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Category Handler", func() {
+	var (
+		h           *handler.Handler
+		serviceMock *mock.MockService
+		r           *gin.Engine
+		ctrl        *gomock.Controller
+		testToken   string
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		serviceMock = mock.NewMockService(ctrl)
+		h = handler.NewHandler(&handler.Dependencies{
+			Service:   serviceMock,
+			Validator: validator.New(),
+		}, &handler.Config{})
+
+		gin.SetMode(gin.TestMode)
+		r = gin.Default()
+		handler.RegisterCategoryRoutes(r.Group("/api"), h)
+
+		var err error
+		testToken, err = middleware.GenerateToken(uint(1), constant.UserTypeStaff)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("CreateCategory", func() {
+		It("should create a category", func() {
+			jsonValue, _ := json.Marshal(entity.CategoryCreateRequest{Name: "Fiction", Code: "800"})
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/categories", bytes.NewBuffer(jsonValue))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().
+				CreateCategory(entity.CategoryCreateRequest{Name: "Fiction", Code: "800"}).
+				Return(&entity.CategoryResponse{ID: 1, Name: "Fiction", Code: "800", Children: []entity.CategoryResponse{}}, nil)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.CreateCategory(c)
+
+			Expect(w.Code).To(Equal(http.StatusCreated))
+		})
+
+		It("should return error for an unknown parent", func() {
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/categories", bytes.NewBufferString(`{"name": "Science fiction", "parentId": 9}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().CreateCategory(gomock.Any()).Return(nil, errmap.ErrmapUnknownCategory)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.CreateCategory(c)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Context("ListCategories", func() {
+		It("should return the tree without a token", func() {
+			req, _ := http.NewRequest(http.MethodGet, "/api/categories", nil)
+
+			parentID := uint(1)
+			serviceMock.EXPECT().ListCategories().Return([]entity.CategoryResponse{{
+				ID:       1,
+				Name:     "Fiction",
+				Children: []entity.CategoryResponse{{ID: 2, Name: "Science fiction", ParentID: &parentID, Children: []entity.CategoryResponse{}}},
+			}}, nil)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var response struct {
+				Data []entity.CategoryResponse `json:"data"`
+			}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Data[0].Children[0].Name).To(Equal("Science fiction"))
+		})
+	})
+
+	Context("UpdateCategory", func() {
+		It("should return conflict when moving a category under itself", func() {
+			req, _ := http.NewRequest(http.MethodPut, "/api/management/categories/1", bytes.NewBufferString(`{"name": "Fiction", "parentId": 2}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			parentID := uint(2)
+			serviceMock.EXPECT().
+				UpdateCategory(entity.CategoryUpdateRequest{ID: 1, Name: "Fiction", ParentID: &parentID}).
+				Return(errmap.ErrmapCategoryCycle)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "1"})
+			c.Request = req
+
+			h.UpdateCategory(c)
+
+			Expect(w.Code).To(Equal(http.StatusConflict))
+		})
+	})
+
+	Context("DeleteCategory", func() {
+		It("should return conflict while the category has books", func() {
+			req, _ := http.NewRequest(http.MethodDelete, "/api/management/categories/1", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().DeleteCategory(uint(1)).Return(errmap.ErrmapConflict)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "1"})
+			c.Request = req
+
+			h.DeleteCategory(c)
+
+			Expect(w.Code).To(Equal(http.StatusConflict))
+		})
+	})
+})
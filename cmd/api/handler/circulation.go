@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"net/http"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	"go-library-service/cmd/api/middleware"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// Checkout lends a copy to a patron at the circulation desk
+// @Summary Check out a copy
+// @Description Lend a scanned copy to a patron found by user ID or card number
+// @Tags management circulation
+// @Accept  json
+// @Produce  json
+// @Param   checkout  body      entity.CheckoutRequest  true  "Checkout"
+// @Success 200 {object} entity.ResponseData{data=entity.CirculationReceiptResponse}
+// @Failure 400 {object} entity.ResponseError
+// @Failure 403 {object} entity.ResponseError
+// @Failure 404 {object} entity.ResponseError
+// @Failure 409 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/circulation/checkout [post]
+func (h *Handler) Checkout(c *gin.Context) {
+	var req entity.CheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.Checkout]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid request", Code: http.StatusBadRequest})
+		return
+	}
+
+	if err := h.deps.Validator.Struct(req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.Checkout]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: err.Error(), Code: http.StatusBadRequest})
+		return
+	}
+
+	receipt, err := h.deps.Service.Checkout(req)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			c.JSON(http.StatusNotFound, entity.ResponseError{Error: "patron or copy not found", Code: http.StatusNotFound})
+			return
+		}
+		if errors.Is(err, errmap.ErrmapInvalidStock) {
+			c.JSON(http.StatusConflict, entity.ResponseError{Error: "copy is not available", Code: http.StatusConflict})
+			return
+		}
+		var limitErr *errmap.LoanLimitError
+		if errors.As(err, &limitErr) {
+			c.JSON(http.StatusForbidden, entity.ResponseError{Error: limitErr.Error(), Code: http.StatusForbidden})
+			return
+		}
+		log.Error(errors.Wrap(err, "[Handler.Checkout]: unable to check out"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to check out", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatusJSON(http.StatusOK, entity.ResponseData{Data: receipt})
+}
+
+// Checkin takes back a copy at the circulation desk
+// @Summary Check in a copy
+// @Description Return a copy by its barcode, or by book ID when only one of its copies is on loan
+// @Tags management circulation
+// @Accept  json
+// @Produce  json
+// @Param   checkin  body      entity.CheckinRequest  true  "Check-in"
+// @Success 200 {object} entity.ResponseData{data=entity.CirculationReceiptResponse}
+// @Failure 400 {object} entity.ResponseError
+// @Failure 404 {object} entity.ResponseError
+// @Failure 409 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/circulation/checkin [post]
+func (h *Handler) Checkin(c *gin.Context) {
+	var req entity.CheckinRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.Checkin]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid request", Code: http.StatusBadRequest})
+		return
+	}
+
+	if err := h.deps.Validator.Struct(req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.Checkin]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: err.Error(), Code: http.StatusBadRequest})
+		return
+	}
+
+	receipt, err := h.deps.Service.Checkin(req)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			c.JSON(http.StatusNotFound, entity.ResponseError{Error: "copy not found", Code: http.StatusNotFound})
+			return
+		}
+		if errors.Is(err, errmap.ErrmapConflict) {
+			c.JSON(http.StatusConflict, entity.ResponseError{Error: "copy is not on loan", Code: http.StatusConflict})
+			return
+		}
+		if errors.Is(err, errmap.ErrmapAmbiguousCopy) {
+			c.JSON(http.StatusConflict, entity.ResponseError{Error: "more than one copy is on loan, scan the barcode", Code: http.StatusConflict})
+			return
+		}
+		log.Error(errors.Wrap(err, "[Handler.Checkin]: unable to check in"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to check in", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatusJSON(http.StatusOK, entity.ResponseData{Data: receipt})
+}
+
+// RegisterCirculationRoutes registers circulation desk routes
+func RegisterCirculationRoutes(router *gin.RouterGroup, handler *Handler) {
+	managementCirculationRoutes := router.Group("/management/circulation")
+	{
+		managementCirculationRoutes.Use(middleware.AuthMiddleware())
+		managementCirculationRoutes.Use(middleware.RoleMiddleware(constant.UserTypeStaff))
+
+		managementCirculationRoutes.POST("/checkout", handler.Checkout)
+		managementCirculationRoutes.POST("/checkin", handler.Checkin)
+	}
+}
@@ -0,0 +1,178 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	"go-library-service/cmd/api/handler"
+	"go-library-service/cmd/api/handler/mock"
+	"go-library-service/cmd/api/middleware"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Circulation Handler", func() {
+	var (
+		h           *handler.Handler
+		serviceMock *mock.MockService
+		r           *gin.Engine
+		ctrl        *gomock.Controller
+		testToken   string
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		serviceMock = mock.NewMockService(ctrl)
+		h = handler.NewHandler(&handler.Dependencies{
+			Service:   serviceMock,
+			Validator: validator.New(),
+		}, &handler.Config{})
+
+		gin.SetMode(gin.TestMode)
+		r = gin.Default()
+		handler.RegisterCirculationRoutes(r.Group("/api"), h)
+
+		var err error
+		testToken, err = middleware.GenerateToken(uint(1), constant.UserTypeStaff)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("Checkout", func() {
+		It("should check out a copy by card number", func() {
+			jsonValue, _ := json.Marshal(entity.CheckoutRequest{CardNumber: "C-0001", Barcode: "0001"})
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/circulation/checkout", bytes.NewBuffer(jsonValue))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().
+				Checkout(entity.CheckoutRequest{CardNumber: "C-0001", Barcode: "0001"}).
+				Return(&entity.CirculationReceiptResponse{HistoryID: 5, UserID: 2, Barcode: "0001", Status: constant.BorrowStatusBorrowed}, nil)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.Checkout(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var response map[string]map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response["data"]["historyId"]).To(BeEquivalentTo(5))
+		})
+
+		It("should return error without a patron", func() {
+			jsonValue, _ := json.Marshal(entity.CheckoutRequest{Barcode: "0001"})
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/circulation/checkout", bytes.NewBuffer(jsonValue))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.Checkout(c)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return conflict when the copy is not available", func() {
+			jsonValue, _ := json.Marshal(entity.CheckoutRequest{UserID: 2, Barcode: "0001"})
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/circulation/checkout", bytes.NewBuffer(jsonValue))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().Checkout(gomock.Any()).Return(nil, errmap.ErrmapInvalidStock)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.Checkout(c)
+
+			Expect(w.Code).To(Equal(http.StatusConflict))
+		})
+
+		It("should return forbidden when the loan limit is reached", func() {
+			jsonValue, _ := json.Marshal(entity.CheckoutRequest{UserID: 2, Barcode: "0001"})
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/circulation/checkout", bytes.NewBuffer(jsonValue))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().Checkout(gomock.Any()).Return(nil, &errmap.LoanLimitError{Limit: constant.LoanLimitActiveLoans, Max: 5})
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.Checkout(c)
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+		})
+	})
+
+	Context("Checkin", func() {
+		It("should check in a copy by book ID", func() {
+			jsonValue, _ := json.Marshal(entity.CheckinRequest{BookID: 1})
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/circulation/checkin", bytes.NewBuffer(jsonValue))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().
+				Checkin(entity.CheckinRequest{BookID: 1}).
+				Return(&entity.CirculationReceiptResponse{HistoryID: 5, Status: constant.BorrowStatusReturned}, nil)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.Checkin(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+		})
+
+		It("should return error without a barcode or book", func() {
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/circulation/checkin", bytes.NewBufferString(`{}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.Checkin(c)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return conflict when more than one copy is on loan", func() {
+			jsonValue, _ := json.Marshal(entity.CheckinRequest{BookID: 1})
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/circulation/checkin", bytes.NewBuffer(jsonValue))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().Checkin(gomock.Any()).Return(nil, errmap.ErrmapAmbiguousCopy)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.Checkin(c)
+
+			Expect(w.Code).To(Equal(http.StatusConflict))
+		})
+	})
+})
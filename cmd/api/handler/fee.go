@@ -0,0 +1,294 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	"go-library-service/cmd/api/middleware"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// GetMyFees gets fees of the current user
+// @Summary Get my fees
+// @Description Get the fee balance and ledger of the current user
+// @Tags fines
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} entity.ResponseData{data=entity.FeeBalanceResponse}
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /users/me/fines [get]
+func (h *Handler) GetMyFees(c *gin.Context) {
+	userID := h.getJWTInfo(c)
+
+	fees, err := h.deps.Service.GetUserFees(userID)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.GetMyFees]: unable to get fees"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to get fees", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatusJSON(http.StatusOK, entity.ResponseData{Data: fees})
+}
+
+// GetUserFees gets fees of a user
+// @Summary Get user fees
+// @Description Get the fee balance and ledger of a user
+// @Tags management fines
+// @Accept  json
+// @Produce  json
+// @Param   id   path      int  true  "User ID"
+// @Success 200 {object} entity.ResponseData{data=entity.FeeBalanceResponse}
+// @Failure 400 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/users/{id}/fines [get]
+func (h *Handler) GetUserFees(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.GetUserFees]: unable to convert user id"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid user id", Code: http.StatusBadRequest})
+		return
+	}
+
+	fees, err := h.deps.Service.GetUserFees(uint(userID))
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.GetUserFees]: unable to get fees"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to get fees", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatusJSON(http.StatusOK, entity.ResponseData{Data: fees})
+}
+
+// RecordFeePayment records a fee payment
+// @Summary Record a fee payment
+// @Description Record a payment against the fee balance of a user
+// @Tags management fines
+// @Accept  json
+// @Produce  json
+// @Param   id   path      int  true  "User ID"
+// @Param   payment  body      entity.FeePaymentRequest  true  "Fee payment"
+// @Success 201 {object} entity.ResponseData
+// @Failure 400 {object} entity.ResponseError
+// @Failure 404 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/users/{id}/fines/payments [post]
+func (h *Handler) RecordFeePayment(c *gin.Context) {
+	staffID := h.getJWTInfo(c)
+
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.RecordFeePayment]: unable to convert user id"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid user id", Code: http.StatusBadRequest})
+		return
+	}
+
+	var req entity.FeePaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.RecordFeePayment]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid request", Code: http.StatusBadRequest})
+		return
+	}
+
+	if err := h.deps.Validator.Struct(req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.RecordFeePayment]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: err.Error(), Code: http.StatusBadRequest})
+		return
+	}
+
+	req.UserID = uint(userID)
+	req.StaffID = staffID
+
+	if err := h.deps.Service.RecordFeePayment(req); err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			c.JSON(http.StatusNotFound, entity.ResponseError{Error: "user not found", Code: http.StatusNotFound})
+			return
+		}
+
+		if errors.Is(err, errmap.ErrmapInvalidAmount) {
+			c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "amount exceeds the outstanding balance", Code: http.StatusBadRequest})
+			return
+		}
+
+		log.Error(errors.Wrap(err, "[Handler.RecordFeePayment]: unable to record payment"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to record payment", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatus(http.StatusCreated)
+}
+
+// WaiveFees waives fees
+// @Summary Waive fees
+// @Description Waive part of the fee balance of a user
+// @Tags management fines
+// @Accept  json
+// @Produce  json
+// @Param   id   path      int  true  "User ID"
+// @Param   waiver  body      entity.FeeWaiverRequest  true  "Fee waiver"
+// @Success 201 {object} entity.ResponseData
+// @Failure 400 {object} entity.ResponseError
+// @Failure 404 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/users/{id}/fines/waivers [post]
+func (h *Handler) WaiveFees(c *gin.Context) {
+	staffID := h.getJWTInfo(c)
+
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.WaiveFees]: unable to convert user id"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid user id", Code: http.StatusBadRequest})
+		return
+	}
+
+	var req entity.FeeWaiverRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.WaiveFees]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid request", Code: http.StatusBadRequest})
+		return
+	}
+
+	if err := h.deps.Validator.Struct(req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.WaiveFees]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: err.Error(), Code: http.StatusBadRequest})
+		return
+	}
+
+	req.UserID = uint(userID)
+	req.StaffID = staffID
+
+	if err := h.deps.Service.WaiveFees(req); err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			c.JSON(http.StatusNotFound, entity.ResponseError{Error: "user not found", Code: http.StatusNotFound})
+			return
+		}
+
+		if errors.Is(err, errmap.ErrmapInvalidAmount) {
+			c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "amount exceeds the outstanding balance", Code: http.StatusBadRequest})
+			return
+		}
+
+		log.Error(errors.Wrap(err, "[Handler.WaiveFees]: unable to waive fees"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to waive fees", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatus(http.StatusCreated)
+}
+
+// ChargeReplacementCost charges the replacement cost of a book
+// @Summary Charge replacement cost
+// @Description Charge the borrower of a lost or damaged book the price of the book, once per borrow
+// @Tags management fines
+// @Accept  json
+// @Produce  json
+// @Param   charge  body      entity.ReplacementChargeRequest  true  "Replacement charge"
+// @Success 201 {object} entity.ResponseData{data=entity.FeeTransaction}
+// @Failure 400 {object} entity.ResponseError
+// @Failure 404 {object} entity.ResponseError
+// @Failure 409 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/fines/replacements [post]
+func (h *Handler) ChargeReplacementCost(c *gin.Context) {
+	staffID := h.getJWTInfo(c)
+
+	var req entity.ReplacementChargeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ChargeReplacementCost]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid request", Code: http.StatusBadRequest})
+		return
+	}
+
+	if err := h.deps.Validator.Struct(req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ChargeReplacementCost]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: err.Error(), Code: http.StatusBadRequest})
+		return
+	}
+
+	req.StaffID = staffID
+
+	transaction, err := h.deps.Service.ChargeReplacementCost(req)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			c.JSON(http.StatusNotFound, entity.ResponseError{Error: "borrow history not found", Code: http.StatusNotFound})
+			return
+		}
+
+		if errors.Is(err, errmap.ErrmapConflict) {
+			c.JSON(http.StatusConflict, entity.ResponseError{Error: "replacement cost already charged", Code: http.StatusConflict})
+			return
+		}
+
+		if errors.Is(err, errmap.ErrmapBorrowNotLost) {
+			c.JSON(http.StatusConflict, entity.ResponseError{Error: "book is not lost or damaged", Code: http.StatusConflict})
+			return
+		}
+
+		log.Error(errors.Wrap(err, "[Handler.ChargeReplacementCost]: unable to charge replacement cost"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to charge replacement cost", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatusJSON(http.StatusCreated, entity.ResponseData{Data: transaction})
+}
+
+// AccrueLateFees accrues late fees
+// @Summary Accrue late fees
+// @Description Charge the late fees accrued by every late borrow up to now
+// @Tags management fines
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} entity.ResponseData{data=entity.AccrueLateFeesResponse}
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /management/fines/accrue [post]
+func (h *Handler) AccrueLateFees(c *gin.Context) {
+	result, err := h.deps.Service.AccrueLateFees()
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.AccrueLateFees]: unable to accrue late fees"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to accrue late fees", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatusJSON(http.StatusOK, entity.ResponseData{Data: result})
+}
+
+// RegisterFeeRoutes registers fee routes
+func RegisterFeeRoutes(router *gin.RouterGroup, handler *Handler) {
+	userFeeRoutes := router.Group("/users/me/fines")
+	{
+		userFeeRoutes.Use(middleware.AuthMiddleware())
+		userFeeRoutes.Use(middleware.RoleMiddleware(constant.UserTypeUser, constant.UserTypeStaff))
+
+		userFeeRoutes.GET("", handler.GetMyFees)
+	}
+
+	managementUserFeeRoutes := router.Group("/management/users/:id/fines")
+	{
+		managementUserFeeRoutes.Use(middleware.AuthMiddleware())
+		managementUserFeeRoutes.Use(middleware.RoleMiddleware(constant.UserTypeStaff))
+
+		managementUserFeeRoutes.GET("", handler.GetUserFees)
+		managementUserFeeRoutes.POST("/payments", handler.RecordFeePayment)
+		managementUserFeeRoutes.POST("/waivers", handler.WaiveFees)
+	}
+
+	managementFeeRoutes := router.Group("/management/fines")
+	{
+		managementFeeRoutes.Use(middleware.AuthMiddleware())
+		managementFeeRoutes.Use(middleware.RoleMiddleware(constant.UserTypeStaff))
+
+		managementFeeRoutes.POST("/replacements", handler.ChargeReplacementCost)
+		managementFeeRoutes.POST("/accrue", handler.AccrueLateFees)
+	}
+}
@@ -0,0 +1,257 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	"go-library-service/cmd/api/handler"
+	"go-library-service/cmd/api/handler/mock"
+	"go-library-service/cmd/api/middleware"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Fee Handler", func() {
+	var (
+		h           *handler.Handler
+		serviceMock *mock.MockService
+		r           *gin.Engine
+		ctrl        *gomock.Controller
+		testToken   string
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		serviceMock = mock.NewMockService(ctrl)
+		h = handler.NewHandler(&handler.Dependencies{
+			Service:   serviceMock,
+			Validator: validator.New(),
+		}, &handler.Config{})
+
+		gin.SetMode(gin.TestMode)
+		r = gin.Default()
+		handler.RegisterFeeRoutes(r.Group("/api"), h)
+
+		var err error
+		testToken, err = middleware.GenerateToken(uint(1), constant.UserTypeStaff)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("GetMyFees", func() {
+		It("should get fees successfully", func() {
+			req, _ := http.NewRequest(http.MethodGet, "/api/users/me/fines", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().
+				GetUserFees(uint(1)).
+				Return(&entity.FeeBalanceResponse{UserID: 1, Balance: 15}, nil)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+			c.Set("userID", uint(1))
+
+			h.GetMyFees(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var response map[string]map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response["data"]["balance"]).To(BeEquivalentTo(15))
+		})
+
+		It("should return error when service fails", func() {
+			req, _ := http.NewRequest(http.MethodGet, "/api/users/me/fines", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().
+				GetUserFees(uint(1)).
+				Return(nil, errors.New("internal server error"))
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+			c.Set("userID", uint(1))
+
+			h.GetMyFees(c)
+
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	Context("RecordFeePayment", func() {
+		It("should record payment successfully", func() {
+			jsonValue, _ := json.Marshal(entity.FeePaymentRequest{Amount: 10, Note: "cash"})
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/users/2/fines/payments", bytes.NewBuffer(jsonValue))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().
+				RecordFeePayment(entity.FeePaymentRequest{UserID: 2, Amount: 10, Note: "cash", StaffID: 1}).
+				Return(nil)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "2"})
+			c.Request = req
+			c.Set("userID", uint(1))
+
+			h.RecordFeePayment(c)
+
+			Expect(w.Code).To(Equal(http.StatusCreated))
+		})
+
+		It("should return bad request when amount exceeds the balance", func() {
+			jsonValue, _ := json.Marshal(entity.FeePaymentRequest{Amount: 1000})
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/users/2/fines/payments", bytes.NewBuffer(jsonValue))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().
+				RecordFeePayment(gomock.Any()).
+				Return(errmap.ErrmapInvalidAmount)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "2"})
+			c.Request = req
+			c.Set("userID", uint(1))
+
+			h.RecordFeePayment(c)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return error for invalid amount", func() {
+			jsonValue, _ := json.Marshal(entity.FeePaymentRequest{Amount: -5})
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/users/2/fines/payments", bytes.NewBuffer(jsonValue))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "2"})
+			c.Request = req
+			c.Set("userID", uint(1))
+
+			h.RecordFeePayment(c)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Context("WaiveFees", func() {
+		It("should waive fees successfully", func() {
+			jsonValue, _ := json.Marshal(entity.FeeWaiverRequest{Amount: 5, Note: "goodwill"})
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/users/2/fines/waivers", bytes.NewBuffer(jsonValue))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().
+				WaiveFees(gomock.Any()).
+				Return(nil)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "2"})
+			c.Request = req
+			c.Set("userID", uint(1))
+
+			h.WaiveFees(c)
+
+			Expect(w.Code).To(Equal(http.StatusCreated))
+		})
+
+		It("should require a note", func() {
+			jsonValue, _ := json.Marshal(entity.FeeWaiverRequest{Amount: 5})
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/users/2/fines/waivers", bytes.NewBuffer(jsonValue))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "2"})
+			c.Request = req
+			c.Set("userID", uint(1))
+
+			h.WaiveFees(c)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Context("ChargeReplacementCost", func() {
+		It("should return conflict when already charged", func() {
+			jsonValue, _ := json.Marshal(entity.ReplacementChargeRequest{BorrowHistoryID: 3})
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/fines/replacements", bytes.NewBuffer(jsonValue))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().
+				ChargeReplacementCost(entity.ReplacementChargeRequest{BorrowHistoryID: 3, StaffID: 1}).
+				Return(nil, errmap.ErrmapConflict)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+			c.Set("userID", uint(1))
+
+			h.ChargeReplacementCost(c)
+
+			Expect(w.Code).To(Equal(http.StatusConflict))
+		})
+
+		It("should return conflict when the book is not lost or damaged", func() {
+			jsonValue, _ := json.Marshal(entity.ReplacementChargeRequest{BorrowHistoryID: 3})
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/fines/replacements", bytes.NewBuffer(jsonValue))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().
+				ChargeReplacementCost(entity.ReplacementChargeRequest{BorrowHistoryID: 3, StaffID: 1}).
+				Return(nil, errmap.ErrmapBorrowNotLost)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+			c.Set("userID", uint(1))
+
+			h.ChargeReplacementCost(c)
+
+			Expect(w.Code).To(Equal(http.StatusConflict))
+		})
+	})
+
+	Context("AccrueLateFees", func() {
+		It("should accrue late fees successfully", func() {
+			req, _ := http.NewRequest(http.MethodPost, "/api/management/fines/accrue", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().
+				AccrueLateFees().
+				Return(&entity.AccrueLateFeesResponse{Charged: 2}, nil)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.AccrueLateFees(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+		})
+	})
+})
@@ -3,6 +3,8 @@ package handler
 import (
 	"errors"
 	"go-library-service/cmd/api/entity"
+	"io"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -23,16 +25,53 @@ type Dependencies struct {
 }
 
 // Config is a configuration of handler
-type Config struct{}
+type Config struct {
+	// OAIRepositoryName is the name OAI-PMH harvesters and SRU clients know the catalog by
+	OAIRepositoryName string
+	// OAIRepositoryIdentifier is the domain name in the OAI identifiers of books, defaults to the host of a request
+	OAIRepositoryIdentifier string
+	// OAIAdminEmail is the address OAI-PMH harvesters write to about the catalog
+	OAIAdminEmail string
+}
 
 // Service manages the service layer
 type Service interface {
 	// Book
 	CreateBook(request entity.BookCreateRequest) error
-	ListBook(req entity.ListBookRequest) ([]entity.BookResponse, error)
+	ListBook(req entity.ListBookRequest) (*entity.ListBookResult, error)
 	GetBookByID(bookID uint) (*entity.BookResponse, error)
+	GetBookByISBN(isbn string) (*entity.BookResponse, error)
 	UpdateBook(req entity.BookUpdateRequest) error
-	ListLatestBooks() ([]entity.BookResponse, error)
+	ImportBooks(rows []entity.BookImportRow, req entity.BookImportRequest) (*entity.BookImportReport, error)
+	ExportBooks(req entity.ListBookRequest, fn func(books []entity.BookExport) error) error
+	ListLatestBooks(req entity.ListLatestBookRequest) ([]entity.BookResponse, error)
+	ArchiveBook(bookID uint) error
+	RestoreBook(bookID uint) error
+	ListBookChanges(req entity.ListBookChangeRequest) (*entity.ListBookChangeResult, error)
+	GetEarliestBookChange() (*time.Time, error)
+	SearchBooks(req entity.SearchBookRequest) (*entity.SearchBookResult, error)
+	UploadBookCover(bookID uint, file io.Reader) (*entity.BookCoverResponse, error)
+	DeleteBookCover(bookID uint) error
+
+	// Author
+	CreateAuthor(req entity.AuthorCreateRequest) (*entity.AuthorResponse, error)
+	ListAuthors(req entity.ListAuthorRequest) (*entity.ListAuthorResult, error)
+	GetAuthor(authorID uint) (*entity.AuthorDetailResponse, error)
+	UpdateAuthor(req entity.AuthorUpdateRequest) error
+	DeleteAuthor(authorID uint) error
+
+	// Category
+	CreateCategory(req entity.CategoryCreateRequest) (*entity.CategoryResponse, error)
+	ListCategories() ([]entity.CategoryResponse, error)
+	UpdateCategory(req entity.CategoryUpdateRequest) error
+	DeleteCategory(categoryID uint) error
+
+	// BookCopy
+	AddBookCopy(req entity.BookCopyCreateRequest) (*entity.BookCopyResponse, error)
+	ListBookCopies(bookID uint) ([]entity.BookCopyResponse, error)
+	GetBookCopyByBarcode(barcode string) (*entity.BookCopyResponse, error)
+	UpdateBookCopy(req entity.BookCopyUpdateRequest) error
+	RetireBookCopy(copyID uint) error
 
 	// User
 	CreateUser(user entity.UserCreateRequest) (*uint, error)
@@ -40,11 +79,35 @@ type Service interface {
 	LoginUser(username, password string) (*entity.User, error)
 	UpdateUser(user entity.UserUpdateRequest) error
 	DeleteUser(userID uint) error
+	AssignCardNumber(req entity.UserCardRequest) error
 
 	// Borrow
 	BorrowBook(req entity.BorrowBookRequest) (*entity.BorrowHistory, error)
 	ReturnBook(req entity.ReturnBookRequest) error
+	RenewBook(req entity.RenewBookRequest) (*entity.BorrowHistoryResponse, error)
 	GetBookBorrowHistory(bookID uint) ([]entity.BorrowHistoryResponse, error)
+	ListOverdueBorrows(req entity.ListOverdueBorrowRequest) (*entity.ListOverdueBorrowResult, error)
+	ListUserLoans(userID uint) ([]entity.UserLoanResponse, error)
+	ListUserBorrowHistory(req entity.ListUserBorrowHistoryRequest) (*entity.ListUserBorrowHistoryResult, error)
+	MarkBookLost(req entity.LostBookRequest) (*entity.BorrowHistoryResponse, error)
+	ReturnBookDamaged(req entity.DamagedBookRequest) (*entity.BorrowHistoryResponse, error)
+	MarkBookFound(req entity.FoundBookRequest) (*entity.BorrowHistoryResponse, error)
+
+	// Circulation
+	Checkout(req entity.CheckoutRequest) (*entity.CirculationReceiptResponse, error)
+	Checkin(req entity.CheckinRequest) (*entity.CirculationReceiptResponse, error)
+
+	// Hold
+	PlaceHold(req entity.PlaceHoldRequest) (*entity.HoldResponse, error)
+	ListUserHolds(userID uint) ([]entity.HoldResponse, error)
+	CancelHold(req entity.CancelHoldRequest) error
+
+	// Fee
+	GetUserFees(userID uint) (*entity.FeeBalanceResponse, error)
+	RecordFeePayment(req entity.FeePaymentRequest) error
+	WaiveFees(req entity.FeeWaiverRequest) error
+	ChargeReplacementCost(req entity.ReplacementChargeRequest) (*entity.FeeTransaction, error)
+	AccrueLateFees() (*entity.AccrueLateFeesResponse, error)
 }
 
 // NewHandler creates a new handler
@@ -75,6 +138,16 @@ func InitRoute(router *gin.RouterGroup, handler *Handler) error {
 
 	RegisterUserRoutes(router, handler)
 	RegisterBookRoutes(router, handler)
+	RegisterAuthorRoutes(router, handler)
+	RegisterCategoryRoutes(router, handler)
+	RegisterBookCopyRoutes(router, handler)
+	RegisterBorrowHistoryRoutes(router, handler)
+	RegisterHoldRoutes(router, handler)
+	RegisterFeeRoutes(router, handler)
+	RegisterCirculationRoutes(router, handler)
+	RegisterOPDSRoutes(router, handler)
+	RegisterOAIRoutes(router, handler)
+	RegisterSRURoutes(router, handler)
 	
 	return nil
 }
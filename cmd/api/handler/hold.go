@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	"go-library-service/cmd/api/middleware"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// PlaceHold places a hold on a book
+// @Summary Place a hold on a book
+// @Description Join the reservation queue of an out of stock book
+// @Tags holds
+// @Accept  json
+// @Produce  json
+// @Param   id   path      int  true  "Book ID"
+// @Param   hold  body      entity.PlaceHoldRequest  true  "Place hold"
+// @Success 201 {object} entity.ResponseData{data=entity.HoldResponse}
+// @Failure 400 {object} entity.ResponseError
+// @Failure 404 {object} entity.ResponseError
+// @Failure 409 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /books/{id}/holds [post]
+func (h *Handler) PlaceHold(c *gin.Context) {
+	userID := h.getJWTInfo(c)
+
+	var req entity.PlaceHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.PlaceHold]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid request", Code: http.StatusBadRequest})
+		return
+	}
+
+	if err := h.deps.Validator.Struct(req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.PlaceHold]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: err.Error(), Code: http.StatusBadRequest})
+		return
+	}
+
+	req.UserID = userID
+
+	hold, err := h.deps.Service.PlaceHold(req)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			c.JSON(http.StatusNotFound, entity.ResponseError{Error: "book not found", Code: http.StatusNotFound})
+			return
+		}
+
+		if errors.Is(err, errmap.ErrmapBookAvailable) {
+			c.JSON(http.StatusConflict, entity.ResponseError{Error: "book is available to borrow", Code: http.StatusConflict})
+			return
+		}
+
+		if errors.Is(err, errmap.ErrmapConflict) {
+			c.JSON(http.StatusConflict, entity.ResponseError{Error: "hold already placed", Code: http.StatusConflict})
+			return
+		}
+
+		log.Error(errors.Wrap(err, "[Handler.PlaceHold]: unable to place hold"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to place hold", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatusJSON(http.StatusCreated, entity.ResponseData{Data: hold})
+}
+
+// ListMyHolds lists holds of the current user
+// @Summary List my holds
+// @Description Get the active holds of the current user with their queue position
+// @Tags holds
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} entity.ResponseData{data=[]entity.HoldResponse}
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /users/me/holds [get]
+func (h *Handler) ListMyHolds(c *gin.Context) {
+	userID := h.getJWTInfo(c)
+
+	holds, err := h.deps.Service.ListUserHolds(userID)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.ListMyHolds]: unable to list holds"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to list holds", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatusJSON(http.StatusOK, entity.ResponseData{Data: holds})
+}
+
+// CancelMyHold cancels a hold of the current user
+// @Summary Cancel my hold
+// @Description Cancel an active hold of the current user
+// @Tags holds
+// @Accept  json
+// @Produce  json
+// @Param   id   path      int  true  "Hold ID"
+// @Success 200 {object} entity.ResponseData
+// @Failure 400 {object} entity.ResponseError
+// @Failure 404 {object} entity.ResponseError
+// @Failure 409 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Security BearerAuth
+// @Router /users/me/holds/{id} [delete]
+func (h *Handler) CancelMyHold(c *gin.Context) {
+	userID := h.getJWTInfo(c)
+
+	holdID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.CancelMyHold]: unable to convert hold id"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid hold id", Code: http.StatusBadRequest})
+		return
+	}
+
+	req := entity.CancelHoldRequest{
+		HoldID: uint(holdID),
+		UserID: userID,
+	}
+
+	if err := h.deps.Service.CancelHold(req); err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			c.JSON(http.StatusNotFound, entity.ResponseError{Error: "hold not found", Code: http.StatusNotFound})
+			return
+		}
+
+		if errors.Is(err, errmap.ErrmapConflict) {
+			c.JSON(http.StatusConflict, entity.ResponseError{Error: "hold is not active", Code: http.StatusConflict})
+			return
+		}
+
+		log.Error(errors.Wrap(err, "[Handler.CancelMyHold]: unable to cancel hold"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to cancel hold", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatus(http.StatusOK)
+}
+
+// RegisterHoldRoutes registers hold routes
+func RegisterHoldRoutes(router *gin.RouterGroup, handler *Handler) {
+	bookHoldRoutes := router.Group("/books")
+	{
+		bookHoldRoutes.Use(middleware.AuthMiddleware())
+		bookHoldRoutes.Use(middleware.RoleMiddleware(constant.UserTypeUser, constant.UserTypeStaff))
+
+		bookHoldRoutes.POST("/:id/holds", handler.PlaceHold)
+	}
+
+	userHoldRoutes := router.Group("/users/me/holds")
+	{
+		userHoldRoutes.Use(middleware.AuthMiddleware())
+		userHoldRoutes.Use(middleware.RoleMiddleware(constant.UserTypeUser, constant.UserTypeStaff))
+
+		userHoldRoutes.GET("", handler.ListMyHolds)
+		userHoldRoutes.DELETE("/:id", handler.CancelMyHold)
+	}
+}
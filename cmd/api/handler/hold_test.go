@@ -0,0 +1,208 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	"go-library-service/cmd/api/handler"
+	"go-library-service/cmd/api/handler/mock"
+	"go-library-service/cmd/api/middleware"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Hold Handler", func() {
+	var (
+		h           *handler.Handler
+		serviceMock *mock.MockService
+		r           *gin.Engine
+		ctrl        *gomock.Controller
+		testToken   string
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		serviceMock = mock.NewMockService(ctrl)
+		h = handler.NewHandler(&handler.Dependencies{
+			Service:   serviceMock,
+			Validator: validator.New(),
+		}, &handler.Config{})
+
+		gin.SetMode(gin.TestMode)
+		r = gin.Default()
+		handler.RegisterHoldRoutes(r.Group("/api"), h)
+
+		var err error
+		testToken, err = middleware.GenerateToken(uint(1), constant.UserTypeUser)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("PlaceHold", func() {
+		It("should place hold successfully", func() {
+			jsonValue, _ := json.Marshal(entity.PlaceHoldRequest{BookID: 1})
+			req, _ := http.NewRequest(http.MethodPost, "/api/books/1/holds", bytes.NewBuffer(jsonValue))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().
+				PlaceHold(entity.PlaceHoldRequest{BookID: 1, UserID: 1}).
+				Return(&entity.HoldResponse{ID: 1, BookID: 1, UserID: 1, Status: constant.HoldStatusWaiting, QueuePosition: 2}, nil)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "1"})
+			c.Request = req
+			c.Set("userID", uint(1))
+
+			h.PlaceHold(c)
+
+			Expect(w.Code).To(Equal(http.StatusCreated))
+			var response map[string]map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response["data"]["queuePosition"]).To(BeEquivalentTo(2))
+		})
+
+		It("should return conflict when book is available", func() {
+			jsonValue, _ := json.Marshal(entity.PlaceHoldRequest{BookID: 1})
+			req, _ := http.NewRequest(http.MethodPost, "/api/books/1/holds", bytes.NewBuffer(jsonValue))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().
+				PlaceHold(gomock.Any()).
+				Return(nil, errmap.ErrmapBookAvailable)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+			c.Set("userID", uint(1))
+
+			h.PlaceHold(c)
+
+			Expect(w.Code).To(Equal(http.StatusConflict))
+		})
+
+		It("should return error for invalid request", func() {
+			req, _ := http.NewRequest(http.MethodPost, "/api/books/1/holds", bytes.NewBuffer([]byte(`{}`)))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+			c.Set("userID", uint(1))
+
+			h.PlaceHold(c)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Context("ListMyHolds", func() {
+		It("should list holds successfully", func() {
+			req, _ := http.NewRequest(http.MethodGet, "/api/users/me/holds", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().
+				ListUserHolds(uint(1)).
+				Return([]entity.HoldResponse{{ID: 1, BookID: 1, UserID: 1, Status: constant.HoldStatusReady}}, nil)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+			c.Set("userID", uint(1))
+
+			h.ListMyHolds(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+		})
+
+		It("should return error when service fails", func() {
+			req, _ := http.NewRequest(http.MethodGet, "/api/users/me/holds", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().
+				ListUserHolds(uint(1)).
+				Return(nil, errors.New("internal server error"))
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+			c.Set("userID", uint(1))
+
+			h.ListMyHolds(c)
+
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	Context("CancelMyHold", func() {
+		It("should cancel hold successfully", func() {
+			req, _ := http.NewRequest(http.MethodDelete, "/api/users/me/holds/1", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().
+				CancelHold(entity.CancelHoldRequest{HoldID: 1, UserID: 1}).
+				Return(nil)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "1"})
+			c.Request = req
+			c.Set("userID", uint(1))
+
+			h.CancelMyHold(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+		})
+
+		It("should return error for invalid hold id", func() {
+			req, _ := http.NewRequest(http.MethodDelete, "/api/users/me/holds/invalid", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "invalid"})
+			c.Request = req
+			c.Set("userID", uint(1))
+
+			h.CancelMyHold(c)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return not found when hold doesn't exist", func() {
+			req, _ := http.NewRequest(http.MethodDelete, "/api/users/me/holds/999", nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+
+			serviceMock.EXPECT().
+				CancelHold(gomock.Any()).
+				Return(errmap.ErrmapNotFound)
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "999"})
+			c.Request = req
+			c.Set("userID", uint(1))
+
+			h.CancelMyHold(c)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+})
@@ -6,7 +6,9 @@ package mock
 
 import (
 	entity "go-library-service/cmd/api/entity"
+	io "io"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -34,6 +36,64 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// AccrueLateFees mocks base method.
+func (m *MockService) AccrueLateFees() (*entity.AccrueLateFeesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccrueLateFees")
+	ret0, _ := ret[0].(*entity.AccrueLateFeesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccrueLateFees indicates an expected call of AccrueLateFees.
+func (mr *MockServiceMockRecorder) AccrueLateFees() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccrueLateFees", reflect.TypeOf((*MockService)(nil).AccrueLateFees))
+}
+
+// AddBookCopy mocks base method.
+func (m *MockService) AddBookCopy(req entity.BookCopyCreateRequest) (*entity.BookCopyResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddBookCopy", req)
+	ret0, _ := ret[0].(*entity.BookCopyResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddBookCopy indicates an expected call of AddBookCopy.
+func (mr *MockServiceMockRecorder) AddBookCopy(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBookCopy", reflect.TypeOf((*MockService)(nil).AddBookCopy), req)
+}
+
+// ArchiveBook mocks base method.
+func (m *MockService) ArchiveBook(bookID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveBook", bookID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ArchiveBook indicates an expected call of ArchiveBook.
+func (mr *MockServiceMockRecorder) ArchiveBook(bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveBook", reflect.TypeOf((*MockService)(nil).ArchiveBook), bookID)
+}
+
+// AssignCardNumber mocks base method.
+func (m *MockService) AssignCardNumber(req entity.UserCardRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignCardNumber", req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignCardNumber indicates an expected call of AssignCardNumber.
+func (mr *MockServiceMockRecorder) AssignCardNumber(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignCardNumber", reflect.TypeOf((*MockService)(nil).AssignCardNumber), req)
+}
+
 // BorrowBook mocks base method.
 func (m *MockService) BorrowBook(req entity.BorrowBookRequest) (*entity.BorrowHistory, error) {
 	m.ctrl.T.Helper()
@@ -49,6 +109,80 @@ func (mr *MockServiceMockRecorder) BorrowBook(req interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BorrowBook", reflect.TypeOf((*MockService)(nil).BorrowBook), req)
 }
 
+// CancelHold mocks base method.
+func (m *MockService) CancelHold(req entity.CancelHoldRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelHold", req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelHold indicates an expected call of CancelHold.
+func (mr *MockServiceMockRecorder) CancelHold(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelHold", reflect.TypeOf((*MockService)(nil).CancelHold), req)
+}
+
+// ChargeReplacementCost mocks base method.
+func (m *MockService) ChargeReplacementCost(req entity.ReplacementChargeRequest) (*entity.FeeTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChargeReplacementCost", req)
+	ret0, _ := ret[0].(*entity.FeeTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChargeReplacementCost indicates an expected call of ChargeReplacementCost.
+func (mr *MockServiceMockRecorder) ChargeReplacementCost(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChargeReplacementCost", reflect.TypeOf((*MockService)(nil).ChargeReplacementCost), req)
+}
+
+// Checkin mocks base method.
+func (m *MockService) Checkin(req entity.CheckinRequest) (*entity.CirculationReceiptResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Checkin", req)
+	ret0, _ := ret[0].(*entity.CirculationReceiptResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Checkin indicates an expected call of Checkin.
+func (mr *MockServiceMockRecorder) Checkin(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Checkin", reflect.TypeOf((*MockService)(nil).Checkin), req)
+}
+
+// Checkout mocks base method.
+func (m *MockService) Checkout(req entity.CheckoutRequest) (*entity.CirculationReceiptResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Checkout", req)
+	ret0, _ := ret[0].(*entity.CirculationReceiptResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Checkout indicates an expected call of Checkout.
+func (mr *MockServiceMockRecorder) Checkout(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Checkout", reflect.TypeOf((*MockService)(nil).Checkout), req)
+}
+
+// CreateAuthor mocks base method.
+func (m *MockService) CreateAuthor(req entity.AuthorCreateRequest) (*entity.AuthorResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuthor", req)
+	ret0, _ := ret[0].(*entity.AuthorResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuthor indicates an expected call of CreateAuthor.
+func (mr *MockServiceMockRecorder) CreateAuthor(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuthor", reflect.TypeOf((*MockService)(nil).CreateAuthor), req)
+}
+
 // CreateBook mocks base method.
 func (m *MockService) CreateBook(request entity.BookCreateRequest) error {
 	m.ctrl.T.Helper()
@@ -63,6 +197,21 @@ func (mr *MockServiceMockRecorder) CreateBook(request interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBook", reflect.TypeOf((*MockService)(nil).CreateBook), request)
 }
 
+// CreateCategory mocks base method.
+func (m *MockService) CreateCategory(req entity.CategoryCreateRequest) (*entity.CategoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCategory", req)
+	ret0, _ := ret[0].(*entity.CategoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCategory indicates an expected call of CreateCategory.
+func (mr *MockServiceMockRecorder) CreateCategory(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCategory", reflect.TypeOf((*MockService)(nil).CreateCategory), req)
+}
+
 // CreateUser mocks base method.
 func (m *MockService) CreateUser(user entity.UserCreateRequest) (*uint, error) {
 	m.ctrl.T.Helper()
@@ -78,6 +227,48 @@ func (mr *MockServiceMockRecorder) CreateUser(user interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockService)(nil).CreateUser), user)
 }
 
+// DeleteAuthor mocks base method.
+func (m *MockService) DeleteAuthor(authorID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAuthor", authorID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAuthor indicates an expected call of DeleteAuthor.
+func (mr *MockServiceMockRecorder) DeleteAuthor(authorID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAuthor", reflect.TypeOf((*MockService)(nil).DeleteAuthor), authorID)
+}
+
+// DeleteBookCover mocks base method.
+func (m *MockService) DeleteBookCover(bookID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBookCover", bookID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBookCover indicates an expected call of DeleteBookCover.
+func (mr *MockServiceMockRecorder) DeleteBookCover(bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBookCover", reflect.TypeOf((*MockService)(nil).DeleteBookCover), bookID)
+}
+
+// DeleteCategory mocks base method.
+func (m *MockService) DeleteCategory(categoryID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCategory", categoryID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCategory indicates an expected call of DeleteCategory.
+func (mr *MockServiceMockRecorder) DeleteCategory(categoryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCategory", reflect.TypeOf((*MockService)(nil).DeleteCategory), categoryID)
+}
+
 // DeleteUser mocks base method.
 func (m *MockService) DeleteUser(userID uint) error {
 	m.ctrl.T.Helper()
@@ -92,6 +283,35 @@ func (mr *MockServiceMockRecorder) DeleteUser(userID interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockService)(nil).DeleteUser), userID)
 }
 
+// ExportBooks mocks base method.
+func (m *MockService) ExportBooks(req entity.ListBookRequest, fn func([]entity.BookExport) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportBooks", req, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportBooks indicates an expected call of ExportBooks.
+func (mr *MockServiceMockRecorder) ExportBooks(req, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportBooks", reflect.TypeOf((*MockService)(nil).ExportBooks), req, fn)
+}
+
+// GetAuthor mocks base method.
+func (m *MockService) GetAuthor(authorID uint) (*entity.AuthorDetailResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthor", authorID)
+	ret0, _ := ret[0].(*entity.AuthorDetailResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthor indicates an expected call of GetAuthor.
+func (mr *MockServiceMockRecorder) GetAuthor(authorID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthor", reflect.TypeOf((*MockService)(nil).GetAuthor), authorID)
+}
+
 // GetBookBorrowHistory mocks base method.
 func (m *MockService) GetBookBorrowHistory(bookID uint) ([]entity.BorrowHistoryResponse, error) {
 	m.ctrl.T.Helper()
@@ -122,6 +342,51 @@ func (mr *MockServiceMockRecorder) GetBookByID(bookID interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookByID", reflect.TypeOf((*MockService)(nil).GetBookByID), bookID)
 }
 
+// GetBookByISBN mocks base method.
+func (m *MockService) GetBookByISBN(isbn string) (*entity.BookResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookByISBN", isbn)
+	ret0, _ := ret[0].(*entity.BookResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookByISBN indicates an expected call of GetBookByISBN.
+func (mr *MockServiceMockRecorder) GetBookByISBN(isbn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookByISBN", reflect.TypeOf((*MockService)(nil).GetBookByISBN), isbn)
+}
+
+// GetBookCopyByBarcode mocks base method.
+func (m *MockService) GetBookCopyByBarcode(barcode string) (*entity.BookCopyResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookCopyByBarcode", barcode)
+	ret0, _ := ret[0].(*entity.BookCopyResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookCopyByBarcode indicates an expected call of GetBookCopyByBarcode.
+func (mr *MockServiceMockRecorder) GetBookCopyByBarcode(barcode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookCopyByBarcode", reflect.TypeOf((*MockService)(nil).GetBookCopyByBarcode), barcode)
+}
+
+// GetEarliestBookChange mocks base method.
+func (m *MockService) GetEarliestBookChange() (*time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEarliestBookChange")
+	ret0, _ := ret[0].(*time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEarliestBookChange indicates an expected call of GetEarliestBookChange.
+func (mr *MockServiceMockRecorder) GetEarliestBookChange() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEarliestBookChange", reflect.TypeOf((*MockService)(nil).GetEarliestBookChange))
+}
+
 // GetUserByID mocks base method.
 func (m *MockService) GetUserByID(userID uint) (*entity.UserResponse, error) {
 	m.ctrl.T.Helper()
@@ -137,11 +402,56 @@ func (mr *MockServiceMockRecorder) GetUserByID(userID interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockService)(nil).GetUserByID), userID)
 }
 
+// GetUserFees mocks base method.
+func (m *MockService) GetUserFees(userID uint) (*entity.FeeBalanceResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserFees", userID)
+	ret0, _ := ret[0].(*entity.FeeBalanceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserFees indicates an expected call of GetUserFees.
+func (mr *MockServiceMockRecorder) GetUserFees(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserFees", reflect.TypeOf((*MockService)(nil).GetUserFees), userID)
+}
+
+// ImportBooks mocks base method.
+func (m *MockService) ImportBooks(rows []entity.BookImportRow, req entity.BookImportRequest) (*entity.BookImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportBooks", rows, req)
+	ret0, _ := ret[0].(*entity.BookImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportBooks indicates an expected call of ImportBooks.
+func (mr *MockServiceMockRecorder) ImportBooks(rows, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportBooks", reflect.TypeOf((*MockService)(nil).ImportBooks), rows, req)
+}
+
+// ListAuthors mocks base method.
+func (m *MockService) ListAuthors(req entity.ListAuthorRequest) (*entity.ListAuthorResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuthors", req)
+	ret0, _ := ret[0].(*entity.ListAuthorResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuthors indicates an expected call of ListAuthors.
+func (mr *MockServiceMockRecorder) ListAuthors(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuthors", reflect.TypeOf((*MockService)(nil).ListAuthors), req)
+}
+
 // ListBook mocks base method.
-func (m *MockService) ListBook(req entity.ListBookRequest) ([]entity.BookResponse, error) {
+func (m *MockService) ListBook(req entity.ListBookRequest) (*entity.ListBookResult, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListBook", req)
-	ret0, _ := ret[0].([]entity.BookResponse)
+	ret0, _ := ret[0].(*entity.ListBookResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -152,19 +462,124 @@ func (mr *MockServiceMockRecorder) ListBook(req interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBook", reflect.TypeOf((*MockService)(nil).ListBook), req)
 }
 
+// ListBookChanges mocks base method.
+func (m *MockService) ListBookChanges(req entity.ListBookChangeRequest) (*entity.ListBookChangeResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBookChanges", req)
+	ret0, _ := ret[0].(*entity.ListBookChangeResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBookChanges indicates an expected call of ListBookChanges.
+func (mr *MockServiceMockRecorder) ListBookChanges(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBookChanges", reflect.TypeOf((*MockService)(nil).ListBookChanges), req)
+}
+
+// ListBookCopies mocks base method.
+func (m *MockService) ListBookCopies(bookID uint) ([]entity.BookCopyResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBookCopies", bookID)
+	ret0, _ := ret[0].([]entity.BookCopyResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBookCopies indicates an expected call of ListBookCopies.
+func (mr *MockServiceMockRecorder) ListBookCopies(bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBookCopies", reflect.TypeOf((*MockService)(nil).ListBookCopies), bookID)
+}
+
+// ListCategories mocks base method.
+func (m *MockService) ListCategories() ([]entity.CategoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCategories")
+	ret0, _ := ret[0].([]entity.CategoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCategories indicates an expected call of ListCategories.
+func (mr *MockServiceMockRecorder) ListCategories() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCategories", reflect.TypeOf((*MockService)(nil).ListCategories))
+}
+
 // ListLatestBooks mocks base method.
-func (m *MockService) ListLatestBooks() ([]entity.BookResponse, error) {
+func (m *MockService) ListLatestBooks(req entity.ListLatestBookRequest) ([]entity.BookResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListLatestBooks")
+	ret := m.ctrl.Call(m, "ListLatestBooks", req)
 	ret0, _ := ret[0].([]entity.BookResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListLatestBooks indicates an expected call of ListLatestBooks.
-func (mr *MockServiceMockRecorder) ListLatestBooks() *gomock.Call {
+func (mr *MockServiceMockRecorder) ListLatestBooks(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLatestBooks", reflect.TypeOf((*MockService)(nil).ListLatestBooks), req)
+}
+
+// ListOverdueBorrows mocks base method.
+func (m *MockService) ListOverdueBorrows(req entity.ListOverdueBorrowRequest) (*entity.ListOverdueBorrowResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOverdueBorrows", req)
+	ret0, _ := ret[0].(*entity.ListOverdueBorrowResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOverdueBorrows indicates an expected call of ListOverdueBorrows.
+func (mr *MockServiceMockRecorder) ListOverdueBorrows(req interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLatestBooks", reflect.TypeOf((*MockService)(nil).ListLatestBooks))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOverdueBorrows", reflect.TypeOf((*MockService)(nil).ListOverdueBorrows), req)
+}
+
+// ListUserBorrowHistory mocks base method.
+func (m *MockService) ListUserBorrowHistory(req entity.ListUserBorrowHistoryRequest) (*entity.ListUserBorrowHistoryResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserBorrowHistory", req)
+	ret0, _ := ret[0].(*entity.ListUserBorrowHistoryResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserBorrowHistory indicates an expected call of ListUserBorrowHistory.
+func (mr *MockServiceMockRecorder) ListUserBorrowHistory(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserBorrowHistory", reflect.TypeOf((*MockService)(nil).ListUserBorrowHistory), req)
+}
+
+// ListUserHolds mocks base method.
+func (m *MockService) ListUserHolds(userID uint) ([]entity.HoldResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserHolds", userID)
+	ret0, _ := ret[0].([]entity.HoldResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserHolds indicates an expected call of ListUserHolds.
+func (mr *MockServiceMockRecorder) ListUserHolds(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserHolds", reflect.TypeOf((*MockService)(nil).ListUserHolds), userID)
+}
+
+// ListUserLoans mocks base method.
+func (m *MockService) ListUserLoans(userID uint) ([]entity.UserLoanResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserLoans", userID)
+	ret0, _ := ret[0].([]entity.UserLoanResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserLoans indicates an expected call of ListUserLoans.
+func (mr *MockServiceMockRecorder) ListUserLoans(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserLoans", reflect.TypeOf((*MockService)(nil).ListUserLoans), userID)
 }
 
 // LoginUser mocks base method.
@@ -182,6 +597,108 @@ func (mr *MockServiceMockRecorder) LoginUser(username, password interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginUser", reflect.TypeOf((*MockService)(nil).LoginUser), username, password)
 }
 
+// MarkBookFound mocks base method.
+func (m *MockService) MarkBookFound(req entity.FoundBookRequest) (*entity.BorrowHistoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkBookFound", req)
+	ret0, _ := ret[0].(*entity.BorrowHistoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkBookFound indicates an expected call of MarkBookFound.
+func (mr *MockServiceMockRecorder) MarkBookFound(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkBookFound", reflect.TypeOf((*MockService)(nil).MarkBookFound), req)
+}
+
+// MarkBookLost mocks base method.
+func (m *MockService) MarkBookLost(req entity.LostBookRequest) (*entity.BorrowHistoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkBookLost", req)
+	ret0, _ := ret[0].(*entity.BorrowHistoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkBookLost indicates an expected call of MarkBookLost.
+func (mr *MockServiceMockRecorder) MarkBookLost(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkBookLost", reflect.TypeOf((*MockService)(nil).MarkBookLost), req)
+}
+
+// PlaceHold mocks base method.
+func (m *MockService) PlaceHold(req entity.PlaceHoldRequest) (*entity.HoldResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlaceHold", req)
+	ret0, _ := ret[0].(*entity.HoldResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlaceHold indicates an expected call of PlaceHold.
+func (mr *MockServiceMockRecorder) PlaceHold(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceHold", reflect.TypeOf((*MockService)(nil).PlaceHold), req)
+}
+
+// RecordFeePayment mocks base method.
+func (m *MockService) RecordFeePayment(req entity.FeePaymentRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordFeePayment", req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordFeePayment indicates an expected call of RecordFeePayment.
+func (mr *MockServiceMockRecorder) RecordFeePayment(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFeePayment", reflect.TypeOf((*MockService)(nil).RecordFeePayment), req)
+}
+
+// RenewBook mocks base method.
+func (m *MockService) RenewBook(req entity.RenewBookRequest) (*entity.BorrowHistoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenewBook", req)
+	ret0, _ := ret[0].(*entity.BorrowHistoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenewBook indicates an expected call of RenewBook.
+func (mr *MockServiceMockRecorder) RenewBook(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewBook", reflect.TypeOf((*MockService)(nil).RenewBook), req)
+}
+
+// RestoreBook mocks base method.
+func (m *MockService) RestoreBook(bookID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreBook", bookID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreBook indicates an expected call of RestoreBook.
+func (mr *MockServiceMockRecorder) RestoreBook(bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreBook", reflect.TypeOf((*MockService)(nil).RestoreBook), bookID)
+}
+
+// RetireBookCopy mocks base method.
+func (m *MockService) RetireBookCopy(copyID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetireBookCopy", copyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetireBookCopy indicates an expected call of RetireBookCopy.
+func (mr *MockServiceMockRecorder) RetireBookCopy(copyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetireBookCopy", reflect.TypeOf((*MockService)(nil).RetireBookCopy), copyID)
+}
+
 // ReturnBook mocks base method.
 func (m *MockService) ReturnBook(req entity.ReturnBookRequest) error {
 	m.ctrl.T.Helper()
@@ -196,6 +713,50 @@ func (mr *MockServiceMockRecorder) ReturnBook(req interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReturnBook", reflect.TypeOf((*MockService)(nil).ReturnBook), req)
 }
 
+// ReturnBookDamaged mocks base method.
+func (m *MockService) ReturnBookDamaged(req entity.DamagedBookRequest) (*entity.BorrowHistoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReturnBookDamaged", req)
+	ret0, _ := ret[0].(*entity.BorrowHistoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReturnBookDamaged indicates an expected call of ReturnBookDamaged.
+func (mr *MockServiceMockRecorder) ReturnBookDamaged(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReturnBookDamaged", reflect.TypeOf((*MockService)(nil).ReturnBookDamaged), req)
+}
+
+// SearchBooks mocks base method.
+func (m *MockService) SearchBooks(req entity.SearchBookRequest) (*entity.SearchBookResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchBooks", req)
+	ret0, _ := ret[0].(*entity.SearchBookResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchBooks indicates an expected call of SearchBooks.
+func (mr *MockServiceMockRecorder) SearchBooks(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchBooks", reflect.TypeOf((*MockService)(nil).SearchBooks), req)
+}
+
+// UpdateAuthor mocks base method.
+func (m *MockService) UpdateAuthor(req entity.AuthorUpdateRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAuthor", req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAuthor indicates an expected call of UpdateAuthor.
+func (mr *MockServiceMockRecorder) UpdateAuthor(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAuthor", reflect.TypeOf((*MockService)(nil).UpdateAuthor), req)
+}
+
 // UpdateBook mocks base method.
 func (m *MockService) UpdateBook(req entity.BookUpdateRequest) error {
 	m.ctrl.T.Helper()
@@ -210,6 +771,34 @@ func (mr *MockServiceMockRecorder) UpdateBook(req interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBook", reflect.TypeOf((*MockService)(nil).UpdateBook), req)
 }
 
+// UpdateBookCopy mocks base method.
+func (m *MockService) UpdateBookCopy(req entity.BookCopyUpdateRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBookCopy", req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBookCopy indicates an expected call of UpdateBookCopy.
+func (mr *MockServiceMockRecorder) UpdateBookCopy(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBookCopy", reflect.TypeOf((*MockService)(nil).UpdateBookCopy), req)
+}
+
+// UpdateCategory mocks base method.
+func (m *MockService) UpdateCategory(req entity.CategoryUpdateRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCategory", req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCategory indicates an expected call of UpdateCategory.
+func (mr *MockServiceMockRecorder) UpdateCategory(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCategory", reflect.TypeOf((*MockService)(nil).UpdateCategory), req)
+}
+
 // UpdateUser mocks base method.
 func (m *MockService) UpdateUser(user entity.UserUpdateRequest) error {
 	m.ctrl.T.Helper()
@@ -223,3 +812,32 @@ func (mr *MockServiceMockRecorder) UpdateUser(user interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockService)(nil).UpdateUser), user)
 }
+
+// UploadBookCover mocks base method.
+func (m *MockService) UploadBookCover(bookID uint, file io.Reader) (*entity.BookCoverResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadBookCover", bookID, file)
+	ret0, _ := ret[0].(*entity.BookCoverResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadBookCover indicates an expected call of UploadBookCover.
+func (mr *MockServiceMockRecorder) UploadBookCover(bookID, file interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadBookCover", reflect.TypeOf((*MockService)(nil).UploadBookCover), bookID, file)
+}
+
+// WaiveFees mocks base method.
+func (m *MockService) WaiveFees(req entity.FeeWaiverRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaiveFees", req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WaiveFees indicates an expected call of WaiveFees.
+func (mr *MockServiceMockRecorder) WaiveFees(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaiveFees", reflect.TypeOf((*MockService)(nil).WaiveFees), req)
+}
@@ -0,0 +1,392 @@
+package handler
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-library-service/cmd/api/entity"
+	"go-library-service/internal/dublincore"
+	errmap "go-library-service/internal/error_map"
+	"go-library-service/internal/oaipmh"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	oaiPath     = "/oai"
+	oaiPageSize = 100
+)
+
+// oaiSet is a category as a set of records, within the set of its parent category
+type oaiSet struct {
+	categoryID uint
+	spec       string
+	name       string
+}
+
+// HandleOAI answers an OAI-PMH request
+// @Summary Harvest the catalog with OAI-PMH
+// @Description Answer an OAI-PMH 2.0 request for the records of the catalog in Dublin Core. Lists come 100 records at a time, continued by a resumption token; from and until harvest the books changed in a range, archived books as deleted records; and the sets are the categories, a subcategory within the set of its parent.
+// @Tags oai
+// @Accept  application/x-www-form-urlencoded
+// @Produce  text/xml
+// @Param   verb             query     string  true   "Identify, ListMetadataFormats, ListSets, GetRecord, ListIdentifiers or ListRecords"
+// @Param   identifier       query     string  false  "OAI identifier of a book, such as oai:library.example.org:books/1"
+// @Param   metadataPrefix   query     string  false  "Metadata format, only oai_dc"
+// @Param   from             query     string  false  "Only records changed at or after this day or second, such as 2026-01-01 or 2026-01-01T08:00:00Z"
+// @Param   until            query     string  false  "Only records changed up to this day or second"
+// @Param   set              query     string  false  "Only records in this set"
+// @Param   resumptionToken  query     string  false  "Continues an incomplete list, in place of the other arguments"
+// @Success 200 {string} string "OAI-PMH response"
+// @Failure 500 {object} entity.ResponseError
+// @Router /oai [get]
+// @Router /oai [post]
+func (h *Handler) HandleOAI(c *gin.Context) {
+	args := c.Request.URL.Query()
+	if c.Request.Method == http.MethodPost {
+		if err := c.Request.ParseForm(); err != nil {
+			log.Error(errors.Wrap(err, "[Handler.HandleOAI]: unable to parse form"))
+			c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "unable to parse form", Code: http.StatusBadRequest})
+			return
+		}
+		args = c.Request.PostForm
+	}
+
+	request, errs := oaipmh.ParseRequest(args)
+	response := oaipmh.Response{ResponseDate: oaipmh.FormatDatestamp(time.Now()), Request: request, Errors: errs}
+	response.Request.URL = requestOrigin(c) + c.Request.URL.Path
+
+	if len(errs) == 0 {
+		var err error
+		switch request.Verb {
+		case oaipmh.VerbIdentify:
+			err = h.oaiIdentify(c, &response)
+		case oaipmh.VerbListMetadataFormats:
+			err = h.oaiListMetadataFormats(c, &response)
+		case oaipmh.VerbListSets:
+			err = h.oaiListSets(&response)
+		case oaipmh.VerbGetRecord:
+			err = h.oaiGetRecord(c, &response)
+		case oaipmh.VerbListIdentifiers, oaipmh.VerbListRecords:
+			err = h.oaiList(c, &response)
+		}
+
+		var oaiErr oaipmh.Error
+		if errors.As(err, &oaiErr) {
+			response.Errors = []oaipmh.Error{oaiErr}
+		} else if err != nil {
+			log.Error(errors.Wrap(err, "[Handler.HandleOAI]: unable to answer "+request.Verb))
+			c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to answer request", Code: http.StatusInternalServerError})
+			return
+		}
+	}
+
+	data, err := response.Marshal()
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.HandleOAI]: unable to write response"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to write response", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.Data(http.StatusOK, "text/xml; charset=utf-8", data)
+}
+
+// oaiIdentify describes the catalog as a repository
+func (h *Handler) oaiIdentify(c *gin.Context, response *oaipmh.Response) error {
+	earliest, err := h.deps.Service.GetEarliestBookChange()
+	if err != nil {
+		return err
+	}
+
+	// An empty catalog has nothing older than now
+	earliestDatestamp := time.Now()
+	if earliest != nil {
+		earliestDatestamp = *earliest
+	}
+
+	response.Identify = &oaipmh.Identify{
+		RepositoryName:    h.config.OAIRepositoryName,
+		BaseURL:           response.Request.URL,
+		ProtocolVersion:   oaipmh.ProtocolVersion,
+		AdminEmail:        []string{h.config.OAIAdminEmail},
+		EarliestDatestamp: oaipmh.FormatDatestamp(earliestDatestamp),
+		DeletedRecord:     oaipmh.DeletedRecordPersistent,
+		Granularity:       oaipmh.GranularitySecond,
+	}
+	return nil
+}
+
+// oaiListMetadataFormats lists the formats of the records, which are the same for every book
+func (h *Handler) oaiListMetadataFormats(c *gin.Context, response *oaipmh.Response) error {
+	if response.Request.Identifier != "" {
+		if _, err := h.oaiBook(c, response.Request.Identifier); err != nil {
+			return err
+		}
+	}
+
+	response.ListMetadataFormats = &oaipmh.ListMetadataFormats{Formats: []oaipmh.MetadataFormat{oaipmh.DublinCoreFormat}}
+	return nil
+}
+
+// oaiListSets lists the categories as sets, whole as no resumption token is ever handed out for them
+func (h *Handler) oaiListSets(response *oaipmh.Response) error {
+	if response.Request.ResumptionToken != "" {
+		return oaipmh.Error{Code: oaipmh.ErrBadResumptionToken, Message: "resumptionToken is invalid or has expired"}
+	}
+
+	categories, err := h.deps.Service.ListCategories()
+	if err != nil {
+		return err
+	}
+
+	sets := oaiSets(categories, "")
+	if len(sets) == 0 {
+		return oaipmh.Error{Code: oaipmh.ErrNoSetHierarchy, Message: "the catalog has no categories"}
+	}
+
+	response.ListSets = &oaipmh.ListSets{}
+	for _, set := range sets {
+		response.ListSets.Sets = append(response.ListSets.Sets, oaipmh.Set{Spec: set.spec, Name: set.name})
+	}
+	return nil
+}
+
+// oaiGetRecord gets the record of a book
+func (h *Handler) oaiGetRecord(c *gin.Context, response *oaipmh.Response) error {
+	if response.Request.MetadataPrefix != oaipmh.OAIDC {
+		return oaipmh.Error{Code: oaipmh.ErrCannotDisseminateFormat, Message: "metadataPrefix " + response.Request.MetadataPrefix + " is not supported"}
+	}
+
+	book, err := h.oaiBook(c, response.Request.Identifier)
+	if err != nil {
+		return err
+	}
+
+	categories, err := h.deps.Service.ListCategories()
+	if err != nil {
+		return err
+	}
+
+	response.GetRecord = &oaipmh.GetRecord{Record: h.oaiRecord(c, *book, oaiSetSpecs(categories), true)}
+	return nil
+}
+
+// oaiList lists the headers or records of the books changed in a range and set, a part at a time
+func (h *Handler) oaiList(c *gin.Context, response *oaipmh.Response) error {
+	request := response.Request
+	token := oaipmh.Token{MetadataPrefix: request.MetadataPrefix, From: request.From, Until: request.Until, Set: request.Set}
+	if request.ResumptionToken != "" {
+		var err error
+		if token, err = oaipmh.ParseToken(request.ResumptionToken); err != nil {
+			return err
+		}
+	}
+
+	if token.MetadataPrefix != oaipmh.OAIDC {
+		return oaipmh.Error{Code: oaipmh.ErrCannotDisseminateFormat, Message: "metadataPrefix " + token.MetadataPrefix + " is not supported"}
+	}
+
+	from, before, err := oaipmh.ParseDateRange(token.From, token.Until)
+	if err != nil {
+		return err
+	}
+
+	categories, err := h.deps.Service.ListCategories()
+	if err != nil {
+		return err
+	}
+	specs := oaiSetSpecs(categories)
+
+	listReq := entity.ListBookChangeRequest{From: from, Before: before, Size: oaiPageSize, Cursor: token.Cursor}
+	if token.Set != "" {
+		for categoryID, spec := range specs {
+			if spec == token.Set {
+				listReq.CategoryID = &categoryID
+				break
+			}
+		}
+		if listReq.CategoryID == nil {
+			return oaipmh.Error{Code: oaipmh.ErrNoRecordsMatch, Message: "set " + token.Set + " does not exist"}
+		}
+	}
+
+	result, err := h.deps.Service.ListBookChanges(listReq)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapInvalidCursor) {
+			return oaipmh.Error{Code: oaipmh.ErrBadResumptionToken, Message: "resumptionToken is invalid or has expired"}
+		}
+		return err
+	}
+
+	if len(result.Books) == 0 {
+		return oaipmh.Error{Code: oaipmh.ErrNoRecordsMatch, Message: "no records match the request"}
+	}
+
+	total := token.Total
+	if total == nil {
+		total = result.Total
+	}
+
+	// The last part of a resumed list ends it with an empty token
+	var resumption *oaipmh.ResumptionToken
+	if result.Next != nil {
+		next := token
+		next.Cursor = *result.Next
+		next.Position += len(result.Books)
+		next.Total = total
+		resumption = &oaipmh.ResumptionToken{CompleteListSize: total, Cursor: token.Position, Value: next.Encode()}
+	} else if request.ResumptionToken != "" {
+		resumption = &oaipmh.ResumptionToken{CompleteListSize: total, Cursor: token.Position}
+	}
+
+	withMetadata := request.Verb == oaipmh.VerbListRecords
+	var records []oaipmh.Record
+	for _, book := range result.Books {
+		records = append(records, h.oaiRecord(c, book, specs, withMetadata))
+	}
+
+	if withMetadata {
+		response.ListRecords = &oaipmh.ListRecords{Records: records, ResumptionToken: resumption}
+		return nil
+	}
+
+	response.ListIdentifiers = &oaipmh.ListIdentifiers{ResumptionToken: resumption}
+	for _, record := range records {
+		response.ListIdentifiers.Headers = append(response.ListIdentifiers.Headers, record.Header)
+	}
+	return nil
+}
+
+// oaiBook gets the book an OAI identifier names, archived or not
+func (h *Handler) oaiBook(c *gin.Context, identifier string) (*entity.BookResponse, error) {
+	notFound := oaipmh.Error{Code: oaipmh.ErrIDDoesNotExist, Message: "identifier " + identifier + " does not exist"}
+
+	id, ok := strings.CutPrefix(identifier, h.oaiIdentifierPrefix(c))
+	if !ok {
+		return nil, notFound
+	}
+
+	bookID, err := strconv.ParseUint(id, 10, 0)
+	if err != nil || bookID == 0 {
+		return nil, notFound
+	}
+
+	book, err := h.deps.Service.GetBookByID(uint(bookID))
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return nil, notFound
+		}
+		return nil, err
+	}
+	return book, nil
+}
+
+// oaiIdentifierPrefix is what the OAI identifiers of books start with, before the book ID
+func (h *Handler) oaiIdentifierPrefix(c *gin.Context) string {
+	repository := h.config.OAIRepositoryIdentifier
+	if repository == "" {
+		repository = c.Request.Host
+		if host, _, err := net.SplitHostPort(repository); err == nil {
+			repository = host
+		}
+	}
+	return "oai:" + repository + ":books/"
+}
+
+// oaiRecord is the record of a book, in Dublin Core when its metadata is wanted and the book is not archived
+func (h *Handler) oaiRecord(c *gin.Context, book entity.BookResponse, specs map[uint]string, withMetadata bool) oaipmh.Record {
+	datestamp := book.UpdatedAt
+	if datestamp == nil {
+		datestamp = book.CreatedAt
+	}
+
+	record := oaipmh.Record{Header: oaipmh.Header{Identifier: fmt.Sprintf("%s%d", h.oaiIdentifierPrefix(c), book.ID)}}
+	if datestamp != nil {
+		record.Header.Datestamp = oaipmh.FormatDatestamp(*datestamp)
+	}
+	for _, category := range book.Categories {
+		if spec, ok := specs[category.ID]; ok {
+			record.Header.SetSpecs = append(record.Header.SetSpecs, spec)
+		}
+	}
+
+	if book.DeletedAt != nil {
+		record.Header.Status = oaipmh.StatusDeleted
+		return record
+	}
+
+	if withMetadata {
+		record.Metadata = &oaipmh.Metadata{DublinCore: &oaipmh.DublinCore{Record: bookDublinCore(apiURL(c, oaiPath), book)}}
+	}
+	return record
+}
+
+// bookDublinCore describes a book in simple Dublin Core, identified by its ISBN and its URL under the API
+func bookDublinCore(api string, book entity.BookResponse) dublincore.Record {
+	dc := dublincore.Record{
+		Title:   []string{book.Title},
+		Creator: bookAuthorNames(book),
+		Type:    []string{"Text"},
+	}
+
+	for _, category := range book.Categories {
+		dc.Subject = append(dc.Subject, category.Name)
+	}
+	dc.Subject = append(dc.Subject, book.Subjects...)
+
+	if book.Edition != "" {
+		dc.Description = append(dc.Description, book.Edition)
+	}
+	if book.Publisher != "" {
+		dc.Publisher = append(dc.Publisher, book.Publisher)
+	}
+	if book.PublicationYear != nil {
+		dc.Date = append(dc.Date, strconv.Itoa(*book.PublicationYear))
+	}
+	if book.PageCount != nil {
+		dc.Format = append(dc.Format, fmt.Sprintf("%d pages", *book.PageCount))
+	}
+	if book.ISBN != nil {
+		dc.Identifier = append(dc.Identifier, "urn:isbn:"+*book.ISBN)
+	}
+	dc.Identifier = append(dc.Identifier, fmt.Sprintf("%s/books/%d", api, book.ID))
+	if book.Language != "" {
+		dc.Language = append(dc.Language, book.Language)
+	}
+	return dc
+}
+
+// oaiSets lists categories as sets, each before the sets within it
+func oaiSets(categories []entity.CategoryResponse, parent string) []oaiSet {
+	var sets []oaiSet
+	for _, category := range categories {
+		spec := strconv.FormatUint(uint64(category.ID), 10)
+		if parent != "" {
+			spec = oaipmh.SetSpec(parent, spec)
+		}
+		sets = append(sets, oaiSet{categoryID: category.ID, spec: spec, name: category.Name})
+		sets = append(sets, oaiSets(category.Children, spec)...)
+	}
+	return sets
+}
+
+// oaiSetSpecs maps the categories to the specs of their sets
+func oaiSetSpecs(categories []entity.CategoryResponse) map[uint]string {
+	specs := map[uint]string{}
+	for _, set := range oaiSets(categories, "") {
+		specs[set.categoryID] = set.spec
+	}
+	return specs
+}
+
+// RegisterOAIRoutes serves the catalog to OAI-PMH harvesters, which take either GET or POST requests
+func RegisterOAIRoutes(router *gin.RouterGroup, handler *Handler) {
+	router.GET(oaiPath, handler.HandleOAI)
+	router.POST(oaiPath, handler.HandleOAI)
+}
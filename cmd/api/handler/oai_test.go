@@ -0,0 +1,314 @@
+package handler_test
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-library-service/cmd/api/entity"
+	"go-library-service/cmd/api/handler"
+	"go-library-service/cmd/api/handler/mock"
+	errmap "go-library-service/internal/error_map"
+	"go-library-service/internal/oaipmh"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// oaiResponse reads back what the tests look at in an OAI-PMH response
+type oaiResponse struct {
+	Request struct {
+		Verb string `xml:"verb,attr"`
+		URL  string `xml:",chardata"`
+	} `xml:"request"`
+	Errors   []oaipmh.Error `xml:"error"`
+	Identify struct {
+		RepositoryName    string `xml:"repositoryName"`
+		BaseURL           string `xml:"baseURL"`
+		AdminEmail        string `xml:"adminEmail"`
+		EarliestDatestamp string `xml:"earliestDatestamp"`
+		DeletedRecord     string `xml:"deletedRecord"`
+	} `xml:"Identify"`
+	Sets    []oaipmh.Set `xml:"ListSets>set"`
+	Records []struct {
+		Header struct {
+			Status     string   `xml:"status,attr"`
+			Identifier string   `xml:"identifier"`
+			Datestamp  string   `xml:"datestamp"`
+			SetSpecs   []string `xml:"setSpec"`
+		} `xml:"header"`
+		DC *struct {
+			Title      []string `xml:"title"`
+			Creator    []string `xml:"creator"`
+			Subject    []string `xml:"subject"`
+			Date       []string `xml:"date"`
+			Identifier []string `xml:"identifier"`
+		} `xml:"metadata>dc"`
+	} `xml:"ListRecords>record"`
+	Record struct {
+		Header struct {
+			Identifier string `xml:"identifier"`
+		} `xml:"header"`
+	} `xml:"GetRecord>record"`
+	Headers         []struct{} `xml:"ListIdentifiers>header"`
+	ResumptionToken *struct {
+		CompleteListSize string `xml:"completeListSize,attr"`
+		Cursor           string `xml:"cursor,attr"`
+		Value            string `xml:",chardata"`
+	} `xml:"ListRecords>resumptionToken"`
+}
+
+var _ = Describe("OAI Handler", func() {
+	var (
+		h           *handler.Handler
+		serviceMock *mock.MockService
+		r           *gin.Engine
+		ctrl        *gomock.Controller
+		dune        entity.BookResponse
+		archived    entity.BookResponse
+		categories  []entity.CategoryResponse
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		serviceMock = mock.NewMockService(ctrl)
+		h = handler.NewHandler(&handler.Dependencies{
+			Service:   serviceMock,
+			Validator: validator.New(),
+		}, &handler.Config{
+			OAIRepositoryName:       "Library",
+			OAIRepositoryIdentifier: "library.test",
+			OAIAdminEmail:           "librarian@library.test",
+		})
+
+		gin.SetMode(gin.TestMode)
+		r = gin.Default()
+		handler.RegisterOAIRoutes(r.Group("/api"), h)
+
+		isbn, year := "9780441172719", 1965
+		updatedAt := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+		dune = entity.BookResponse{
+			ID:              1,
+			Title:           "Dune",
+			Author:          "Frank Herbert",
+			ISBN:            &isbn,
+			PublicationYear: &year,
+			Authors:         []entity.BookAuthorResponse{{ID: 3, Name: "Frank Herbert"}},
+			Categories:      []entity.BookCategoryResponse{{ID: 7, Name: "Science fiction"}},
+			Subjects:        []string{"Ecology"},
+			UpdatedAt:       &updatedAt,
+		}
+		archivedAt := time.Date(2026, 1, 2, 9, 30, 0, 0, time.UTC)
+		archived = entity.BookResponse{ID: 2, Title: "Dune Messiah", Author: "Frank Herbert", UpdatedAt: &archivedAt, DeletedAt: &archivedAt}
+		categories = []entity.CategoryResponse{
+			{ID: 4, Name: "Fiction", Children: []entity.CategoryResponse{{ID: 7, Name: "Science fiction"}}},
+			{ID: 5, Name: "History"},
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	harvest := func(query string) oaiResponse {
+		req, _ := http.NewRequest(http.MethodGet, "http://library.test/api/oai?"+query, nil)
+
+		w := httptest.NewRecorder()
+		c := gin.CreateTestContextOnly(w, r)
+		c.Request = req
+
+		h.HandleOAI(c)
+
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(w.Header().Get("Content-Type")).To(Equal("text/xml; charset=utf-8"))
+
+		var response oaiResponse
+		Expect(xml.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+		return response
+	}
+
+	Context("Identify", func() {
+		It("should describe the repository", func() {
+			earliest := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+			serviceMock.EXPECT().GetEarliestBookChange().Return(&earliest, nil)
+
+			response := harvest("verb=Identify")
+
+			Expect(response.Errors).To(BeEmpty())
+			Expect(response.Request.URL).To(Equal("http://library.test/api/oai"))
+			Expect(response.Identify.RepositoryName).To(Equal("Library"))
+			Expect(response.Identify.BaseURL).To(Equal("http://library.test/api/oai"))
+			Expect(response.Identify.AdminEmail).To(Equal("librarian@library.test"))
+			Expect(response.Identify.EarliestDatestamp).To(Equal("2025-06-01T12:00:00Z"))
+			Expect(response.Identify.DeletedRecord).To(Equal("persistent"))
+		})
+
+		It("should answer a POST request", func() {
+			serviceMock.EXPECT().GetEarliestBookChange().Return(nil, nil)
+
+			req, _ := http.NewRequest(http.MethodPost, "http://library.test/api/oai", strings.NewReader("verb=Identify"))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.HandleOAI(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Body.String()).To(ContainSubstring(`<request verb="Identify">http://library.test/api/oai</request>`))
+		})
+	})
+
+	Context("ListRecords", func() {
+		It("should list the books changed in a range and set in Dublin Core", func() {
+			total, next := int64(150), "next-cursor"
+			serviceMock.EXPECT().ListCategories().Return(categories, nil)
+			serviceMock.EXPECT().
+				ListBookChanges(gomock.Any()).
+				DoAndReturn(func(req entity.ListBookChangeRequest) (*entity.ListBookChangeResult, error) {
+					Expect(*req.From).To(Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)))
+					Expect(*req.Before).To(Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)))
+					Expect(*req.CategoryID).To(Equal(uint(4)))
+					Expect(req.Size).To(Equal(100))
+					Expect(req.Cursor).To(BeEmpty())
+					return &entity.ListBookChangeResult{Books: []entity.BookResponse{dune, archived}, Next: &next, Total: &total}, nil
+				})
+
+			response := harvest("verb=ListRecords&metadataPrefix=oai_dc&from=2026-01-01&until=2026-01-31&set=4")
+
+			Expect(response.Errors).To(BeEmpty())
+			Expect(response.Records).To(HaveLen(2))
+
+			record := response.Records[0]
+			Expect(record.Header.Identifier).To(Equal("oai:library.test:books/1"))
+			Expect(record.Header.Datestamp).To(Equal("2026-01-01T08:00:00Z"))
+			Expect(record.Header.SetSpecs).To(Equal([]string{"4:7"}))
+			Expect(record.DC.Title).To(Equal([]string{"Dune"}))
+			Expect(record.DC.Creator).To(Equal([]string{"Frank Herbert"}))
+			Expect(record.DC.Subject).To(Equal([]string{"Science fiction", "Ecology"}))
+			Expect(record.DC.Date).To(Equal([]string{"1965"}))
+			Expect(record.DC.Identifier).To(Equal([]string{"urn:isbn:9780441172719", "http://library.test/api/books/1"}))
+
+			Expect(response.Records[1].Header.Status).To(Equal("deleted"))
+			Expect(response.Records[1].DC).To(BeNil())
+
+			Expect(response.ResumptionToken.CompleteListSize).To(Equal("150"))
+			Expect(response.ResumptionToken.Cursor).To(Equal("0"))
+			token, err := oaipmh.ParseToken(response.ResumptionToken.Value)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token).To(Equal(oaipmh.Token{
+				MetadataPrefix: "oai_dc", From: "2026-01-01", Until: "2026-01-31", Set: "4",
+				Cursor: "next-cursor", Position: 2, Total: &total,
+			}))
+		})
+
+		It("should end a resumed list with an empty token", func() {
+			total := int64(101)
+			token := oaipmh.Token{MetadataPrefix: "oai_dc", Cursor: "next-cursor", Position: 100, Total: &total}
+			serviceMock.EXPECT().ListCategories().Return(categories, nil)
+			serviceMock.EXPECT().
+				ListBookChanges(entity.ListBookChangeRequest{Size: 100, Cursor: "next-cursor"}).
+				Return(&entity.ListBookChangeResult{Books: []entity.BookResponse{dune}}, nil)
+
+			response := harvest("verb=ListRecords&resumptionToken=" + url.QueryEscape(token.Encode()))
+
+			Expect(response.Records).To(HaveLen(1))
+			Expect(response.ResumptionToken.CompleteListSize).To(Equal("101"))
+			Expect(response.ResumptionToken.Cursor).To(Equal("100"))
+			Expect(response.ResumptionToken.Value).To(BeEmpty())
+		})
+
+		It("should answer noRecordsMatch for an empty range", func() {
+			total := int64(0)
+			serviceMock.EXPECT().ListCategories().Return(categories, nil)
+			serviceMock.EXPECT().ListBookChanges(gomock.Any()).Return(&entity.ListBookChangeResult{Total: &total}, nil)
+
+			response := harvest("verb=ListRecords&metadataPrefix=oai_dc&from=2030-01-01")
+
+			Expect(response.Errors).To(Equal([]oaipmh.Error{{Code: "noRecordsMatch", Message: "no records match the request"}}))
+		})
+
+		It("should answer badResumptionToken for a cursor it did not make", func() {
+			token := oaipmh.Token{MetadataPrefix: "oai_dc", Cursor: "forged"}
+			serviceMock.EXPECT().ListCategories().Return(categories, nil)
+			serviceMock.EXPECT().ListBookChanges(gomock.Any()).Return(nil, errmap.ErrmapInvalidCursor)
+
+			response := harvest("verb=ListRecords&resumptionToken=" + token.Encode())
+
+			Expect(response.Errors[0].Code).To(Equal("badResumptionToken"))
+		})
+
+		It("should answer cannotDisseminateFormat for a format other than Dublin Core", func() {
+			response := harvest("verb=ListRecords&metadataPrefix=marc21")
+
+			Expect(response.Errors[0].Code).To(Equal("cannotDisseminateFormat"))
+		})
+	})
+
+	Context("ListIdentifiers", func() {
+		It("should list the headers of the books", func() {
+			serviceMock.EXPECT().ListCategories().Return(categories, nil)
+			serviceMock.EXPECT().ListBookChanges(gomock.Any()).Return(&entity.ListBookChangeResult{Books: []entity.BookResponse{dune, archived}}, nil)
+
+			response := harvest("verb=ListIdentifiers&metadataPrefix=oai_dc")
+
+			Expect(response.Errors).To(BeEmpty())
+			Expect(response.Headers).To(HaveLen(2))
+			Expect(response.Records).To(BeEmpty())
+		})
+	})
+
+	Context("GetRecord", func() {
+		It("should get the record of a book", func() {
+			serviceMock.EXPECT().GetBookByID(uint(1)).Return(&dune, nil)
+			serviceMock.EXPECT().ListCategories().Return(categories, nil)
+
+			response := harvest("verb=GetRecord&metadataPrefix=oai_dc&identifier=oai:library.test:books/1")
+
+			Expect(response.Errors).To(BeEmpty())
+			Expect(response.Record.Header.Identifier).To(Equal("oai:library.test:books/1"))
+		})
+
+		It("should answer idDoesNotExist for an unknown book", func() {
+			serviceMock.EXPECT().GetBookByID(uint(999)).Return(nil, errmap.ErrmapNotFound)
+
+			response := harvest("verb=GetRecord&metadataPrefix=oai_dc&identifier=oai:library.test:books/999")
+
+			Expect(response.Errors[0].Code).To(Equal("idDoesNotExist"))
+		})
+
+		It("should answer idDoesNotExist for an identifier of another repository", func() {
+			response := harvest("verb=GetRecord&metadataPrefix=oai_dc&identifier=oai:elsewhere.test:books/1")
+
+			Expect(response.Errors[0].Code).To(Equal("idDoesNotExist"))
+		})
+	})
+
+	Context("ListSets", func() {
+		It("should list the categories as sets within their parents", func() {
+			serviceMock.EXPECT().ListCategories().Return(categories, nil)
+
+			response := harvest("verb=ListSets")
+
+			Expect(response.Sets).To(Equal([]oaipmh.Set{
+				{Spec: "4", Name: "Fiction"},
+				{Spec: "4:7", Name: "Science fiction"},
+				{Spec: "5", Name: "History"},
+			}))
+		})
+	})
+
+	It("should answer badVerb without echoing the arguments", func() {
+		response := harvest("verb=ListBooks&metadataPrefix=oai_dc")
+
+		Expect(response.Request.Verb).To(BeEmpty())
+		Expect(response.Errors[0].Code).To(Equal("badVerb"))
+	})
+})
@@ -0,0 +1,620 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-library-service/cmd/api/entity"
+	"go-library-service/internal/opds"
+	"go-library-service/internal/search"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	opdsPath     = "/opds"
+	opds2Path    = "/opds/v2"
+	opdsPageSize = 20
+)
+
+// opdsFeed is a catalog feed before it is written in a version of OPDS
+type opdsFeed struct {
+	path        string // under the root of the version, which also identifies the feed
+	title       string
+	acquisition bool // lists books rather than other feeds
+	navigation  []opdsNavigation
+	books       []entity.BookResponse
+	pagination  *entity.Pagination
+}
+
+// opdsNavigation is an entry of a navigation feed, leading to another feed
+type opdsNavigation struct {
+	path        string
+	title       string
+	summary     string
+	acquisition bool
+	rel         string // subsection unless the feed is of a kind of its own, such as new arrivals
+}
+
+// opdsWriter writes a feed in a version of OPDS
+type opdsWriter func(c *gin.Context, feed opdsFeed)
+
+// opdsRootFeed leads to the ways of browsing the catalog
+func opdsRootFeed() opdsFeed {
+	return opdsFeed{
+		title: "Library catalog",
+		navigation: []opdsNavigation{
+			{path: "/new", title: "New arrivals", summary: "The books most recently added to the catalog", acquisition: true, rel: opds.RelNew},
+			{path: "/categories", title: "Categories", summary: "Browse the books by category"},
+			{path: "/books", title: "All books", summary: "Every book in the catalog", acquisition: true},
+		},
+	}
+}
+
+// opdsNew writes the feed of the latest books
+func (h *Handler) opdsNew(c *gin.Context, write opdsWriter) {
+	books, err := h.deps.Service.ListLatestBooks(entity.ListLatestBookRequest{})
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.opdsNew]: unable to list latest books"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to list latest books", Code: http.StatusInternalServerError})
+		return
+	}
+
+	write(c, opdsFeed{path: "/new", title: "New arrivals", acquisition: true, books: books})
+}
+
+// opdsCategories writes the feed of the categories under a parent, or at the top of the tree. A category
+// with subcategories leads to their feed, any other to its books.
+func (h *Handler) opdsCategories(c *gin.Context, write opdsWriter) {
+	var req entity.OPDSCategoriesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.opdsCategories]: unable to bind query"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "unable to bind query", Code: http.StatusBadRequest})
+		return
+	}
+
+	categories, err := h.deps.Service.ListCategories()
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.opdsCategories]: unable to list categories"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to list categories", Code: http.StatusInternalServerError})
+		return
+	}
+
+	feed := opdsFeed{path: "/categories", title: "Categories"}
+	if req.ParentID != nil {
+		parent := findCategory(categories, *req.ParentID)
+		if parent == nil {
+			c.JSON(http.StatusNotFound, entity.ResponseError{Error: "category not found", Code: http.StatusNotFound})
+			return
+		}
+
+		feed.path = fmt.Sprintf("/categories?parentId=%d", parent.ID)
+		feed.title = parent.Name
+		feed.navigation = append(feed.navigation, opdsNavigation{
+			path:        fmt.Sprintf("/books?categoryId=%d", parent.ID),
+			title:       "All of " + parent.Name,
+			summary:     "Every book filed under " + parent.Name + " or its subcategories",
+			acquisition: true,
+		})
+		categories = parent.Children
+	}
+
+	for _, category := range categories {
+		if len(category.Children) > 0 {
+			feed.navigation = append(feed.navigation, opdsNavigation{
+				path:    fmt.Sprintf("/categories?parentId=%d", category.ID),
+				title:   category.Name,
+				summary: "Subcategories of " + category.Name,
+			})
+			continue
+		}
+
+		feed.navigation = append(feed.navigation, opdsNavigation{
+			path:        fmt.Sprintf("/books?categoryId=%d", category.ID),
+			title:       category.Name,
+			summary:     "Books filed under " + category.Name,
+			acquisition: true,
+		})
+	}
+
+	write(c, feed)
+}
+
+// opdsBooks writes a page of the books matching a search, in a category when given one
+func (h *Handler) opdsBooks(c *gin.Context, write opdsWriter) {
+	var req entity.OPDSBooksRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.opdsBooks]: unable to bind query"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "unable to bind query", Code: http.StatusBadRequest})
+		return
+	}
+
+	if err := h.deps.Validator.Struct(req); err != nil {
+		log.Error(errors.Wrap(err, "[Handler.opdsBooks]: invalid request"))
+		c.JSON(http.StatusBadRequest, entity.ResponseError{Error: err.Error(), Code: http.StatusBadRequest})
+		return
+	}
+
+	feed := opdsFeed{path: "/books", title: "All books", acquisition: true}
+	listReq := entity.ListBookRequest{Page: max(req.Page, 1), Size: opdsPageSize, CategoryID: req.CategoryID}
+
+	// The feed is identified by what it lists, whatever page of it this is
+	query := url.Values{}
+	if req.CategoryID != nil {
+		categories, err := h.deps.Service.ListCategories()
+		if err != nil {
+			log.Error(errors.Wrap(err, "[Handler.opdsBooks]: unable to list categories"))
+			c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to list categories", Code: http.StatusInternalServerError})
+			return
+		}
+
+		category := findCategory(categories, *req.CategoryID)
+		if category == nil {
+			c.JSON(http.StatusNotFound, entity.ResponseError{Error: "category not found", Code: http.StatusNotFound})
+			return
+		}
+		feed.title = category.Name
+		query.Set("categoryId", strconv.FormatUint(uint64(category.ID), 10))
+	}
+	if req.Query != "" {
+		feed.title = fmt.Sprintf("Search for %q", req.Query)
+		listReq.Search = &req.Query
+		query.Set("q", req.Query)
+	}
+	if len(query) > 0 {
+		feed.path += "?" + query.Encode()
+	}
+
+	result, err := h.deps.Service.ListBook(listReq)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.opdsBooks]: unable to list books"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to list books", Code: http.StatusInternalServerError})
+		return
+	}
+
+	feed.books = result.Books
+	feed.pagination = &result.Pagination
+	write(c, feed)
+}
+
+// findCategory looks a category up anywhere in the tree
+func findCategory(categories []entity.CategoryResponse, categoryID uint) *entity.CategoryResponse {
+	for i := range categories {
+		if categories[i].ID == categoryID {
+			return &categories[i]
+		}
+		if category := findCategory(categories[i].Children, categoryID); category != nil {
+			return category
+		}
+	}
+	return nil
+}
+
+// requestOrigin is the scheme and host a request was sent to
+func requestOrigin(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host
+}
+
+// apiURL is the absolute URL of the API a request is served under, given the path of its routes under the API
+func apiURL(c *gin.Context, routes string) string {
+	path := c.Request.URL.Path
+	if i := strings.Index(path, routes); i >= 0 {
+		path = path[:i]
+	}
+	return requestOrigin(c) + path
+}
+
+// opdsPageLinks links a page of a feed to the first, previous, next and last pages by relation,
+// keeping the rest of the request query
+func opdsPageLinks(c *gin.Context, pagination *entity.Pagination) map[string]string {
+	links := map[string]string{}
+	if pagination == nil || pagination.TotalPages == nil {
+		return links
+	}
+
+	link := func(page int64) string {
+		query := c.Request.URL.Query()
+		query.Set("page", strconv.FormatInt(page, 10))
+		return requestOrigin(c) + c.Request.URL.Path + "?" + query.Encode()
+	}
+
+	last := max(*pagination.TotalPages, 1)
+	links[opds.RelFirst] = link(1)
+	links[opds.RelLast] = link(last)
+	if pagination.Page > 1 {
+		links[opds.RelPrevious] = link(min(int64(pagination.Page-1), last))
+	}
+	if int64(pagination.Page) < last {
+		links[opds.RelNext] = link(int64(pagination.Page + 1))
+	}
+	return links
+}
+
+// bookAuthorNames names the authors of a book, from its credit line when it has no linked authors
+func bookAuthorNames(book entity.BookResponse) []string {
+	var names []string
+	for _, author := range book.Authors {
+		names = append(names, author.Name)
+	}
+	if len(names) == 0 {
+		names = search.SplitNames(book.Author)
+	}
+	return names
+}
+
+// opdsUpdated is when a book last changed, now when that is not known
+func opdsUpdated(book entity.BookResponse, now time.Time) time.Time {
+	switch {
+	case book.UpdatedAt != nil:
+		return book.UpdatedAt.UTC()
+	case book.CreatedAt != nil:
+		return book.CreatedAt.UTC()
+	}
+	return now
+}
+
+// opdsAvailability tells whether a book has a copy on the shelf
+func opdsAvailability(book entity.BookResponse) string {
+	if book.Stock > 0 {
+		return opds.Available
+	}
+	return opds.Unavailable
+}
+
+// writeOPDSFeed writes a feed in OPDS 1.2
+func writeOPDSFeed(c *gin.Context, feed opdsFeed) {
+	api := apiURL(c, opdsPath)
+	root := api + opdsPath
+	now := time.Now().UTC()
+
+	kind := opds.NavigationType
+	if feed.acquisition {
+		kind = opds.AcquisitionType
+	}
+
+	atom := opds.Feed{
+		ID:      root + feed.path,
+		Title:   feed.title,
+		Updated: now,
+		Links: []opds.Link{
+			{Rel: opds.RelSelf, Href: root + feed.path, Type: kind},
+			{Rel: opds.RelStart, Href: root, Type: opds.NavigationType, Title: "Library catalog"},
+			{Rel: opds.RelSearch, Href: root + "/opensearch.xml", Type: opds.OpenSearchType, Title: "Search the catalog"},
+		},
+		Entries: []opds.Entry{},
+	}
+
+	pageLinks := opdsPageLinks(c, feed.pagination)
+	for _, rel := range []string{opds.RelFirst, opds.RelPrevious, opds.RelNext, opds.RelLast} {
+		if href, ok := pageLinks[rel]; ok {
+			atom.Links = append(atom.Links, opds.Link{Rel: rel, Href: href, Type: kind})
+		}
+	}
+	if feed.pagination != nil && feed.pagination.Total != nil {
+		atom.TotalResults = feed.pagination.Total
+		atom.ItemsPerPage = feed.pagination.Size
+		atom.StartIndex = (feed.pagination.Page-1)*feed.pagination.Size + 1
+	}
+
+	for _, navigation := range feed.navigation {
+		linkType, rel := opds.NavigationType, navigation.rel
+		if navigation.acquisition {
+			linkType = opds.AcquisitionType
+		}
+		if rel == "" {
+			rel = opds.RelSubsection
+		}
+
+		atom.Entries = append(atom.Entries, opds.Entry{
+			Title:   navigation.title,
+			ID:      root + navigation.path,
+			Updated: now,
+			Content: &opds.Content{Type: "text", Value: navigation.summary},
+			Links:   []opds.Link{{Rel: rel, Href: root + navigation.path, Type: linkType}},
+		})
+	}
+
+	for _, book := range feed.books {
+		atom.Entries = append(atom.Entries, opdsEntry(api, book, now))
+	}
+
+	data, err := atom.Marshal()
+	if err != nil {
+		log.Error(errors.Wrap(err, "[writeOPDSFeed]: unable to write feed"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to write feed", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.Data(http.StatusOK, kind, data)
+}
+
+// opdsEntry writes a book as an entry of an acquisition feed. Books are borrowed through the API.
+func opdsEntry(api string, book entity.BookResponse, now time.Time) opds.Entry {
+	bookURL := fmt.Sprintf("%s/books/%d", api, book.ID)
+	entry := opds.Entry{
+		Title:     book.Title,
+		ID:        bookURL,
+		Updated:   opdsUpdated(book, now),
+		Publisher: book.Publisher,
+		Language:  book.Language,
+		Links: []opds.Link{
+			{Rel: "alternate", Href: bookURL, Type: "application/json"},
+			{Rel: opds.RelBorrow, Href: bookURL + "/borrow", Type: "application/json",
+				Availability: &opds.Availability{Status: opdsAvailability(book)}},
+		},
+	}
+
+	for _, name := range bookAuthorNames(book) {
+		entry.Authors = append(entry.Authors, opds.Person{Name: name})
+	}
+	if book.ISBN != nil {
+		entry.Identifier = "urn:isbn:" + *book.ISBN
+	}
+	if book.PublicationYear != nil {
+		entry.Issued = strconv.Itoa(*book.PublicationYear)
+	}
+	for _, category := range book.Categories {
+		entry.Categories = append(entry.Categories, opds.Category{Scheme: api + "/categories", Term: category.Code, Label: category.Name})
+	}
+	for _, subject := range book.Subjects {
+		entry.Categories = append(entry.Categories, opds.Category{Term: subject, Label: subject})
+	}
+
+	return entry
+}
+
+// writeOPDS2Feed writes a feed in OPDS 2.0
+func writeOPDS2Feed(c *gin.Context, feed opdsFeed) {
+	api := apiURL(c, opdsPath)
+	root := api + opds2Path
+
+	catalog := opds.Catalog{
+		Metadata: opds.CatalogMetadata{Title: feed.title},
+		Links: []opds.CatalogLink{
+			{Rel: opds.RelSelf, Href: root + feed.path, Type: opds.CatalogType},
+			{Rel: opds.RelStart, Href: root, Type: opds.CatalogType, Title: "Library catalog"},
+			{Rel: opds.RelSearch, Href: root + "/books{?q}", Type: opds.CatalogType, Title: "Search the catalog", Templated: true},
+		},
+	}
+
+	pageLinks := opdsPageLinks(c, feed.pagination)
+	for _, rel := range []string{opds.RelFirst, opds.RelPrevious, opds.RelNext, opds.RelLast} {
+		if href, ok := pageLinks[rel]; ok {
+			catalog.Links = append(catalog.Links, opds.CatalogLink{Rel: rel, Href: href, Type: opds.CatalogType})
+		}
+	}
+	if feed.pagination != nil && feed.pagination.Total != nil {
+		catalog.Metadata.NumberOfItems = feed.pagination.Total
+		catalog.Metadata.ItemsPerPage = feed.pagination.Size
+		catalog.Metadata.CurrentPage = feed.pagination.Page
+	}
+
+	for _, navigation := range feed.navigation {
+		catalog.Navigation = append(catalog.Navigation, opds.CatalogLink{
+			Rel:   navigation.rel,
+			Href:  root + navigation.path,
+			Type:  opds.CatalogType,
+			Title: navigation.title,
+		})
+	}
+
+	// A feed of books has publications even when there are none on the page
+	if feed.acquisition {
+		catalog.Publications = []opds.Publication{}
+	}
+	now := time.Now().UTC()
+	for _, book := range feed.books {
+		catalog.Publications = append(catalog.Publications, opdsPublication(api, book, now))
+	}
+
+	c.Header("Content-Type", opds.CatalogType)
+	c.AbortWithStatusJSON(http.StatusOK, catalog)
+}
+
+// opdsPublication writes a book as a publication of an OPDS 2.0 feed. Books are borrowed through the API.
+func opdsPublication(api string, book entity.BookResponse, now time.Time) opds.Publication {
+	bookURL := fmt.Sprintf("%s/books/%d", api, book.ID)
+	updated := opdsUpdated(book, now)
+	publication := opds.Publication{
+		Metadata: opds.PublicationMetadata{
+			Type:          "http://schema.org/Book",
+			Identifier:    bookURL,
+			Title:         book.Title,
+			Publisher:     book.Publisher,
+			Language:      book.Language,
+			Modified:      &updated,
+			NumberOfPages: book.PageCount,
+		},
+		Links: []opds.CatalogLink{
+			{Rel: "alternate", Href: bookURL, Type: "application/json"},
+			{Rel: opds.RelBorrow, Href: bookURL + "/borrow", Type: "application/json",
+				Properties: &opds.LinkProperties{Availability: &opds.State{State: opdsAvailability(book)}}},
+		},
+	}
+
+	for _, name := range bookAuthorNames(book) {
+		publication.Metadata.Author = append(publication.Metadata.Author, opds.Contributor{Name: name})
+	}
+	if book.ISBN != nil {
+		publication.Metadata.Identifier = "urn:isbn:" + *book.ISBN
+	}
+	if book.PublicationYear != nil {
+		publication.Metadata.Published = strconv.Itoa(*book.PublicationYear)
+	}
+	for _, category := range book.Categories {
+		publication.Metadata.Subject = append(publication.Metadata.Subject, opds.Subject{Name: category.Name, Code: category.Code, Scheme: api + "/categories"})
+	}
+	for _, subject := range book.Subjects {
+		publication.Metadata.Subject = append(publication.Metadata.Subject, opds.Subject{Name: subject})
+	}
+
+	return publication
+}
+
+// GetOPDSRoot gets the root of the OPDS 1.2 catalog
+// @Summary Get the OPDS catalog
+// @Description Get the navigation feed at the root of the OPDS 1.2 catalog, leading to new arrivals, categories and all books
+// @Tags opds
+// @Produce  application/atom+xml
+// @Success 200 {string} string "OPDS 1.2 navigation feed"
+// @Router /opds [get]
+func (h *Handler) GetOPDSRoot(c *gin.Context) {
+	writeOPDSFeed(c, opdsRootFeed())
+}
+
+// GetOPDSNew gets the OPDS 1.2 feed of new arrivals
+// @Summary Get the OPDS feed of new arrivals
+// @Description Get the acquisition feed of the books most recently added to the catalog
+// @Tags opds
+// @Produce  application/atom+xml
+// @Success 200 {string} string "OPDS 1.2 acquisition feed"
+// @Failure 500 {object} entity.ResponseError
+// @Router /opds/new [get]
+func (h *Handler) GetOPDSNew(c *gin.Context) {
+	h.opdsNew(c, writeOPDSFeed)
+}
+
+// GetOPDSCategories gets an OPDS 1.2 feed of categories
+// @Summary Get an OPDS feed of categories
+// @Description Get the navigation feed of the categories under a parent, or at the top of the tree. A category with subcategories leads to their feed, any other to its books.
+// @Tags opds
+// @Produce  application/atom+xml
+// @Param   parentId  query     int     false  "Parent category, the top of the tree when missing"
+// @Success 200 {string} string "OPDS 1.2 navigation feed"
+// @Failure 400 {object} entity.ResponseError
+// @Failure 404 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Router /opds/categories [get]
+func (h *Handler) GetOPDSCategories(c *gin.Context) {
+	h.opdsCategories(c, writeOPDSFeed)
+}
+
+// GetOPDSBooks gets an OPDS 1.2 feed of books
+// @Summary Get an OPDS feed of books
+// @Description Get a page of the acquisition feed of the books matching a search, in a category when given one, linked to the pages around it
+// @Tags opds
+// @Produce  application/atom+xml
+// @Param   q           query     string  false  "Search title and author, supports quoted phrases, prefix*, -excluded and OR"
+// @Param   categoryId  query     int     false  "Only books filed under this category or its subcategories"
+// @Param   page        query     int     false  "Page number, the first page when missing"
+// @Success 200 {string} string "OPDS 1.2 acquisition feed"
+// @Failure 400 {object} entity.ResponseError
+// @Failure 404 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Router /opds/books [get]
+func (h *Handler) GetOPDSBooks(c *gin.Context) {
+	h.opdsBooks(c, writeOPDSFeed)
+}
+
+// GetOPDSSearchDescription gets the OpenSearch description of the OPDS 1.2 catalog
+// @Summary Get the OpenSearch description of the OPDS catalog
+// @Description Get the OpenSearch description telling e-reader apps how to search the catalog
+// @Tags opds
+// @Produce  application/opensearchdescription+xml
+// @Success 200 {string} string "OpenSearch description"
+// @Failure 500 {object} entity.ResponseError
+// @Router /opds/opensearch.xml [get]
+func (h *Handler) GetOPDSSearchDescription(c *gin.Context) {
+	root := apiURL(c, opdsPath) + opdsPath
+	description := opds.OpenSearchDescription{
+		ShortName:      "Library",
+		Description:    "Search the books of the library by title and author",
+		InputEncoding:  "UTF-8",
+		OutputEncoding: "UTF-8",
+		URL:            opds.SearchURL{Type: opds.AcquisitionType, Template: root + "/books?q={searchTerms}"},
+	}
+
+	data, err := description.Marshal()
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.GetOPDSSearchDescription]: unable to write description"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to write description", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.Data(http.StatusOK, opds.OpenSearchType, data)
+}
+
+// GetOPDS2Root gets the root of the OPDS 2.0 catalog
+// @Summary Get the OPDS 2.0 catalog
+// @Description Get the navigation feed at the root of the OPDS 2.0 catalog, leading to new arrivals, categories and all books, with a templated search link
+// @Tags opds
+// @Produce  application/opds+json
+// @Success 200 {string} string "OPDS 2.0 feed"
+// @Router /opds/v2 [get]
+func (h *Handler) GetOPDS2Root(c *gin.Context) {
+	writeOPDS2Feed(c, opdsRootFeed())
+}
+
+// GetOPDS2New gets the OPDS 2.0 feed of new arrivals
+// @Summary Get the OPDS 2.0 feed of new arrivals
+// @Description Get the feed of the books most recently added to the catalog
+// @Tags opds
+// @Produce  application/opds+json
+// @Success 200 {string} string "OPDS 2.0 feed"
+// @Failure 500 {object} entity.ResponseError
+// @Router /opds/v2/new [get]
+func (h *Handler) GetOPDS2New(c *gin.Context) {
+	h.opdsNew(c, writeOPDS2Feed)
+}
+
+// GetOPDS2Categories gets an OPDS 2.0 feed of categories
+// @Summary Get an OPDS 2.0 feed of categories
+// @Description Get the navigation feed of the categories under a parent, or at the top of the tree. A category with subcategories leads to their feed, any other to its books.
+// @Tags opds
+// @Produce  application/opds+json
+// @Param   parentId  query     int     false  "Parent category, the top of the tree when missing"
+// @Success 200 {string} string "OPDS 2.0 feed"
+// @Failure 400 {object} entity.ResponseError
+// @Failure 404 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Router /opds/v2/categories [get]
+func (h *Handler) GetOPDS2Categories(c *gin.Context) {
+	h.opdsCategories(c, writeOPDS2Feed)
+}
+
+// GetOPDS2Books gets an OPDS 2.0 feed of books
+// @Summary Get an OPDS 2.0 feed of books
+// @Description Get a page of the feed of the books matching a search, in a category when given one, linked to the pages around it
+// @Tags opds
+// @Produce  application/opds+json
+// @Param   q           query     string  false  "Search title and author, supports quoted phrases, prefix*, -excluded and OR"
+// @Param   categoryId  query     int     false  "Only books filed under this category or its subcategories"
+// @Param   page        query     int     false  "Page number, the first page when missing"
+// @Success 200 {string} string "OPDS 2.0 feed"
+// @Failure 400 {object} entity.ResponseError
+// @Failure 404 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Router /opds/v2/books [get]
+func (h *Handler) GetOPDS2Books(c *gin.Context) {
+	h.opdsBooks(c, writeOPDS2Feed)
+}
+
+// RegisterOPDSRoutes serves the catalog to e-reader apps, which browse it without signing in
+func RegisterOPDSRoutes(router *gin.RouterGroup, handler *Handler) {
+	opdsRoutes := router.Group(opdsPath)
+	{
+		opdsRoutes.GET("", handler.GetOPDSRoot)
+		opdsRoutes.GET("/new", handler.GetOPDSNew)
+		opdsRoutes.GET("/categories", handler.GetOPDSCategories)
+		opdsRoutes.GET("/books", handler.GetOPDSBooks)
+		opdsRoutes.GET("/opensearch.xml", handler.GetOPDSSearchDescription)
+	}
+
+	opds2Routes := router.Group(opds2Path)
+	{
+		opds2Routes.GET("", handler.GetOPDS2Root)
+		opds2Routes.GET("/new", handler.GetOPDS2New)
+		opds2Routes.GET("/categories", handler.GetOPDS2Categories)
+		opds2Routes.GET("/books", handler.GetOPDS2Books)
+	}
+}
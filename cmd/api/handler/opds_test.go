@@ -0,0 +1,308 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"go-library-service/cmd/api/entity"
+	"go-library-service/cmd/api/handler"
+	"go-library-service/cmd/api/handler/mock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// atomFeed reads back what the tests look at in an OPDS 1.2 feed
+type atomFeed struct {
+	ID           string     `xml:"id"`
+	Title        string     `xml:"title"`
+	Links        []atomLink `xml:"link"`
+	TotalResults int64      `xml:"totalResults"`
+	Entries      []struct {
+		Title      string     `xml:"title"`
+		ID         string     `xml:"id"`
+		Identifier string     `xml:"identifier"`
+		Authors    []string   `xml:"author>name"`
+		Links      []atomLink `xml:"link"`
+	} `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel          string `xml:"rel,attr"`
+	Href         string `xml:"href,attr"`
+	Type         string `xml:"type,attr"`
+	Availability struct {
+		Status string `xml:"status,attr"`
+	} `xml:"availability"`
+}
+
+// linksByRel maps the links of a feed by relation
+func linksByRel(links []atomLink) map[string]atomLink {
+	byRel := map[string]atomLink{}
+	for _, link := range links {
+		byRel[link.Rel] = link
+	}
+	return byRel
+}
+
+var _ = Describe("OPDS Handler", func() {
+	var (
+		h           *handler.Handler
+		serviceMock *mock.MockService
+		r           *gin.Engine
+		ctrl        *gomock.Controller
+		dune        entity.BookResponse
+		categories  []entity.CategoryResponse
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		serviceMock = mock.NewMockService(ctrl)
+		h = handler.NewHandler(&handler.Dependencies{
+			Service:   serviceMock,
+			Validator: validator.New(),
+		}, &handler.Config{})
+
+		gin.SetMode(gin.TestMode)
+		r = gin.Default()
+		handler.RegisterOPDSRoutes(r.Group("/api"), h)
+
+		isbn, year := "9780441172719", 1965
+		updatedAt := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+		dune = entity.BookResponse{
+			ID:              1,
+			Title:           "Dune",
+			Author:          "Frank Herbert",
+			Stock:           2,
+			ISBN:            &isbn,
+			PublicationYear: &year,
+			Authors:         []entity.BookAuthorResponse{{ID: 3, Name: "Frank Herbert"}},
+			Categories:      []entity.BookCategoryResponse{{ID: 7, Name: "Science fiction", Code: "SF"}},
+			UpdatedAt:       &updatedAt,
+		}
+		categories = []entity.CategoryResponse{
+			{ID: 4, Name: "Fiction", Children: []entity.CategoryResponse{{ID: 7, Name: "Science fiction"}}},
+			{ID: 5, Name: "History"},
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	feedRequest := func(url string) (*httptest.ResponseRecorder, *gin.Context) {
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+
+		w := httptest.NewRecorder()
+		c := gin.CreateTestContextOnly(w, r)
+		c.Request = req
+		return w, c
+	}
+
+	Context("GetOPDSRoot", func() {
+		It("should lead to new arrivals, categories and all books", func() {
+			w, c := feedRequest("http://library.test/api/opds")
+
+			h.GetOPDSRoot(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Header().Get("Content-Type")).To(Equal("application/atom+xml;profile=opds-catalog;kind=navigation"))
+
+			var feed atomFeed
+			Expect(xml.Unmarshal(w.Body.Bytes(), &feed)).To(Succeed())
+			Expect(feed.ID).To(Equal("http://library.test/api/opds"))
+			Expect(linksByRel(feed.Links)["search"].Href).To(Equal("http://library.test/api/opds/opensearch.xml"))
+
+			Expect(feed.Entries).To(HaveLen(3))
+			Expect(feed.Entries[0].Links[0]).To(Equal(atomLink{
+				Rel:  "http://opds-spec.org/sort/new",
+				Href: "http://library.test/api/opds/new",
+				Type: "application/atom+xml;profile=opds-catalog;kind=acquisition",
+			}))
+			Expect(feed.Entries[1].Links[0].Rel).To(Equal("subsection"))
+		})
+	})
+
+	Context("GetOPDSBooks", func() {
+		It("should write a page of books linked to the pages around it", func() {
+			total, totalPages := int64(41), int64(3)
+			serviceMock.EXPECT().ListCategories().Return(categories, nil)
+			serviceMock.EXPECT().
+				ListBook(entity.ListBookRequest{Page: 2, Size: 20, CategoryID: func() *uint { id := uint(7); return &id }()}).
+				Return(&entity.ListBookResult{
+					Books:      []entity.BookResponse{dune},
+					Pagination: entity.Pagination{Page: 2, Size: 20, Total: &total, TotalPages: &totalPages},
+				}, nil)
+
+			w, c := feedRequest("http://library.test/api/opds/books?categoryId=7&page=2")
+
+			h.GetOPDSBooks(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+
+			var feed atomFeed
+			Expect(xml.Unmarshal(w.Body.Bytes(), &feed)).To(Succeed())
+			Expect(feed.ID).To(Equal("http://library.test/api/opds/books?categoryId=7"))
+			Expect(feed.Title).To(Equal("Science fiction"))
+			Expect(feed.TotalResults).To(Equal(int64(41)))
+
+			links := linksByRel(feed.Links)
+			Expect(links["first"].Href).To(Equal("http://library.test/api/opds/books?categoryId=7&page=1"))
+			Expect(links["previous"].Href).To(Equal("http://library.test/api/opds/books?categoryId=7&page=1"))
+			Expect(links["next"].Href).To(Equal("http://library.test/api/opds/books?categoryId=7&page=3"))
+			Expect(links["last"].Href).To(Equal("http://library.test/api/opds/books?categoryId=7&page=3"))
+
+			Expect(feed.Entries).To(HaveLen(1))
+			entry := feed.Entries[0]
+			Expect(entry.ID).To(Equal("http://library.test/api/books/1"))
+			Expect(entry.Identifier).To(Equal("urn:isbn:9780441172719"))
+			Expect(entry.Authors).To(Equal([]string{"Frank Herbert"}))
+
+			borrow := linksByRel(entry.Links)["http://opds-spec.org/acquisition/borrow"]
+			Expect(borrow.Href).To(Equal("http://library.test/api/books/1/borrow"))
+			Expect(borrow.Availability.Status).To(Equal("available"))
+		})
+
+		It("should search the catalog", func() {
+			search := "dune"
+			serviceMock.EXPECT().
+				ListBook(entity.ListBookRequest{Page: 1, Size: 20, Search: &search}).
+				Return(&entity.ListBookResult{Books: []entity.BookResponse{}}, nil)
+
+			w, c := feedRequest("http://library.test/api/opds/books?q=dune")
+
+			h.GetOPDSBooks(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var feed atomFeed
+			Expect(xml.Unmarshal(w.Body.Bytes(), &feed)).To(Succeed())
+			Expect(feed.ID).To(Equal("http://library.test/api/opds/books?q=dune"))
+			Expect(feed.Entries).To(BeEmpty())
+		})
+
+		It("should return not found for an unknown category", func() {
+			serviceMock.EXPECT().ListCategories().Return(categories, nil)
+
+			w, c := feedRequest("http://library.test/api/opds/books?categoryId=99")
+
+			h.GetOPDSBooks(c)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+
+		It("should return error for an invalid page", func() {
+			w, c := feedRequest("http://library.test/api/opds/books?page=-1")
+
+			h.GetOPDSBooks(c)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Context("GetOPDSCategories", func() {
+		It("should lead to subcategories or to books", func() {
+			serviceMock.EXPECT().ListCategories().Return(categories, nil)
+
+			w, c := feedRequest("http://library.test/api/opds/categories")
+
+			h.GetOPDSCategories(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var feed atomFeed
+			Expect(xml.Unmarshal(w.Body.Bytes(), &feed)).To(Succeed())
+			Expect(feed.Entries).To(HaveLen(2))
+			Expect(feed.Entries[0].Links[0].Href).To(Equal("http://library.test/api/opds/categories?parentId=4"))
+			Expect(feed.Entries[1].Links[0].Href).To(Equal("http://library.test/api/opds/books?categoryId=5"))
+		})
+
+		It("should list the books of a parent before its subcategories", func() {
+			serviceMock.EXPECT().ListCategories().Return(categories, nil)
+
+			w, c := feedRequest("http://library.test/api/opds/categories?parentId=4")
+
+			h.GetOPDSCategories(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var feed atomFeed
+			Expect(xml.Unmarshal(w.Body.Bytes(), &feed)).To(Succeed())
+			Expect(feed.Title).To(Equal("Fiction"))
+			Expect(feed.Entries).To(HaveLen(2))
+			Expect(feed.Entries[0].Title).To(Equal("All of Fiction"))
+			Expect(feed.Entries[0].Links[0].Href).To(Equal("http://library.test/api/opds/books?categoryId=4"))
+			Expect(feed.Entries[1].Links[0].Href).To(Equal("http://library.test/api/opds/books?categoryId=7"))
+		})
+
+		It("should return not found for an unknown parent", func() {
+			serviceMock.EXPECT().ListCategories().Return(categories, nil)
+
+			w, c := feedRequest("http://library.test/api/opds/categories?parentId=99")
+
+			h.GetOPDSCategories(c)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("GetOPDSSearchDescription", func() {
+		It("should describe the search of the catalog", func() {
+			w, c := feedRequest("http://library.test/api/opds/opensearch.xml")
+			c.Request.Header.Set("X-Forwarded-Proto", "https")
+
+			h.GetOPDSSearchDescription(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Header().Get("Content-Type")).To(Equal("application/opensearchdescription+xml"))
+			Expect(w.Body.String()).To(ContainSubstring(`template="https://library.test/api/opds/books?q={searchTerms}"`))
+		})
+	})
+
+	Context("GetOPDS2New", func() {
+		It("should write the latest books as publications", func() {
+			serviceMock.EXPECT().ListLatestBooks(entity.ListLatestBookRequest{}).Return([]entity.BookResponse{dune}, nil)
+
+			w, c := feedRequest("http://library.test/api/opds/v2/new")
+
+			h.GetOPDS2New(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Header().Get("Content-Type")).To(Equal("application/opds+json"))
+
+			var catalog struct {
+				Metadata struct {
+					Title string `json:"title"`
+				} `json:"metadata"`
+				Links []struct {
+					Rel       string `json:"rel"`
+					Href      string `json:"href"`
+					Templated bool   `json:"templated"`
+				} `json:"links"`
+				Publications []struct {
+					Metadata map[string]interface{} `json:"metadata"`
+					Links    []struct {
+						Rel        string                 `json:"rel"`
+						Properties map[string]interface{} `json:"properties"`
+					} `json:"links"`
+				} `json:"publications"`
+			}
+			Expect(json.Unmarshal(w.Body.Bytes(), &catalog)).To(Succeed())
+			Expect(catalog.Metadata.Title).To(Equal("New arrivals"))
+			Expect(catalog.Links[2].Href).To(Equal("http://library.test/api/opds/v2/books{?q}"))
+			Expect(catalog.Links[2].Templated).To(BeTrue())
+
+			Expect(catalog.Publications).To(HaveLen(1))
+			metadata := catalog.Publications[0].Metadata
+			Expect(metadata["identifier"]).To(Equal("urn:isbn:9780441172719"))
+			Expect(metadata["published"]).To(Equal("1965"))
+			Expect(metadata["author"]).To(Equal([]interface{}{map[string]interface{}{"name": "Frank Herbert"}}))
+			Expect(catalog.Publications[0].Links[1].Properties).To(Equal(map[string]interface{}{
+				"availability": map[string]interface{}{"state": "available"},
+			}))
+		})
+	})
+})
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"net/url"
+	"strconv"
+
+	"go-library-service/cmd/api/entity"
+
+	"github.com/gin-gonic/gin"
+)
+
+// linkPages links a page to the pages around it, keeping the rest of the request query
+func linkPages(c *gin.Context, pagination *entity.Pagination) {
+	link := func(set func(query url.Values)) *string {
+		query := c.Request.URL.Query()
+		set(query)
+		link := c.Request.URL.Path + "?" + query.Encode()
+		return &link
+	}
+
+	if pagination.NextCursor != nil {
+		pagination.Next = link(func(query url.Values) {
+			query.Del("page")
+			query.Set("cursor", *pagination.NextCursor)
+		})
+	}
+
+	// Pages after a cursor are not counted and only link forward
+	if pagination.TotalPages == nil {
+		return
+	}
+
+	if int64(pagination.Page) < *pagination.TotalPages {
+		pagination.Next = link(func(query url.Values) {
+			query.Set("page", strconv.Itoa(pagination.Page+1))
+		})
+	}
+
+	if pagination.Page > 1 && *pagination.TotalPages > 0 {
+		prev := min(int64(pagination.Page-1), *pagination.TotalPages)
+		pagination.Prev = link(func(query url.Values) {
+			query.Set("page", strconv.FormatInt(prev, 10))
+		})
+	}
+}
@@ -0,0 +1,467 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	"go-library-service/internal/cql"
+	"go-library-service/internal/isbn"
+	"go-library-service/internal/sru"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	sruPath           = "/sru"
+	sruDefaultRecords = 10
+	sruMaxRecords     = 100
+)
+
+// sruIndex is an index a search can use and the field of books it searches
+type sruIndex struct {
+	field string
+	title string
+	set   string
+	name  string
+}
+
+// sruContextSet is a context set the indexes of a search are in
+type sruContextSet struct {
+	name       string
+	identifier string
+}
+
+var sruContextSets = []sruContextSet{
+	{"cql", "info:srw/cql-context-set/1/cql-v1.2"},
+	{"dc", "info:srw/cql-context-set/1/dc-v1.1"},
+	{"bath", "http://zing.z3950.org/cql/bath/2.0/"},
+}
+
+var sruIndexes = []sruIndex{
+	{constant.BookQueryFieldAny, "Any", "cql", "serverChoice"},
+	{constant.BookQueryFieldTitle, "Title", "dc", "title"},
+	{constant.BookQueryFieldAuthor, "Author", "dc", "creator"},
+	{constant.BookQueryFieldSubject, "Subject", "dc", "subject"},
+	{constant.BookQueryFieldPublisher, "Publisher", "dc", "publisher"},
+	{constant.BookQueryFieldLanguage, "Language", "dc", "language"},
+	{constant.BookQueryFieldYear, "Year of publication", "dc", "date"},
+	{constant.BookQueryFieldISBN, "ISBN", "bath", "isbn"},
+}
+
+// sruIndexAliases are the fields searched by indexes named without a context set
+var sruIndexAliases = map[string]string{
+	"title":     constant.BookQueryFieldTitle,
+	"author":    constant.BookQueryFieldAuthor,
+	"creator":   constant.BookQueryFieldAuthor,
+	"subject":   constant.BookQueryFieldSubject,
+	"publisher": constant.BookQueryFieldPublisher,
+	"language":  constant.BookQueryFieldLanguage,
+	"date":      constant.BookQueryFieldYear,
+	"year":      constant.BookQueryFieldYear,
+	"isbn":      constant.BookQueryFieldISBN,
+}
+
+var (
+	sruTextRelations = map[string]string{
+		"=":     constant.BookQueryRelationContains,
+		"adj":   constant.BookQueryRelationContains,
+		"==":    constant.BookQueryRelationExact,
+		"exact": constant.BookQueryRelationExact,
+		"all":   constant.BookQueryRelationAll,
+		"any":   constant.BookQueryRelationAny,
+	}
+	sruExactRelations = map[string]string{
+		"=":     constant.BookQueryRelationExact,
+		"==":    constant.BookQueryRelationExact,
+		"exact": constant.BookQueryRelationExact,
+	}
+)
+
+// sruRelations are the relations each field can be searched by
+var sruRelations = map[string]map[string]string{
+	constant.BookQueryFieldAny: {
+		"=":   constant.BookQueryRelationAll,
+		"all": constant.BookQueryRelationAll,
+		"any": constant.BookQueryRelationAny,
+		"adj": constant.BookQueryRelationContains,
+	},
+	constant.BookQueryFieldTitle:     sruTextRelations,
+	constant.BookQueryFieldAuthor:    sruTextRelations,
+	constant.BookQueryFieldSubject:   sruTextRelations,
+	constant.BookQueryFieldPublisher: sruTextRelations,
+	constant.BookQueryFieldLanguage:  sruExactRelations,
+	constant.BookQueryFieldISBN:      sruExactRelations,
+	constant.BookQueryFieldYear: {
+		"=":  constant.BookQueryRelationEq,
+		"==": constant.BookQueryRelationEq,
+		"<>": constant.BookQueryRelationNe,
+		"<":  constant.BookQueryRelationLt,
+		"<=": constant.BookQueryRelationLe,
+		">":  constant.BookQueryRelationGt,
+		">=": constant.BookQueryRelationGe,
+	},
+}
+
+// sruParameters are the parameters of a request, apart from the extensions starting with x-, which are ignored
+var sruParameters = map[string]bool{
+	"operation": true, "version": true, "query": true, "queryType": true, "startRecord": true, "maximumRecords": true,
+	"recordXMLEscaping": true, "recordPacking": true, "recordSchema": true, "resultSetTTL": true, "sortKeys": true,
+}
+
+// HandleSRU answers an SRU search or explain request
+// @Summary Search the catalog with SRU
+// @Description Answer an SRU 2.0 request. A request with a CQL query searches the books, archived books left out, in the order they were added; one without explains the server, its indexes and schemas. Indexes are cql.serverChoice, dc.title, dc.creator, dc.subject, dc.publisher, dc.language, dc.date and bath.isbn, or title, author, subject, publisher, language, year and isbn, joined by and, or and not. A request that cannot be answered gets a diagnostic.
+// @Tags sru
+// @Accept  application/x-www-form-urlencoded
+// @Produce  application/sru+xml
+// @Param   query              query     string  false  "CQL query, such as title=dune and author=herbert"
+// @Param   startRecord        query     int     false  "Position of the first record, from 1"
+// @Param   maximumRecords     query     int     false  "Number of records, 10 by default and at most 100"
+// @Param   recordSchema       query     string  false  "dc or marcxml, or the URI of either"
+// @Param   recordXMLEscaping  query     string  false  "xml to write records inline or string to escape them"
+// @Param   version            query     string  false  "Only 2.0"
+// @Success 200 {string} string "SRU response"
+// @Failure 500 {object} entity.ResponseError
+// @Router /sru [get]
+// @Router /sru [post]
+func (h *Handler) HandleSRU(c *gin.Context) {
+	args := c.Request.URL.Query()
+	if c.Request.Method == http.MethodPost {
+		if err := c.Request.ParseForm(); err != nil {
+			log.Error(errors.Wrap(err, "[Handler.HandleSRU]: unable to parse form"))
+			c.JSON(http.StatusBadRequest, entity.ResponseError{Error: "unable to parse form", Code: http.StatusBadRequest})
+			return
+		}
+		args = c.Request.PostForm
+	}
+
+	var data []byte
+	var err error
+	operation := args.Get("operation")
+	switch {
+	case operation == sru.OperationExplain || (operation == "" && args.Get("query") == ""):
+		data, err = h.sruExplain(c, args).Marshal()
+	default:
+		var response *sru.SearchRetrieveResponse
+		if response, err = h.sruSearchRetrieve(c, args); err != nil {
+			log.Error(errors.Wrap(err, "[Handler.HandleSRU]: unable to search books"))
+			c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to search books", Code: http.StatusInternalServerError})
+			return
+		}
+		data, err = response.Marshal()
+	}
+
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Handler.HandleSRU]: unable to write response"))
+		c.JSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to write response", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.Data(http.StatusOK, "application/sru+xml; charset=utf-8", data)
+}
+
+// sruSearchRetrieve searches the books a query matches, answering with a diagnostic what it cannot search
+func (h *Handler) sruSearchRetrieve(c *gin.Context, args url.Values) (*sru.SearchRetrieveResponse, error) {
+	response := &sru.SearchRetrieveResponse{Version: sru.Version}
+
+	search, err := sruSearchParams(args)
+	if err != nil {
+		return sruDiagnosed(response, err)
+	}
+	query, err := sruQuery(search.query.Root)
+	if err != nil {
+		return sruDiagnosed(response, err)
+	}
+
+	result, err := h.deps.Service.SearchBooks(entity.SearchBookRequest{Query: *query, Offset: search.start - 1, Limit: search.maximum})
+	if err != nil {
+		return nil, err
+	}
+
+	response.NumberOfRecords = result.Total
+	if result.Total > 0 && int64(search.start) > result.Total {
+		return sruDiagnosed(response, sru.NewDiagnostic(sru.ErrFirstRecordOutOfRange, strconv.Itoa(search.start)))
+	}
+
+	api := apiURL(c, sruPath)
+	for i, book := range result.Books {
+		response.Records = append(response.Records, sruRecord(api, book, search, search.start+i))
+	}
+	if next := search.start + len(result.Books); len(result.Books) > 0 && int64(next) <= result.Total {
+		response.NextRecordPosition = next
+	}
+	return response, nil
+}
+
+// sruDiagnosed answers a search with the diagnostic an error is, passing on any other error
+func sruDiagnosed(response *sru.SearchRetrieveResponse, err error) (*sru.SearchRetrieveResponse, error) {
+	var diagnostic sru.Diagnostic
+	if !errors.As(err, &diagnostic) {
+		return nil, err
+	}
+	response.Diagnostics = sru.Diagnostics{diagnostic}
+	return response, nil
+}
+
+// sruSearch is what a search request asks for
+type sruSearch struct {
+	query    *cql.Query
+	start    int
+	maximum  int
+	schema   string
+	escaping string
+}
+
+// sruSearchParams reads the parameters of a search request, or the diagnostic of the first one it cannot take
+func sruSearchParams(args url.Values) (sruSearch, error) {
+	fail := func(code int, details string) (sruSearch, error) {
+		return sruSearch{}, sru.NewDiagnostic(code, details)
+	}
+
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !sruParameters[name] && !strings.HasPrefix(name, "x-") {
+			return fail(sru.ErrUnsupportedParameter, name)
+		}
+	}
+
+	if operation := args.Get("operation"); operation != "" && operation != sru.OperationSearchRetrieve {
+		return fail(sru.ErrUnsupportedOperation, operation)
+	}
+	if version := args.Get("version"); version != "" && version != sru.Version {
+		return fail(sru.ErrUnsupportedVersion, sru.Version)
+	}
+	if queryType := args.Get("queryType"); queryType != "" && queryType != "cql" {
+		return fail(sru.ErrUnsupportedParameterValue, "queryType")
+	}
+	if packing := args.Get("recordPacking"); packing != "" && packing != "packed" {
+		return fail(sru.ErrUnsupportedParameterValue, "recordPacking")
+	}
+	if args.Get("sortKeys") != "" {
+		return fail(sru.ErrSortUnsupported, "sortKeys")
+	}
+
+	search := sruSearch{start: 1, maximum: sruDefaultRecords, schema: sru.DCSchema, escaping: sru.EscapingXML}
+
+	if value := args.Get("startRecord"); value != "" {
+		start, err := strconv.Atoi(value)
+		if err != nil || start < 1 {
+			return fail(sru.ErrUnsupportedParameterValue, "startRecord")
+		}
+		search.start = start
+	}
+	if value := args.Get("maximumRecords"); value != "" {
+		maximum, err := strconv.Atoi(value)
+		if err != nil || maximum < 0 {
+			return fail(sru.ErrUnsupportedParameterValue, "maximumRecords")
+		}
+		search.maximum = min(maximum, sruMaxRecords)
+	}
+
+	switch schema := args.Get("recordSchema"); schema {
+	case "", "dc", sru.DCSchema:
+	case "marcxml", sru.MARCXMLSchema:
+		search.schema = sru.MARCXMLSchema
+	default:
+		return fail(sru.ErrUnknownSchema, schema)
+	}
+
+	switch escaping := args.Get("recordXMLEscaping"); escaping {
+	case "", sru.EscapingXML:
+	case sru.EscapingString:
+		search.escaping = sru.EscapingString
+	default:
+		return fail(sru.ErrUnsupportedEscaping, escaping)
+	}
+
+	if args.Get("query") == "" {
+		return fail(sru.ErrMissingParameter, "query")
+	}
+	query, err := cql.Parse(args.Get("query"))
+	if err != nil {
+		return fail(sru.ErrQuerySyntax, err.Error())
+	}
+	if len(query.SortKeys) > 0 {
+		return fail(sru.ErrSortUnsupported, "sortby")
+	}
+	for _, prefix := range query.Prefixes {
+		if !sruKnownContextSet(prefix) {
+			return fail(sru.ErrUnsupportedContextSet, prefix.URI)
+		}
+	}
+
+	search.query = query
+	return search, nil
+}
+
+// sruKnownContextSet tells whether a prefix assignment gives one of the context sets its own name
+func sruKnownContextSet(prefix cql.Prefix) bool {
+	for _, set := range sruContextSets {
+		if set.identifier == prefix.URI && (prefix.Name == "" || strings.EqualFold(prefix.Name, set.name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// sruQuery translates a CQL query into a book query, or the diagnostic of the first part it cannot search by
+func sruQuery(node cql.Node) (*entity.BookQuery, error) {
+	switch node := node.(type) {
+	case cql.Boolean:
+		if len(node.Modifiers) > 0 {
+			return nil, sru.NewDiagnostic(sru.ErrUnsupportedBooleanModifier, node.Modifiers[0].Name)
+		}
+
+		var operator string
+		switch node.Operator {
+		case cql.And:
+			operator = constant.BookQueryAnd
+		case cql.Or:
+			operator = constant.BookQueryOr
+		case cql.Not:
+			operator = constant.BookQueryNot
+		default:
+			return nil, sru.NewDiagnostic(sru.ErrUnsupportedBoolean, node.Operator)
+		}
+
+		left, err := sruQuery(node.Left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := sruQuery(node.Right)
+		if err != nil {
+			return nil, err
+		}
+		return &entity.BookQuery{Operator: operator, Left: left, Right: right}, nil
+
+	case cql.SearchClause:
+		field, ok := sruField(node.Index)
+		if !ok {
+			return nil, sru.NewDiagnostic(sru.ErrUnsupportedIndex, node.Index)
+		}
+		if len(node.Relation.Modifiers) > 0 {
+			return nil, sru.NewDiagnostic(sru.ErrUnsupportedRelationModifier, node.Relation.Modifiers[0].Name)
+		}
+		relation, ok := sruRelations[field][node.Relation.Name]
+		if !ok {
+			return nil, sru.NewDiagnostic(sru.ErrUnsupportedRelation, node.Relation.Name)
+		}
+
+		term := strings.TrimSpace(node.Term)
+		if term == "" {
+			return nil, sru.NewDiagnostic(sru.ErrEmptyTerm, node.Index)
+		}
+		switch field {
+		case constant.BookQueryFieldISBN:
+			normalized, ok := isbn.Normalize(term)
+			if !ok {
+				return nil, sru.NewDiagnostic(sru.ErrInvalidTerm, term)
+			}
+			term = normalized
+		case constant.BookQueryFieldYear:
+			if _, err := strconv.Atoi(term); err != nil {
+				return nil, sru.NewDiagnostic(sru.ErrInvalidTerm, term)
+			}
+		}
+		return &entity.BookQuery{Field: field, Relation: relation, Term: term}, nil
+	}
+	return nil, errors.Errorf("unknown node %T", node)
+}
+
+// sruField is the field of books an index searches, by its name in a context set or, without one, by an alias
+func sruField(index string) (string, bool) {
+	set, name, qualified := strings.Cut(index, ".")
+	if !qualified {
+		field, ok := sruIndexAliases[strings.ToLower(index)]
+		return field, ok
+	}
+
+	for _, sruIndex := range sruIndexes {
+		if strings.EqualFold(sruIndex.set, set) && strings.EqualFold(sruIndex.name, name) {
+			return sruIndex.field, true
+		}
+	}
+	return "", false
+}
+
+// sruRecord is the record of a book in the schema and escaping a search asks for
+func sruRecord(api string, book entity.BookResponse, search sruSearch, position int) sru.Record {
+	record := sru.Record{Schema: search.schema, Escaping: search.escaping, Position: position}
+	if search.schema == sru.MARCXMLSchema {
+		record.Data = bookMARCRecord(book)
+	} else {
+		record.Data = sru.DublinCore{Record: bookDublinCore(api, book)}
+	}
+	return record
+}
+
+// sruExplain explains the server in a ZeeRex record: where it is, its indexes, schemas and limits
+func (h *Handler) sruExplain(c *gin.Context, args url.Values) sru.ExplainResponse {
+	origin, _ := url.Parse(requestOrigin(c))
+	host, port := origin.Hostname(), origin.Port()
+	if port == "" {
+		port = "80"
+		if origin.Scheme == "https" {
+			port = "443"
+		}
+	}
+	portNumber, _ := strconv.Atoi(port)
+
+	explain := sru.Explain{
+		ServerInfo: sru.ServerInfo{
+			Protocol:  "SRU",
+			Version:   sru.Version,
+			Transport: origin.Scheme,
+			Host:      host,
+			Port:      portNumber,
+			Database:  strings.TrimPrefix(c.Request.URL.Path, "/"),
+		},
+		DatabaseInfo: sru.DatabaseInfo{Title: h.config.OAIRepositoryName},
+		SchemaInfo: sru.SchemaInfo{Schemas: []sru.Schema{
+			{Identifier: sru.DCSchema, Name: "dc", Title: "Dublin Core"},
+			{Identifier: sru.MARCXMLSchema, Name: "marcxml", Title: "MARCXML"},
+		}},
+		ConfigInfo: sru.ConfigInfo{
+			Defaults: []sru.Setting{
+				{Type: "numberOfRecords", Value: strconv.Itoa(sruDefaultRecords)},
+				{Type: "retrieveSchema", Value: sru.DCSchema},
+			},
+			Settings: []sru.Setting{{Type: "maximumRecords", Value: strconv.Itoa(sruMaxRecords)}},
+		},
+	}
+	for _, set := range sruContextSets {
+		explain.IndexInfo.Sets = append(explain.IndexInfo.Sets, sru.ContextSet{Name: set.name, Identifier: set.identifier})
+	}
+	for _, index := range sruIndexes {
+		explain.IndexInfo.Indexes = append(explain.IndexInfo.Indexes, sru.Index{
+			Title: index.title,
+			Names: []sru.IndexName{{Set: index.set, Name: index.name}},
+		})
+	}
+
+	response := sru.ExplainResponse{
+		Version: sru.Version,
+		Record:  sru.Record{Schema: sru.ExplainSchema, Escaping: sru.EscapingXML, Data: explain},
+	}
+	if version := args.Get("version"); version != "" && version != sru.Version {
+		response.Diagnostics = sru.Diagnostics{sru.NewDiagnostic(sru.ErrUnsupportedVersion, sru.Version)}
+	}
+	return response
+}
+
+// RegisterSRURoutes serves the catalog to SRU clients, which take either GET or POST requests
+func RegisterSRURoutes(router *gin.RouterGroup, handler *Handler) {
+	router.GET(sruPath, handler.HandleSRU)
+	router.POST(sruPath, handler.HandleSRU)
+}
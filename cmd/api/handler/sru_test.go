@@ -0,0 +1,317 @@
+package handler_test
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	"go-library-service/cmd/api/handler"
+	"go-library-service/cmd/api/handler/mock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// sruResponse reads back what the tests look at in an SRU response
+type sruResponse struct {
+	XMLName            xml.Name
+	NumberOfRecords    int64 `xml:"numberOfRecords"`
+	NextRecordPosition int   `xml:"nextRecordPosition"`
+	Records            []struct {
+		Schema   string `xml:"recordSchema"`
+		Escaping string `xml:"recordXMLEscaping"`
+		Data     struct {
+			Inner string `xml:",innerxml"`
+			DC    *struct {
+				Title      []string `xml:"title"`
+				Creator    []string `xml:"creator"`
+				Identifier []string `xml:"identifier"`
+			} `xml:"dc"`
+			MARC *struct {
+				Leader string `xml:"leader"`
+			} `xml:"record"`
+		} `xml:"recordData"`
+		Position int `xml:"recordPosition"`
+	} `xml:"records>record"`
+	Diagnostics []struct {
+		URI     string `xml:"uri"`
+		Details string `xml:"details"`
+	} `xml:"diagnostics>diagnostic"`
+	Explain *struct {
+		Host    string   `xml:"serverInfo>host"`
+		Port    int      `xml:"serverInfo>port"`
+		Title   string   `xml:"databaseInfo>title"`
+		Indexes []string `xml:"indexInfo>index>map>name"`
+		Schemas []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"schemaInfo>schema"`
+	} `xml:"record>recordData>explain"`
+}
+
+var _ = Describe("SRU Handler", func() {
+	var (
+		h           *handler.Handler
+		serviceMock *mock.MockService
+		r           *gin.Engine
+		ctrl        *gomock.Controller
+		dune        entity.BookResponse
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		serviceMock = mock.NewMockService(ctrl)
+		h = handler.NewHandler(&handler.Dependencies{
+			Service:   serviceMock,
+			Validator: validator.New(),
+		}, &handler.Config{OAIRepositoryName: "Library"})
+
+		gin.SetMode(gin.TestMode)
+		r = gin.Default()
+		handler.RegisterSRURoutes(r.Group("/api"), h)
+
+		isbn, year := "9780441172719", 1965
+		dune = entity.BookResponse{
+			ID:              1,
+			Title:           "Dune",
+			Author:          "Frank Herbert",
+			ISBN:            &isbn,
+			PublicationYear: &year,
+			Authors:         []entity.BookAuthorResponse{{ID: 3, Name: "Frank Herbert"}},
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	search := func(args url.Values) sruResponse {
+		req, _ := http.NewRequest(http.MethodGet, "http://library.test/api/sru?"+args.Encode(), nil)
+
+		w := httptest.NewRecorder()
+		c := gin.CreateTestContextOnly(w, r)
+		c.Request = req
+
+		h.HandleSRU(c)
+
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(w.Header().Get("Content-Type")).To(Equal("application/sru+xml; charset=utf-8"))
+
+		var response sruResponse
+		Expect(xml.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+		return response
+	}
+
+	Context("searchRetrieve", func() {
+		It("should translate a query into a book query and answer with Dublin Core records", func() {
+			serviceMock.EXPECT().
+				SearchBooks(gomock.Any()).
+				DoAndReturn(func(req entity.SearchBookRequest) (*entity.SearchBookResult, error) {
+					Expect(req.Offset).To(Equal(0))
+					Expect(req.Limit).To(Equal(10))
+					Expect(req.Query).To(Equal(entity.BookQuery{
+						Operator: constant.BookQueryOr,
+						Left: &entity.BookQuery{
+							Operator: constant.BookQueryAnd,
+							Left:     &entity.BookQuery{Field: constant.BookQueryFieldTitle, Relation: constant.BookQueryRelationContains, Term: "dune"},
+							Right:    &entity.BookQuery{Field: constant.BookQueryFieldAuthor, Relation: constant.BookQueryRelationAll, Term: "frank herbert"},
+						},
+						Right: &entity.BookQuery{Field: constant.BookQueryFieldISBN, Relation: constant.BookQueryRelationExact, Term: "9780441172719"},
+					}))
+					return &entity.SearchBookResult{Books: []entity.BookResponse{dune}, Total: 1}, nil
+				})
+
+			response := search(url.Values{"query": {`dc.title = dune AND author all "frank herbert" or isbn=0-441-17271-7`}})
+
+			Expect(response.XMLName.Local).To(Equal("searchRetrieveResponse"))
+			Expect(response.Diagnostics).To(BeEmpty())
+			Expect(response.NumberOfRecords).To(Equal(int64(1)))
+			Expect(response.NextRecordPosition).To(BeZero())
+			Expect(response.Records).To(HaveLen(1))
+
+			record := response.Records[0]
+			Expect(record.Schema).To(Equal("info:srw/schema/1/dc-v1.1"))
+			Expect(record.Escaping).To(Equal("xml"))
+			Expect(record.Position).To(Equal(1))
+			Expect(record.Data.DC.Title).To(Equal([]string{"Dune"}))
+			Expect(record.Data.DC.Creator).To(Equal([]string{"Frank Herbert"}))
+			Expect(record.Data.DC.Identifier).To(Equal([]string{"urn:isbn:9780441172719", "http://library.test/api/books/1"}))
+		})
+
+		It("should answer with MARCXML records from a later position", func() {
+			serviceMock.EXPECT().
+				SearchBooks(gomock.Any()).
+				DoAndReturn(func(req entity.SearchBookRequest) (*entity.SearchBookResult, error) {
+					Expect(req.Query).To(Equal(entity.BookQuery{Field: constant.BookQueryFieldYear, Relation: constant.BookQueryRelationGe, Term: "1960"}))
+					Expect(req.Offset).To(Equal(4))
+					Expect(req.Limit).To(Equal(1))
+					return &entity.SearchBookResult{Books: []entity.BookResponse{dune}, Total: 12}, nil
+				})
+
+			response := search(url.Values{"query": {"year >= 1960"}, "startRecord": {"5"}, "maximumRecords": {"1"}, "recordSchema": {"marcxml"}})
+
+			Expect(response.Diagnostics).To(BeEmpty())
+			Expect(response.NumberOfRecords).To(Equal(int64(12)))
+			Expect(response.NextRecordPosition).To(Equal(6))
+			Expect(response.Records[0].Schema).To(Equal("info:srw/schema/1/marcxml-v1.1"))
+			Expect(response.Records[0].Position).To(Equal(5))
+			Expect(response.Records[0].Data.MARC).NotTo(BeNil())
+			Expect(response.Records[0].Data.Inner).To(ContainSubstring(`<record xmlns="http://www.loc.gov/MARC21/slim">`))
+		})
+
+		It("should escape records as strings when asked", func() {
+			serviceMock.EXPECT().SearchBooks(gomock.Any()).Return(&entity.SearchBookResult{Books: []entity.BookResponse{dune}, Total: 1}, nil)
+
+			response := search(url.Values{"query": {"dune"}, "recordXMLEscaping": {"string"}})
+
+			Expect(response.Records[0].Escaping).To(Equal("string"))
+			Expect(response.Records[0].Data.DC).To(BeNil())
+			Expect(response.Records[0].Data.Inner).To(ContainSubstring(`&lt;dc:title&gt;Dune&lt;/dc:title&gt;`))
+		})
+
+		It("should search the full text for a term alone and cap the number of records", func() {
+			serviceMock.EXPECT().
+				SearchBooks(gomock.Any()).
+				DoAndReturn(func(req entity.SearchBookRequest) (*entity.SearchBookResult, error) {
+					Expect(req.Query).To(Equal(entity.BookQuery{Field: constant.BookQueryFieldAny, Relation: constant.BookQueryRelationAll, Term: "desert planet"}))
+					Expect(req.Limit).To(Equal(100))
+					return &entity.SearchBookResult{Books: []entity.BookResponse{}, Total: 0}, nil
+				})
+
+			response := search(url.Values{"query": {`"desert planet"`}, "maximumRecords": {"1000"}})
+
+			Expect(response.Diagnostics).To(BeEmpty())
+			Expect(response.NumberOfRecords).To(BeZero())
+			Expect(response.Records).To(BeEmpty())
+		})
+
+		It("should answer a start past the last match with a diagnostic", func() {
+			serviceMock.EXPECT().SearchBooks(gomock.Any()).Return(&entity.SearchBookResult{Books: []entity.BookResponse{}, Total: 3}, nil)
+
+			response := search(url.Values{"query": {"dune"}, "startRecord": {"4"}})
+
+			Expect(response.NumberOfRecords).To(Equal(int64(3)))
+			Expect(response.Diagnostics).To(HaveLen(1))
+			Expect(response.Diagnostics[0].URI).To(Equal("info:srw/diagnostic/1/61"))
+		})
+
+		It("should answer what it cannot search with a diagnostic", func() {
+			for query, diagnostic := range map[string]string{
+				"title = (dune":                     "10",
+				"dc.coverage = arrakis":             "16",
+				"title < dune":                      "19",
+				"title =/stem dune":                 "20",
+				`title = ""`:                        "27",
+				"isbn = 12345":                      "36",
+				"year = sixties":                    "36",
+				"dune prox messiah":                 "37",
+				"dune and/rel.algorithm=cori sand":  "46",
+				"dune sortby title":                 "80",
+				"> x = info:unknown x.title = dune": "15",
+			} {
+				response := search(url.Values{"query": {query}})
+				Expect(response.Diagnostics).To(HaveLen(1), query)
+				Expect(response.Diagnostics[0].URI).To(Equal("info:srw/diagnostic/1/"+diagnostic), query)
+				Expect(response.Records).To(BeEmpty(), query)
+			}
+		})
+
+		It("should take context sets assigned their own names", func() {
+			serviceMock.EXPECT().SearchBooks(gomock.Any()).Return(&entity.SearchBookResult{Books: []entity.BookResponse{}, Total: 0}, nil)
+
+			response := search(url.Values{"query": {`> dc = "info:srw/cql-context-set/1/dc-v1.1" dc.title = dune`}})
+
+			Expect(response.Diagnostics).To(BeEmpty())
+		})
+
+		It("should answer parameters it cannot take with a diagnostic", func() {
+			for _, test := range []struct {
+				args       url.Values
+				diagnostic string
+				details    string
+			}{
+				{url.Values{"query": {"dune"}, "version": {"1.2"}}, "5", "2.0"},
+				{url.Values{"query": {"dune"}, "startRecord": {"0"}}, "6", "startRecord"},
+				{url.Values{"query": {"dune"}, "maximumRecords": {"many"}}, "6", "maximumRecords"},
+				{url.Values{"query": {"dune"}, "recordSchema": {"mods"}}, "66", "mods"},
+				{url.Values{"query": {"dune"}, "recordXMLEscaping": {"json"}}, "71", "json"},
+				{url.Values{"query": {"dune"}, "sortKeys": {"title"}}, "80", "sortKeys"},
+				{url.Values{"query": {"dune"}, "stylesheet": {"sru.xsl"}}, "8", "stylesheet"},
+				{url.Values{"operation": {"searchRetrieve"}}, "7", "query"},
+				{url.Values{"operation": {"scan"}, "scanClause": {"dune"}}, "8", "scanClause"},
+				{url.Values{"operation": {"scan"}}, "4", "scan"},
+			} {
+				response := search(test.args)
+				Expect(response.Diagnostics).To(HaveLen(1), test.args.Encode())
+				Expect(response.Diagnostics[0].URI).To(Equal("info:srw/diagnostic/1/"+test.diagnostic), test.args.Encode())
+				Expect(response.Diagnostics[0].Details).To(Equal(test.details), test.args.Encode())
+			}
+		})
+
+		It("should ignore extension parameters", func() {
+			serviceMock.EXPECT().SearchBooks(gomock.Any()).Return(&entity.SearchBookResult{Books: []entity.BookResponse{}, Total: 0}, nil)
+
+			response := search(url.Values{"query": {"dune"}, "x-info-5-restrictionSet": {"public"}})
+
+			Expect(response.Diagnostics).To(BeEmpty())
+		})
+
+		It("should answer a POST request", func() {
+			serviceMock.EXPECT().SearchBooks(gomock.Any()).Return(&entity.SearchBookResult{Books: []entity.BookResponse{dune}, Total: 1}, nil)
+
+			req, _ := http.NewRequest(http.MethodPost, "http://library.test/api/sru", strings.NewReader("query=title%3Ddune"))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.HandleSRU(c)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Body.String()).To(ContainSubstring(`<numberOfRecords>1</numberOfRecords>`))
+		})
+
+		It("should return internal server error when the search fails", func() {
+			serviceMock.EXPECT().SearchBooks(gomock.Any()).Return(nil, errors.New("connection refused"))
+
+			req, _ := http.NewRequest(http.MethodGet, "http://library.test/api/sru?query=dune", nil)
+			w := httptest.NewRecorder()
+			c := gin.CreateTestContextOnly(w, r)
+			c.Request = req
+
+			h.HandleSRU(c)
+
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	Context("explain", func() {
+		It("should explain the server to a request without a query", func() {
+			response := search(url.Values{})
+
+			Expect(response.XMLName.Local).To(Equal("explainResponse"))
+			Expect(response.Diagnostics).To(BeEmpty())
+			Expect(response.Explain.Host).To(Equal("library.test"))
+			Expect(response.Explain.Port).To(Equal(80))
+			Expect(response.Explain.Title).To(Equal("Library"))
+			Expect(response.Explain.Indexes).To(ContainElements("serverChoice", "title", "creator", "isbn"))
+			Expect(response.Explain.Schemas).To(HaveLen(2))
+			Expect(response.Explain.Schemas[1].Name).To(Equal("marcxml"))
+		})
+
+		It("should explain the server when asked to", func() {
+			response := search(url.Values{"operation": {"explain"}, "version": {"1.1"}})
+
+			Expect(response.XMLName.Local).To(Equal("explainResponse"))
+			Expect(response.Diagnostics[0].URI).To(Equal("info:srw/diagnostic/1/5"))
+		})
+	})
+})
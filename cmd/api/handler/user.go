@@ -204,6 +204,57 @@ func (h *Handler) DeleteUser(c *gin.Context) {
 	c.AbortWithStatus(http.StatusOK)
 }
 
+// AssignCardNumber handles assigning a library card to a user
+// @Summary Assign a library card
+// @Description Assign the library card number used to find the user at the circulation desk
+// @Tags management users
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id   path      int  true  "User ID"
+// @Param   card  body      entity.UserCardRequest  true  "Library card"
+// @Success 200 {object} entity.ResponseData
+// @Failure 400 {object} entity.ResponseError
+// @Failure 404 {object} entity.ResponseError
+// @Failure 409 {object} entity.ResponseError
+// @Failure 500 {object} entity.ResponseError
+// @Router /management/users/{id}/card [put]
+func (h *Handler) AssignCardNumber(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid user id", Code: http.StatusBadRequest})
+		return
+	}
+
+	var req entity.UserCardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, entity.ResponseError{Error: "invalid request", Code: http.StatusBadRequest})
+		return
+	}
+
+	if err := h.deps.Validator.Struct(req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, entity.ResponseError{Error: err.Error(), Code: http.StatusBadRequest})
+		return
+	}
+
+	req.ID = uint(userID)
+
+	if err := h.deps.Service.AssignCardNumber(req); err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, entity.ResponseError{Error: "user not found", Code: http.StatusNotFound})
+			return
+		}
+		if errors.Is(err, errmap.ErrmapConflict) {
+			c.AbortWithStatusJSON(http.StatusConflict, entity.ResponseError{Error: "card number already in use", Code: http.StatusConflict})
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, entity.ResponseError{Error: "unable to assign card number", Code: http.StatusInternalServerError})
+		return
+	}
+
+	c.AbortWithStatus(http.StatusOK)
+}
+
 // RegisterUserRoutes registers user routes
 func RegisterUserRoutes(router *gin.RouterGroup, handler *Handler) {
 
@@ -220,5 +271,6 @@ func RegisterUserRoutes(router *gin.RouterGroup, handler *Handler) {
 	managementUserRoutes.Use(middleware.RoleMiddleware(constant.UserTypeStaff))
 	{
 		managementUserRoutes.DELETE("/:id", handler.DeleteUser)
+		managementUserRoutes.PUT("/:id/card", handler.AssignCardNumber)
 	}
 }
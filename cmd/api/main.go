@@ -1,14 +1,17 @@
 package main
 
 import (
+	"encoding/json"
 	"go-library-service/cmd/api/constant"
 	"go-library-service/cmd/api/docs"
 	"go-library-service/cmd/api/entity"
 	"go-library-service/cmd/api/handler"
 	"go-library-service/cmd/api/repository"
 	"go-library-service/cmd/api/service"
+	"go-library-service/internal/blob"
 	"go-library-service/internal/utils"
 	"os"
+	"time"
 
 	"github.com/fvbock/endless"
 	"github.com/gin-gonic/gin"
@@ -66,24 +69,114 @@ func initRedisRepository() *repository.RedisRepository {
 	return r
 }
 
+// loanPolicy is the JSON shape of a loan policy in the LOAN_POLICIES env, a field left out keeps its default
+// while a zero is kept, e.g. {"maxRenewals": 0} allows no renewals
+type loanPolicy struct {
+	MaxActiveLoans *uint    `json:"maxActiveLoans"`
+	LoanPeriodDays *uint    `json:"loanPeriodDays"`
+	MaxRenewals    *uint    `json:"maxRenewals"`
+	FineRatePerDay *float64 `json:"fineRatePerDay"`
+}
+
+// initLoanPolicies reads per role loan policies, e.g. {"STAFF": {"maxActiveLoans": 20}}
+func initLoanPolicies() map[string]service.LoanPolicy {
+	env := os.Getenv("LOAN_POLICIES")
+	if env == "" {
+		return nil
+	}
+
+	var policies map[string]loanPolicy
+	if err := json.Unmarshal([]byte(env), &policies); err != nil {
+		log.Error("Failed to parse LOAN_POLICIES: ", err)
+		panic(err)
+	}
+
+	loanPolicies := make(map[string]service.LoanPolicy, len(policies))
+	for role, policy := range policies {
+		loanPolicy := service.LoanPolicy{
+			MaxActiveLoans: policy.MaxActiveLoans,
+			MaxRenewals:    policy.MaxRenewals,
+			FineRatePerDay: policy.FineRatePerDay,
+		}
+		if policy.LoanPeriodDays != nil {
+			loanPeriod := time.Duration(*policy.LoanPeriodDays) * 24 * time.Hour
+			loanPolicy.LoanPeriod = &loanPeriod
+		}
+		loanPolicies[role] = loanPolicy
+	}
+
+	return loanPolicies
+}
+
+// Where covers are kept, and the path covers kept on the local filesystem are served from
+const (
+	coverStorageLocal = "local"
+	coverStorageS3    = "s3"
+	coverPath         = "/covers"
+)
+
+// initBlobStorage picks where covers are kept, the local filesystem unless COVER_STORAGE is s3
+func initBlobStorage() service.BlobStorage {
+	switch storage := utils.StringEnv("COVER_STORAGE", coverStorageLocal); storage {
+	case coverStorageLocal:
+		return blob.NewLocalStorage(
+			utils.StringEnv("COVER_DIR", "./data/covers"),
+			utils.StringEnv("COVER_BASE_URL", "http://localhost:8080"+coverPath),
+		)
+	case coverStorageS3:
+		return blob.NewS3Storage(blob.S3Config{
+			Endpoint:        utils.RequiredEnv("S3_ENDPOINT"),
+			Region:          utils.StringEnv("S3_REGION", "us-east-1"),
+			Bucket:          utils.RequiredEnv("S3_BUCKET"),
+			AccessKeyID:     utils.RequiredEnv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: utils.RequiredEnv("S3_SECRET_ACCESS_KEY"),
+			PublicURL:       utils.StringEnv("S3_PUBLIC_URL", ""),
+		})
+	default:
+		log.Error("Unknown COVER_STORAGE: ", storage)
+		panic("unknown COVER_STORAGE " + storage)
+	}
+}
+
 func initService() *service.Service {
+	maxRenewals := uint(utils.IntEnv("MAX_RENEWALS", 2))
+
 	return service.NewService(
 		&service.Dependencies{
 			PostgresRepo: initPostgresRepository(),
 			BcryptService: utils.NewBcryptService(),
 			RedisRepo: initRedisRepository(),
+			BlobStorage: initBlobStorage(),
+		},
+		&service.Config{
+			LoanPeriod:       time.Duration(utils.IntEnv("LOAN_PERIOD_DAYS", 14)) * 24 * time.Hour,
+			MaxRenewals:      &maxRenewals,
+			HoldPickupWindow: time.Duration(utils.IntEnv("HOLD_PICKUP_DAYS", 3)) * 24 * time.Hour,
+			FineRatePerDay:   utils.FloatEnv("FINE_RATE_PER_DAY", 5),
+			MaxActiveLoans:   uint(utils.IntEnv("MAX_ACTIVE_LOANS", 5)),
+			LoanPolicies:     initLoanPolicies(),
+			MaxCoverSize:     int64(utils.IntEnv("MAX_COVER_SIZE_KB", 5120)) << 10,
 		},
-		&service.Config{},
 	)
 }
 
 func initHandler() *handler.Handler {
+	validate := validator.New()
+	if err := service.RegisterValidations(validate); err != nil {
+		log.Error("Failed to register validations: ", err)
+		panic(err)
+	}
+
 	return handler.NewHandler(
 		&handler.Dependencies{
 			Service: initService(),
-			Validator: validator.New(),
+			Validator: validate,
+		},
+		&handler.Config{
+			OAIRepositoryName:       utils.StringEnv("OAI_REPOSITORY_NAME", "Go Library Service"),
+			OAIRepositoryIdentifier: utils.StringEnv("OAI_REPOSITORY_IDENTIFIER", ""),
+			OAIAdminEmail:           utils.StringEnv("OAI_ADMIN_EMAIL", "admin@localhost"),
 		},
-		&handler.Config{},
 	)
 }
 
@@ -115,5 +208,8 @@ func main() {
 	port := os.Getenv("APP_PORT")
 	
 	handler.InitRoute(g.Group("/api"), h)
+	if utils.StringEnv("COVER_STORAGE", coverStorageLocal) == coverStorageLocal {
+		g.Static(coverPath, utils.StringEnv("COVER_DIR", "./data/covers"))
+	}
 	endless.ListenAndServe(":"+port, g)
 }
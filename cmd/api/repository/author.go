@@ -0,0 +1,365 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"go-library-service/cmd/api/entity"
+	errmap "go-library-service/internal/error_map"
+	"go-library-service/internal/search"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// authorResponseColumns select an author with the number of catalog books crediting them
+const authorResponseColumns = "authors.*, (SELECT COUNT(*) FROM book_authors JOIN books ON books.id = book_authors.book_id " +
+	"WHERE book_authors.author_id = authors.id AND books.deleted_at IS NULL) AS book_count"
+
+// authorCreditSeparator joins the names of a credit line so it splits back into the same authors
+const authorCreditSeparator = "; "
+
+// authorOrder sorts authors by name
+var authorOrder = keysetOrder{{SQL: "authors.name"}, {SQL: "authors.id"}}
+
+// CreateAuthor creates an author unless one with the same name under another spelling exists
+func (r *PostgresRepository) CreateAuthor(author *entity.Author) (*entity.AuthorResponse, error) {
+	author.NameKey = search.NameKey(author.Name)
+
+	var count int64
+	if err := r.postgres.Table("authors").Where("name_key = ?", author.NameKey).Count(&count).Error; err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.CreateAuthor]: unable to check name")
+	}
+
+	if count > 0 {
+		return nil, errmap.ErrmapConflict
+	}
+
+	if err := r.postgres.Table("authors").Create(author).Error; err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.CreateAuthor]: unable to create author")
+	}
+
+	return &entity.AuthorResponse{
+		ID:        author.ID,
+		Name:      author.Name,
+		CreatedAt: author.CreatedAt,
+		UpdatedAt: author.UpdatedAt,
+	}, nil
+}
+
+// GetAuthorByID retrieves an author by ID
+func (r *PostgresRepository) GetAuthorByID(authorID uint) (*entity.AuthorResponse, error) {
+	var author entity.AuthorResponse
+	err := r.postgres.Table("authors").
+		Select(authorResponseColumns).
+		Where("authors.id = ?", authorID).
+		Take(&author).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmap.ErrmapNotFound
+		}
+		return nil, errors.Wrap(err, "[PostgresRepository.GetAuthorByID]: unable to get author")
+	}
+	return &author, nil
+}
+
+// GetAuthorsByIDs retrieves the authors with the given IDs, leaving out the ones that do not exist
+func (r *PostgresRepository) GetAuthorsByIDs(authorIDs []uint) ([]entity.Author, error) {
+	var authors []entity.Author
+	if err := r.postgres.Table("authors").Where("id IN ?", authorIDs).Find(&authors).Error; err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.GetAuthorsByIDs]: unable to get authors")
+	}
+	return authors, nil
+}
+
+// ListAuthors lists a page of authors by name
+func (r *PostgresRepository) ListAuthors(req entity.ListAuthorRequest) ([]entity.AuthorResponse, error) {
+	var authors []entity.AuthorResponse
+	query, err := paginate(r.listableAuthors(req).Select(authorResponseColumns), authorOrder, req.Page, req.Size, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := query.Find(&authors).Error; err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.ListAuthors]: unable to get authors")
+	}
+	return authors, nil
+}
+
+// CountAuthors counts the authors matching a listing
+func (r *PostgresRepository) CountAuthors(req entity.ListAuthorRequest) (int64, error) {
+	var total int64
+	if err := r.listableAuthors(req).Count(&total).Error; err != nil {
+		return 0, errors.Wrap(err, "[PostgresRepository.CountAuthors]: unable to count authors")
+	}
+	return total, nil
+}
+
+// listableAuthors selects the authors matching a listing
+func (r *PostgresRepository) listableAuthors(req entity.ListAuthorRequest) *gorm.DB {
+	query := r.postgres.Table("authors")
+	if req.Search != nil && strings.TrimSpace(*req.Search) != "" {
+		query = query.Where("authors.name ILIKE ?", "%"+escapeLike(strings.TrimSpace(*req.Search))+"%")
+	}
+	return query
+}
+
+// UpdateAuthor renames an author and rewrites the credit lines of their books, which count as changed
+func (r *PostgresRepository) UpdateAuthor(author entity.Author, updatedAt time.Time) error {
+	tx := r.postgres.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return errors.Wrap(tx.Error, "[PostgresRepository.UpdateAuthor]: unable to begin transaction")
+	}
+
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Table("authors").First(&entity.Author{}, author.ID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errmap.ErrmapNotFound
+		}
+		return errors.Wrap(err, "[PostgresRepository.UpdateAuthor]: unable to get author")
+	}
+
+	nameKey := search.NameKey(author.Name)
+	var count int64
+	if err := tx.Table("authors").Where("name_key = ? AND id <> ?", nameKey, author.ID).Count(&count).Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.UpdateAuthor]: unable to check name")
+	}
+
+	if count > 0 {
+		tx.Rollback()
+		return errmap.ErrmapConflict
+	}
+
+	if err := tx.Table("authors").Where("id = ?", author.ID).Updates(map[string]interface{}{
+		"name":       author.Name,
+		"name_key":   nameKey,
+		"updated_at": updatedAt,
+	}).Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.UpdateAuthor]: unable to update author")
+	}
+
+	var bookIDs []uint
+	if err := tx.Table("book_authors").Where("author_id = ?", author.ID).Pluck("book_id", &bookIDs).Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.UpdateAuthor]: unable to get books")
+	}
+
+	for _, bookID := range bookIDs {
+		if err := refreshBookCredit(tx, bookID); err != nil {
+			tx.Rollback()
+			return errors.Wrap(err, "[PostgresRepository.UpdateAuthor]: unable to refresh book credit")
+		}
+	}
+
+	if len(bookIDs) > 0 {
+		if err := tx.Table("books").Where("id IN ?", bookIDs).Update("updated_at", updatedAt).Error; err != nil {
+			tx.Rollback()
+			return errors.Wrap(err, "[PostgresRepository.UpdateAuthor]: unable to touch books")
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.UpdateAuthor]: unable to commit transaction")
+	}
+
+	return nil
+}
+
+// DeleteAuthor deletes an author no book credits, archived books included
+func (r *PostgresRepository) DeleteAuthor(authorID uint) error {
+	tx := r.postgres.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return errors.Wrap(tx.Error, "[PostgresRepository.DeleteAuthor]: unable to begin transaction")
+	}
+
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Table("authors").First(&entity.Author{}, authorID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errmap.ErrmapNotFound
+		}
+		return errors.Wrap(err, "[PostgresRepository.DeleteAuthor]: unable to get author")
+	}
+
+	var count int64
+	if err := tx.Table("book_authors").Where("author_id = ?", authorID).Count(&count).Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.DeleteAuthor]: unable to count books")
+	}
+
+	if count > 0 {
+		tx.Rollback()
+		return errmap.ErrmapConflict
+	}
+
+	if err := tx.Table("authors").Where("id = ?", authorID).Delete(&entity.Author{}).Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.DeleteAuthor]: unable to delete author")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.DeleteAuthor]: unable to commit transaction")
+	}
+
+	return nil
+}
+
+// ListBooksByAuthorID lists the catalog books crediting an author by title
+func (r *PostgresRepository) ListBooksByAuthorID(authorID uint) ([]entity.BookResponse, error) {
+	var books []entity.BookResponse
+	err := r.postgres.Table("books").
+		Select("books.*").
+		Joins("JOIN book_authors ON book_authors.book_id = books.id").
+		Where("book_authors.author_id = ? AND books.deleted_at IS NULL", authorID).
+		Order("books.title ASC, books.id ASC").
+		Find(&books).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.ListBooksByAuthorID]: unable to get books")
+	}
+
+	if err := attachBookDetails(r.postgres, books); err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.ListBooksByAuthorID]: unable to get book details")
+	}
+	return books, nil
+}
+
+// creditBookAuthors credits authors on a book in order, creating the ones not known by any spelling yet,
+// and rewrites its credit line and search index
+func creditBookAuthors(tx *gorm.DB, bookID uint, authors []entity.Author) error {
+	if err := tx.Table("book_authors").Where("book_id = ?", bookID).Delete(&entity.BookAuthor{}).Error; err != nil {
+		return errors.Wrap(err, "[creditBookAuthors]: unable to clear authors")
+	}
+
+	credited := map[uint]bool{}
+	for _, author := range authors {
+		if author.ID == 0 {
+			author.NameKey = search.NameKey(author.Name)
+			if err := tx.Table("authors").Where("name_key = ?", author.NameKey).FirstOrCreate(&author).Error; err != nil {
+				return errors.Wrap(err, "[creditBookAuthors]: unable to get author")
+			}
+		}
+
+		if credited[author.ID] {
+			continue
+		}
+		credited[author.ID] = true
+
+		link := entity.BookAuthor{BookID: bookID, AuthorID: author.ID, Position: len(credited)}
+		if err := tx.Table("book_authors").Create(&link).Error; err != nil {
+			return errors.Wrap(err, "[creditBookAuthors]: unable to credit author")
+		}
+	}
+
+	return refreshBookCredit(tx, bookID)
+}
+
+// refreshBookCredit writes the names of the authors of a book into its credit line and reindexes it
+func refreshBookCredit(tx *gorm.DB, bookID uint) error {
+	var names []string
+	err := tx.Table("book_authors").
+		Joins("JOIN authors ON authors.id = book_authors.author_id").
+		Where("book_authors.book_id = ?", bookID).
+		Order("book_authors.position ASC").
+		Pluck("authors.name", &names).Error
+	if err != nil {
+		return errors.Wrap(err, "[refreshBookCredit]: unable to get author names")
+	}
+
+	if err := tx.Table("books").Where("id = ?", bookID).Update("author", strings.Join(names, authorCreditSeparator)).Error; err != nil {
+		return errors.Wrap(err, "[refreshBookCredit]: unable to update credit")
+	}
+
+	return indexBook(tx, bookID)
+}
+
+// attachBookAuthors fills in the authors credited on each book
+func attachBookAuthors(db *gorm.DB, books []entity.BookResponse) error {
+	if len(books) == 0 {
+		return nil
+	}
+
+	bookIDs := make([]uint, len(books))
+	for i, book := range books {
+		bookIDs[i] = book.ID
+	}
+
+	var credits []struct {
+		BookID uint
+		ID     uint
+		Name   string
+	}
+	err := db.Table("book_authors").
+		Select("book_authors.book_id, authors.id, authors.name").
+		Joins("JOIN authors ON authors.id = book_authors.author_id").
+		Where("book_authors.book_id IN ?", bookIDs).
+		Order("book_authors.book_id ASC, book_authors.position ASC").
+		Find(&credits).Error
+	if err != nil {
+		return errors.Wrap(err, "[attachBookAuthors]: unable to get authors")
+	}
+
+	authors := map[uint][]entity.BookAuthorResponse{}
+	for _, credit := range credits {
+		authors[credit.BookID] = append(authors[credit.BookID], entity.BookAuthorResponse{ID: credit.ID, Name: credit.Name})
+	}
+
+	for i := range books {
+		books[i].Authors = authors[books[i].ID]
+		if books[i].Authors == nil {
+			books[i].Authors = []entity.BookAuthorResponse{}
+		}
+	}
+	return nil
+}
+
+// backfillBookAuthors credits authors on the books kept from before authors had records of their own,
+// splitting their credit lines into names
+func backfillBookAuthors(db *gorm.DB) error {
+	var books []entity.Book
+	err := db.Table("books").
+		Select("id", "author").
+		Where("author <> '' AND NOT EXISTS (SELECT 1 FROM book_authors WHERE book_authors.book_id = books.id)").
+		FindInBatches(&books, 500, func(batch *gorm.DB, _ int) error {
+			for _, book := range books {
+				var authors []entity.Author
+				for _, name := range search.SplitNames(book.Author) {
+					authors = append(authors, entity.Author{Name: name})
+				}
+
+				// A credit line without a name is left as it is
+				if len(authors) == 0 {
+					continue
+				}
+
+				if err := creditBookAuthors(batch, book.ID, authors); err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return errors.Wrap(err, "[backfillBookAuthors]: unable to credit authors")
+	}
+	return nil
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern
+func escapeLike(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(text)
+}
@@ -0,0 +1,42 @@
+package repository_test
+
+import (
+	"go-library-service/cmd/api/entity"
+	"go-library-service/cmd/api/repository"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Author Repository", func() {
+	var (
+		statements []string
+		r          *repository.PostgresRepository
+	)
+
+	BeforeEach(func() {
+		statements = nil
+		r = repository.NewPostgresRepositoryWithDB(dryRunDB(&statements))
+	})
+
+	Context("ListAuthors", func() {
+		It("should list authors by name with their book count", func() {
+			search := " 100%_ "
+			_, err := r.ListAuthors(entity.ListAuthorRequest{Page: 2, Size: 10, Search: &search})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(statements).To(Equal([]string{`SELECT authors.*, ` +
+				`(SELECT COUNT(*) FROM book_authors JOIN books ON books.id = book_authors.book_id WHERE book_authors.author_id = authors.id AND books.deleted_at IS NULL) AS book_count ` +
+				`FROM "authors" WHERE authors.name ILIKE '%100\%\_%' ORDER BY authors.name ASC, authors.id ASC LIMIT 10 OFFSET 10`}))
+		})
+	})
+
+	Context("CountAuthors", func() {
+		It("should count all authors without a search", func() {
+			_, err := r.CountAuthors(entity.ListAuthorRequest{Page: 1, Size: 10})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(statements).To(Equal([]string{`SELECT count(*) FROM "authors"`}))
+		})
+	})
+})
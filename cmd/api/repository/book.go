@@ -1,25 +1,62 @@
 package repository
 
 import (
+	"strings"
+	"time"
+
+	"go-library-service/cmd/api/constant"
 	"go-library-service/cmd/api/entity"
 	errmap "go-library-service/internal/error_map"
+	"go-library-service/internal/isbn"
+	"go-library-service/internal/search"
 
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
-// CreateBook creates a new book
-func (r *PostgresRepository) CreateBook(book entity.Book) error {
-	err := r.postgres.Table("books").Create(&book).Error
-	if err != nil {
+// CreateBook creates a new book with one copy per unit of stock, crediting its authors in order
+// and filing it under its categories and subjects
+func (r *PostgresRepository) CreateBook(book entity.Book, authors []entity.Author, categoryIDs []uint, subjects []string) error {
+	tx := r.postgres.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return errors.Wrap(tx.Error, "[PostgresRepository.CreateBook]: unable to begin transaction")
+	}
+
+	if book.ISBN != nil {
+		var count int64
+		if err := tx.Table("books").Where("isbn = ?", *book.ISBN).Count(&count).Error; err != nil {
+			tx.Rollback()
+			return errors.Wrap(err, "[PostgresRepository.CreateBook]: unable to check isbn")
+		}
+
+		if count > 0 {
+			tx.Rollback()
+			return errmap.ErrmapConflict
+		}
+	}
+
+	if err := createBook(tx, &book, authors, categoryIDs, subjects); err != nil {
+		tx.Rollback()
 		return errors.Wrap(err, "[PostgresRepository.CreateBook]: unable to create book")
 	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.CreateBook]: unable to commit transaction")
+	}
+
 	return nil
 }
 
-// UpdateBook updates a book 
-func (r *PostgresRepository) UpdateBook(book entity.Book) error {
+// UpdateBook updates a book and credits its authors in order, replacing its categories and subjects when given
+func (r *PostgresRepository) UpdateBook(book entity.Book, authors []entity.Author, categoryIDs []uint, subjects []string) error {
     tx := r.postgres.Begin()
 	defer func() {
 		if r := recover(); r != nil {
@@ -40,8 +77,20 @@ func (r *PostgresRepository) UpdateBook(book entity.Book) error {
         return errors.Wrap(err, "[PostgresRepository.UpdateBook]: unable to find book")
     }
 
-    err = tx.Table("books").Where("id = ?", book.ID).Updates(book).Error
-    if err != nil {
+    if book.ISBN != nil {
+        var count int64
+        if err := tx.Table("books").Where("isbn = ? AND id <> ?", *book.ISBN, book.ID).Count(&count).Error; err != nil {
+            tx.Rollback()
+            return errors.Wrap(err, "[PostgresRepository.UpdateBook]: unable to check isbn")
+        }
+
+        if count > 0 {
+            tx.Rollback()
+            return errmap.ErrmapConflict
+        }
+    }
+
+    if err := updateBook(tx, book, authors, categoryIDs, subjects); err != nil {
         tx.Rollback()
         return errors.Wrap(err, "[PostgresRepository.UpdateBook]: unable to update book")
     }
@@ -54,6 +103,49 @@ func (r *PostgresRepository) UpdateBook(book entity.Book) error {
     return nil
 }
 
+// createBook creates a book with one copy per unit of stock, crediting its authors and classifying it
+func createBook(tx *gorm.DB, book *entity.Book, authors []entity.Author, categoryIDs []uint, subjects []string) error {
+	if err := tx.Table("books").Create(book).Error; err != nil {
+		return errors.Wrap(err, "[createBook]: unable to create book")
+	}
+
+	if _, err := createBookCopies(tx, book.ID, 1, int(book.Stock), constant.CopyStatusAvailable); err != nil {
+		return errors.Wrap(err, "[createBook]: unable to create book copies")
+	}
+
+	if err := classifyBook(tx, book.ID, categoryIDs, subjects); err != nil {
+		return errors.Wrap(err, "[createBook]: unable to classify book")
+	}
+
+	// Crediting the authors reindexes the book, subjects included
+	if err := creditBookAuthors(tx, book.ID, authors); err != nil {
+		return errors.Wrap(err, "[createBook]: unable to credit authors")
+	}
+	return nil
+}
+
+// updateBook rewrites the catalog record of a book, leaving its copies as they are
+func updateBook(tx *gorm.DB, book entity.Book, authors []entity.Author, categoryIDs []uint, subjects []string) error {
+	// Listing the columns lets blank bibliographic fields clear what was there
+	err := tx.Table("books").
+		Where("id = ?", book.ID).
+		Select("title", "author", "price", "isbn", "publisher", "publication_year", "edition", "language", "page_count").
+		Updates(book).Error
+	if err != nil {
+		return errors.Wrap(err, "[updateBook]: unable to update book")
+	}
+
+	if err := classifyBook(tx, book.ID, categoryIDs, subjects); err != nil {
+		return errors.Wrap(err, "[updateBook]: unable to classify book")
+	}
+
+	// Crediting the authors reindexes the book, subjects included
+	if err := creditBookAuthors(tx, book.ID, authors); err != nil {
+		return errors.Wrap(err, "[updateBook]: unable to credit authors")
+	}
+	return nil
+}
+
 // GetBookByID retrieves a book by ID
 func (r *PostgresRepository) GetBookByID(bookID uint) (*entity.BookResponse, error) {
 	var book entity.BookResponse
@@ -64,33 +156,428 @@ func (r *PostgresRepository) GetBookByID(bookID uint) (*entity.BookResponse, err
 		}
 		return nil, errors.Wrap(err, "[PostgresRepository.GetBookByID]: unable to get book")
 	}
-	return &book, nil
+
+	books := []entity.BookResponse{book}
+	if err := attachBookDetails(r.postgres, books); err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.GetBookByID]: unable to get book details")
+	}
+	return &books[0], nil
 }
 
-// ListBook lists books with pagination
-func (r *PostgresRepository) ListBook(req entity.ListBookRequest) ([]entity.BookResponse, error) {
+// GetBookByISBN retrieves a book by its ISBN-13
+func (r *PostgresRepository) GetBookByISBN(isbn string) (*entity.BookResponse, error) {
 	var books []entity.BookResponse
-	query := r.postgres.Table("books")
+	if err := r.postgres.Table("books").Where("isbn = ?", isbn).Limit(1).Find(&books).Error; err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.GetBookByISBN]: unable to get book")
+	}
 
-	if req.Search != nil {
-		query = query.Where("title ILIKE ?", "%"+*req.Search+"%")
+	if len(books) == 0 {
+		return nil, errmap.ErrmapNotFound
 	}
 
-	err := query.Debug().Offset((req.Page - 1) * req.Size).
-		Limit(req.Size).
-		Find(&books).Error
+	if err := attachBookDetails(r.postgres, books); err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.GetBookByISBN]: unable to get book details")
+	}
+	return &books[0], nil
+}
+
+// ListBook lists a page of books in the requested order or best search matches first,
+// with the cursor of the page after it when paging by cursor and there is one
+func (r *PostgresRepository) ListBook(req entity.ListBookRequest) ([]entity.BookResponse, *string, error) {
+	var books []entity.BookResponse
+	_, rank := bookSearch(req)
+	order := bookOrder(req.Sort, rank)
+
+	query, err := paginate(r.listableBooks(req, ""), order, req.Page, req.Size, req.Cursor)
 	if err != nil {
-		return nil, errors.Wrap(err, "[PostgresRepository.ListBook]: unable to get books")
+		return nil, nil, err
 	}
-	return books, nil
+
+	if err := query.Find(&books).Error; err != nil {
+		return nil, nil, errors.Wrap(err, "[PostgresRepository.ListBook]: unable to get books")
+	}
+
+	var next *string
+	if req.Cursor != nil && len(books) > req.Size {
+		books = books[:req.Size]
+		if next, err = r.nextCursor("books", order, books[len(books)-1].ID); err != nil {
+			return nil, nil, errors.Wrap(err, "[PostgresRepository.ListBook]: unable to get next cursor")
+		}
+	}
+
+	if err := attachBookDetails(r.postgres, books); err != nil {
+		return nil, nil, errors.Wrap(err, "[PostgresRepository.ListBook]: unable to get book details")
+	}
+	return books, next, nil
 }
 
-// ListLatestBooks lists latest books
-func (r *PostgresRepository) ListLatestBooks() ([]entity.BookResponse, error) {
+// CountBooks counts the books matching a listing
+func (r *PostgresRepository) CountBooks(req entity.ListBookRequest) (int64, error) {
+	var total int64
+	if err := r.listableBooks(req, "").Count(&total).Error; err != nil {
+		return 0, errors.Wrap(err, "[PostgresRepository.CountBooks]: unable to count books")
+	}
+	return total, nil
+}
+
+// ListLatestBooks lists latest books, in a category and with a subject when given
+func (r *PostgresRepository) ListLatestBooks(req entity.ListLatestBookRequest) ([]entity.BookResponse, error) {
 	var books []entity.BookResponse
-	err := r.postgres.Table("books").Order("created_at DESC").Limit(5).Find(&books).Error
+	query := classifiedBooks(r.postgres.Table("books").Where("deleted_at IS NULL"), req.CategoryID, req.Subject)
+	err := query.Order("created_at DESC").Limit(5).Find(&books).Error
 	if err != nil {
 		return nil, errors.Wrap(err, "[PostgresRepository.ListLatestBooks]: unable to get latest books")
 	}
+
+	if err := attachBookDetails(r.postgres, books); err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.ListLatestBooks]: unable to get book details")
+	}
 	return books, nil
-}
\ No newline at end of file
+}
+
+// ArchiveBook withdraws a book from the catalog, cancelling its holds and freeing their copies
+func (r *PostgresRepository) ArchiveBook(bookID uint, archivedAt time.Time) error {
+	tx := r.postgres.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return errors.Wrap(tx.Error, "[PostgresRepository.ArchiveBook]: unable to begin transaction")
+	}
+
+	var book entity.Book
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Table("books").First(&book, bookID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errmap.ErrmapNotFound
+		}
+		return errors.Wrap(err, "[PostgresRepository.ArchiveBook]: unable to get book")
+	}
+
+	if book.DeletedAt != nil {
+		tx.Rollback()
+		return errmap.ErrmapConflict
+	}
+
+	var activeLoans int64
+	if err := tx.Table("borrow_histories").
+		Where("book_id = ? AND returned_at IS NULL AND status IN ?", bookID, []string{constant.BorrowStatusBorrowed, constant.BorrowStatusOverdue}).
+		Count(&activeLoans).Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.ArchiveBook]: unable to count active loans")
+	}
+
+	if activeLoans > 0 {
+		tx.Rollback()
+		return errmap.ErrmapActiveLoans
+	}
+
+	if err := tx.Table("holds").
+		Where("book_id = ? AND status IN ?", bookID, activeHoldStatuses).
+		Updates(map[string]interface{}{
+			"status":     constant.HoldStatusCancelled,
+			"updated_at": archivedAt,
+		}).Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.ArchiveBook]: unable to cancel holds")
+	}
+
+	if err := tx.Table("book_copies").
+		Where("book_id = ? AND status = ?", bookID, constant.CopyStatusOnHold).
+		Updates(map[string]interface{}{
+			"status":     constant.CopyStatusAvailable,
+			"updated_at": archivedAt,
+		}).Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.ArchiveBook]: unable to release held copies")
+	}
+
+	if err := syncBookStock(tx, bookID); err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.ArchiveBook]: unable to sync stock")
+	}
+
+	// Harvesters pick up the archived book as a deleted record by when it last changed
+	if err := tx.Table("books").Where("id = ?", bookID).Updates(map[string]interface{}{
+		"deleted_at": archivedAt,
+		"updated_at": archivedAt,
+	}).Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.ArchiveBook]: unable to archive book")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.ArchiveBook]: unable to commit transaction")
+	}
+
+	return nil
+}
+
+// RestoreBook puts an archived book back in the catalog
+func (r *PostgresRepository) RestoreBook(bookID uint, restoredAt time.Time) error {
+	result := r.postgres.Table("books").
+		Where("id = ? AND deleted_at IS NOT NULL", bookID).
+		Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"updated_at": restoredAt,
+		})
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "[PostgresRepository.RestoreBook]: unable to restore book")
+	}
+
+	if result.RowsAffected == 0 {
+		return errmap.ErrmapConflict
+	}
+
+	return nil
+}
+
+// SuggestBookSearch finds the title or author closest to a search that may be misspelled
+func (r *PostgresRepository) SuggestBookSearch(text string) (*string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, nil
+	}
+
+	titles := r.postgres.Table("books").Select("title AS candidate").Where("deleted_at IS NULL")
+	// Authors are suggested one by one rather than as the credit line of a book
+	authors := r.postgres.Table("authors").
+		Select("authors.name AS candidate").
+		Where("EXISTS (SELECT 1 FROM book_authors JOIN books ON books.id = book_authors.book_id WHERE book_authors.author_id = authors.id AND books.deleted_at IS NULL)")
+
+	var candidates []string
+	err := r.postgres.Table("(? UNION ?) AS candidates", titles, authors).
+		Where("? <% candidate", text).
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:  "word_similarity(?, candidate) DESC, candidate",
+			Vars: []interface{}{text},
+		}}).
+		Limit(1).
+		Pluck("candidate", &candidates).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.SuggestBookSearch]: unable to get suggestion")
+	}
+
+	if len(candidates) == 0 || strings.EqualFold(candidates[0], text) {
+		return nil, nil
+	}
+	return &candidates[0], nil
+}
+
+// listableBooks selects the books in the catalog that match a listing, leaving out the filter a facet counts
+func (r *PostgresRepository) listableBooks(req entity.ListBookRequest, facet string) *gorm.DB {
+	query := r.postgres.Table("books").Where("deleted_at IS NULL")
+
+	if condition, _ := bookSearch(req); condition != nil {
+		query = query.Where(*condition)
+	}
+
+	if facet != bookFacetAuthor {
+		if req.Author != nil {
+			query = query.Where("EXISTS (SELECT 1 FROM book_authors JOIN authors ON authors.id = book_authors.author_id "+
+				"WHERE book_authors.book_id = books.id AND authors.name_key = ?)", search.NameKey(*req.Author))
+		}
+		if req.AuthorID != nil {
+			query = query.Where("EXISTS (SELECT 1 FROM book_authors WHERE book_authors.book_id = books.id AND book_authors.author_id = ?)", *req.AuthorID)
+		}
+	}
+
+	if facet != bookFacetCategory {
+		query = classifiedBooks(query, req.CategoryID, nil)
+	}
+
+	if facet != bookFacetSubject {
+		query = classifiedBooks(query, nil, req.Subject)
+	}
+
+	if req.Available != nil && facet != bookFacetAvailability {
+		if *req.Available {
+			query = query.Where("stock > 0")
+		} else {
+			query = query.Where("stock = 0")
+		}
+	}
+
+	if facet != bookFacetPrice {
+		if req.MinPrice != nil {
+			query = query.Where("price >= ?", *req.MinPrice)
+		}
+		if req.MaxPrice != nil {
+			query = query.Where("price <= ?", *req.MaxPrice)
+		}
+	}
+
+	if req.Language != nil && facet != bookFacetLanguage {
+		query = query.Where("LOWER(language) = LOWER(?)", *req.Language)
+	}
+
+	if facet != bookFacetYear {
+		if req.MinYear != nil {
+			query = query.Where("publication_year >= ?", *req.MinYear)
+		}
+		if req.MaxYear != nil {
+			query = query.Where("publication_year <= ?", *req.MaxYear)
+		}
+	}
+
+	return query
+}
+
+// bookSearch returns the condition a search matches books by and the expression ranking the matches,
+// both nil when there is nothing to search for
+func bookSearch(req entity.ListBookRequest) (condition, rank *clause.Expr) {
+	if req.Search == nil {
+		return nil, nil
+	}
+
+	if req.Mode == constant.SearchModeFuzzy {
+		return fuzzySearch(*req.Search)
+	}
+	return fullTextSearch(*req.Search)
+}
+
+// fullTextSearch matches books against the search index, ranked by how well they match
+func fullTextSearch(text string) (condition, rank *clause.Expr) {
+	q := search.Parse(text)
+	if q.Empty() {
+		return nil, nil
+	}
+
+	// The query is analyzed like the index, so it is cast as is rather than parsed by to_tsquery
+	tsquery := q.TSQuery()
+	return &clause.Expr{SQL: "search_vector @@ ?::tsquery", Vars: []interface{}{tsquery}},
+		&clause.Expr{SQL: "ts_rank(search_vector, ?::tsquery)", Vars: []interface{}{tsquery}}
+}
+
+// fuzzySearch matches books whose title or author is close to the search by trigram similarity, ranked by closeness
+func fuzzySearch(text string) (condition, rank *clause.Expr) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, nil
+	}
+
+	return &clause.Expr{SQL: "(? <% title OR ? <% author)", Vars: []interface{}{text, text}},
+		&clause.Expr{SQL: "GREATEST(word_similarity(?, title), word_similarity(?, author))", Vars: []interface{}{text, text}}
+}
+
+// bookSortColumns are the fields a book listing can be sorted by
+var bookSortColumns = map[string]string{
+	"title":      "title",
+	"author":     "author",
+	"createdAt":  "created_at",
+	"popularity": "(SELECT COUNT(*) FROM borrow_histories WHERE borrow_histories.book_id = books.id)",
+}
+
+// bookOrder sorts by the requested fields, then by search rank, then by id so pages are stable
+func bookOrder(sort []string, rank *clause.Expr) keysetOrder {
+	var order keysetOrder
+
+	for _, field := range sort {
+		desc := strings.HasPrefix(field, "-")
+		if column, ok := bookSortColumns[strings.TrimPrefix(field, "-")]; ok {
+			order = append(order, sortKey{SQL: column, Desc: desc})
+		}
+	}
+
+	if rank != nil {
+		order = append(order, sortKey{SQL: rank.SQL, Vars: rank.Vars, Desc: true})
+	}
+
+	return append(order, sortKey{SQL: "id"})
+}
+
+// bookSearchDocument analyzes a book for the search index, titles and ISBNs weighing above authors and authors
+// above subjects. The ISBN is indexed in both its forms.
+func bookSearchDocument(book entity.Book, subjects []string) string {
+	fields := []search.Field{
+		{Text: book.Title, Weight: 'A'},
+		{Text: book.Author, Weight: 'B'},
+	}
+	if book.ISBN != nil {
+		fields = append(fields, search.Field{Text: *book.ISBN, Weight: 'A'})
+		if isbn10, ok := isbn.ISBN10(*book.ISBN); ok {
+			fields = append(fields, search.Field{Text: isbn10, Weight: 'A'})
+		}
+	}
+	for _, subject := range subjects {
+		fields = append(fields, search.Field{Text: subject, Weight: 'C'})
+	}
+	return search.Document(fields...)
+}
+
+// indexBook refreshes the search vector of a book
+func indexBook(tx *gorm.DB, bookID uint) error {
+	var book entity.Book
+	if err := tx.Table("books").Select("id", "title", "author", "isbn").First(&book, bookID).Error; err != nil {
+		return errors.Wrap(err, "[indexBook]: unable to get book")
+	}
+
+	subjects, err := bookSubjects(tx, []uint{bookID})
+	if err != nil {
+		return errors.Wrap(err, "[indexBook]: unable to get subjects")
+	}
+
+	return tx.Table("books").
+		Where("id = ?", bookID).
+		Updates(map[string]interface{}{
+			"search_vector":  gorm.Expr("?::tsvector", bookSearchDocument(book, subjects[bookID])),
+			"search_version": search.Version,
+		}).Error
+}
+
+// backfillBookSearch indexes books stored before the search vector existed or analyzed by an older version
+func backfillBookSearch(db *gorm.DB) error {
+	var books []entity.Book
+	err := db.Table("books").
+		Select("id", "title", "author", "isbn").
+		Where("search_version < ? OR search_vector IS NULL", search.Version).
+		FindInBatches(&books, 500, func(batch *gorm.DB, _ int) error {
+			bookIDs := make([]uint, len(books))
+			for i, book := range books {
+				bookIDs[i] = book.ID
+			}
+
+			subjects, err := bookSubjects(batch, bookIDs)
+			if err != nil {
+				return err
+			}
+
+			for _, book := range books {
+				if err := batch.Table("books").
+					Where("id = ?", book.ID).
+					Updates(map[string]interface{}{
+						"search_vector":  gorm.Expr("?::tsvector", bookSearchDocument(book, subjects[book.ID])),
+						"search_version": search.Version,
+					}).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return errors.Wrap(err, "[backfillBookSearch]: unable to index books")
+	}
+	return nil
+}
+
+// attachBookDetails fills in the authors, categories and subjects of each book
+func attachBookDetails(db *gorm.DB, books []entity.BookResponse) error {
+	if err := attachBookAuthors(db, books); err != nil {
+		return err
+	}
+	return attachBookClassification(db, books)
+}
+
+// createBookTrigramIndexes lets fuzzy search use an index for titles and authors
+func createBookTrigramIndexes(db *gorm.DB) error {
+	for _, column := range []string{"title", "author"} {
+		if err := db.Exec("CREATE INDEX IF NOT EXISTS idx_books_" + column + "_trgm ON books USING gin (" + column + " gin_trgm_ops)").Error; err != nil {
+			return errors.Wrap(err, "[createBookTrigramIndexes]: unable to create index on "+column)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"time"
+
+	"go-library-service/cmd/api/entity"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// bookChangeOrder sorts books by when they last changed, which archiving and restoring a book count as
+var bookChangeOrder = keysetOrder{{SQL: "updated_at"}, {SQL: "id"}}
+
+// ListBookChanges lists a page of the books changed in a time range, archived books included,
+// with the cursor of the page after it when there is one
+func (r *PostgresRepository) ListBookChanges(req entity.ListBookChangeRequest) ([]entity.BookResponse, *string, error) {
+	query, err := paginate(r.changedBooks(req), bookChangeOrder, 1, req.Size, &req.Cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var books []entity.BookResponse
+	if err := query.Find(&books).Error; err != nil {
+		return nil, nil, errors.Wrap(err, "[PostgresRepository.ListBookChanges]: unable to get books")
+	}
+
+	var next *string
+	if len(books) > req.Size {
+		books = books[:req.Size]
+		if next, err = r.nextCursor("books", bookChangeOrder, books[len(books)-1].ID); err != nil {
+			return nil, nil, errors.Wrap(err, "[PostgresRepository.ListBookChanges]: unable to get next cursor")
+		}
+	}
+
+	if err := attachBookDetails(r.postgres, books); err != nil {
+		return nil, nil, errors.Wrap(err, "[PostgresRepository.ListBookChanges]: unable to get book details")
+	}
+	return books, next, nil
+}
+
+// CountBookChanges counts the books changed in a time range, archived books included
+func (r *PostgresRepository) CountBookChanges(req entity.ListBookChangeRequest) (int64, error) {
+	var total int64
+	if err := r.changedBooks(req).Count(&total).Error; err != nil {
+		return 0, errors.Wrap(err, "[PostgresRepository.CountBookChanges]: unable to count books")
+	}
+	return total, nil
+}
+
+// GetEarliestBookChange gets when the book that changed longest ago last changed, nil without books
+func (r *PostgresRepository) GetEarliestBookChange() (*time.Time, error) {
+	var earliest []*time.Time
+	if err := r.postgres.Table("books").Pluck("MIN(updated_at)", &earliest).Error; err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.GetEarliestBookChange]: unable to get earliest change")
+	}
+
+	if len(earliest) == 0 {
+		return nil, nil
+	}
+	return earliest[0], nil
+}
+
+// changedBooks selects the books changed in a time range, archived books included
+func (r *PostgresRepository) changedBooks(req entity.ListBookChangeRequest) *gorm.DB {
+	query := classifiedBooks(r.postgres.Table("books"), req.CategoryID, nil)
+	if req.From != nil {
+		query = query.Where("updated_at >= ?", *req.From)
+	}
+	if req.Before != nil {
+		query = query.Where("updated_at < ?", *req.Before)
+	}
+	return query
+}
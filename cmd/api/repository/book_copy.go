@@ -0,0 +1,307 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const bookCopyResponseColumns = "book_copies.*, books.title AS book_title"
+
+// CreateBookCopy adds a copy of a book and hands it to the next waiting hold if there is one
+func (r *PostgresRepository) CreateBookCopy(bookCopy *entity.BookCopy, now, pickupExpiresAt time.Time) (*entity.BookCopyResponse, error) {
+	tx := r.postgres.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Table("books").First(&entity.Book{}, bookCopy.BookID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmap.ErrmapNotFound
+		}
+		return nil, errors.Wrap(err, "[PostgresRepository.CreateBookCopy]: unable to get book")
+	}
+
+	var count int64
+	if err := tx.Table("book_copies").Where("barcode = ?", bookCopy.Barcode).Count(&count).Error; err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "[PostgresRepository.CreateBookCopy]: unable to check barcode")
+	}
+
+	if count > 0 {
+		tx.Rollback()
+		return nil, errmap.ErrmapConflict
+	}
+
+	if err := tx.Table("book_copies").Create(bookCopy).Error; err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "[PostgresRepository.CreateBookCopy]: unable to create book copy")
+	}
+
+	if err := releaseCopy(tx, *bookCopy, now, pickupExpiresAt); err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "[PostgresRepository.CreateBookCopy]: unable to release copy")
+	}
+
+	var response entity.BookCopyResponse
+	if err := tx.Table("book_copies").
+		Select(bookCopyResponseColumns).
+		Joins("JOIN books ON books.id = book_copies.book_id").
+		Where("book_copies.id = ?", bookCopy.ID).
+		Take(&response).Error; err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "[PostgresRepository.CreateBookCopy]: unable to get book copy")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "[PostgresRepository.CreateBookCopy]: unable to commit transaction")
+	}
+
+	return &response, nil
+}
+
+// GetBookCopyByID retrieves a book copy by ID
+func (r *PostgresRepository) GetBookCopyByID(copyID uint) (*entity.BookCopyResponse, error) {
+	var bookCopy entity.BookCopyResponse
+	err := r.postgres.Table("book_copies").
+		Select(bookCopyResponseColumns).
+		Joins("JOIN books ON books.id = book_copies.book_id").
+		Where("book_copies.id = ?", copyID).
+		Take(&bookCopy).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmap.ErrmapNotFound
+		}
+		return nil, errors.Wrap(err, "[PostgresRepository.GetBookCopyByID]: unable to get book copy")
+	}
+	return &bookCopy, nil
+}
+
+// GetBookCopyByBarcode retrieves a book copy by its barcode
+func (r *PostgresRepository) GetBookCopyByBarcode(barcode string) (*entity.BookCopyResponse, error) {
+	var bookCopy entity.BookCopyResponse
+	err := r.postgres.Table("book_copies").
+		Select(bookCopyResponseColumns).
+		Joins("JOIN books ON books.id = book_copies.book_id").
+		Where("book_copies.barcode = ?", barcode).
+		Take(&bookCopy).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmap.ErrmapNotFound
+		}
+		return nil, errors.Wrap(err, "[PostgresRepository.GetBookCopyByBarcode]: unable to get book copy")
+	}
+	return &bookCopy, nil
+}
+
+// ListBookCopiesByBookID lists the copies of a book
+func (r *PostgresRepository) ListBookCopiesByBookID(bookID uint) ([]entity.BookCopyResponse, error) {
+	var copies []entity.BookCopyResponse
+	err := r.postgres.Table("book_copies").
+		Select(bookCopyResponseColumns).
+		Joins("JOIN books ON books.id = book_copies.book_id").
+		Where("book_copies.book_id = ?", bookID).
+		Order("book_copies.id ASC").
+		Find(&copies).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.ListBookCopiesByBookID]: unable to get book copies")
+	}
+	return copies, nil
+}
+
+// UpdateBookCopy updates the shelf location and condition of a copy
+func (r *PostgresRepository) UpdateBookCopy(bookCopy entity.BookCopy) error {
+	result := r.postgres.Table("book_copies").
+		Where("id = ?", bookCopy.ID).
+		Updates(map[string]interface{}{
+			"shelf_location": bookCopy.ShelfLocation,
+			"condition":      bookCopy.Condition,
+			"updated_at":     time.Now(),
+		})
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "[PostgresRepository.UpdateBookCopy]: unable to update book copy")
+	}
+
+	if result.RowsAffected == 0 {
+		return errmap.ErrmapNotFound
+	}
+
+	return nil
+}
+
+// RetireBookCopy takes an available copy out of circulation
+func (r *PostgresRepository) RetireBookCopy(copyID uint) error {
+	tx := r.postgres.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	bookCopy, err := lockCopy(tx, copyID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if bookCopy.Status != constant.CopyStatusAvailable {
+		tx.Rollback()
+		return errmap.ErrmapConflict
+	}
+
+	if err := tx.Table("book_copies").
+		Where("id = ?", copyID).
+		Updates(map[string]interface{}{
+			"status":     constant.CopyStatusRetired,
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.RetireBookCopy]: unable to update book copy")
+	}
+
+	if err := syncBookStock(tx, bookCopy.BookID); err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.RetireBookCopy]: unable to update book stock")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.RetireBookCopy]: unable to commit transaction")
+	}
+
+	return nil
+}
+
+// lockCopy locks a copy for the rest of the transaction
+func lockCopy(tx *gorm.DB, copyID uint) (*entity.BookCopy, error) {
+	var bookCopy entity.BookCopy
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Table("book_copies").First(&bookCopy, copyID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmap.ErrmapNotFound
+		}
+		return nil, errors.Wrap(err, "[lockCopy]: unable to get book copy")
+	}
+	return &bookCopy, nil
+}
+
+// setCopyStatus moves a copy to another circulation status
+func setCopyStatus(tx *gorm.DB, copyID uint, status string) error {
+	return tx.Table("book_copies").
+		Where("id = ?", copyID).
+		Updates(map[string]interface{}{
+			"status":     status,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// syncBookStock sets the stock of a book to the number of its available copies
+func syncBookStock(tx *gorm.DB, bookID uint) error {
+	return tx.Table("books").
+		Where("id = ?", bookID).
+		Update("stock", gorm.Expr("(SELECT COUNT(*) FROM book_copies WHERE book_copies.book_id = books.id AND book_copies.status = ?)", constant.CopyStatusAvailable)).Error
+}
+
+// copyBarcode generates the barcode of the n-th copy created for a book
+func copyBarcode(bookID uint, n int) string {
+	return fmt.Sprintf("B%06d-%03d", bookID, n)
+}
+
+// createBookCopies creates copies with generated barcodes, numbered from the given position
+func createBookCopies(tx *gorm.DB, bookID uint, from, count int, status string) ([]entity.BookCopy, error) {
+	copies := make([]entity.BookCopy, 0, count)
+	for i := 0; i < count; i++ {
+		copies = append(copies, entity.BookCopy{
+			BookID:    bookID,
+			Barcode:   copyBarcode(bookID, from+i),
+			Condition: constant.CopyConditionGood,
+			Status:    status,
+		})
+	}
+
+	if len(copies) == 0 {
+		return copies, nil
+	}
+
+	if err := tx.Table("book_copies").Create(&copies).Error; err != nil {
+		return nil, errors.Wrap(err, "[createBookCopies]: unable to create book copies")
+	}
+	return copies, nil
+}
+
+// backfillBookCopies creates copies for books that were only tracked by their stock counter,
+// including one for every active loan and every hold waiting for pickup
+func backfillBookCopies(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var books []entity.Book
+		if err := tx.Table("books").
+			Where("NOT EXISTS (SELECT 1 FROM book_copies WHERE book_copies.book_id = books.id)").
+			Find(&books).Error; err != nil {
+			return errors.Wrap(err, "[backfillBookCopies]: unable to get books")
+		}
+
+		for _, book := range books {
+			if _, err := createBookCopies(tx, book.ID, 1, int(book.Stock), constant.CopyStatusAvailable); err != nil {
+				return err
+			}
+			next := int(book.Stock) + 1
+
+			var loans []entity.BorrowHistory
+			if err := tx.Table("borrow_histories").
+				Where("book_id = ? AND returned_at IS NULL AND copy_id IS NULL", book.ID).
+				Find(&loans).Error; err != nil {
+				return errors.Wrap(err, "[backfillBookCopies]: unable to get active loans")
+			}
+
+			copies, err := createBookCopies(tx, book.ID, next, len(loans), constant.CopyStatusOnLoan)
+			if err != nil {
+				return err
+			}
+			next += len(loans)
+
+			for i, loan := range loans {
+				if err := tx.Table("borrow_histories").Where("id = ?", loan.ID).Update("copy_id", copies[i].ID).Error; err != nil {
+					return errors.Wrap(err, "[backfillBookCopies]: unable to update borrow history")
+				}
+			}
+
+			var holds []entity.Hold
+			if err := tx.Table("holds").
+				Where("book_id = ? AND status = ? AND copy_id IS NULL", book.ID, constant.HoldStatusReady).
+				Find(&holds).Error; err != nil {
+				return errors.Wrap(err, "[backfillBookCopies]: unable to get ready holds")
+			}
+
+			copies, err = createBookCopies(tx, book.ID, next, len(holds), constant.CopyStatusOnHold)
+			if err != nil {
+				return err
+			}
+
+			for i, hold := range holds {
+				if err := tx.Table("holds").Where("id = ?", hold.ID).Update("copy_id", copies[i].ID).Error; err != nil {
+					return errors.Wrap(err, "[backfillBookCopies]: unable to update hold")
+				}
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"time"
+
+	"go-library-service/cmd/api/entity"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UpdateBookCover sets the cover of a book, or clears it when its keys are nil, and returns the cover it
+// replaced so its blobs can be deleted. The book is locked so two uploads cannot both replace the same cover.
+func (r *PostgresRepository) UpdateBookCover(bookID uint, cover entity.BookCover, updatedAt time.Time) (*entity.BookCover, error) {
+	tx := r.postgres.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "[PostgresRepository.UpdateBookCover]: unable to begin transaction")
+	}
+
+	var previous entity.BookCover
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Table("books").
+		Select("cover_key", "cover_thumbnail_key").
+		Where("id = ?", bookID).
+		Take(&previous).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmap.ErrmapNotFound
+		}
+		return nil, errors.Wrap(err, "[PostgresRepository.UpdateBookCover]: unable to get book")
+	}
+
+	if err := tx.Table("books").Where("id = ?", bookID).Updates(map[string]interface{}{
+		"cover_key":           cover.CoverKey,
+		"cover_thumbnail_key": cover.CoverThumbnailKey,
+		"updated_at":          updatedAt,
+	}).Error; err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "[PostgresRepository.UpdateBookCover]: unable to update book")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.UpdateBookCover]: unable to commit transaction")
+	}
+	return &previous, nil
+}
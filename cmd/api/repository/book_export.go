@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+
+	"github.com/pkg/errors"
+)
+
+// EachBook hands the books matching a listing to fn a batch at a time, in the order of the listing,
+// stopping at the first error fn returns. Each batch continues after the last book of the one before,
+// so only a batch is held at once however large the catalog.
+func (r *PostgresRepository) EachBook(req entity.ListBookRequest, size int, fn func(books []entity.BookResponse) error) error {
+	_, rank := bookSearch(req)
+	order := bookOrder(req.Sort, rank)
+
+	cursor := ""
+	for {
+		query, err := paginate(r.listableBooks(req, ""), order, 1, size, &cursor)
+		if err != nil {
+			return errors.Wrap(err, "[PostgresRepository.EachBook]: unable to page books")
+		}
+
+		var books []entity.BookResponse
+		if err := query.Find(&books).Error; err != nil {
+			return errors.Wrap(err, "[PostgresRepository.EachBook]: unable to get books")
+		}
+
+		more := len(books) > size
+		if more {
+			books = books[:size]
+			next, err := r.nextCursor("books", order, books[len(books)-1].ID)
+			if err != nil {
+				return errors.Wrap(err, "[PostgresRepository.EachBook]: unable to get next cursor")
+			}
+			cursor = *next
+		}
+
+		if len(books) > 0 {
+			if err := attachBookDetails(r.postgres, books); err != nil {
+				return errors.Wrap(err, "[PostgresRepository.EachBook]: unable to get book details")
+			}
+
+			if err := fn(books); err != nil {
+				return err
+			}
+		}
+
+		if !more {
+			return nil
+		}
+	}
+}
+
+// GetBookAvailability counts the copies of books by status
+func (r *PostgresRepository) GetBookAvailability(bookIDs []uint) ([]entity.BookAvailability, error) {
+	var availability []entity.BookAvailability
+	if len(bookIDs) == 0 {
+		return availability, nil
+	}
+
+	err := r.postgres.Table("book_copies").
+		Select("book_id, "+
+			"COUNT(*) FILTER (WHERE status NOT IN (?, ?)) AS copies, "+
+			"COUNT(*) FILTER (WHERE status = ?) AS available, "+
+			"COUNT(*) FILTER (WHERE status = ?) AS on_loan, "+
+			"COUNT(*) FILTER (WHERE status = ?) AS on_hold",
+			constant.CopyStatusRetired, constant.CopyStatusLost,
+			constant.CopyStatusAvailable, constant.CopyStatusOnLoan, constant.CopyStatusOnHold).
+		Where("book_id IN ?", bookIDs).
+		Group("book_id").
+		Find(&availability).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.GetBookAvailability]: unable to count copies")
+	}
+	return availability, nil
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"strconv"
+	"strings"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	bookFacetAuthor       = "author"
+	bookFacetCategory     = "category"
+	bookFacetSubject      = "subject"
+	bookFacetAvailability = "availability"
+	bookFacetPrice        = "price"
+	bookFacetLanguage     = "language"
+	bookFacetYear         = "year"
+
+	// maxValueFacets caps the author, category, subject and language facets to the most common values
+	maxValueFacets = 20
+)
+
+// bookPriceBounds split the price facet into ranges
+var bookPriceBounds = []float64{100, 300, 500, 1000}
+
+// GetBookFacets counts the books matching a listing by author, category, subject, availability, price range,
+// language and decade of publication
+func (r *PostgresRepository) GetBookFacets(req entity.ListBookRequest) (*entity.BookFacets, error) {
+	facets := entity.BookFacets{
+		Authors:      []entity.FacetCount{},
+		Categories:   []entity.FacetCount{},
+		Subjects:     []entity.FacetCount{},
+		Availability: []entity.FacetCount{},
+		PriceRanges:  []entity.PriceRangeFacet{},
+		Languages:    []entity.FacetCount{},
+		Decades:      []entity.YearRangeFacet{},
+	}
+
+	err := r.listableBooks(req, bookFacetAuthor).
+		Select("authors.id AS id, authors.name AS value, COUNT(*) AS count").
+		Joins("JOIN book_authors ON book_authors.book_id = books.id").
+		Joins("JOIN authors ON authors.id = book_authors.author_id").
+		Group("authors.id").
+		Order("count DESC, authors.name ASC").
+		Limit(maxValueFacets).
+		Find(&facets.Authors).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.GetBookFacets]: unable to count authors")
+	}
+
+	// Books count under the categories they are filed under, not under the parents of those
+	err = r.listableBooks(req, bookFacetCategory).
+		Select("categories.id AS id, categories.name AS value, COUNT(*) AS count").
+		Joins("JOIN book_categories ON book_categories.book_id = books.id").
+		Joins("JOIN categories ON categories.id = book_categories.category_id").
+		Group("categories.id").
+		Order("count DESC, categories.name ASC").
+		Limit(maxValueFacets).
+		Find(&facets.Categories).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.GetBookFacets]: unable to count categories")
+	}
+
+	err = r.listableBooks(req, bookFacetSubject).
+		Select("MIN(book_subjects.name) AS value, COUNT(*) AS count").
+		Joins("JOIN book_subjects ON book_subjects.book_id = books.id").
+		Group("LOWER(book_subjects.name)").
+		Order("count DESC, value ASC").
+		Limit(maxValueFacets).
+		Find(&facets.Subjects).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.GetBookFacets]: unable to count subjects")
+	}
+
+	err = r.listableBooks(req, bookFacetAvailability).
+		Select("CASE WHEN stock > 0 THEN ? ELSE ? END AS value, COUNT(*) AS count", constant.BookAvailable, constant.BookUnavailable).
+		Group("value").
+		Order("value ASC").
+		Find(&facets.Availability).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.GetBookFacets]: unable to count availability")
+	}
+
+	var buckets []struct {
+		Bucket int
+		Count  int64
+	}
+	err = r.listableBooks(req, bookFacetPrice).
+		Select("width_bucket(price, " + bookPriceThresholds() + ") AS bucket, COUNT(*) AS count").
+		Group("bucket").
+		Order("bucket ASC").
+		Find(&buckets).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.GetBookFacets]: unable to count price ranges")
+	}
+
+	// width_bucket numbers the range below the first bound 0 and the one from the last bound len(bounds)
+	for _, bucket := range buckets {
+		facet := entity.PriceRangeFacet{Count: bucket.Count}
+		if bucket.Bucket > 0 {
+			facet.Min = bookPriceBounds[bucket.Bucket-1]
+		}
+		if bucket.Bucket < len(bookPriceBounds) {
+			facet.Max = &bookPriceBounds[bucket.Bucket]
+		}
+		facets.PriceRanges = append(facets.PriceRanges, facet)
+	}
+
+	// Tags are matched regardless of case, so en and EN count as one language
+	err = r.listableBooks(req, bookFacetLanguage).
+		Select("MIN(language) AS value, COUNT(*) AS count").
+		Where("language <> ''").
+		Group("LOWER(language)").
+		Order("count DESC, value ASC").
+		Limit(maxValueFacets).
+		Find(&facets.Languages).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.GetBookFacets]: unable to count languages")
+	}
+
+	var decades []struct {
+		Decade int
+		Count  int64
+	}
+	err = r.listableBooks(req, bookFacetYear).
+		Select("publication_year / 10 * 10 AS decade, COUNT(*) AS count").
+		Where("publication_year IS NOT NULL").
+		Group("decade").
+		Order("decade ASC").
+		Find(&decades).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.GetBookFacets]: unable to count decades")
+	}
+
+	for _, decade := range decades {
+		facets.Decades = append(facets.Decades, entity.YearRangeFacet{Min: decade.Decade, Max: decade.Decade + 9, Count: decade.Count})
+	}
+
+	return &facets, nil
+}
+
+// bookPriceThresholds writes the price bounds as an array for width_bucket
+func bookPriceThresholds() string {
+	bounds := make([]string, len(bookPriceBounds))
+	for i, bound := range bookPriceBounds {
+		bounds[i] = strconv.FormatFloat(bound, 'f', -1, 64)
+	}
+	return "ARRAY[" + strings.Join(bounds, ",") + "]::double precision[]"
+}
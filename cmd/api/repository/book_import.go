@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// importedBookColumns are the book columns written from the columns of an import file
+var importedBookColumns = map[string]string{
+	"title":           "title",
+	"author":          "author",
+	"authorids":       "author",
+	"price":           "price",
+	"isbn":            "isbn",
+	"publisher":       "publisher",
+	"publicationyear": "publication_year",
+	"edition":         "edition",
+	"language":        "language",
+	"pagecount":       "page_count",
+}
+
+// ImportBooks writes a batch of imported books in one transaction, telling for each whether it was
+// created, updated, or left alone for being archived or for being new without a stock. A book with the ISBN of one already in the catalog
+// updates that one instead of adding copies. The batch is rolled back as a whole when a row fails, and
+// always on a dry run.
+func (r *PostgresRepository) ImportBooks(items []entity.BookImportItem, dryRun bool) ([]string, error) {
+	tx := r.postgres.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "[PostgresRepository.ImportBooks]: unable to begin transaction")
+	}
+
+	actions := make([]string, len(items))
+	for i, item := range items {
+		action, err := importBook(tx, item)
+		if err != nil {
+			tx.Rollback()
+			return nil, &errmap.ImportRowError{Line: item.Line, Err: errors.Wrap(err, "[PostgresRepository.ImportBooks]: unable to import book")}
+		}
+		actions[i] = action
+	}
+
+	if dryRun {
+		if err := tx.Rollback().Error; err != nil {
+			return nil, errors.Wrap(err, "[PostgresRepository.ImportBooks]: unable to roll back dry run")
+		}
+		return actions, nil
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "[PostgresRepository.ImportBooks]: unable to commit transaction")
+	}
+
+	return actions, nil
+}
+
+// importBook updates the book with the ISBN of an imported one, or creates it when there is none
+func importBook(tx *gorm.DB, item entity.BookImportItem) (string, error) {
+	if item.Book.ISBN != nil {
+		var existing []entity.Book
+		err := tx.Table("books").
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("isbn = ?", *item.Book.ISBN).
+			Limit(1).
+			Find(&existing).Error
+		if err != nil {
+			return "", errors.Wrap(err, "[importBook]: unable to get book by isbn")
+		}
+
+		if len(existing) > 0 {
+			if existing[0].DeletedAt != nil {
+				return constant.BookImportArchived, nil
+			}
+
+			book := item.Book
+			book.ID = existing[0].ID
+			if err := updateImportedBook(tx, book, item); err != nil {
+				return "", errors.Wrap(err, "[importBook]: unable to update book")
+			}
+			return constant.BookImportUpdated, nil
+		}
+	}
+
+	if item.Book.Stock < 1 {
+		return constant.BookImportNoStock, nil
+	}
+
+	book := item.Book
+	if err := createBook(tx, &book, item.Authors, item.CategoryIDs, item.Subjects); err != nil {
+		return "", errors.Wrap(err, "[importBook]: unable to create book")
+	}
+	return constant.BookImportCreated, nil
+}
+
+// updateImportedBook writes the columns an import file gives over a book, leaving the rest as they are.
+// A row of a file without column names updates the book the way an edit does.
+func updateImportedBook(tx *gorm.DB, book entity.Book, item entity.BookImportItem) error {
+	if item.Columns == nil {
+		return updateBook(tx, book, item.Authors, item.CategoryIDs, item.Subjects)
+	}
+
+	given := map[string]bool{}
+	var columns []string
+	for _, column := range item.Columns {
+		given[column] = true
+		if name, ok := importedBookColumns[column]; ok && name != "author" {
+			columns = append(columns, name)
+		}
+	}
+
+	if len(columns) > 0 {
+		if err := tx.Table("books").Where("id = ?", book.ID).Select(columns).Updates(book).Error; err != nil {
+			return errors.Wrap(err, "[updateImportedBook]: unable to update book")
+		}
+	}
+
+	// A blank value of a given list column clears the list, a missing column keeps it
+	var categoryIDs []uint
+	if given["categoryids"] {
+		categoryIDs = append([]uint{}, item.CategoryIDs...)
+	}
+	var subjects []string
+	if given["subjects"] {
+		subjects = append([]string{}, item.Subjects...)
+	}
+	if err := classifyBook(tx, book.ID, categoryIDs, subjects); err != nil {
+		return errors.Wrap(err, "[updateImportedBook]: unable to classify book")
+	}
+
+	if given["author"] || given["authorids"] {
+		// Crediting the authors reindexes the book, subjects included
+		if err := creditBookAuthors(tx, book.ID, item.Authors); err != nil {
+			return errors.Wrap(err, "[updateImportedBook]: unable to credit authors")
+		}
+		return nil
+	}
+
+	return indexBook(tx, book.ID)
+}
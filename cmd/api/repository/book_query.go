@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"strconv"
+	"strings"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// bookQueryComparisons are the operators of the relations a year is compared by
+var bookQueryComparisons = map[string]string{
+	constant.BookQueryRelationEq: "=",
+	constant.BookQueryRelationNe: "<>",
+	constant.BookQueryRelationLt: "<",
+	constant.BookQueryRelationLe: "<=",
+	constant.BookQueryRelationGt: ">",
+	constant.BookQueryRelationGe: ">=",
+}
+
+// SearchBooks lists a slice of the books matching a query, archived books left out, in the order they were added
+func (r *PostgresRepository) SearchBooks(req entity.SearchBookRequest) ([]entity.BookResponse, error) {
+	query, err := r.queriedBooks(req.Query)
+	if err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.SearchBooks]: unable to translate query")
+	}
+
+	var books []entity.BookResponse
+	if err := query.Order("id ASC").Offset(req.Offset).Limit(req.Limit).Find(&books).Error; err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.SearchBooks]: unable to get books")
+	}
+
+	if err := attachBookDetails(r.postgres, books); err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.SearchBooks]: unable to get book details")
+	}
+	return books, nil
+}
+
+// CountSearchBooks counts the books matching a query, archived books left out
+func (r *PostgresRepository) CountSearchBooks(q entity.BookQuery) (int64, error) {
+	query, err := r.queriedBooks(q)
+	if err != nil {
+		return 0, errors.Wrap(err, "[PostgresRepository.CountSearchBooks]: unable to translate query")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return 0, errors.Wrap(err, "[PostgresRepository.CountSearchBooks]: unable to count books")
+	}
+	return total, nil
+}
+
+// queriedBooks selects the books matching a query, archived books left out
+func (r *PostgresRepository) queriedBooks(q entity.BookQuery) (*gorm.DB, error) {
+	condition, err := bookQueryCondition(q)
+	if err != nil {
+		return nil, err
+	}
+	// The condition is passed through a bare ? as it brings its own parentheses, which gorm would add again
+	return r.postgres.Table("books").Where("deleted_at IS NULL").Where(clause.Expr{SQL: "?", Vars: []interface{}{condition}}), nil
+}
+
+// bookQueryCondition translates a query into a condition on books, each boolean in parentheses of its own
+func bookQueryCondition(q entity.BookQuery) (clause.Expr, error) {
+	if q.Operator != "" {
+		if q.Left == nil || q.Right == nil {
+			return clause.Expr{}, errors.Errorf("%s is missing a side", q.Operator)
+		}
+		left, err := bookQueryCondition(*q.Left)
+		if err != nil {
+			return clause.Expr{}, err
+		}
+		right, err := bookQueryCondition(*q.Right)
+		if err != nil {
+			return clause.Expr{}, err
+		}
+
+		switch q.Operator {
+		case constant.BookQueryAnd:
+			return clause.Expr{SQL: "(? AND ?)", Vars: []interface{}{left, right}}, nil
+		case constant.BookQueryOr:
+			return clause.Expr{SQL: "(? OR ?)", Vars: []interface{}{left, right}}, nil
+		case constant.BookQueryNot:
+			return clause.Expr{SQL: "(? AND NOT ?)", Vars: []interface{}{left, right}}, nil
+		}
+		return clause.Expr{}, errors.Errorf("unknown operator %q", q.Operator)
+	}
+
+	switch q.Field {
+	case constant.BookQueryFieldAny:
+		return fullTextMatch(q.Relation, q.Term)
+	case constant.BookQueryFieldTitle:
+		return textMatch("title ILIKE ?", q.Relation, q.Term)
+	case constant.BookQueryFieldAuthor:
+		return textMatch("author ILIKE ?", q.Relation, q.Term)
+	case constant.BookQueryFieldPublisher:
+		return textMatch("publisher ILIKE ?", q.Relation, q.Term)
+	case constant.BookQueryFieldSubject:
+		return textMatch("(EXISTS (SELECT 1 FROM book_subjects WHERE book_subjects.book_id = books.id AND book_subjects.name ILIKE ?) "+
+			"OR EXISTS (SELECT 1 FROM book_categories JOIN categories ON categories.id = book_categories.category_id "+
+			"WHERE book_categories.book_id = books.id AND categories.name ILIKE ?))", q.Relation, q.Term)
+	case constant.BookQueryFieldLanguage:
+		if q.Relation != constant.BookQueryRelationExact {
+			return clause.Expr{}, errors.Errorf("language cannot be compared by %s", q.Relation)
+		}
+		return textMatch("language ILIKE ?", q.Relation, q.Term)
+	case constant.BookQueryFieldISBN:
+		if q.Relation != constant.BookQueryRelationExact {
+			return clause.Expr{}, errors.Errorf("isbn cannot be compared by %s", q.Relation)
+		}
+		return clause.Expr{SQL: "isbn = ?", Vars: []interface{}{q.Term}}, nil
+	case constant.BookQueryFieldYear:
+		operator, ok := bookQueryComparisons[q.Relation]
+		if !ok {
+			return clause.Expr{}, errors.Errorf("year cannot be compared by %s", q.Relation)
+		}
+		year, err := strconv.Atoi(q.Term)
+		if err != nil {
+			return clause.Expr{}, errors.Errorf("year %q is not a number", q.Term)
+		}
+		return clause.Expr{SQL: "publication_year " + operator + " ?", Vars: []interface{}{year}}, nil
+	}
+	return clause.Expr{}, errors.Errorf("unknown field %q", q.Field)
+}
+
+// textMatch matches a text against the LIKE patterns of a term: the whole of it for exact, within the text
+// for contains, and each word within the text for all and any. The condition takes the pattern at each ?.
+func textMatch(condition, relation, term string) (clause.Expr, error) {
+	if strings.TrimSpace(term) == "" {
+		return clause.Expr{}, errors.New("term is empty")
+	}
+
+	var patterns []string
+	switch relation {
+	case constant.BookQueryRelationExact:
+		patterns = []string{likePattern(term)}
+	case constant.BookQueryRelationContains:
+		patterns = []string{"%" + likePattern(term) + "%"}
+	case constant.BookQueryRelationAll, constant.BookQueryRelationAny:
+		for _, word := range strings.Fields(term) {
+			patterns = append(patterns, "%"+likePattern(word)+"%")
+		}
+	default:
+		return clause.Expr{}, errors.Errorf("text cannot be compared by %s", relation)
+	}
+
+	joined := " AND "
+	if relation == constant.BookQueryRelationAny {
+		joined = " OR "
+	}
+
+	matches := make([]string, len(patterns))
+	vars := make([]interface{}, len(patterns))
+	for i, pattern := range patterns {
+		matches[i] = "?"
+		expr := clause.Expr{SQL: condition}
+		for range strings.Count(condition, "?") {
+			expr.Vars = append(expr.Vars, pattern)
+		}
+		vars[i] = expr
+	}
+	if len(patterns) == 1 {
+		return vars[0].(clause.Expr), nil
+	}
+	return clause.Expr{SQL: "(" + strings.Join(matches, joined) + ")", Vars: vars}, nil
+}
+
+// fullTextMatch matches the search vector against the words of a term: all of them, any of them,
+// or the words as a phrase for contains
+func fullTextMatch(relation, term string) (clause.Expr, error) {
+	text := unescapeTerm(term)
+	switch relation {
+	case constant.BookQueryRelationAll:
+	case constant.BookQueryRelationAny:
+		text = strings.Join(strings.Fields(text), " OR ")
+	case constant.BookQueryRelationContains:
+		text = `"` + strings.ReplaceAll(text, `"`, "") + `"`
+	default:
+		return clause.Expr{}, errors.Errorf("full text cannot be compared by %s", relation)
+	}
+
+	condition, _ := fullTextSearch(text)
+	if condition == nil {
+		// A term of nothing but stop words matches no book rather than every book
+		return clause.Expr{SQL: "FALSE"}, nil
+	}
+	return *condition, nil
+}
+
+// likePattern turns a term into a LIKE pattern, its masks into wildcards and anything else into literals
+func likePattern(term string) string {
+	var pattern strings.Builder
+	for i := 0; i < len(term); i++ {
+		c := term[i]
+		switch {
+		case c == '\\' && i+1 < len(term):
+			i++
+			if term[i] == '%' || term[i] == '_' || term[i] == '\\' {
+				pattern.WriteByte('\\')
+			}
+			pattern.WriteByte(term[i])
+		case c == '*':
+			pattern.WriteByte('%')
+		case c == '?':
+			pattern.WriteByte('_')
+		case c == '%' || c == '_' || c == '\\':
+			pattern.WriteByte('\\')
+			pattern.WriteByte(c)
+		default:
+			pattern.WriteByte(c)
+		}
+	}
+	return pattern.String()
+}
+
+// unescapeTerm drops the backslashes of a term, leaving the characters they escape
+func unescapeTerm(term string) string {
+	var text strings.Builder
+	for i := 0; i < len(term); i++ {
+		if term[i] == '\\' && i+1 < len(term) {
+			i++
+		}
+		text.WriteByte(term[i])
+	}
+	return text.String()
+}
@@ -0,0 +1,451 @@
+package repository_test
+
+import (
+	"time"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	"go-library-service/cmd/api/repository"
+	errmap "go-library-service/internal/error_map"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Book Repository", func() {
+	var (
+		statements []string
+		r          *repository.PostgresRepository
+	)
+
+	BeforeEach(func() {
+		statements = nil
+		r = repository.NewPostgresRepositoryWithDB(dryRunDB(&statements))
+	})
+
+	Context("ListBook", func() {
+		It("should list books that are not archived", func() {
+			_, _, err := r.ListBook(entity.ListBookRequest{Page: 2, Size: 10})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(statements).To(HaveLen(1))
+			Expect(statements[0]).To(Equal(`SELECT * FROM "books" WHERE deleted_at IS NULL ORDER BY id ASC LIMIT 10 OFFSET 10`))
+		})
+
+		It("should rank full-text matches", func() {
+			search := `"harry potter" rowl*`
+			_, _, err := r.ListBook(entity.ListBookRequest{Page: 1, Size: 10, Search: &search})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(statements).To(HaveLen(1))
+			tsquery := `'((''harry'' <-> ''potter'') | (''~har'' <-> ''~ary'' <-> ''~ryp'' <-> ''~ypo'' <-> ''~pot'' <-> ''~ote'' <-> ''~ter'')) & (''rowl'':* | (''~row'' <-> ''~owl''))'`
+			Expect(statements[0]).To(ContainSubstring(`search_vector @@ ` + tsquery + `::tsquery`))
+			Expect(statements[0]).To(ContainSubstring(`ORDER BY ts_rank(search_vector, ` + tsquery + `::tsquery) DESC, id ASC`))
+		})
+
+		It("should match title and author by trigram similarity in fuzzy mode", func() {
+			search := " rowlnig "
+			_, _, err := r.ListBook(entity.ListBookRequest{Page: 1, Size: 10, Search: &search, Mode: constant.SearchModeFuzzy})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(statements).To(HaveLen(1))
+			Expect(statements[0]).To(ContainSubstring(`('rowlnig' <% title OR 'rowlnig' <% author)`))
+			Expect(statements[0]).To(ContainSubstring(`ORDER BY GREATEST(word_similarity('rowlnig', title), word_similarity('rowlnig', author)) DESC, id ASC`))
+		})
+
+		It("should filter and sort books", func() {
+			author := "Frank Herbert"
+			available := true
+			minPrice, maxPrice := 100.0, 500.0
+			_, _, err := r.ListBook(entity.ListBookRequest{
+				Page:      1,
+				Size:      10,
+				Author:    &author,
+				Available: &available,
+				MinPrice:  &minPrice,
+				MaxPrice:  &maxPrice,
+				Sort:      []string{"-popularity", "title"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(statements).To(HaveLen(1))
+			Expect(statements[0]).To(Equal(`SELECT * FROM "books" WHERE deleted_at IS NULL AND ` +
+				`(EXISTS (SELECT 1 FROM book_authors JOIN authors ON authors.id = book_authors.author_id WHERE book_authors.book_id = books.id AND authors.name_key = 'frank herbert')) AND stock > 0 AND price >= 100 AND price <= 500 ` +
+				`ORDER BY (SELECT COUNT(*) FROM borrow_histories WHERE borrow_histories.book_id = books.id) DESC, title ASC, id ASC LIMIT 10`))
+		})
+
+		It("should filter by author id", func() {
+			authorID := uint(3)
+			_, _, err := r.ListBook(entity.ListBookRequest{Page: 1, Size: 10, AuthorID: &authorID})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(statements).To(HaveLen(1))
+			Expect(statements[0]).To(Equal(`SELECT * FROM "books" WHERE deleted_at IS NULL AND ` +
+				`(EXISTS (SELECT 1 FROM book_authors WHERE book_authors.book_id = books.id AND book_authors.author_id = 3)) ORDER BY id ASC LIMIT 10`))
+		})
+
+		It("should sort by the requested fields before the search rank", func() {
+			search := "dune"
+			_, _, err := r.ListBook(entity.ListBookRequest{Page: 1, Size: 10, Search: &search, Sort: []string{"-createdAt"}})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(statements).To(HaveLen(1))
+			Expect(statements[0]).To(ContainSubstring(`ORDER BY created_at DESC, ts_rank(search_vector, `))
+			Expect(statements[0]).To(HaveSuffix(`::tsquery) DESC, id ASC LIMIT 10`))
+		})
+
+		It("should ignore a blank search", func() {
+			search := "  "
+			_, _, err := r.ListBook(entity.ListBookRequest{Page: 1, Size: 10, Search: &search, Mode: constant.SearchModeFuzzy})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(statements).To(HaveLen(1))
+			Expect(statements[0]).NotTo(ContainSubstring("similarity"))
+		})
+	})
+
+	Context("ListBook by cursor", func() {
+		It("should read one book more than a page to tell whether another follows", func() {
+			cursor := ""
+			books, next, err := r.ListBook(entity.ListBookRequest{Size: 10, Cursor: &cursor})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(books).To(BeEmpty())
+			Expect(next).To(BeNil())
+
+			Expect(statements).To(Equal([]string{`SELECT * FROM "books" WHERE deleted_at IS NULL ORDER BY id ASC LIMIT 11`}))
+		})
+
+		It("should continue after the last book of the page before", func() {
+			req := entity.ListBookRequest{Size: 10, Sort: []string{"-createdAt", "title"}}
+			createdAt := time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC)
+			cursor, err := repository.EncodeBookCursor(req, createdAt, "Dune", int64(42))
+			Expect(err).NotTo(HaveOccurred())
+			req.Cursor = &cursor
+
+			_, _, err = r.ListBook(req)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(statements).To(HaveLen(1))
+			Expect(statements[0]).To(Equal(`SELECT * FROM "books" WHERE deleted_at IS NULL AND ((created_at < '2024-05-01 08:30:00') ` +
+				`OR (created_at = '2024-05-01 08:30:00' AND title > 'Dune') ` +
+				`OR (created_at = '2024-05-01 08:30:00' AND title = 'Dune' AND id > 42)) ` +
+				`ORDER BY created_at DESC, title ASC, id ASC LIMIT 11`))
+		})
+
+		It("should compare the search rank with its own vars", func() {
+			search := "dune"
+			req := entity.ListBookRequest{Size: 10, Search: &search, Mode: constant.SearchModeFuzzy}
+			cursor, err := repository.EncodeBookCursor(req, float32(0.5), int64(7))
+			Expect(err).NotTo(HaveOccurred())
+			req.Cursor = &cursor
+
+			_, _, err = r.ListBook(req)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(statements).To(HaveLen(1))
+			rank := `GREATEST(word_similarity('dune', title), word_similarity('dune', author))`
+			Expect(statements[0]).To(ContainSubstring(`((` + rank + ` < 0.5) OR (` + rank + ` = 0.5 AND id > 7))`))
+		})
+
+		It("should refuse a cursor made for another sort", func() {
+			cursor, err := repository.EncodeBookCursor(entity.ListBookRequest{Sort: []string{"title"}}, "Dune", int64(42))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, err = r.ListBook(entity.ListBookRequest{Size: 10, Sort: []string{"author"}, Cursor: &cursor})
+			Expect(err).To(Equal(errmap.ErrmapInvalidCursor))
+			Expect(statements).To(BeEmpty())
+		})
+
+		It("should refuse a cursor that is not one", func() {
+			cursor := "not a cursor"
+			_, _, err := r.ListBook(entity.ListBookRequest{Size: 10, Cursor: &cursor})
+			Expect(err).To(Equal(errmap.ErrmapInvalidCursor))
+		})
+	})
+
+	Context("GetBookByISBN", func() {
+		It("should look the book up by its normalized isbn", func() {
+			_, err := r.GetBookByISBN("9780306406157")
+			Expect(err).To(Equal(errmap.ErrmapNotFound))
+
+			Expect(statements).To(Equal([]string{`SELECT * FROM "books" WHERE isbn = '9780306406157' LIMIT 1`}))
+		})
+	})
+
+	Context("BookSearchDocument", func() {
+		It("should index both forms of the isbn with the title", func() {
+			isbn := "9780441172719"
+			document := repository.BookSearchDocument(entity.Book{Title: "Dune", Author: "Frank Herbert", ISBN: &isbn}, nil)
+
+			Expect(document).To(ContainSubstring(`'9780441172719':6A`))
+			Expect(document).To(ContainSubstring(`'0441172717':8A`))
+			Expect(document).To(ContainSubstring(`'dune':1A`))
+		})
+	})
+
+	Context("CountBooks", func() {
+		It("should count the books matching the filters", func() {
+			available := true
+			_, err := r.CountBooks(entity.ListBookRequest{Page: 3, Size: 10, Available: &available, Sort: []string{"title"}})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(statements).To(Equal([]string{`SELECT count(*) FROM "books" WHERE deleted_at IS NULL AND stock > 0`}))
+		})
+	})
+
+	Context("GetBookFacets", func() {
+		It("should count each facet without its own filter", func() {
+			search := "dune"
+			author := "Frank Herbert"
+			categoryID := uint(4)
+			subject := "Ecology"
+			available := false
+			minPrice := 100.0
+			language := "th"
+			minYear := 1960
+			facets, err := r.GetBookFacets(entity.ListBookRequest{Search: &search, Mode: constant.SearchModeFuzzy, Author: &author,
+				CategoryID: &categoryID, Subject: &subject, Available: &available, MinPrice: &minPrice, Language: &language, MinYear: &minYear})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(facets.Authors).To(BeEmpty())
+			Expect(facets.Categories).To(BeEmpty())
+			Expect(facets.PriceRanges).To(BeEmpty())
+			Expect(facets.Languages).To(BeEmpty())
+			Expect(facets.Decades).To(BeEmpty())
+
+			matches := `WHERE deleted_at IS NULL AND (('dune' <% title OR 'dune' <% author)) `
+			byAuthor := `AND (EXISTS (SELECT 1 FROM book_authors JOIN authors ON authors.id = book_authors.author_id WHERE book_authors.book_id = books.id AND authors.name_key = 'frank herbert')) `
+			byCategory := `AND (EXISTS (SELECT 1 FROM book_categories WHERE book_categories.book_id = books.id AND book_categories.category_id IN (` +
+				`WITH RECURSIVE subtree AS (SELECT id FROM categories WHERE id = 4 UNION ALL SELECT categories.id FROM categories JOIN subtree ON categories.parent_id = subtree.id) SELECT id FROM subtree))) `
+			bySubject := `AND (EXISTS (SELECT 1 FROM book_subjects WHERE book_subjects.book_id = books.id AND LOWER(book_subjects.name) = LOWER('Ecology'))) `
+			byLanguage := `AND LOWER(language) = LOWER('th') `
+			byYear := `AND publication_year >= 1960 `
+
+			Expect(statements).To(Equal([]string{
+				`SELECT authors.id AS id, authors.name AS value, COUNT(*) AS count FROM "books" ` +
+					`JOIN book_authors ON book_authors.book_id = books.id JOIN authors ON authors.id = book_authors.author_id ` +
+					matches + byCategory + bySubject + `AND stock = 0 AND price >= 100 ` + byLanguage + byYear +
+					`GROUP BY "authors"."id" ORDER BY count DESC, authors.name ASC LIMIT 20`,
+				`SELECT categories.id AS id, categories.name AS value, COUNT(*) AS count FROM "books" ` +
+					`JOIN book_categories ON book_categories.book_id = books.id JOIN categories ON categories.id = book_categories.category_id ` +
+					matches + byAuthor + bySubject + `AND stock = 0 AND price >= 100 ` + byLanguage + byYear +
+					`GROUP BY "categories"."id" ORDER BY count DESC, categories.name ASC LIMIT 20`,
+				`SELECT MIN(book_subjects.name) AS value, COUNT(*) AS count FROM "books" JOIN book_subjects ON book_subjects.book_id = books.id ` +
+					matches + byAuthor + byCategory + `AND stock = 0 AND price >= 100 ` + byLanguage + byYear +
+					`GROUP BY LOWER(book_subjects.name) ORDER BY count DESC, value ASC LIMIT 20`,
+				`SELECT CASE WHEN stock > 0 THEN 'available' ELSE 'unavailable' END AS value, COUNT(*) AS count FROM "books" ` +
+					matches + byAuthor + byCategory + bySubject + `AND price >= 100 ` + byLanguage + byYear +
+					`GROUP BY "value" ORDER BY value ASC`,
+				`SELECT width_bucket(price, ARRAY[100,300,500,1000]::double precision[]) AS bucket, COUNT(*) AS count FROM "books" ` +
+					matches + byAuthor + byCategory + bySubject + `AND stock = 0 ` + byLanguage + byYear +
+					`GROUP BY "bucket" ORDER BY bucket ASC`,
+				`SELECT MIN(language) AS value, COUNT(*) AS count FROM "books" ` +
+					matches + byAuthor + byCategory + bySubject + `AND stock = 0 AND price >= 100 ` + byYear +
+					`AND language <> '' GROUP BY LOWER(language) ORDER BY count DESC, value ASC LIMIT 20`,
+				`SELECT publication_year / 10 * 10 AS decade, COUNT(*) AS count FROM "books" ` +
+					matches + byAuthor + byCategory + bySubject + `AND stock = 0 AND price >= 100 ` + byLanguage +
+					`AND publication_year IS NOT NULL GROUP BY "decade" ORDER BY decade ASC`,
+			}))
+		})
+	})
+
+	Context("SuggestBookSearch", func() {
+		It("should look for the closest title or author", func() {
+			suggestion, err := r.SuggestBookSearch("rowlnig")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(suggestion).To(BeNil())
+
+			// Building the union subqueries records them too
+			Expect(statements).To(ContainElement(`SELECT "candidate" FROM (SELECT title AS candidate FROM "books" WHERE deleted_at IS NULL ` +
+				`UNION SELECT authors.name AS candidate FROM "authors" WHERE EXISTS (SELECT 1 FROM book_authors JOIN books ON books.id = book_authors.book_id ` +
+				`WHERE book_authors.author_id = authors.id AND books.deleted_at IS NULL)) AS candidates ` +
+				`WHERE 'rowlnig' <% candidate ORDER BY word_similarity('rowlnig', candidate) DESC, candidate LIMIT 1`))
+		})
+
+		It("should not query for a blank search", func() {
+			suggestion, err := r.SuggestBookSearch(" ")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(suggestion).To(BeNil())
+			Expect(statements).To(BeEmpty())
+		})
+	})
+
+	Context("EachBook", func() {
+		It("should page through the books of a listing by keyset", func() {
+			available := true
+			err := r.EachBook(entity.ListBookRequest{Available: &available, Sort: []string{"-createdAt"}}, 500, func(books []entity.BookResponse) error {
+				Fail("no books in a dry run")
+				return nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(statements).To(Equal([]string{`SELECT * FROM "books" WHERE deleted_at IS NULL AND stock > 0 ORDER BY created_at DESC, id ASC LIMIT 501`}))
+		})
+	})
+
+	Context("GetBookAvailability", func() {
+		It("should count the copies of each book by status", func() {
+			_, err := r.GetBookAvailability([]uint{1, 2})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(statements).To(Equal([]string{`SELECT book_id, ` +
+				`COUNT(*) FILTER (WHERE status NOT IN ('RETIRED', 'LOST')) AS copies, ` +
+				`COUNT(*) FILTER (WHERE status = 'AVAILABLE') AS available, ` +
+				`COUNT(*) FILTER (WHERE status = 'ON_LOAN') AS on_loan, ` +
+				`COUNT(*) FILTER (WHERE status = 'ON_HOLD') AS on_hold ` +
+				`FROM "book_copies" WHERE book_id IN (1,2) GROUP BY "book_id"`}))
+		})
+	})
+
+	Context("ListBookChanges", func() {
+		It("should list the books changed in a range, archived books included", func() {
+			from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+			before := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+			categoryID := uint(4)
+
+			_, next, err := r.ListBookChanges(entity.ListBookChangeRequest{From: &from, Before: &before, CategoryID: &categoryID, Size: 100})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(next).To(BeNil())
+
+			Expect(statements).To(Equal([]string{`SELECT * FROM "books" WHERE (EXISTS (SELECT 1 FROM book_categories ` +
+				`WHERE book_categories.book_id = books.id AND book_categories.category_id IN (WITH RECURSIVE subtree AS ` +
+				`(SELECT id FROM categories WHERE id = 4 UNION ALL SELECT categories.id FROM categories JOIN subtree ON categories.parent_id = subtree.id) ` +
+				`SELECT id FROM subtree))) AND updated_at >= '2026-01-01 00:00:00' AND updated_at < '2026-02-01 00:00:00' ` +
+				`ORDER BY updated_at ASC, id ASC LIMIT 101`}))
+		})
+
+		It("should continue after the last book of the page before", func() {
+			cursor, err := repository.EncodeBookChangeCursor(time.Date(2026, 1, 5, 8, 30, 0, 0, time.UTC), 42)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, err = r.ListBookChanges(entity.ListBookChangeRequest{Size: 100, Cursor: cursor})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(statements).To(Equal([]string{`SELECT * FROM "books" WHERE (updated_at > '2026-01-05 08:30:00') ` +
+				`OR (updated_at = '2026-01-05 08:30:00' AND id > 42) ORDER BY updated_at ASC, id ASC LIMIT 101`}))
+		})
+
+		It("should return invalid cursor for a cursor of another listing", func() {
+			cursor, err := repository.EncodeUserBorrowCursor(time.Date(2026, 1, 5, 8, 30, 0, 0, time.UTC), int64(42))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, err = r.ListBookChanges(entity.ListBookChangeRequest{Size: 100, Cursor: cursor})
+			Expect(err).To(Equal(errmap.ErrmapInvalidCursor))
+		})
+	})
+
+	Context("CountBookChanges", func() {
+		It("should count the books changed since a time", func() {
+			from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+			_, err := r.CountBookChanges(entity.ListBookChangeRequest{From: &from})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(statements).To(Equal([]string{`SELECT count(*) FROM "books" WHERE updated_at >= '2026-01-01 00:00:00'`}))
+		})
+	})
+
+	Context("GetEarliestBookChange", func() {
+		It("should get the earliest time a book last changed", func() {
+			_, err := r.GetEarliestBookChange()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(statements).To(Equal([]string{`SELECT MIN(updated_at) FROM "books"`}))
+		})
+	})
+
+	Context("SearchBooks", func() {
+		It("should translate each boolean of a query into parentheses of its own", func() {
+			_, err := r.SearchBooks(entity.SearchBookRequest{
+				Query: entity.BookQuery{
+					Operator: constant.BookQueryNot,
+					Left: &entity.BookQuery{
+						Operator: constant.BookQueryOr,
+						Left:     &entity.BookQuery{Field: constant.BookQueryFieldTitle, Relation: constant.BookQueryRelationContains, Term: "dune"},
+						Right:    &entity.BookQuery{Field: constant.BookQueryFieldAuthor, Relation: constant.BookQueryRelationExact, Term: "Herbert*"},
+					},
+					Right: &entity.BookQuery{Field: constant.BookQueryFieldYear, Relation: constant.BookQueryRelationLt, Term: "1970"},
+				},
+				Offset: 10,
+				Limit:  10,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(statements).To(Equal([]string{`SELECT * FROM "books" WHERE deleted_at IS NULL AND ` +
+				`((title ILIKE '%dune%' OR author ILIKE 'Herbert%') AND NOT publication_year < 1970) ORDER BY id ASC LIMIT 10 OFFSET 10`}))
+		})
+
+		It("should match every or any word of a term, masks as wildcards and escapes as literals", func() {
+			_, err := r.SearchBooks(entity.SearchBookRequest{
+				Query: entity.BookQuery{
+					Operator: constant.BookQueryAnd,
+					Left:     &entity.BookQuery{Field: constant.BookQueryFieldPublisher, Relation: constant.BookQueryRelationAll, Term: `ace b?oks`},
+					Right:    &entity.BookQuery{Field: constant.BookQueryFieldTitle, Relation: constant.BookQueryRelationAny, Term: `100% \*star\*`},
+				},
+				Limit: 10,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(statements).To(Equal([]string{`SELECT * FROM "books" WHERE deleted_at IS NULL AND ` +
+				`((publisher ILIKE '%ace%' AND publisher ILIKE '%b_oks%') AND (title ILIKE '%100\%%' OR title ILIKE '%*star*%')) ` +
+				`ORDER BY id ASC LIMIT 10`}))
+		})
+
+		It("should match a subject against the subjects and category names of a book", func() {
+			_, err := r.SearchBooks(entity.SearchBookRequest{
+				Query: entity.BookQuery{Field: constant.BookQueryFieldSubject, Relation: constant.BookQueryRelationExact, Term: "science fiction"},
+				Limit: 10,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(statements).To(Equal([]string{`SELECT * FROM "books" WHERE deleted_at IS NULL AND ` +
+				`(EXISTS (SELECT 1 FROM book_subjects WHERE book_subjects.book_id = books.id AND book_subjects.name ILIKE 'science fiction') ` +
+				`OR EXISTS (SELECT 1 FROM book_categories JOIN categories ON categories.id = book_categories.category_id ` +
+				`WHERE book_categories.book_id = books.id AND categories.name ILIKE 'science fiction')) ORDER BY id ASC LIMIT 10`}))
+		})
+
+		It("should search the full text of books for any field", func() {
+			_, err := r.SearchBooks(entity.SearchBookRequest{
+				Query: entity.BookQuery{
+					Operator: constant.BookQueryAnd,
+					Left:     &entity.BookQuery{Field: constant.BookQueryFieldAny, Relation: constant.BookQueryRelationAll, Term: "desert planet"},
+					Right:    &entity.BookQuery{Field: constant.BookQueryFieldISBN, Relation: constant.BookQueryRelationExact, Term: "9780441172719"},
+				},
+				Limit: 10,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(statements).To(HaveLen(1))
+			Expect(statements[0]).To(ContainSubstring(`(search_vector @@ `))
+			Expect(statements[0]).To(ContainSubstring(`::tsquery AND isbn = '9780441172719')`))
+		})
+
+		It("should reject a year that is not a number", func() {
+			_, err := r.SearchBooks(entity.SearchBookRequest{
+				Query: entity.BookQuery{Field: constant.BookQueryFieldYear, Relation: constant.BookQueryRelationEq, Term: "sixties"},
+				Limit: 10,
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(statements).To(BeEmpty())
+		})
+	})
+
+	Context("CountSearchBooks", func() {
+		It("should count the books matching a query", func() {
+			_, err := r.CountSearchBooks(entity.BookQuery{Field: constant.BookQueryFieldLanguage, Relation: constant.BookQueryRelationExact, Term: "en"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(statements).To(Equal([]string{`SELECT count(*) FROM "books" WHERE deleted_at IS NULL AND language ILIKE 'en'`}))
+		})
+	})
+
+	Context("ImportBooks", func() {
+		It("should not create a new book without a stock", func() {
+			isbn := "9780441172719"
+			actions, err := r.ImportBooks([]entity.BookImportItem{{Line: 2, Book: entity.Book{Title: "Dune", ISBN: &isbn}}}, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actions).To(Equal([]string{constant.BookImportNoStock}))
+
+			Expect(statements).To(Equal([]string{`SELECT * FROM "books" WHERE isbn = '9780441172719' LIMIT 1 FOR UPDATE`}))
+		})
+	})
+})
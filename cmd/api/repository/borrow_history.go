@@ -13,7 +13,7 @@ import (
 	"gorm.io/gorm/clause"
 )
 
-func (r *PostgresRepository) BorrowBook(history *entity.BorrowHistory) (*entity.BorrowHistory, error) {
+func (r *PostgresRepository) BorrowBook(history *entity.BorrowHistory, maxActiveLoans uint) (*entity.BorrowHistory, error) {
 	tx := r.postgres.Begin()
 	defer func() {
 		if r := recover(); r != nil {
@@ -25,22 +25,94 @@ func (r *PostgresRepository) BorrowBook(history *entity.BorrowHistory) (*entity.
 		return nil, tx.Error
 	}
 
-	var book entity.Book
-	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Table("books").First(&book, history.BookID).Error; err != nil {
+	if err := lockUser(tx, history.UserID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	var activeLoans int64
+	if err := activeLoansOf(tx, history.UserID).Count(&activeLoans).Error; err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "[PostgresRepository.BorrowBook]: unable to count active loans")
+	}
+
+	if activeLoans >= int64(maxActiveLoans) {
+		tx.Rollback()
+		return nil, &errmap.LoanLimitError{Limit: constant.LoanLimitActiveLoans, Max: maxActiveLoans}
+	}
+
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Table("books").Where("deleted_at IS NULL").First(&entity.Book{}, history.BookID).Error; err != nil {
 		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmap.ErrmapNotFound
+		}
 		return nil, errors.Wrap(err, "[PostgresRepository.BorrowBook]: unable to get book")
 	}
 
-	if book.Stock < 1 {
+	// A copy picked at the desk only fulfills the hold it was set aside for
+	holdQuery := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Table("holds").
+		Where("book_id = ? AND user_id = ? AND status = ? AND expires_at > ?", history.BookID, history.UserID, constant.HoldStatusReady, time.Now())
+	if history.CopyID != nil {
+		holdQuery = holdQuery.Where("copy_id = ?", *history.CopyID)
+	}
+
+	var readyHold entity.Hold
+	err := holdQuery.First(&readyHold).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		tx.Rollback()
-		return nil, errmap.ErrmapInvalidStock
+		return nil, errors.Wrap(err, "[PostgresRepository.BorrowBook]: unable to get ready hold")
 	}
 
-	if err := tx.Table("books").Where("id = ?", history.BookID).Update("stock", gorm.Expr("stock - ?", 1)).Error; err != nil {
+	var bookCopy *entity.BookCopy
+	if err == nil {
+		// The copy set aside for this hold was never made available
+		if readyHold.CopyID == nil {
+			tx.Rollback()
+			return nil, errors.New("[PostgresRepository.BorrowBook]: ready hold has no copy")
+		}
+
+		if bookCopy, err = lockCopy(tx, *readyHold.CopyID); err != nil {
+			tx.Rollback()
+			return nil, errors.Wrap(err, "[PostgresRepository.BorrowBook]: unable to get held copy")
+		}
+
+		if err := tx.Table("holds").Where("id = ?", readyHold.ID).Updates(map[string]interface{}{
+			"status":     constant.HoldStatusFulfilled,
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+			tx.Rollback()
+			return nil, errors.Wrap(err, "[PostgresRepository.BorrowBook]: unable to fulfill hold")
+		}
+	} else {
+		copyQuery := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Table("book_copies").
+			Where("book_id = ? AND status = ?", history.BookID, constant.CopyStatusAvailable)
+		if history.CopyID != nil {
+			copyQuery = copyQuery.Where("id = ?", *history.CopyID)
+		}
+
+		bookCopy = &entity.BookCopy{}
+		err := copyQuery.Order("id ASC").First(bookCopy).Error
+		if err != nil {
+			tx.Rollback()
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errmap.ErrmapInvalidStock
+			}
+			return nil, errors.Wrap(err, "[PostgresRepository.BorrowBook]: unable to get available copy")
+		}
+	}
+
+	if err := setCopyStatus(tx, bookCopy.ID, constant.CopyStatusOnLoan); err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "[PostgresRepository.BorrowBook]: unable to update book copy")
+	}
+
+	if err := syncBookStock(tx, history.BookID); err != nil {
 		tx.Rollback()
 		return nil, errors.Wrap(err, "[PostgresRepository.BorrowBook]: unable to update book stock")
 	}
 
+	history.CopyID = &bookCopy.ID
+
 	if err := tx.Table("borrow_histories").Create(history).Error; err != nil {
 		tx.Rollback()
 		return nil, errors.Wrap(err, "[PostgresRepository.BorrowBook]: unable to create borrow history")
@@ -54,7 +126,7 @@ func (r *PostgresRepository) BorrowBook(history *entity.BorrowHistory) (*entity.
 	return history, nil
 }
 
-func (r *PostgresRepository) ReturnBook(historyID, BookID uint, returnedAt time.Time) error {
+func (r *PostgresRepository) ReturnBook(historyID, BookID uint, returnedAt, pickupExpiresAt time.Time) error {
 	tx := r.postgres.Begin()
 	defer func() {
 		if r := recover(); r != nil {
@@ -66,10 +138,11 @@ func (r *PostgresRepository) ReturnBook(historyID, BookID uint, returnedAt time.
 		return tx.Error
 	}
 
-	var history entity.BorrowHistory
-	if err := tx.Table("borrow_histories").First(&history, historyID).Error; err != nil {
+	// A concurrent return, loss or damage of the borrow must not release its copy a second time
+	history, err := lockActiveBorrowHistory(tx, historyID)
+	if err != nil {
 		tx.Rollback()
-		return errors.Wrap(err, "[PostgresRepository.ReturnBook]: unable to get borrow history")
+		return err
 	}
 
 	if history.BookID != BookID {
@@ -82,14 +155,24 @@ func (r *PostgresRepository) ReturnBook(historyID, BookID uint, returnedAt time.
 		Updates(map[string]interface{}{
 			"returned_at": returnedAt,
 			"status":      constant.BorrowStatusReturned,
+			"updated_at":  returnedAt,
 		}).Error; err != nil {
 		tx.Rollback()
 		return errors.Wrap(err, "[PostgresRepository.ReturnBook]: unable to update borrow history")
 	}
 
-	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Table("books").
-		Where("id = ?", history.BookID).
-		Update("stock", gorm.Expr("stock + ?", 1)).Error; err != nil {
+	if history.CopyID == nil {
+		tx.Rollback()
+		return errors.New("[PostgresRepository.ReturnBook]: borrow history has no copy")
+	}
+
+	bookCopy, err := lockCopy(tx, *history.CopyID)
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.ReturnBook]: unable to get book copy")
+	}
+
+	if err := releaseCopy(tx, *bookCopy, returnedAt, pickupExpiresAt); err != nil {
 		tx.Rollback()
 		return errors.Wrap(err, "[PostgresRepository.ReturnBook]: unable to update book stock")
 	}
@@ -102,6 +185,100 @@ func (r *PostgresRepository) ReturnBook(historyID, BookID uint, returnedAt time.
 	return nil
 }
 
+// RenewBook extends the due date of a borrow renewed fewer than maxRenewals times by loanPeriod, from the
+// current due date or from now when it has passed, and returns the new due date. A borrow whose book is
+// waited for cannot be renewed. The late fee of an overdue borrow is charged up to now first, borrows
+// without a fine rate of their own are charged ratePerDay.
+func (r *PostgresRepository) RenewBook(historyID uint, now time.Time, loanPeriod time.Duration, maxRenewals uint, ratePerDay float64) (time.Time, error) {
+	tx := r.postgres.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return time.Time{}, tx.Error
+	}
+
+	var history entity.BorrowHistory
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Table("borrow_histories").First(&history, historyID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return time.Time{}, errmap.ErrmapNotFound
+		}
+		return time.Time{}, errors.Wrap(err, "[PostgresRepository.RenewBook]: unable to get borrow history")
+	}
+
+	if history.ReturnedAt != nil {
+		tx.Rollback()
+		return time.Time{}, errmap.ErrmapConflict
+	}
+
+	// A concurrent renewal may have used up the last one since the borrow was read
+	if history.RenewalCount >= maxRenewals {
+		tx.Rollback()
+		return time.Time{}, errmap.ErrmapRenewalLimit
+	}
+
+	// The ledger is locked before the book, in the order BorrowBook takes them
+	if err := lockUser(tx, history.UserID); err != nil {
+		tx.Rollback()
+		return time.Time{}, errors.Wrap(err, "[PostgresRepository.RenewBook]: unable to lock fee ledger")
+	}
+
+	// CreateHold queues on the book under the same lock, so no hold is placed between the check and the renewal
+	var book entity.Book
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Table("books").Select("id").First(&book, history.BookID).Error; err != nil {
+		tx.Rollback()
+		return time.Time{}, errors.Wrap(err, "[PostgresRepository.RenewBook]: unable to lock book")
+	}
+
+	var waiting int64
+	if err := tx.Table("holds").
+		Where("book_id = ? AND status = ?", history.BookID, constant.HoldStatusWaiting).
+		Count(&waiting).Error; err != nil {
+		tx.Rollback()
+		return time.Time{}, errors.Wrap(err, "[PostgresRepository.RenewBook]: unable to count holds")
+	}
+
+	if waiting > 0 {
+		tx.Rollback()
+		return time.Time{}, errmap.ErrmapHoldPending
+	}
+
+	base := now
+	if history.DueAt != nil && history.DueAt.After(now) {
+		base = *history.DueAt
+	}
+	dueAt := base.Add(loanPeriod)
+
+	// Moving the due date would forgive the days the borrow is already late
+	if _, err := accrueLateFees(tx, now, ratePerDay, nil, &historyID); err != nil {
+		tx.Rollback()
+		return time.Time{}, errors.Wrap(err, "[PostgresRepository.RenewBook]: unable to accrue late fees")
+	}
+
+	if err := tx.Table("borrow_histories").
+		Where("id = ?", historyID).
+		Updates(map[string]interface{}{
+			"due_at":        dueAt,
+			"renewal_count": gorm.Expr("renewal_count + ?", 1),
+			"status":        constant.BorrowStatusBorrowed,
+			"updated_at":    now,
+		}).Error; err != nil {
+		tx.Rollback()
+		return time.Time{}, errors.Wrap(err, "[PostgresRepository.RenewBook]: unable to update borrow history")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return time.Time{}, errors.Wrap(err, "[PostgresRepository.RenewBook]: unable to commit transaction")
+	}
+
+	return dueAt, nil
+}
+
 func (r *PostgresRepository) GetBorrowHistoryByBookID(bookID uint) ([]entity.BorrowHistoryResponse, error) {
 	var history []entity.BorrowHistoryResponse
 	err := r.postgres.Table("borrow_histories").Where("book_id = ?", bookID).Order("created_at DESC").Find(&history).Error
@@ -123,3 +300,407 @@ func (r *PostgresRepository) GetBorrowHistoryByID(id uint) (*entity.BorrowHistor
 	}
 	return &history, nil
 }
+
+// MarkOverdueBorrowHistories flags borrowed books whose due date has passed as overdue, for one user or
+// everyone when userID is nil
+func (r *PostgresRepository) MarkOverdueBorrowHistories(now time.Time, userID *uint) (int64, error) {
+	query := r.postgres.Table("borrow_histories").
+		Where("status = ? AND returned_at IS NULL AND due_at < ?", constant.BorrowStatusBorrowed, now)
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	}
+
+	result := query.Updates(map[string]interface{}{
+			"status":     constant.BorrowStatusOverdue,
+			"updated_at": now,
+		})
+	if result.Error != nil {
+		return 0, errors.Wrap(result.Error, "[PostgresRepository.MarkOverdueBorrowHistories]: unable to mark overdue borrow histories")
+	}
+	return result.RowsAffected, nil
+}
+
+// overdueBorrowOrder sorts overdue borrows by how long they have been due
+var overdueBorrowOrder = keysetOrder{{SQL: "due_at"}, {SQL: "id"}}
+
+// ListOverdueBorrowHistories lists unreturned borrow histories past their due date,
+// with the cursor of the page after it when paging by cursor and there is one
+func (r *PostgresRepository) ListOverdueBorrowHistories(req entity.ListOverdueBorrowRequest, now time.Time) ([]entity.BorrowHistoryResponse, *string, error) {
+	var histories []entity.BorrowHistoryResponse
+	query, err := paginate(r.overdueBorrowHistories(req, now), overdueBorrowOrder, req.Page, req.Size, req.Cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := query.Find(&histories).Error; err != nil {
+		return nil, nil, errors.Wrap(err, "[PostgresRepository.ListOverdueBorrowHistories]: unable to get overdue borrow histories")
+	}
+
+	if req.Cursor == nil || len(histories) <= req.Size {
+		return histories, nil, nil
+	}
+
+	histories = histories[:req.Size]
+	next, err := r.nextCursor("borrow_histories", overdueBorrowOrder, histories[len(histories)-1].ID)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "[PostgresRepository.ListOverdueBorrowHistories]: unable to get next cursor")
+	}
+	return histories, next, nil
+}
+
+// CountOverdueBorrowHistories counts unreturned borrow histories past their due date
+func (r *PostgresRepository) CountOverdueBorrowHistories(req entity.ListOverdueBorrowRequest, now time.Time) (int64, error) {
+	var total int64
+	if err := r.overdueBorrowHistories(req, now).Count(&total).Error; err != nil {
+		return 0, errors.Wrap(err, "[PostgresRepository.CountOverdueBorrowHistories]: unable to count overdue borrow histories")
+	}
+	return total, nil
+}
+
+// overdueBorrowHistories selects the overdue borrow histories matching a listing
+func (r *PostgresRepository) overdueBorrowHistories(req entity.ListOverdueBorrowRequest, now time.Time) *gorm.DB {
+	query := r.postgres.Table("borrow_histories").
+		Where("returned_at IS NULL AND due_at < ?", now).
+		Where("status IN ?", []string{constant.BorrowStatusBorrowed, constant.BorrowStatusOverdue})
+
+	if req.UserID != nil {
+		query = query.Where("user_id = ?", *req.UserID)
+	}
+
+	if req.BookID != nil {
+		query = query.Where("book_id = ?", *req.BookID)
+	}
+
+	return query
+}
+
+// activeLoansOf selects the books a user has not returned yet
+func activeLoansOf(db *gorm.DB, userID uint) *gorm.DB {
+	return db.Table("borrow_histories").
+		Where("user_id = ? AND returned_at IS NULL AND status IN ?", userID, []string{constant.BorrowStatusBorrowed, constant.BorrowStatusOverdue})
+}
+
+const userLoanColumns = "borrow_histories.id, borrow_histories.book_id, books.title AS book_title, books.author AS book_author, " +
+	"borrow_histories.copy_id, borrow_histories.user_id, borrow_histories.borrowed_at, borrow_histories.due_at, borrow_histories.renewal_count, " +
+	"borrow_histories.returned_at, borrow_histories.status, borrow_histories.created_at, borrow_histories.updated_at"
+
+// ListActiveLoansByUserID lists the unreturned borrows of a user with their book details
+func (r *PostgresRepository) ListActiveLoansByUserID(userID uint) ([]entity.UserLoanResponse, error) {
+	var loans []entity.UserLoanResponse
+	err := r.postgres.Table("borrow_histories").
+		Select(userLoanColumns).
+		Joins("LEFT JOIN books ON books.id = borrow_histories.book_id").
+		Where("borrow_histories.user_id = ? AND borrow_histories.returned_at IS NULL", userID).
+		Where("borrow_histories.status IN ?", []string{constant.BorrowStatusBorrowed, constant.BorrowStatusOverdue}).
+		Order("borrow_histories.due_at ASC").
+		Find(&loans).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.ListActiveLoansByUserID]: unable to get active loans")
+	}
+	return loans, nil
+}
+
+// userBorrowOrder sorts the borrow history of a user latest first
+var userBorrowOrder = keysetOrder{{SQL: "borrow_histories.borrowed_at", Desc: true}, {SQL: "borrow_histories.id", Desc: true}}
+
+// ListBorrowHistoriesByUserID lists the borrow history of a user with their book details,
+// with the cursor of the page after it when paging by cursor and there is one
+func (r *PostgresRepository) ListBorrowHistoriesByUserID(req entity.ListUserBorrowHistoryRequest) ([]entity.UserLoanResponse, *string, error) {
+	var histories []entity.UserLoanResponse
+	query := r.userBorrowHistories(req).
+		Select(userLoanColumns).
+		Joins("LEFT JOIN books ON books.id = borrow_histories.book_id")
+
+	query, err := paginate(query, userBorrowOrder, req.Page, req.Size, req.Cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := query.Find(&histories).Error; err != nil {
+		return nil, nil, errors.Wrap(err, "[PostgresRepository.ListBorrowHistoriesByUserID]: unable to get borrow histories")
+	}
+
+	if req.Cursor == nil || len(histories) <= req.Size {
+		return histories, nil, nil
+	}
+
+	histories = histories[:req.Size]
+	next, err := r.nextCursor("borrow_histories", userBorrowOrder, histories[len(histories)-1].ID)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "[PostgresRepository.ListBorrowHistoriesByUserID]: unable to get next cursor")
+	}
+	return histories, next, nil
+}
+
+// CountBorrowHistoriesByUserID counts the borrow history of a user matching a listing
+func (r *PostgresRepository) CountBorrowHistoriesByUserID(req entity.ListUserBorrowHistoryRequest) (int64, error) {
+	var total int64
+	if err := r.userBorrowHistories(req).Count(&total).Error; err != nil {
+		return 0, errors.Wrap(err, "[PostgresRepository.CountBorrowHistoriesByUserID]: unable to count borrow histories")
+	}
+	return total, nil
+}
+
+// userBorrowHistories selects the borrow histories of a user matching a listing
+func (r *PostgresRepository) userBorrowHistories(req entity.ListUserBorrowHistoryRequest) *gorm.DB {
+	query := r.postgres.Table("borrow_histories").
+		Where("borrow_histories.user_id = ?", req.UserID)
+
+	if req.Status != "" {
+		query = query.Where("borrow_histories.status = ?", req.Status)
+	}
+
+	if req.From != nil {
+		query = query.Where("borrow_histories.borrowed_at >= ?", *req.From)
+	}
+
+	if req.To != nil {
+		// to is a date, so include the whole day
+		query = query.Where("borrow_histories.borrowed_at < ?", req.To.AddDate(0, 0, 1))
+	}
+
+	return query
+}
+
+// GetActiveBorrowHistoryByCopyID retrieves the unreturned borrow of a copy
+func (r *PostgresRepository) GetActiveBorrowHistoryByCopyID(copyID uint) (*entity.BorrowHistoryResponse, error) {
+	var history entity.BorrowHistoryResponse
+	err := r.postgres.Table("borrow_histories").
+		Where("copy_id = ? AND returned_at IS NULL", copyID).
+		Where("status IN ?", []string{constant.BorrowStatusBorrowed, constant.BorrowStatusOverdue}).
+		First(&history).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmap.ErrmapNotFound
+		}
+		return nil, errors.Wrap(err, "[PostgresRepository.GetActiveBorrowHistoryByCopyID]: unable to get borrow history")
+	}
+	return &history, nil
+}
+
+// ListActiveBorrowHistoriesByBookID lists the unreturned borrows of a book
+func (r *PostgresRepository) ListActiveBorrowHistoriesByBookID(bookID uint) ([]entity.BorrowHistoryResponse, error) {
+	var histories []entity.BorrowHistoryResponse
+	err := r.postgres.Table("borrow_histories").
+		Where("book_id = ? AND returned_at IS NULL", bookID).
+		Where("status IN ?", []string{constant.BorrowStatusBorrowed, constant.BorrowStatusOverdue}).
+		Order("borrowed_at ASC").
+		Find(&histories).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.ListActiveBorrowHistoriesByBookID]: unable to get borrow histories")
+	}
+	return histories, nil
+}
+
+// lockActiveBorrowHistory locks a borrow that has not been returned yet
+func lockActiveBorrowHistory(tx *gorm.DB, historyID uint) (*entity.BorrowHistory, error) {
+	var history entity.BorrowHistory
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Table("borrow_histories").First(&history, historyID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmap.ErrmapNotFound
+		}
+		return nil, errors.Wrap(err, "[lockActiveBorrowHistory]: unable to get borrow history")
+	}
+
+	if history.ReturnedAt != nil ||
+		(history.Status != constant.BorrowStatusBorrowed && history.Status != constant.BorrowStatusOverdue) {
+		return nil, errmap.ErrmapConflict
+	}
+
+	return &history, nil
+}
+
+// MarkBorrowLost closes a borrow as lost, its copy stays out of stock. The replacement charge, when given,
+// is billed in the same transaction unless the borrow was already charged.
+func (r *PostgresRepository) MarkBorrowLost(historyID uint, lostAt time.Time, replacement *entity.FeeTransaction) error {
+	tx := r.postgres.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	history, err := lockActiveBorrowHistory(tx, historyID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// The ledger is locked before the copy and the book, in the order BorrowBook takes them
+	if err := lockUser(tx, history.UserID); err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.MarkBorrowLost]: unable to lock fee ledger")
+	}
+
+	if err := tx.Table("borrow_histories").
+		Where("id = ?", historyID).
+		Updates(map[string]interface{}{
+			"lost_at":    lostAt,
+			"status":     constant.BorrowStatusLost,
+			"updated_at": lostAt,
+		}).Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.MarkBorrowLost]: unable to update borrow history")
+	}
+
+	if history.CopyID != nil {
+		if err := setCopyStatus(tx, *history.CopyID, constant.CopyStatusLost); err != nil {
+			tx.Rollback()
+			return errors.Wrap(err, "[PostgresRepository.MarkBorrowLost]: unable to update book copy")
+		}
+	}
+
+	if replacement != nil {
+		if err := createReplacementCharge(tx, replacement); err != nil && !errors.Is(err, errmap.ErrmapConflict) {
+			tx.Rollback()
+			return errors.Wrap(err, "[PostgresRepository.MarkBorrowLost]: unable to charge replacement cost")
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.MarkBorrowLost]: unable to commit transaction")
+	}
+
+	return nil
+}
+
+// ReturnBookDamaged closes a borrow whose book came back damaged and withdraws the copy. The replacement charge,
+// when given, is billed in the same transaction unless the borrow was already charged.
+func (r *PostgresRepository) ReturnBookDamaged(historyID uint, returnedAt time.Time, replacement *entity.FeeTransaction) error {
+	tx := r.postgres.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	history, err := lockActiveBorrowHistory(tx, historyID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// The ledger is locked before the copy and the book, in the order BorrowBook takes them
+	if err := lockUser(tx, history.UserID); err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.ReturnBookDamaged]: unable to lock fee ledger")
+	}
+
+	if err := tx.Table("borrow_histories").
+		Where("id = ?", historyID).
+		Updates(map[string]interface{}{
+			"returned_at": returnedAt,
+			"status":      constant.BorrowStatusDamaged,
+			"updated_at":  returnedAt,
+		}).Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.ReturnBookDamaged]: unable to update borrow history")
+	}
+
+	if history.CopyID != nil {
+		if err := tx.Table("book_copies").
+			Where("id = ?", *history.CopyID).
+			Updates(map[string]interface{}{
+				"condition":  constant.CopyConditionDamaged,
+				"status":     constant.CopyStatusRetired,
+				"updated_at": returnedAt,
+			}).Error; err != nil {
+			tx.Rollback()
+			return errors.Wrap(err, "[PostgresRepository.ReturnBookDamaged]: unable to update book copy")
+		}
+	}
+
+	if replacement != nil {
+		if err := createReplacementCharge(tx, replacement); err != nil && !errors.Is(err, errmap.ErrmapConflict) {
+			tx.Rollback()
+			return errors.Wrap(err, "[PostgresRepository.ReturnBookDamaged]: unable to charge replacement cost")
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.ReturnBookDamaged]: unable to commit transaction")
+	}
+
+	return nil
+}
+
+// MarkBorrowFound returns a lost book that turned up, puts its copy back in circulation and waives what is
+// still owed on its replacement. The amount of the waiver is filled in from the ledger of the borrower.
+func (r *PostgresRepository) MarkBorrowFound(historyID uint, foundAt, pickupExpiresAt time.Time, waiver *entity.FeeTransaction) error {
+	tx := r.postgres.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	var history entity.BorrowHistory
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Table("borrow_histories").First(&history, historyID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errmap.ErrmapNotFound
+		}
+		return errors.Wrap(err, "[PostgresRepository.MarkBorrowFound]: unable to get borrow history")
+	}
+
+	if history.Status != constant.BorrowStatusLost {
+		tx.Rollback()
+		return errmap.ErrmapConflict
+	}
+
+	// The ledger is locked before the copy and the book, in the order BorrowBook takes them
+	if err := lockUser(tx, history.UserID); err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.MarkBorrowFound]: unable to lock fee ledger")
+	}
+
+	if err := tx.Table("borrow_histories").
+		Where("id = ?", historyID).
+		Updates(map[string]interface{}{
+			"returned_at": foundAt,
+			"status":      constant.BorrowStatusReturned,
+			"updated_at":  foundAt,
+		}).Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.MarkBorrowFound]: unable to update borrow history")
+	}
+
+	if history.CopyID != nil {
+		bookCopy, err := lockCopy(tx, *history.CopyID)
+		if err != nil {
+			tx.Rollback()
+			return errors.Wrap(err, "[PostgresRepository.MarkBorrowFound]: unable to get book copy")
+		}
+
+		if err := releaseCopy(tx, *bookCopy, foundAt, pickupExpiresAt); err != nil {
+			tx.Rollback()
+			return errors.Wrap(err, "[PostgresRepository.MarkBorrowFound]: unable to release copy")
+		}
+	}
+
+	if err := waiveReplacement(tx, waiver); err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.MarkBorrowFound]: unable to waive replacement cost")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.MarkBorrowFound]: unable to commit transaction")
+	}
+
+	return nil
+}
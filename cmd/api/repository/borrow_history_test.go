@@ -0,0 +1,170 @@
+package repository_test
+
+import (
+	"time"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	"go-library-service/cmd/api/repository"
+	errmap "go-library-service/internal/error_map"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BorrowHistory Repository", func() {
+	var (
+		statements []string
+		r          *repository.PostgresRepository
+	)
+
+	BeforeEach(func() {
+		statements = nil
+		r = repository.NewPostgresRepositoryWithDB(dryRunDB(&statements))
+	})
+
+	Context("ReturnBook", func() {
+		It("should refuse a borrow closed since it was read without releasing its copy", func() {
+			returnedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+			copyID := uint(4)
+			db := dryRunDB(&statements)
+			dryRunRow(db, entity.BorrowHistory{ID: 7, BookID: 2, CopyID: &copyID, UserID: 3, ReturnedAt: &returnedAt, Status: constant.BorrowStatusReturned})
+			r = repository.NewPostgresRepositoryWithDB(db)
+
+			err := r.ReturnBook(7, 2, returnedAt, returnedAt.Add(72*time.Hour))
+			Expect(err).To(Equal(errmap.ErrmapConflict))
+			Expect(statements).To(Equal([]string{`SELECT * FROM "borrow_histories" WHERE "borrow_histories"."id" = 7 ORDER BY "borrow_histories"."id" LIMIT 1 FOR UPDATE`}))
+		})
+
+		It("should close the borrow it locked", func() {
+			returnedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+			copyID := uint(4)
+			db := dryRunDB(&statements)
+			dryRunRow(db, entity.BorrowHistory{ID: 7, BookID: 2, CopyID: &copyID, UserID: 3, Status: constant.BorrowStatusBorrowed})
+			r = repository.NewPostgresRepositoryWithDB(db)
+
+			Expect(r.ReturnBook(7, 2, returnedAt, returnedAt.Add(72*time.Hour))).To(Succeed())
+			Expect(statements).To(ContainElement(`UPDATE "borrow_histories" SET "returned_at"='2024-05-01 00:00:00',"status"='RETURNED',"updated_at"='2024-05-01 00:00:00' WHERE id = 7`))
+		})
+	})
+
+	Context("MarkBorrowFound", func() {
+		It("should lock the ledger of the borrower before the copy", func() {
+			foundAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+			copyID := uint(4)
+			historyID := uint(7)
+			db := dryRunDB(&statements)
+			dryRunRow(db, entity.BorrowHistory{ID: historyID, BookID: 2, CopyID: &copyID, UserID: 3, Status: constant.BorrowStatusLost})
+			r = repository.NewPostgresRepositoryWithDB(db)
+
+			// A dry run cannot scan the sums of the ledger, so it stops at the waiver
+			err := r.MarkBorrowFound(historyID, foundAt, foundAt.Add(72*time.Hour), &entity.FeeTransaction{UserID: 3, BorrowHistoryID: &historyID})
+			Expect(err).To(MatchError(ContainSubstring("unable to waive replacement cost")))
+			Expect(len(statements)).To(BeNumerically(">", 3))
+			Expect(statements[1]).To(Equal(`SELECT "id" FROM "users" WHERE "users"."id" = 3 ORDER BY "users"."id" LIMIT 1 FOR UPDATE`))
+			Expect(statements[3]).To(HavePrefix(`SELECT * FROM "book_copies"`))
+		})
+	})
+
+	Context("RenewBook", func() {
+		It("should charge the late fee of an overdue borrow before moving its due date", func() {
+			dueAt := time.Date(2024, 4, 20, 0, 0, 0, 0, time.UTC)
+			now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+			db := dryRunDB(&statements)
+			dryRunRow(db, entity.BorrowHistory{ID: 7, BookID: 2, UserID: 3, DueAt: &dueAt, Status: constant.BorrowStatusOverdue})
+			r = repository.NewPostgresRepositoryWithDB(db)
+
+			renewedDueAt, err := r.RenewBook(7, now, 14*24*time.Hour, 2, 5)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(renewedDueAt).To(Equal(now.Add(14 * 24 * time.Hour)))
+
+			Expect(statements).To(HaveLen(6))
+			Expect(statements[0]).To(Equal(`SELECT * FROM "borrow_histories" WHERE "borrow_histories"."id" = 7 ORDER BY "borrow_histories"."id" LIMIT 1 FOR UPDATE`))
+			Expect(statements[1]).To(Equal(`SELECT "id" FROM "users" WHERE "users"."id" = 3 ORDER BY "users"."id" LIMIT 1 FOR UPDATE`))
+			Expect(statements[2]).To(Equal(`SELECT "id" FROM "books" WHERE "books"."id" = 2 ORDER BY "books"."id" LIMIT 1 FOR UPDATE`))
+			Expect(statements[3]).To(Equal(`SELECT count(*) FROM "holds" WHERE book_id = 2 AND status = 'WAITING'`))
+			// The days late so far are charged against the old due date, counting only what was charged since it
+			Expect(statements[4]).To(HavePrefix("\nINSERT INTO fee_transactions"))
+			Expect(statements[4]).To(ContainSubstring("'LATE_FEE', late.total - late.charged, 'late fee', '2024-05-01 00:00:00'"))
+			Expect(statements[4]).To(ContainSubstring("AND fee_transactions.created_at > borrow_histories.due_at"))
+			Expect(statements[4]).To(ContainSubstring("AND (CAST(7 AS bigint) IS NULL OR borrow_histories.id = 7)"))
+			Expect(statements[5]).To(Equal(`UPDATE "borrow_histories" SET "due_at"='2024-05-15 00:00:00',"renewal_count"=renewal_count + 1,` +
+				`"status"='BORROWED',"updated_at"='2024-05-01 00:00:00' WHERE id = 7`))
+		})
+
+		It("should refuse a borrow renewed as many times as allowed without charging it", func() {
+			dueAt := time.Date(2024, 4, 20, 0, 0, 0, 0, time.UTC)
+			db := dryRunDB(&statements)
+			dryRunRow(db, entity.BorrowHistory{ID: 7, BookID: 2, UserID: 3, DueAt: &dueAt, RenewalCount: 2, Status: constant.BorrowStatusOverdue})
+			r = repository.NewPostgresRepositoryWithDB(db)
+
+			_, err := r.RenewBook(7, dueAt.Add(24*time.Hour), 14*24*time.Hour, 2, 5)
+			Expect(err).To(Equal(errmap.ErrmapRenewalLimit))
+			Expect(statements).To(HaveLen(1))
+		})
+
+		It("should refuse a borrow whose book is waited for once the book is locked", func() {
+			dueAt := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+			db := dryRunDB(&statements)
+			dryRunRow(db, entity.BorrowHistory{ID: 7, BookID: 2, UserID: 3, DueAt: &dueAt, Status: constant.BorrowStatusBorrowed})
+			dryRunRow(db, int64(1))
+			r = repository.NewPostgresRepositoryWithDB(db)
+
+			_, err := r.RenewBook(7, dueAt.Add(-24*time.Hour), 14*24*time.Hour, 2, 5)
+			Expect(err).To(Equal(errmap.ErrmapHoldPending))
+			Expect(statements).To(HaveLen(4))
+			Expect(statements[2]).To(HaveSuffix("FOR UPDATE"))
+			Expect(statements[3]).To(HavePrefix(`SELECT count(*) FROM "holds"`))
+		})
+
+		It("should extend a borrow that is not due yet from its due date", func() {
+			dueAt := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+			db := dryRunDB(&statements)
+			dryRunRow(db, entity.BorrowHistory{ID: 7, BookID: 2, UserID: 3, DueAt: &dueAt, Status: constant.BorrowStatusBorrowed})
+			r = repository.NewPostgresRepositoryWithDB(db)
+
+			renewedDueAt, err := r.RenewBook(7, dueAt.Add(-24*time.Hour), 14*24*time.Hour, 2, 5)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(renewedDueAt).To(Equal(dueAt.Add(14 * 24 * time.Hour)))
+		})
+	})
+
+	Context("ListOverdueBorrowHistories", func() {
+		It("should page by number in due order", func() {
+			now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+			userID := uint(3)
+			_, next, err := r.ListOverdueBorrowHistories(entity.ListOverdueBorrowRequest{Page: 2, Size: 20, UserID: &userID}, now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(next).To(BeNil())
+
+			Expect(statements).To(Equal([]string{`SELECT * FROM "borrow_histories" WHERE (returned_at IS NULL AND due_at < '2024-05-01 00:00:00') ` +
+				`AND status IN ('BORROWED','OVERDUE') AND user_id = 3 ORDER BY due_at ASC, id ASC LIMIT 20 OFFSET 20`}))
+		})
+	})
+
+	Context("ListBorrowHistoriesByUserID", func() {
+		It("should continue after the last borrow of the page before, latest first", func() {
+			borrowedAt := time.Date(2024, 4, 2, 10, 0, 0, 0, time.UTC)
+			cursor, err := repository.EncodeUserBorrowCursor(borrowedAt, int64(15))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, err = r.ListBorrowHistoriesByUserID(entity.ListUserBorrowHistoryRequest{Size: 5, Cursor: &cursor, UserID: 3})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(statements).To(HaveLen(1))
+			Expect(statements[0]).To(HavePrefix(`SELECT borrow_histories.id, `))
+			Expect(statements[0]).To(HaveSuffix(`LEFT JOIN books ON books.id = borrow_histories.book_id WHERE borrow_histories.user_id = 3 ` +
+				`AND ((borrow_histories.borrowed_at < '2024-04-02 10:00:00') OR (borrow_histories.borrowed_at = '2024-04-02 10:00:00' AND borrow_histories.id < 15)) ` +
+				`ORDER BY borrow_histories.borrowed_at DESC, borrow_histories.id DESC LIMIT 6`))
+		})
+	})
+
+	Context("CountBorrowHistoriesByUserID", func() {
+		It("should count the borrows matching the filters", func() {
+			_, err := r.CountBorrowHistoriesByUserID(entity.ListUserBorrowHistoryRequest{UserID: 3, Status: "RETURNED"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(statements).To(Equal([]string{`SELECT count(*) FROM "borrow_histories" WHERE borrow_histories.user_id = 3 AND borrow_histories.status = 'RETURNED'`}))
+		})
+	})
+})
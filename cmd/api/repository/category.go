@@ -0,0 +1,328 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"go-library-service/cmd/api/entity"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// categorySubtree selects the ids of a category and every category below it
+const categorySubtree = "WITH RECURSIVE subtree AS (SELECT id FROM categories WHERE id = ? " +
+	"UNION ALL SELECT categories.id FROM categories JOIN subtree ON categories.parent_id = subtree.id) SELECT id FROM subtree"
+
+// CreateCategory creates a category under its parent, unless the parent already has one of the same name
+func (r *PostgresRepository) CreateCategory(category *entity.Category) (*entity.CategoryResponse, error) {
+	if category.ParentID != nil {
+		if err := r.postgres.Table("categories").Select("id").First(&entity.Category{}, *category.ParentID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errmap.ErrmapUnknownCategory
+			}
+			return nil, errors.Wrap(err, "[PostgresRepository.CreateCategory]: unable to get parent category")
+		}
+	}
+
+	var count int64
+	if err := siblingCategories(r.postgres, category.ParentID, category.Name).Count(&count).Error; err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.CreateCategory]: unable to check name")
+	}
+
+	if count > 0 {
+		return nil, errmap.ErrmapConflict
+	}
+
+	if err := r.postgres.Table("categories").Create(category).Error; err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.CreateCategory]: unable to create category")
+	}
+
+	return &entity.CategoryResponse{
+		ID:        category.ID,
+		Name:      category.Name,
+		Code:      category.Code,
+		ParentID:  category.ParentID,
+		CreatedAt: category.CreatedAt,
+		UpdatedAt: category.UpdatedAt,
+	}, nil
+}
+
+// ListCategories lists every category by name, parents and children alike
+func (r *PostgresRepository) ListCategories() ([]entity.CategoryResponse, error) {
+	var categories []entity.CategoryResponse
+	if err := r.postgres.Table("categories").Order("name ASC, id ASC").Find(&categories).Error; err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.ListCategories]: unable to get categories")
+	}
+	return categories, nil
+}
+
+// GetCategoriesByIDs retrieves the categories with the given IDs, leaving out unknown ones
+func (r *PostgresRepository) GetCategoriesByIDs(categoryIDs []uint) ([]entity.Category, error) {
+	var categories []entity.Category
+	if err := r.postgres.Table("categories").Where("id IN ?", categoryIDs).Find(&categories).Error; err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.GetCategoriesByIDs]: unable to get categories")
+	}
+	return categories, nil
+}
+
+// UpdateCategory renames a category or moves it under another parent, never under itself. The books filed
+// under it or its subcategories count as changed.
+func (r *PostgresRepository) UpdateCategory(category entity.Category, updatedAt time.Time) error {
+	tx := r.postgres.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return errors.Wrap(tx.Error, "[PostgresRepository.UpdateCategory]: unable to begin transaction")
+	}
+
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Table("categories").First(&entity.Category{}, category.ID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errmap.ErrmapNotFound
+		}
+		return errors.Wrap(err, "[PostgresRepository.UpdateCategory]: unable to get category")
+	}
+
+	if category.ParentID != nil {
+		if err := tx.Table("categories").Select("id").First(&entity.Category{}, *category.ParentID).Error; err != nil {
+			tx.Rollback()
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errmap.ErrmapUnknownCategory
+			}
+			return errors.Wrap(err, "[PostgresRepository.UpdateCategory]: unable to get parent category")
+		}
+
+		var cycles int64
+		if err := tx.Table("categories").
+			Where("id = ? AND id IN ("+categorySubtree+")", *category.ParentID, category.ID).
+			Count(&cycles).Error; err != nil {
+			tx.Rollback()
+			return errors.Wrap(err, "[PostgresRepository.UpdateCategory]: unable to check parent category")
+		}
+
+		if cycles > 0 {
+			tx.Rollback()
+			return errmap.ErrmapCategoryCycle
+		}
+	}
+
+	var count int64
+	if err := siblingCategories(tx, category.ParentID, category.Name).Where("id <> ?", category.ID).Count(&count).Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.UpdateCategory]: unable to check name")
+	}
+
+	if count > 0 {
+		tx.Rollback()
+		return errmap.ErrmapConflict
+	}
+
+	if err := tx.Table("categories").
+		Where("id = ?", category.ID).
+		Updates(map[string]interface{}{
+			"name":       category.Name,
+			"code":       category.Code,
+			"parent_id":  category.ParentID,
+			"updated_at": updatedAt,
+		}).Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.UpdateCategory]: unable to update category")
+	}
+
+	if err := tx.Table("books").
+		Where("id IN (SELECT book_id FROM book_categories WHERE category_id IN ("+categorySubtree+"))", category.ID).
+		Update("updated_at", updatedAt).Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.UpdateCategory]: unable to touch books")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.UpdateCategory]: unable to commit transaction")
+	}
+
+	return nil
+}
+
+// DeleteCategory deletes a category without subcategories or books filed under it
+func (r *PostgresRepository) DeleteCategory(categoryID uint) error {
+	tx := r.postgres.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return errors.Wrap(tx.Error, "[PostgresRepository.DeleteCategory]: unable to begin transaction")
+	}
+
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Table("categories").First(&entity.Category{}, categoryID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errmap.ErrmapNotFound
+		}
+		return errors.Wrap(err, "[PostgresRepository.DeleteCategory]: unable to get category")
+	}
+
+	var children, books int64
+	if err := tx.Table("categories").Where("parent_id = ?", categoryID).Count(&children).Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.DeleteCategory]: unable to count subcategories")
+	}
+
+	if err := tx.Table("book_categories").Where("category_id = ?", categoryID).Count(&books).Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.DeleteCategory]: unable to count books")
+	}
+
+	if children > 0 || books > 0 {
+		tx.Rollback()
+		return errmap.ErrmapConflict
+	}
+
+	if err := tx.Table("categories").Where("id = ?", categoryID).Delete(&entity.Category{}).Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.DeleteCategory]: unable to delete category")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.DeleteCategory]: unable to commit transaction")
+	}
+
+	return nil
+}
+
+// siblingCategories selects the categories under a parent with a name, ignoring case
+func siblingCategories(db *gorm.DB, parentID *uint, name string) *gorm.DB {
+	query := db.Table("categories").Where("LOWER(name) = LOWER(?)", name)
+	if parentID == nil {
+		return query.Where("parent_id IS NULL")
+	}
+	return query.Where("parent_id = ?", *parentID)
+}
+
+// classifyBook files a book under categories and tags it with subjects. Either is left as it is when nil,
+// and the book is left for the caller to reindex.
+func classifyBook(tx *gorm.DB, bookID uint, categoryIDs []uint, subjects []string) error {
+	if categoryIDs != nil {
+		if err := tx.Table("book_categories").Where("book_id = ?", bookID).Delete(&entity.BookCategory{}).Error; err != nil {
+			return errors.Wrap(err, "[classifyBook]: unable to clear categories")
+		}
+
+		filed := map[uint]bool{}
+		for _, categoryID := range categoryIDs {
+			if filed[categoryID] {
+				continue
+			}
+			filed[categoryID] = true
+
+			link := entity.BookCategory{BookID: bookID, CategoryID: categoryID}
+			if err := tx.Table("book_categories").Create(&link).Error; err != nil {
+				return errors.Wrap(err, "[classifyBook]: unable to file book under category")
+			}
+		}
+	}
+
+	if subjects != nil {
+		if err := tx.Table("book_subjects").Where("book_id = ?", bookID).Delete(&entity.BookSubject{}).Error; err != nil {
+			return errors.Wrap(err, "[classifyBook]: unable to clear subjects")
+		}
+
+		for _, subject := range subjects {
+			tag := entity.BookSubject{BookID: bookID, Name: subject}
+			if err := tx.Table("book_subjects").Create(&tag).Error; err != nil {
+				return errors.Wrap(err, "[classifyBook]: unable to tag subject")
+			}
+		}
+	}
+
+	return nil
+}
+
+// classifiedBooks narrows a book query to a category, with its subcategories, and to a subject
+func classifiedBooks(query *gorm.DB, categoryID *uint, subject *string) *gorm.DB {
+	if categoryID != nil {
+		query = query.Where("EXISTS (SELECT 1 FROM book_categories WHERE book_categories.book_id = books.id "+
+			"AND book_categories.category_id IN ("+categorySubtree+"))", *categoryID)
+	}
+
+	if subject != nil && strings.TrimSpace(*subject) != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM book_subjects WHERE book_subjects.book_id = books.id "+
+			"AND LOWER(book_subjects.name) = LOWER(?))", strings.TrimSpace(*subject))
+	}
+
+	return query
+}
+
+// bookSubjects reads the subjects of books by book
+func bookSubjects(db *gorm.DB, bookIDs []uint) (map[uint][]string, error) {
+	var tags []entity.BookSubject
+	if err := db.Table("book_subjects").Where("book_id IN ?", bookIDs).Order("book_id ASC, name ASC").Find(&tags).Error; err != nil {
+		return nil, errors.Wrap(err, "[bookSubjects]: unable to get subjects")
+	}
+
+	subjects := map[uint][]string{}
+	for _, tag := range tags {
+		subjects[tag.BookID] = append(subjects[tag.BookID], tag.Name)
+	}
+	return subjects, nil
+}
+
+// attachBookClassification fills in the categories and subjects of each book
+func attachBookClassification(db *gorm.DB, books []entity.BookResponse) error {
+	if len(books) == 0 {
+		return nil
+	}
+
+	bookIDs := make([]uint, len(books))
+	for i, book := range books {
+		bookIDs[i] = book.ID
+	}
+
+	var filings []struct {
+		BookID uint
+		ID     uint
+		Name   string
+		Code   string
+	}
+	err := db.Table("book_categories").
+		Select("book_categories.book_id, categories.id, categories.name, categories.code").
+		Joins("JOIN categories ON categories.id = book_categories.category_id").
+		Where("book_categories.book_id IN ?", bookIDs).
+		Order("book_categories.book_id ASC, categories.name ASC").
+		Find(&filings).Error
+	if err != nil {
+		return errors.Wrap(err, "[attachBookClassification]: unable to get categories")
+	}
+
+	categories := map[uint][]entity.BookCategoryResponse{}
+	for _, filing := range filings {
+		categories[filing.BookID] = append(categories[filing.BookID], entity.BookCategoryResponse{ID: filing.ID, Name: filing.Name, Code: filing.Code})
+	}
+
+	subjects, err := bookSubjects(db, bookIDs)
+	if err != nil {
+		return errors.Wrap(err, "[attachBookClassification]: unable to get subjects")
+	}
+
+	for i := range books {
+		books[i].Categories = categories[books[i].ID]
+		if books[i].Categories == nil {
+			books[i].Categories = []entity.BookCategoryResponse{}
+		}
+		books[i].Subjects = subjects[books[i].ID]
+		if books[i].Subjects == nil {
+			books[i].Subjects = []string{}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package repository_test
+
+import (
+	"go-library-service/cmd/api/entity"
+	"go-library-service/cmd/api/repository"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Category Repository", func() {
+	var (
+		statements []string
+		r          *repository.PostgresRepository
+	)
+
+	BeforeEach(func() {
+		statements = nil
+		r = repository.NewPostgresRepositoryWithDB(dryRunDB(&statements))
+	})
+
+	Context("ListCategories", func() {
+		It("should list every category by name", func() {
+			_, err := r.ListCategories()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(statements).To(Equal([]string{`SELECT * FROM "categories" ORDER BY name ASC, id ASC`}))
+		})
+	})
+
+	Context("ListLatestBooks", func() {
+		It("should narrow the latest books to a category tree and a subject", func() {
+			categoryID := uint(4)
+			subject := " Ecology "
+			_, err := r.ListLatestBooks(entity.ListLatestBookRequest{CategoryID: &categoryID, Subject: &subject})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(statements).To(Equal([]string{`SELECT * FROM "books" WHERE deleted_at IS NULL ` +
+				`AND (EXISTS (SELECT 1 FROM book_categories WHERE book_categories.book_id = books.id AND book_categories.category_id IN (` +
+				`WITH RECURSIVE subtree AS (SELECT id FROM categories WHERE id = 4 UNION ALL SELECT categories.id FROM categories JOIN subtree ON categories.parent_id = subtree.id) SELECT id FROM subtree))) ` +
+				`AND (EXISTS (SELECT 1 FROM book_subjects WHERE book_subjects.book_id = books.id AND LOWER(book_subjects.name) = LOWER('Ecology'))) ` +
+				`ORDER BY created_at DESC LIMIT 5`}))
+		})
+	})
+})
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"time"
+
+	"go-library-service/cmd/api/entity"
+
+	"gorm.io/gorm"
+)
+
+// NewPostgresRepositoryWithDB wraps a prepared connection, such as a dry run session in tests
+func NewPostgresRepositoryWithDB(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{postgres: db}
+}
+
+// EncodeBookCursor makes the cursor of a book listing that continues after a row with the given sort keys
+func EncodeBookCursor(req entity.ListBookRequest, keys ...interface{}) (string, error) {
+	_, rank := bookSearch(req)
+	return encodeCursor(bookOrder(req.Sort, rank), keys)
+}
+
+// EncodeUserBorrowCursor makes the cursor of a user's borrow history that continues after a row with the given sort keys
+func EncodeUserBorrowCursor(keys ...interface{}) (string, error) {
+	return encodeCursor(userBorrowOrder, keys)
+}
+
+// ReplacementOwed is what is still owed on the replacement of a borrow with the given ledger sums
+func ReplacementOwed(replacement, other, credited, balance float64) float64 {
+	return replacementOwed(borrowLedger{Replacement: replacement, Other: other, Credited: credited}, balance)
+}
+
+// BookSearchDocument analyzes a book for the search index
+func BookSearchDocument(book entity.Book, subjects []string) string {
+	return bookSearchDocument(book, subjects)
+}
+
+// EncodeBookChangeCursor makes the cursor of a listing of changed books that continues after a book changed at updatedAt
+func EncodeBookChangeCursor(updatedAt time.Time, bookID int64) (string, error) {
+	return encodeCursor(bookChangeOrder, []interface{}{updatedAt, bookID})
+}
@@ -0,0 +1,290 @@
+package repository
+
+import (
+	"math"
+	"time"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// accrueLateFeesQuery charges each late borrow the difference between its late fee so far and what it was already charged
+// since its due date, a lost book stops accruing when it is marked lost. A renewal charges the late fee of the borrow
+// before moving its due date past that charge, so lateness after the renewal is charged from zero again.
+const accrueLateFeesQuery = `
+INSERT INTO fee_transactions (user_id, borrow_history_id, type, amount, note, created_at)
+SELECT late.user_id, late.id, @fee_type, late.total - late.charged, 'late fee', @now
+FROM (
+	SELECT borrow_histories.id, borrow_histories.user_id,
+		ROUND(CAST(CEIL(EXTRACT(EPOCH FROM (COALESCE(borrow_histories.lost_at, borrow_histories.returned_at, @now) - borrow_histories.due_at)) / 86400) AS numeric) * COALESCE(borrow_histories.fine_rate, CAST(@rate AS numeric)), 2) AS total,
+		COALESCE((
+			SELECT SUM(fee_transactions.amount) FROM fee_transactions
+			WHERE fee_transactions.borrow_history_id = borrow_histories.id AND fee_transactions.type = @fee_type
+				AND fee_transactions.created_at > borrow_histories.due_at
+		), 0) AS charged
+	FROM borrow_histories
+	WHERE borrow_histories.due_at < COALESCE(borrow_histories.lost_at, borrow_histories.returned_at, @now)
+		AND (CAST(@user_id AS bigint) IS NULL OR borrow_histories.user_id = @user_id)
+		AND (CAST(@history_id AS bigint) IS NULL OR borrow_histories.id = @history_id)
+) AS late
+WHERE late.total > late.charged`
+
+// lockLateBorrowersQuery locks the ledgers of the users accrueLateFeesQuery charges, in a fixed order so
+// concurrent accruals wait for each other instead of charging the same difference twice
+const lockLateBorrowersQuery = `
+SELECT users.id FROM users
+WHERE users.id IN (
+	SELECT borrow_histories.user_id FROM borrow_histories
+	WHERE borrow_histories.due_at < COALESCE(borrow_histories.lost_at, borrow_histories.returned_at, @now)
+		AND (CAST(@user_id AS bigint) IS NULL OR borrow_histories.user_id = @user_id)
+)
+ORDER BY users.id
+FOR UPDATE`
+
+// ChargeReplacementCost charges the borrower of a lost or damaged book its replacement cost, once per borrow
+func (r *PostgresRepository) ChargeReplacementCost(charge *entity.FeeTransaction) error {
+	tx := r.postgres.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	var history entity.BorrowHistory
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Table("borrow_histories").First(&history, *charge.BorrowHistoryID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errmap.ErrmapNotFound
+		}
+		return errors.Wrap(err, "[PostgresRepository.ChargeReplacementCost]: unable to get borrow history")
+	}
+
+	if history.Status != constant.BorrowStatusLost && history.Status != constant.BorrowStatusDamaged {
+		tx.Rollback()
+		return errmap.ErrmapBorrowNotLost
+	}
+
+	if err := createReplacementCharge(tx, charge); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.ChargeReplacementCost]: unable to commit transaction")
+	}
+
+	return nil
+}
+
+// createReplacementCharge appends the replacement charge of a borrow to the ledger of its borrower, which it
+// locks so the borrow cannot be charged twice
+func createReplacementCharge(tx *gorm.DB, charge *entity.FeeTransaction) error {
+	if err := lockUser(tx, charge.UserID); err != nil {
+		return err
+	}
+
+	var count int64
+	if err := tx.Table("fee_transactions").
+		Where("borrow_history_id = ? AND type = ?", *charge.BorrowHistoryID, constant.FeeTypeReplacement).
+		Count(&count).Error; err != nil {
+		return errors.Wrap(err, "[createReplacementCharge]: unable to count replacement charges")
+	}
+
+	if count > 0 {
+		return errmap.ErrmapConflict
+	}
+
+	if err := tx.Table("fee_transactions").Create(charge).Error; err != nil {
+		return errors.Wrap(err, "[createReplacementCharge]: unable to create replacement charge")
+	}
+	return nil
+}
+
+// borrowLedger sums the fee transactions of a borrow
+type borrowLedger struct {
+	Replacement float64 // replacement charges
+	Other       float64 // late fees and other charges
+	Credited    float64 // payments and waivers, as a positive amount
+}
+
+// replacementOwed is what is still owed on the replacement of a borrow. Credits of the borrow settle its other
+// charges first, and no more than the balance of the borrower is owed, so unlinked payments count too.
+func replacementOwed(ledger borrowLedger, balance float64) float64 {
+	owed := ledger.Replacement - math.Max(ledger.Credited-ledger.Other, 0)
+	owed = math.Min(owed, balance)
+	if owed <= 0 {
+		return 0
+	}
+	return math.Round(owed*100) / 100
+}
+
+// waiveReplacement waives what is still owed on the replacement of a borrow, the ledger of the borrower must be locked
+func waiveReplacement(tx *gorm.DB, waiver *entity.FeeTransaction) error {
+	var ledger borrowLedger
+	if err := tx.Table("fee_transactions").
+		Select(`COALESCE(SUM(amount) FILTER (WHERE type = ?), 0) AS replacement,
+			COALESCE(SUM(amount) FILTER (WHERE type <> ? AND amount > 0), 0) AS other,
+			COALESCE(-SUM(amount) FILTER (WHERE amount < 0), 0) AS credited`,
+			constant.FeeTypeReplacement, constant.FeeTypeReplacement).
+		Where("borrow_history_id = ?", *waiver.BorrowHistoryID).
+		Scan(&ledger).Error; err != nil {
+		return errors.Wrap(err, "[waiveReplacement]: unable to get borrow ledger")
+	}
+
+	if ledger.Replacement <= 0 {
+		return nil
+	}
+
+	var balance float64
+	if err := tx.Table("fee_transactions").
+		Select("COALESCE(SUM(amount), 0)").
+		Where("user_id = ?", waiver.UserID).
+		Scan(&balance).Error; err != nil {
+		return errors.Wrap(err, "[waiveReplacement]: unable to get balance")
+	}
+
+	owed := replacementOwed(ledger, balance)
+	if owed <= 0 {
+		return nil
+	}
+
+	waiver.Amount = -owed
+	if err := tx.Table("fee_transactions").Create(waiver).Error; err != nil {
+		return errors.Wrap(err, "[waiveReplacement]: unable to create waiver")
+	}
+	return nil
+}
+
+// CreateFeeCredit appends a payment or waiver to the fee ledger without letting the balance go below zero
+func (r *PostgresRepository) CreateFeeCredit(transaction *entity.FeeTransaction) error {
+	tx := r.postgres.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := lockUser(tx, transaction.UserID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var balance float64
+	if err := tx.Table("fee_transactions").
+		Select("COALESCE(SUM(amount), 0)").
+		Where("user_id = ?", transaction.UserID).
+		Scan(&balance).Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.CreateFeeCredit]: unable to get balance")
+	}
+
+	if balance+transaction.Amount < 0 {
+		tx.Rollback()
+		return errmap.ErrmapInvalidAmount
+	}
+
+	if err := tx.Table("fee_transactions").Create(transaction).Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.CreateFeeCredit]: unable to create fee credit")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.CreateFeeCredit]: unable to commit transaction")
+	}
+
+	return nil
+}
+
+// GetFeeBalance sums the fee ledger of a user
+func (r *PostgresRepository) GetFeeBalance(userID uint) (float64, error) {
+	var balance float64
+	err := r.postgres.Table("fee_transactions").
+		Select("COALESCE(SUM(amount), 0)").
+		Where("user_id = ?", userID).
+		Scan(&balance).Error
+	if err != nil {
+		return 0, errors.Wrap(err, "[PostgresRepository.GetFeeBalance]: unable to get balance")
+	}
+	return balance, nil
+}
+
+// ListFeeTransactionsByUserID lists the fee ledger of a user
+func (r *PostgresRepository) ListFeeTransactionsByUserID(userID uint) ([]entity.FeeTransactionResponse, error) {
+	var transactions []entity.FeeTransactionResponse
+	err := r.postgres.Table("fee_transactions").
+		Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Find(&transactions).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.ListFeeTransactionsByUserID]: unable to get fee transactions")
+	}
+	return transactions, nil
+}
+
+// AccrueLateFees charges late fees accrued up to now, for one user or everyone when userID is nil.
+// Borrows without a fine rate of their own are charged ratePerDay.
+func (r *PostgresRepository) AccrueLateFees(now time.Time, ratePerDay float64, userID *uint) (int64, error) {
+	tx := r.postgres.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return 0, tx.Error
+	}
+
+	// What was already charged is read after the lock, so a concurrent accrual's charges are seen
+	if err := tx.Exec(lockLateBorrowersQuery, map[string]interface{}{
+		"now":     now,
+		"user_id": userID,
+	}).Error; err != nil {
+		tx.Rollback()
+		return 0, errors.Wrap(err, "[PostgresRepository.AccrueLateFees]: unable to lock ledgers")
+	}
+
+	charged, err := accrueLateFees(tx, now, ratePerDay, userID, nil)
+	if err != nil {
+		tx.Rollback()
+		return 0, errors.Wrap(err, "[PostgresRepository.AccrueLateFees]: unable to accrue late fees")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return 0, errors.Wrap(err, "[PostgresRepository.AccrueLateFees]: unable to commit transaction")
+	}
+
+	return charged, nil
+}
+
+// accrueLateFees charges the late fees accrued up to now, for one user, one borrow or everyone when both are nil.
+// The ledgers it charges must be locked.
+func accrueLateFees(tx *gorm.DB, now time.Time, ratePerDay float64, userID, historyID *uint) (int64, error) {
+	result := tx.Exec(accrueLateFeesQuery, map[string]interface{}{
+		"fee_type":   constant.FeeTypeLateFee,
+		"now":        now,
+		"rate":       ratePerDay,
+		"user_id":    userID,
+		"history_id": historyID,
+	})
+	if result.Error != nil {
+		return 0, errors.Wrap(result.Error, "[accrueLateFees]: unable to accrue late fees")
+	}
+	return result.RowsAffected, nil
+}
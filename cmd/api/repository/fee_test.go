@@ -0,0 +1,25 @@
+package repository_test
+
+import (
+	"go-library-service/cmd/api/repository"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Fee Repository", func() {
+	Context("replacementOwed", func() {
+		DescribeTable("should owe what is left of the replacement",
+			func(replacement, other, credited, balance, owed float64) {
+				Expect(repository.ReplacementOwed(replacement, other, credited, balance)).To(Equal(owed))
+			},
+			Entry("nothing paid", 250.0, 0.0, 0.0, 250.0, 250.0),
+			Entry("late fees of other borrows are left alone", 250.0, 0.0, 0.0, 400.0, 250.0),
+			Entry("credits settle the late fee of the borrow first", 250.0, 30.0, 30.0, 250.0, 250.0),
+			Entry("credits beyond the late fee settle the replacement", 250.0, 30.0, 130.0, 150.0, 150.0),
+			Entry("fully paid", 250.0, 0.0, 250.0, 0.0, 0.0),
+			Entry("paid without linking the payment", 250.0, 0.0, 0.0, 40.0, 40.0),
+			Entry("nothing charged", 0.0, 10.0, 0.0, 10.0, 0.0),
+		)
+	})
+})
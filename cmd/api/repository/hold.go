@@ -0,0 +1,281 @@
+package repository
+
+import (
+	"time"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var activeHoldStatuses = []string{constant.HoldStatusWaiting, constant.HoldStatusReady}
+
+// holdResponseColumns selects a hold with its book title and its position in the book queue
+const holdResponseColumns = `holds.*, books.title AS book_title,
+	CASE WHEN holds.status = 'WAITING' THEN (
+		SELECT COUNT(*) FROM holds AS queue
+		WHERE queue.book_id = holds.book_id AND queue.status = 'WAITING' AND queue.id <= holds.id
+	) ELSE 0 END AS queue_position`
+
+// CreateHold places a hold at the end of the book queue
+func (r *PostgresRepository) CreateHold(hold *entity.Hold) (*entity.HoldResponse, error) {
+	tx := r.postgres.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	var book entity.Book
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Table("books").Where("deleted_at IS NULL").First(&book, hold.BookID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmap.ErrmapNotFound
+		}
+		return nil, errors.Wrap(err, "[PostgresRepository.CreateHold]: unable to get book")
+	}
+
+	if book.Stock > 0 {
+		tx.Rollback()
+		return nil, errmap.ErrmapBookAvailable
+	}
+
+	var activeHolds int64
+	if err := tx.Table("holds").
+		Where("book_id = ? AND user_id = ? AND status IN ?", hold.BookID, hold.UserID, activeHoldStatuses).
+		Count(&activeHolds).Error; err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "[PostgresRepository.CreateHold]: unable to check existing hold")
+	}
+
+	if activeHolds > 0 {
+		tx.Rollback()
+		return nil, errmap.ErrmapConflict
+	}
+
+	if err := tx.Table("holds").Create(hold).Error; err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "[PostgresRepository.CreateHold]: unable to create hold")
+	}
+
+	var response entity.HoldResponse
+	if err := tx.Table("holds").
+		Select(holdResponseColumns).
+		Joins("JOIN books ON books.id = holds.book_id").
+		Where("holds.id = ?", hold.ID).
+		Take(&response).Error; err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "[PostgresRepository.CreateHold]: unable to get hold")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "[PostgresRepository.CreateHold]: unable to commit transaction")
+	}
+
+	return &response, nil
+}
+
+// GetHoldByID retrieves a hold by ID
+func (r *PostgresRepository) GetHoldByID(holdID uint) (*entity.Hold, error) {
+	var hold entity.Hold
+	err := r.postgres.Table("holds").First(&hold, holdID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmap.ErrmapNotFound
+		}
+		return nil, errors.Wrap(err, "[PostgresRepository.GetHoldByID]: unable to get hold")
+	}
+	return &hold, nil
+}
+
+// GetReadyHold retrieves the hold of a user that has a copy waiting for pickup
+func (r *PostgresRepository) GetReadyHold(bookID, userID uint) (*entity.Hold, error) {
+	var hold entity.Hold
+	err := r.postgres.Table("holds").
+		Where("book_id = ? AND user_id = ? AND status = ? AND expires_at > ?", bookID, userID, constant.HoldStatusReady, time.Now()).
+		First(&hold).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmap.ErrmapNotFound
+		}
+		return nil, errors.Wrap(err, "[PostgresRepository.GetReadyHold]: unable to get hold")
+	}
+	return &hold, nil
+}
+
+// ListHoldsByUserID lists active holds of a user with their queue position
+func (r *PostgresRepository) ListHoldsByUserID(userID uint) ([]entity.HoldResponse, error) {
+	var holds []entity.HoldResponse
+	err := r.postgres.Table("holds").
+		Select(holdResponseColumns).
+		Joins("JOIN books ON books.id = holds.book_id").
+		Where("holds.user_id = ? AND holds.status IN ?", userID, activeHoldStatuses).
+		Order("holds.created_at ASC").
+		Find(&holds).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.ListHoldsByUserID]: unable to get holds")
+	}
+	return holds, nil
+}
+
+// CancelHold cancels a hold and passes its reserved copy on when it was ready
+func (r *PostgresRepository) CancelHold(holdID uint, now, pickupExpiresAt time.Time) error {
+	tx := r.postgres.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	var hold entity.Hold
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Table("holds").First(&hold, holdID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errmap.ErrmapNotFound
+		}
+		return errors.Wrap(err, "[PostgresRepository.CancelHold]: unable to get hold")
+	}
+
+	if hold.Status != constant.HoldStatusWaiting && hold.Status != constant.HoldStatusReady {
+		tx.Rollback()
+		return errmap.ErrmapConflict
+	}
+
+	if err := tx.Table("holds").
+		Where("id = ?", holdID).
+		Updates(map[string]interface{}{
+			"status":     constant.HoldStatusCancelled,
+			"updated_at": now,
+		}).Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.CancelHold]: unable to update hold")
+	}
+
+	if hold.Status == constant.HoldStatusReady && hold.CopyID != nil {
+		bookCopy, err := lockCopy(tx, *hold.CopyID)
+		if err != nil {
+			tx.Rollback()
+			return errors.Wrap(err, "[PostgresRepository.CancelHold]: unable to get held copy")
+		}
+
+		if err := releaseCopy(tx, *bookCopy, now, pickupExpiresAt); err != nil {
+			tx.Rollback()
+			return errors.Wrap(err, "[PostgresRepository.CancelHold]: unable to release copy")
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "[PostgresRepository.CancelHold]: unable to commit transaction")
+	}
+
+	return nil
+}
+
+// ExpireReadyHolds expires holds that were not picked up in time and passes their copies on,
+// for one book or every book when bookID is nil
+func (r *PostgresRepository) ExpireReadyHolds(now, pickupExpiresAt time.Time, bookID *uint) (int64, error) {
+	tx := r.postgres.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if tx.Error != nil {
+		return 0, tx.Error
+	}
+
+	query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Table("holds").
+		Where("status = ? AND expires_at <= ?", constant.HoldStatusReady, now)
+	if bookID != nil {
+		query = query.Where("book_id = ?", *bookID)
+	}
+
+	var holds []entity.Hold
+	if err := query.Order("id ASC").Find(&holds).Error; err != nil {
+		tx.Rollback()
+		return 0, errors.Wrap(err, "[PostgresRepository.ExpireReadyHolds]: unable to get expired holds")
+	}
+
+	for _, hold := range holds {
+		if err := tx.Table("holds").
+			Where("id = ?", hold.ID).
+			Updates(map[string]interface{}{
+				"status":     constant.HoldStatusExpired,
+				"updated_at": now,
+			}).Error; err != nil {
+			tx.Rollback()
+			return 0, errors.Wrap(err, "[PostgresRepository.ExpireReadyHolds]: unable to update hold")
+		}
+
+		if hold.CopyID == nil {
+			continue
+		}
+
+		bookCopy, err := lockCopy(tx, *hold.CopyID)
+		if err != nil {
+			tx.Rollback()
+			return 0, errors.Wrap(err, "[PostgresRepository.ExpireReadyHolds]: unable to get held copy")
+		}
+
+		if err := releaseCopy(tx, *bookCopy, now, pickupExpiresAt); err != nil {
+			tx.Rollback()
+			return 0, errors.Wrap(err, "[PostgresRepository.ExpireReadyHolds]: unable to release copy")
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return 0, errors.Wrap(err, "[PostgresRepository.ExpireReadyHolds]: unable to commit transaction")
+	}
+
+	return int64(len(holds)), nil
+}
+
+// releaseCopy sets a copy aside for the next waiting hold or makes it available
+func releaseCopy(tx *gorm.DB, bookCopy entity.BookCopy, now, pickupExpiresAt time.Time) error {
+	var next entity.Hold
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Table("holds").
+		Where("book_id = ? AND status = ?", bookCopy.BookID, constant.HoldStatusWaiting).
+		Order("id ASC").
+		First(&next).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.Wrap(err, "[releaseCopy]: unable to get next hold")
+	}
+
+	status := constant.CopyStatusAvailable
+	if err == nil {
+		if err := tx.Table("holds").
+			Where("id = ?", next.ID).
+			Updates(map[string]interface{}{
+				"copy_id":    bookCopy.ID,
+				"status":     constant.HoldStatusReady,
+				"ready_at":   now,
+				"expires_at": pickupExpiresAt,
+				"updated_at": now,
+			}).Error; err != nil {
+			return errors.Wrap(err, "[releaseCopy]: unable to update hold")
+		}
+		status = constant.CopyStatusOnHold
+	}
+
+	if err := setCopyStatus(tx, bookCopy.ID, status); err != nil {
+		return errors.Wrap(err, "[releaseCopy]: unable to update book copy")
+	}
+
+	return syncBookStock(tx, bookCopy.BookID)
+}
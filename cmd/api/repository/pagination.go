@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// sortKey is one expression a listing is sorted by
+type sortKey struct {
+	SQL  string
+	Vars []interface{}
+	Desc bool
+}
+
+// keysetOrder is the sort of a listing. It ends with the id so every row has a place of its own,
+// which lets a page start right after the last row of the page before instead of counting rows off.
+type keysetOrder []sortKey
+
+// orderBy sorts by every key. It is one expression because a later column order would replace
+// an expression with vars, such as a search rank.
+func (o keysetOrder) orderBy() clause.OrderBy {
+	var parts []string
+	var vars []interface{}
+
+	for _, key := range o {
+		direction := "ASC"
+		if key.Desc {
+			direction = "DESC"
+		}
+		parts = append(parts, key.SQL+" "+direction)
+		vars = append(vars, key.Vars...)
+	}
+
+	return clause.OrderBy{Expression: clause.Expr{SQL: strings.Join(parts, ", "), Vars: vars}}
+}
+
+// after matches the rows sorted after the row with the given keys
+func (o keysetOrder) after(keys []interface{}) clause.Expr {
+	var branches []string
+	var vars []interface{}
+
+	for i, key := range o {
+		var conditions []string
+		for j, tied := range o[:i] {
+			conditions = append(conditions, tied.SQL+" = ?")
+			vars = append(vars, tied.Vars...)
+			vars = append(vars, keys[j])
+		}
+
+		operator := " > ?"
+		if key.Desc {
+			operator = " < ?"
+		}
+		conditions = append(conditions, key.SQL+operator)
+		vars = append(vars, key.Vars...)
+		vars = append(vars, keys[i])
+
+		branches = append(branches, "("+strings.Join(conditions, " AND ")+")")
+	}
+
+	// gorm puts a condition with OR in parentheses of its own
+	return clause.Expr{SQL: strings.Join(branches, " OR "), Vars: vars}
+}
+
+// fingerprint identifies the sort, so a cursor is not followed in a listing sorted another way
+func (o keysetOrder) fingerprint() string {
+	h := fnv.New32a()
+	for _, key := range o {
+		fmt.Fprintf(h, "%s %t %v;", key.SQL, key.Desc, key.Vars)
+	}
+	return strconv.FormatUint(uint64(h.Sum32()), 36)
+}
+
+// pageCursor is what a cursor carries: the sort it was made for and the keys of the last row of its page
+type pageCursor struct {
+	Order string      `json:"o"`
+	Keys  []cursorKey `json:"k"`
+}
+
+// cursorKey keeps times apart from other values so they are bound as times again
+type cursorKey struct {
+	Time  *time.Time  `json:"t,omitempty"`
+	Value interface{} `json:"v"`
+}
+
+// encodeCursor makes an opaque cursor from the keys of the last row of a page
+func encodeCursor(order keysetOrder, keys []interface{}) (string, error) {
+	cursor := pageCursor{Order: order.fingerprint(), Keys: make([]cursorKey, len(keys))}
+	for i, key := range keys {
+		switch value := key.(type) {
+		case time.Time:
+			cursor.Keys[i].Time = &value
+		case float32:
+			// A real printed as such would not compare equal once read back as a double
+			cursor.Keys[i].Value = float64(value)
+		default:
+			cursor.Keys[i].Value = value
+		}
+	}
+
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", errors.Wrap(err, "[encodeCursor]: unable to marshal cursor")
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor reads the keys a cursor continues after, none for the empty cursor of a first page
+func decodeCursor(order keysetOrder, value string) ([]interface{}, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errmap.ErrmapInvalidCursor
+	}
+
+	var cursor pageCursor
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&cursor); err != nil || cursor.Order != order.fingerprint() || len(cursor.Keys) != len(order) {
+		return nil, errmap.ErrmapInvalidCursor
+	}
+
+	keys := make([]interface{}, len(cursor.Keys))
+	for i, key := range cursor.Keys {
+		switch {
+		case key.Time != nil:
+			keys[i] = *key.Time
+		case key.Value != nil:
+			keys[i] = key.Value
+			// Integers stay integers so the comparison can still use an index
+			if number, ok := key.Value.(json.Number); ok {
+				if n, err := number.Int64(); err == nil {
+					keys[i] = n
+				} else if f, err := number.Float64(); err == nil {
+					keys[i] = f
+				}
+			}
+		}
+	}
+	return keys, nil
+}
+
+// paginate limits a listing to a page by number, or to the page after a cursor. A page after a cursor
+// reads one row more than its size to tell whether another page follows.
+func paginate(query *gorm.DB, order keysetOrder, page, size int, cursor *string) (*gorm.DB, error) {
+	query = query.Order(order.orderBy())
+	if cursor == nil {
+		return query.Offset((page - 1) * size).Limit(size), nil
+	}
+
+	keys, err := decodeCursor(order, *cursor)
+	if err != nil {
+		return nil, err
+	}
+	if keys != nil {
+		query = query.Where(order.after(keys))
+	}
+	return query.Limit(size + 1), nil
+}
+
+// nextCursor reads the sort keys of the last row of a page into the cursor of the page after it
+func (r *PostgresRepository) nextCursor(table string, order keysetOrder, id uint) (*string, error) {
+	columns := make([]string, len(order))
+	var vars []interface{}
+	for i, key := range order {
+		columns[i] = fmt.Sprintf("%s AS key_%d", key.SQL, i)
+		vars = append(vars, key.Vars...)
+	}
+
+	var rows []map[string]interface{}
+	err := r.postgres.Table(table).
+		Clauses(clause.Select{Expression: clause.Expr{SQL: strings.Join(columns, ", "), Vars: vars}}).
+		Where(table+".id = ?", id).
+		Find(&rows).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "[PostgresRepository.nextCursor]: unable to get sort keys")
+	}
+	if len(rows) == 0 {
+		return nil, errors.Wrap(errmap.ErrmapNotFound, "[PostgresRepository.nextCursor]: unable to get sort keys")
+	}
+
+	keys := make([]interface{}, len(order))
+	for i := range order {
+		keys[i] = rows[0][fmt.Sprintf("key_%d", i)]
+	}
+
+	cursor, err := encodeCursor(order, keys)
+	if err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
@@ -45,13 +45,40 @@ func NewPostgresRepository(config PostgresConfig) (*PostgresRepository, error){
 }
 
 func postgresqlMigration(db *gorm.DB) error {
+	// pg_trgm backs the fuzzy book search
+	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error; err != nil {
+		return errors.Wrap(err, "[postgresqlMigration]: unable to create pg_trgm extension")
+	}
+
 	err := db.AutoMigrate(
 		&entity.User{},
 		&entity.Book{},
+		&entity.Author{},
+		&entity.BookAuthor{},
+		&entity.Category{},
+		&entity.BookCategory{},
+		&entity.BookSubject{},
+		&entity.BookCopy{},
 		&entity.BorrowHistory{},
+		&entity.Hold{},
+		&entity.FeeTransaction{},
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	if err := backfillBookCopies(db); err != nil {
+		return err
+	}
+
+	if err := backfillBookAuthors(db); err != nil {
+		return err
+	}
+
+	if err := backfillBookSearch(db); err != nil {
+		return err
+	}
 
+	return createBookTrigramIndexes(db)
 }
 
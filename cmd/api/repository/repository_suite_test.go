@@ -0,0 +1,78 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestRepository(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Repository Suite")
+}
+
+// errDryRun is returned by anything that would reach a database in a dry run
+var errDryRun = errors.New("dry run")
+
+// dryRunPool stands in for a connection, so a dry run can begin, commit and roll back transactions
+type dryRunPool struct{}
+
+func (*dryRunPool) PrepareContext(context.Context, string) (*sql.Stmt, error) { return nil, errDryRun }
+
+func (*dryRunPool) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, errDryRun
+}
+
+func (*dryRunPool) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	return nil, errDryRun
+}
+
+func (*dryRunPool) QueryRowContext(context.Context, string, ...interface{}) *sql.Row { return nil }
+
+func (p *dryRunPool) BeginTx(context.Context, *sql.TxOptions) (gorm.ConnPool, error) { return p, nil }
+
+func (*dryRunPool) Commit() error { return nil }
+
+func (*dryRunPool) Rollback() error { return nil }
+
+// dryRunDB opens a session that builds statements without a database and records their SQL, writes included
+func dryRunDB(statements *[]string) *gorm.DB {
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: &dryRunPool{}}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               logger.Default.LogMode(logger.Silent),
+	})
+	Expect(err).NotTo(HaveOccurred())
+
+	record := func(tx *gorm.DB) {
+		*statements = append(*statements, tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...))
+	}
+	Expect(db.Callback().Query().After("gorm:query").Register("test:record", record)).To(Succeed())
+	Expect(db.Callback().Row().After("gorm:row").Register("test:record", record)).To(Succeed())
+	Expect(db.Callback().Raw().After("gorm:raw").Register("test:record", record)).To(Succeed())
+	Expect(db.Callback().Create().After("gorm:create").Register("test:record", record)).To(Succeed())
+	Expect(db.Callback().Update().After("gorm:update").Register("test:record", record)).To(Succeed())
+	Expect(db.Callback().Delete().After("gorm:delete").Register("test:record", record)).To(Succeed())
+
+	return db
+}
+
+// dryRunRow makes the queries of a dry run that scan into the type of row find it
+func dryRunRow(db *gorm.DB, row interface{}) {
+	rowType := reflect.TypeOf(row)
+	Expect(db.Callback().Query().After("gorm:query").Register("test:row:"+rowType.String(), func(tx *gorm.DB) {
+		dest := reflect.ValueOf(tx.Statement.Dest)
+		if dest.Kind() == reflect.Ptr && dest.Elem().Type() == rowType {
+			dest.Elem().Set(reflect.ValueOf(row))
+			tx.RowsAffected = 1
+		}
+	})).To(Succeed())
+}
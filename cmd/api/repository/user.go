@@ -6,8 +6,21 @@ import (
 
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// lockUser locks the row of a user, serializing the writes to the fee ledger and the loans of the user
+func lockUser(tx *gorm.DB, userID uint) error {
+	var user entity.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Table("users").Select("id").First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errmap.ErrmapNotFound
+		}
+		return errors.Wrap(err, "[lockUser]: unable to get user")
+	}
+	return nil
+}
+
 // CreateUser creates a new user
 func (r *PostgresRepository) CreateUser(user entity.User) (*uint, error) {
 	err := r.postgres.Table("users").Create(&user).Error
@@ -62,3 +75,39 @@ func (r *PostgresRepository) DeleteUser(userID uint) error {
 	}
 	return nil
 }
+
+// GetUserByCardNumber retrieves a user by their library card number
+func (r *PostgresRepository) GetUserByCardNumber(cardNumber string) (*entity.UserResponse, error) {
+	var user entity.UserResponse
+	err := r.postgres.Table("users").Where("card_number = ? AND deleted_at IS NULL", cardNumber).First(&user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmap.ErrmapNotFound
+		}
+		return nil, errors.Wrap(err, "[PostgresRepository.GetUserByCardNumber]: unable to get user")
+	}
+	return &user, nil
+}
+
+// UpdateUserCardNumber assigns a library card number to a user
+func (r *PostgresRepository) UpdateUserCardNumber(userID uint, cardNumber string) error {
+	var count int64
+	if err := r.postgres.Table("users").Where("card_number = ? AND id <> ?", cardNumber, userID).Count(&count).Error; err != nil {
+		return errors.Wrap(err, "[PostgresRepository.UpdateUserCardNumber]: unable to check card number")
+	}
+
+	if count > 0 {
+		return errmap.ErrmapConflict
+	}
+
+	result := r.postgres.Table("users").Where("id = ?", userID).Update("card_number", cardNumber)
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "[PostgresRepository.UpdateUserCardNumber]: unable to update user")
+	}
+
+	if result.RowsAffected == 0 {
+		return errmap.ErrmapNotFound
+	}
+
+	return nil
+}
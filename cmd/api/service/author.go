@@ -0,0 +1,101 @@
+package service
+
+import (
+	"time"
+
+	"go-library-service/cmd/api/entity"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// CreateAuthor creates an author
+func (s *Service) CreateAuthor(req entity.AuthorCreateRequest) (*entity.AuthorResponse, error) {
+	author, err := s.deps.PostgresRepo.CreateAuthor(&entity.Author{Name: req.Name})
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapConflict) {
+			return nil, errmap.ErrmapConflict
+		}
+		log.Error(errors.Wrap(err, "[Service.CreateAuthor]: unable to create author"))
+		return nil, errors.Wrap(err, "[Service.CreateAuthor]: unable to create author")
+	}
+
+	return author, nil
+}
+
+// ListAuthors lists authors by name
+func (s *Service) ListAuthors(req entity.ListAuthorRequest) (*entity.ListAuthorResult, error) {
+	authors, err := s.deps.PostgresRepo.ListAuthors(req)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Service.ListAuthors]: unable to get authors"))
+		return nil, errors.Wrap(err, "[Service.ListAuthors]: unable to get authors")
+	}
+
+	total, err := s.deps.PostgresRepo.CountAuthors(req)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Service.ListAuthors]: unable to count authors"))
+		return nil, errors.Wrap(err, "[Service.ListAuthors]: unable to count authors")
+	}
+
+	return &entity.ListAuthorResult{Authors: authors, Pagination: numberedPage(req.Page, req.Size, total)}, nil
+}
+
+// GetAuthor gets an author with the books crediting them
+func (s *Service) GetAuthor(authorID uint) (*entity.AuthorDetailResponse, error) {
+	author, err := s.deps.PostgresRepo.GetAuthorByID(authorID)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return nil, errmap.ErrmapNotFound
+		}
+		log.Error(errors.Wrap(err, "[Service.GetAuthor]: unable to get author"))
+		return nil, errors.Wrap(err, "[Service.GetAuthor]: unable to get author")
+	}
+
+	books, err := s.deps.PostgresRepo.ListBooksByAuthorID(authorID)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Service.GetAuthor]: unable to get books"))
+		return nil, errors.Wrap(err, "[Service.GetAuthor]: unable to get books")
+	}
+	s.attachCoverURLs(books)
+
+	return &entity.AuthorDetailResponse{AuthorResponse: *author, Books: books}, nil
+}
+
+// UpdateAuthor renames an author, which also rewrites the credit line of their books
+func (s *Service) UpdateAuthor(req entity.AuthorUpdateRequest) error {
+	err := s.deps.PostgresRepo.UpdateAuthor(entity.Author{ID: req.ID, Name: req.Name}, time.Now())
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return errmap.ErrmapNotFound
+		}
+		if errors.Is(err, errmap.ErrmapConflict) {
+			return errmap.ErrmapConflict
+		}
+		log.Error(errors.Wrap(err, "[Service.UpdateAuthor]: unable to update author"))
+		return errors.Wrap(err, "[Service.UpdateAuthor]: unable to update author")
+	}
+
+	if err := s.deps.RedisRepo.Delete(cacheKeyLatestBooks); err != nil {
+		log.Error(errors.Wrap(err, "[Service.UpdateAuthor]: unable to delete cache"))
+	}
+
+	return nil
+}
+
+// DeleteAuthor deletes an author who is not credited on any book
+func (s *Service) DeleteAuthor(authorID uint) error {
+	err := s.deps.PostgresRepo.DeleteAuthor(authorID)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return errmap.ErrmapNotFound
+		}
+		if errors.Is(err, errmap.ErrmapConflict) {
+			return errmap.ErrmapConflict
+		}
+		log.Error(errors.Wrap(err, "[Service.DeleteAuthor]: unable to delete author"))
+		return errors.Wrap(err, "[Service.DeleteAuthor]: unable to delete author")
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package service_test
+
+import (
+	"errors"
+
+	"go-library-service/cmd/api/entity"
+	service "go-library-service/cmd/api/service"
+	"go-library-service/cmd/api/service/mock"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Author Service", func() {
+	var (
+		ctrl         *gomock.Controller
+		s            *service.Service
+		postgresMock *mock.MockPostgresRepository
+		redisMock    *mock.MockRedisRepository
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		postgresMock = mock.NewMockPostgresRepository(ctrl)
+		redisMock = mock.NewMockRedisRepository(ctrl)
+		s = service.NewService(&service.Dependencies{
+			PostgresRepo: postgresMock,
+			RedisRepo:    redisMock,
+		}, &service.Config{})
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("CreateAuthor", func() {
+		It("should create an author", func() {
+			expected := &entity.AuthorResponse{ID: 1, Name: "Neil Gaiman"}
+			postgresMock.EXPECT().CreateAuthor(&entity.Author{Name: "Neil Gaiman"}).Return(expected, nil)
+
+			author, err := s.CreateAuthor(entity.AuthorCreateRequest{Name: "Neil Gaiman"})
+			Expect(err).To(BeNil())
+			Expect(author).To(Equal(expected))
+		})
+
+		It("should return conflict when the author exists", func() {
+			postgresMock.EXPECT().CreateAuthor(gomock.Any()).Return(nil, errmap.ErrmapConflict)
+
+			author, err := s.CreateAuthor(entity.AuthorCreateRequest{Name: "Gaiman, Neil"})
+			Expect(err).To(Equal(errmap.ErrmapConflict))
+			Expect(author).To(BeNil())
+		})
+	})
+
+	Context("ListAuthors", func() {
+		It("should return a counted page of authors", func() {
+			req := entity.ListAuthorRequest{Page: 2, Size: 1}
+			authors := []entity.AuthorResponse{{ID: 2, Name: "Terry Pratchett", BookCount: 3}}
+			postgresMock.EXPECT().ListAuthors(req).Return(authors, nil)
+			postgresMock.EXPECT().CountAuthors(req).Return(int64(2), nil)
+
+			result, err := s.ListAuthors(req)
+			Expect(err).To(BeNil())
+			Expect(result.Authors).To(Equal(authors))
+			Expect(*result.Pagination.Total).To(Equal(int64(2)))
+			Expect(*result.Pagination.TotalPages).To(Equal(int64(2)))
+		})
+	})
+
+	Context("GetAuthor", func() {
+		It("should return the author with their books", func() {
+			books := []entity.BookResponse{{ID: 5, Title: "Good Omens"}}
+			postgresMock.EXPECT().GetAuthorByID(uint(1)).Return(&entity.AuthorResponse{ID: 1, Name: "Neil Gaiman", BookCount: 1}, nil)
+			postgresMock.EXPECT().ListBooksByAuthorID(uint(1)).Return(books, nil)
+
+			author, err := s.GetAuthor(1)
+			Expect(err).To(BeNil())
+			Expect(author.Name).To(Equal("Neil Gaiman"))
+			Expect(author.Books).To(Equal(books))
+		})
+
+		It("should return not found when the author is missing", func() {
+			postgresMock.EXPECT().GetAuthorByID(uint(9)).Return(nil, errmap.ErrmapNotFound)
+
+			author, err := s.GetAuthor(9)
+			Expect(err).To(Equal(errmap.ErrmapNotFound))
+			Expect(author).To(BeNil())
+		})
+	})
+
+	Context("UpdateAuthor", func() {
+		It("should rename the author and drop the latest books cache", func() {
+			postgresMock.EXPECT().UpdateAuthor(entity.Author{ID: 1, Name: "Neil Gaiman"}, gomock.Any()).Return(nil)
+			redisMock.EXPECT().Delete("latest_books").Return(nil)
+
+			err := s.UpdateAuthor(entity.AuthorUpdateRequest{ID: 1, Name: "Neil Gaiman"})
+			Expect(err).To(BeNil())
+		})
+
+		It("should return conflict when another author has the name", func() {
+			postgresMock.EXPECT().UpdateAuthor(gomock.Any(), gomock.Any()).Return(errmap.ErrmapConflict)
+
+			err := s.UpdateAuthor(entity.AuthorUpdateRequest{ID: 1, Name: "Terry Pratchett"})
+			Expect(err).To(Equal(errmap.ErrmapConflict))
+		})
+	})
+
+	Context("DeleteAuthor", func() {
+		It("should return conflict when books credit the author", func() {
+			postgresMock.EXPECT().DeleteAuthor(uint(1)).Return(errmap.ErrmapConflict)
+
+			err := s.DeleteAuthor(1)
+			Expect(err).To(Equal(errmap.ErrmapConflict))
+		})
+
+		It("should wrap unexpected errors", func() {
+			postgresMock.EXPECT().DeleteAuthor(uint(1)).Return(errors.New("db error"))
+
+			err := s.DeleteAuthor(1)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unable to delete author"))
+		})
+	})
+})
@@ -2,8 +2,14 @@ package service
 
 import (
 	"encoding/json"
+	"strings"
+	"time"
+
+	"go-library-service/cmd/api/constant"
 	"go-library-service/cmd/api/entity"
 	errmap "go-library-service/internal/error_map"
+	"go-library-service/internal/isbn"
+	"go-library-service/internal/search"
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -15,19 +21,33 @@ const (
 
 // CreateBook creates a new book
 func (s *Service) CreateBook(req entity.BookCreateRequest) error {
-	book := entity.Book{
-		Title:  req.Title,
-		Author: req.Author,
-		Price:  req.Price,
-		Stock:  req.Stock,
+	book, err := newBook(req)
+	if err != nil {
+		return err
 	}
 
-	if req.Stock < 1 {
-		return errmap.ErrmapInvalidStock
+	authors, err := s.bookAuthors(req.Author, req.AuthorIDs)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapUnknownAuthor) {
+			return errmap.ErrmapUnknownAuthor
+		}
+		log.Error(errors.Wrap(err, "[Service.CreateBook]: unable to get authors"))
+		return errors.Wrap(err, "[Service.CreateBook]: unable to get authors")
+	}
+
+	if err := s.checkCategories(req.CategoryIDs); err != nil {
+		if errors.Is(err, errmap.ErrmapUnknownCategory) {
+			return errmap.ErrmapUnknownCategory
+		}
+		log.Error(errors.Wrap(err, "[Service.CreateBook]: unable to get categories"))
+		return errors.Wrap(err, "[Service.CreateBook]: unable to get categories")
 	}
 
-	err := s.deps.PostgresRepo.CreateBook(book)
+	err = s.deps.PostgresRepo.CreateBook(book, authors, req.CategoryIDs, subjectTags(req.Subjects))
 	if err != nil {
+		if errors.Is(err, errmap.ErrmapConflict) {
+			return errmap.ErrmapConflict
+		}
 		log.Error(errors.Wrap(err, "[Service.CreateBook]: unable to create book"))
 		return errors.Wrap(err, "[Service.CreateBook]: unable to create book")
 	}
@@ -51,6 +71,7 @@ func (s *Service) GetBookByID(bookID uint) (*entity.BookResponse, error) {
 		return nil, errors.Wrap(err, "[Service.GetBookByID]: unable to get book")
 	}
 
+	s.attachCoverURL(book)
 	return book, nil
 }
 
@@ -65,19 +86,49 @@ func (s *Service) UpdateBook(req entity.BookUpdateRequest) error {
 		log.Error(errors.Wrap(err, "[Service.UpdateBook]: unable to get book"))
 		return errors.Wrap(err, "[Service.UpdateBook]: unable to get book")
 	}
-	if req.Stock < 1 {
-		return errmap.ErrmapInvalidStock
+
+	authors, err := s.bookAuthors(req.Author, req.AuthorIDs)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapUnknownAuthor) {
+			return errmap.ErrmapUnknownAuthor
+		}
+		log.Error(errors.Wrap(err, "[Service.UpdateBook]: unable to get authors"))
+		return errors.Wrap(err, "[Service.UpdateBook]: unable to get authors")
+	}
+
+	if err := s.checkCategories(req.CategoryIDs); err != nil {
+		if errors.Is(err, errmap.ErrmapUnknownCategory) {
+			return errmap.ErrmapUnknownCategory
+		}
+		log.Error(errors.Wrap(err, "[Service.UpdateBook]: unable to get categories"))
+		return errors.Wrap(err, "[Service.UpdateBook]: unable to get categories")
+	}
+
+	bookIsbn, err := bookISBN(req.ISBN)
+	if err != nil {
+		return err
 	}
 
 	book := entity.Book{
-		ID:     req.ID,
-		Title:  req.Title,
-		Author: req.Author,
-		Price:  req.Price,
-		Stock:  req.Stock,
+		ID:              req.ID,
+		Title:           req.Title,
+		Author:          req.Author,
+		Price:           req.Price,
+		ISBN:            bookIsbn,
+		Publisher:       strings.TrimSpace(req.Publisher),
+		PublicationYear: req.PublicationYear,
+		Edition:         strings.TrimSpace(req.Edition),
+		Language:        req.Language,
+		PageCount:       req.PageCount,
 	}
 
-	if err := s.deps.PostgresRepo.UpdateBook(book);err != nil {
+	if err := s.deps.PostgresRepo.UpdateBook(book, authors, req.CategoryIDs, subjectTags(req.Subjects));err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return errmap.ErrmapNotFound
+		}
+		if errors.Is(err, errmap.ErrmapConflict) {
+			return errmap.ErrmapConflict
+		}
 		log.Error(errors.Wrap(err, "[Service.UpdateBook]: unable to update book"))
 		return errors.Wrap(err, "[Service.UpdateBook]: unable to update book")
 	}
@@ -89,19 +140,83 @@ func (s *Service) UpdateBook(req entity.BookUpdateRequest) error {
 	return nil
 }
 
-// ListBook lists books with pagination
-func (s *Service) ListBook(req entity.ListBookRequest) ([]entity.BookResponse, error) {
-	books, err := s.deps.PostgresRepo.ListBook(req)
+// GetBookByISBN looks up a book by an ISBN-10 or ISBN-13
+func (s *Service) GetBookByISBN(value string) (*entity.BookResponse, error) {
+	normalized, ok := isbn.Normalize(value)
+	if !ok {
+		return nil, errmap.ErrmapInvalidISBN
+	}
+
+	book, err := s.deps.PostgresRepo.GetBookByISBN(normalized)
 	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return nil, errmap.ErrmapNotFound
+		}
+		log.Error(errors.Wrap(err, "[Service.GetBookByISBN]: unable to get book"))
+		return nil, errors.Wrap(err, "[Service.GetBookByISBN]: unable to get book")
+	}
+
+	s.attachCoverURL(book)
+	return book, nil
+}
+
+// ListBook lists a page of books, suggesting a spelling for fuzzy or fruitless searches
+// and counting facets when asked
+func (s *Service) ListBook(req entity.ListBookRequest) (*entity.ListBookResult, error) {
+	books, nextCursor, err := s.deps.PostgresRepo.ListBook(req)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapInvalidCursor) {
+			return nil, errmap.ErrmapInvalidCursor
+		}
 		log.Error(errors.Wrap(err, "[Service.ListBook]: unable to get books"))
 		return nil, errors.Wrap(err, "[Service.ListBook]: unable to get books")
 	}
+	s.attachCoverURLs(books)
 
-	return books, nil
+	result := &entity.ListBookResult{Books: books, Pagination: cursorPage(req.Size, nextCursor)}
+
+	if req.Cursor == nil {
+		total, err := s.deps.PostgresRepo.CountBooks(req)
+		if err != nil {
+			log.Error(errors.Wrap(err, "[Service.ListBook]: unable to count books"))
+			return nil, errors.Wrap(err, "[Service.ListBook]: unable to count books")
+		}
+		result.Pagination = numberedPage(req.Page, req.Size, total)
+	}
+
+	if req.Search != nil && (req.Mode == constant.SearchModeFuzzy || len(books) == 0) {
+		// The books are still worth returning without a suggestion
+		suggestion, err := s.deps.PostgresRepo.SuggestBookSearch(*req.Search)
+		if err != nil {
+			log.Error(errors.Wrap(err, "[Service.ListBook]: unable to suggest search"))
+		}
+		result.Suggestion = suggestion
+	}
+
+	if req.Facets {
+		facets, err := s.deps.PostgresRepo.GetBookFacets(req)
+		if err != nil {
+			log.Error(errors.Wrap(err, "[Service.ListBook]: unable to count facets"))
+			return nil, errors.Wrap(err, "[Service.ListBook]: unable to count facets")
+		}
+		result.Facets = facets
+	}
+
+	return result, nil
 }
 
-// ListLatestBooks lists latest books
-func (s *Service) ListLatestBooks() ([]entity.BookResponse, error) {
+// ListLatestBooks lists latest books, caching them unless they are narrowed to a category or subject
+func (s *Service) ListLatestBooks(req entity.ListLatestBookRequest) ([]entity.BookResponse, error) {
+	if req.CategoryID != nil || req.Subject != nil {
+		books, err := s.deps.PostgresRepo.ListLatestBooks(req)
+		if err != nil {
+			log.Error(errors.Wrap(err, "[Service.ListLatestBooks]: unable to list latest book"))
+			return nil, errors.Wrap(err, "[Service.ListLatestBooks]: unable to list latest book")
+		}
+		s.attachCoverURLs(books)
+		return books, nil
+	}
+
     cachedBooks, err := s.deps.RedisRepo.Get(cacheKeyLatestBooks)
     if err == nil && cachedBooks != "" {
         var books []entity.BookResponse
@@ -110,13 +225,13 @@ func (s *Service) ListLatestBooks() ([]entity.BookResponse, error) {
         }
     }
 	
-	books, err := s.deps.PostgresRepo.ListLatestBooks()
+	books, err := s.deps.PostgresRepo.ListLatestBooks(req)
 	if err != nil {
 		log.Error(errors.Wrap(err, "[Service.ListLatestBooks]: unable to list latest book"))
 		return nil, errors.Wrap(err, "[Service.ListLatestBooks]: unable to list latest book")
 	}
+	s.attachCoverURLs(books)
 
-	   
 	booksJSON, err := json.Marshal(books)
 	if err == nil {
 		expiration := uint(60 * 60) // 1 hour
@@ -126,4 +241,132 @@ func (s *Service) ListLatestBooks() ([]entity.BookResponse, error) {
 	}
 
 	return books, nil
-}
\ No newline at end of file
+}
+
+// ArchiveBook withdraws a book from the catalog
+func (s *Service) ArchiveBook(bookID uint) error {
+	if err := s.deps.PostgresRepo.ArchiveBook(bookID, time.Now()); err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return errmap.ErrmapNotFound
+		}
+		if errors.Is(err, errmap.ErrmapConflict) {
+			return errmap.ErrmapConflict
+		}
+		if errors.Is(err, errmap.ErrmapActiveLoans) {
+			return errmap.ErrmapActiveLoans
+		}
+		log.Error(errors.Wrap(err, "[Service.ArchiveBook]: unable to archive book"))
+		return errors.Wrap(err, "[Service.ArchiveBook]: unable to archive book")
+	}
+
+	if err := s.deps.RedisRepo.Delete(cacheKeyLatestBooks); err != nil {
+		log.Error(errors.Wrap(err, "[Service.ArchiveBook]: unable to delete cache"))
+	}
+
+	return nil
+}
+
+// RestoreBook puts an archived book back in the catalog
+func (s *Service) RestoreBook(bookID uint) error {
+	book, err := s.deps.PostgresRepo.GetBookByID(bookID)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return errmap.ErrmapNotFound
+		}
+		log.Error(errors.Wrap(err, "[Service.RestoreBook]: unable to get book"))
+		return errors.Wrap(err, "[Service.RestoreBook]: unable to get book")
+	}
+
+	if book.DeletedAt == nil {
+		return errmap.ErrmapConflict
+	}
+
+	if err := s.deps.PostgresRepo.RestoreBook(bookID, time.Now()); err != nil {
+		if errors.Is(err, errmap.ErrmapConflict) {
+			return errmap.ErrmapConflict
+		}
+		log.Error(errors.Wrap(err, "[Service.RestoreBook]: unable to restore book"))
+		return errors.Wrap(err, "[Service.RestoreBook]: unable to restore book")
+	}
+
+	if err := s.deps.RedisRepo.Delete(cacheKeyLatestBooks); err != nil {
+		log.Error(errors.Wrap(err, "[Service.RestoreBook]: unable to delete cache"))
+	}
+
+	return nil
+}
+
+// bookAuthors resolves the authors to credit on a book, by ID in the given order or else from the names
+// in its credit line, which the repository matches to known authors or creates
+func (s *Service) bookAuthors(credit string, authorIDs []uint) ([]entity.Author, error) {
+	if len(authorIDs) == 0 {
+		var authors []entity.Author
+		for _, name := range search.SplitNames(credit) {
+			authors = append(authors, entity.Author{Name: name})
+		}
+		return authors, nil
+	}
+
+	found, err := s.deps.PostgresRepo.GetAuthorsByIDs(authorIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := map[uint]entity.Author{}
+	for _, author := range found {
+		byID[author.ID] = author
+	}
+
+	authors := make([]entity.Author, 0, len(authorIDs))
+	for _, authorID := range authorIDs {
+		author, ok := byID[authorID]
+		if !ok {
+			return nil, errmap.ErrmapUnknownAuthor
+		}
+		authors = append(authors, author)
+	}
+	return authors, nil
+}
+
+// newBook makes the book a create request describes
+func newBook(req entity.BookCreateRequest) (entity.Book, error) {
+	if req.Stock < 1 {
+		return entity.Book{}, errmap.ErrmapInvalidStock
+	}
+
+	return requestedBook(req)
+}
+
+// requestedBook reads the book of a create request, whatever its stock
+func requestedBook(req entity.BookCreateRequest) (entity.Book, error) {
+	bookIsbn, err := bookISBN(req.ISBN)
+	if err != nil {
+		return entity.Book{}, err
+	}
+
+	return entity.Book{
+		Title:           req.Title,
+		Author:          req.Author,
+		Price:           req.Price,
+		Stock:           req.Stock,
+		ISBN:            bookIsbn,
+		Publisher:       strings.TrimSpace(req.Publisher),
+		PublicationYear: req.PublicationYear,
+		Edition:         strings.TrimSpace(req.Edition),
+		Language:        req.Language,
+		PageCount:       req.PageCount,
+	}, nil
+}
+
+// bookISBN brings the ISBN given for a book to its ISBN-13 form, none when it is blank
+func bookISBN(value string) (*string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	normalized, ok := isbn.Normalize(value)
+	if !ok {
+		return nil, errmap.ErrmapInvalidISBN
+	}
+	return &normalized, nil
+}
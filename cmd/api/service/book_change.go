@@ -0,0 +1,48 @@
+package service
+
+import (
+	"time"
+
+	"go-library-service/cmd/api/entity"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// ListBookChanges lists a page of the books changed in a time range, archived books included,
+// counting the whole range for the first page
+func (s *Service) ListBookChanges(req entity.ListBookChangeRequest) (*entity.ListBookChangeResult, error) {
+	books, next, err := s.deps.PostgresRepo.ListBookChanges(req)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapInvalidCursor) {
+			return nil, errmap.ErrmapInvalidCursor
+		}
+		log.Error(errors.Wrap(err, "[Service.ListBookChanges]: unable to get books"))
+		return nil, errors.Wrap(err, "[Service.ListBookChanges]: unable to get books")
+	}
+	s.attachCoverURLs(books)
+
+	result := &entity.ListBookChangeResult{Books: books, Next: next}
+
+	if req.Cursor == "" {
+		total, err := s.deps.PostgresRepo.CountBookChanges(req)
+		if err != nil {
+			log.Error(errors.Wrap(err, "[Service.ListBookChanges]: unable to count books"))
+			return nil, errors.Wrap(err, "[Service.ListBookChanges]: unable to count books")
+		}
+		result.Total = &total
+	}
+
+	return result, nil
+}
+
+// GetEarliestBookChange gets when the book that changed longest ago last changed, nil without books
+func (s *Service) GetEarliestBookChange() (*time.Time, error) {
+	earliest, err := s.deps.PostgresRepo.GetEarliestBookChange()
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Service.GetEarliestBookChange]: unable to get earliest change"))
+		return nil, errors.Wrap(err, "[Service.GetEarliestBookChange]: unable to get earliest change")
+	}
+	return earliest, nil
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"time"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// AddBookCopy adds a physical copy of a book
+func (s *Service) AddBookCopy(req entity.BookCopyCreateRequest) (*entity.BookCopyResponse, error) {
+	condition := req.Condition
+	if condition == "" {
+		condition = constant.CopyConditionGood
+	}
+
+	now := time.Now()
+	bookCopy, err := s.deps.PostgresRepo.CreateBookCopy(&entity.BookCopy{
+		BookID:        req.BookID,
+		Barcode:       req.Barcode,
+		ShelfLocation: req.ShelfLocation,
+		Condition:     condition,
+		Status:        constant.CopyStatusAvailable,
+	}, now, now.Add(s.holdPickupWindow()))
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return nil, errmap.ErrmapNotFound
+		}
+		if errors.Is(err, errmap.ErrmapConflict) {
+			return nil, errmap.ErrmapConflict
+		}
+		log.Error(errors.Wrap(err, "[Service.AddBookCopy]: unable to create book copy"))
+		return nil, errors.Wrap(err, "[Service.AddBookCopy]: unable to create book copy")
+	}
+
+	if err := s.deps.RedisRepo.Delete(cacheKeyLatestBooks); err != nil {
+		log.Error(errors.Wrap(err, "[Service.AddBookCopy]: unable to delete cache"))
+	}
+
+	return bookCopy, nil
+}
+
+// ListBookCopies lists the copies of a book
+func (s *Service) ListBookCopies(bookID uint) ([]entity.BookCopyResponse, error) {
+	if _, err := s.deps.PostgresRepo.GetBookByID(bookID); err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return nil, errmap.ErrmapNotFound
+		}
+		log.Error(errors.Wrap(err, "[Service.ListBookCopies]: unable to get book"))
+		return nil, errors.Wrap(err, "[Service.ListBookCopies]: unable to get book")
+	}
+
+	copies, err := s.deps.PostgresRepo.ListBookCopiesByBookID(bookID)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Service.ListBookCopies]: unable to list book copies"))
+		return nil, errors.Wrap(err, "[Service.ListBookCopies]: unable to list book copies")
+	}
+
+	return copies, nil
+}
+
+// GetBookCopyByBarcode looks up a copy by its barcode
+func (s *Service) GetBookCopyByBarcode(barcode string) (*entity.BookCopyResponse, error) {
+	bookCopy, err := s.deps.PostgresRepo.GetBookCopyByBarcode(barcode)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return nil, errmap.ErrmapNotFound
+		}
+		log.Error(errors.Wrap(err, "[Service.GetBookCopyByBarcode]: unable to get book copy"))
+		return nil, errors.Wrap(err, "[Service.GetBookCopyByBarcode]: unable to get book copy")
+	}
+
+	return bookCopy, nil
+}
+
+// UpdateBookCopy updates the shelf location and condition of a copy
+func (s *Service) UpdateBookCopy(req entity.BookCopyUpdateRequest) error {
+	err := s.deps.PostgresRepo.UpdateBookCopy(entity.BookCopy{
+		ID:            req.ID,
+		ShelfLocation: req.ShelfLocation,
+		Condition:     req.Condition,
+	})
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return errmap.ErrmapNotFound
+		}
+		log.Error(errors.Wrap(err, "[Service.UpdateBookCopy]: unable to update book copy"))
+		return errors.Wrap(err, "[Service.UpdateBookCopy]: unable to update book copy")
+	}
+
+	return nil
+}
+
+// RetireBookCopy takes a copy out of circulation, it must be on the shelf
+func (s *Service) RetireBookCopy(copyID uint) error {
+	if err := s.deps.PostgresRepo.RetireBookCopy(copyID); err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return errmap.ErrmapNotFound
+		}
+		if errors.Is(err, errmap.ErrmapConflict) {
+			return errmap.ErrmapConflict
+		}
+		log.Error(errors.Wrap(err, "[Service.RetireBookCopy]: unable to retire book copy"))
+		return errors.Wrap(err, "[Service.RetireBookCopy]: unable to retire book copy")
+	}
+
+	if err := s.deps.RedisRepo.Delete(cacheKeyLatestBooks); err != nil {
+		log.Error(errors.Wrap(err, "[Service.RetireBookCopy]: unable to delete cache"))
+	}
+
+	return nil
+}
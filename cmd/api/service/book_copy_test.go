@@ -0,0 +1,124 @@
+package service_test
+
+import (
+	"errors"
+	"time"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	service "go-library-service/cmd/api/service"
+	"go-library-service/cmd/api/service/mock"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Book Copy Service", func() {
+	var (
+		ctrl         *gomock.Controller
+		s            *service.Service
+		postgresMock *mock.MockPostgresRepository
+		redisMock    *mock.MockRedisRepository
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		postgresMock = mock.NewMockPostgresRepository(ctrl)
+		redisMock = mock.NewMockRedisRepository(ctrl)
+		s = service.NewService(&service.Dependencies{
+			PostgresRepo: postgresMock,
+			RedisRepo:    redisMock,
+		}, &service.Config{HoldPickupWindow: 24 * time.Hour})
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("AddBookCopy", func() {
+		It("should add an available copy in good condition by default", func() {
+			postgresMock.EXPECT().
+				CreateBookCopy(gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(bookCopy *entity.BookCopy, now, pickupExpiresAt time.Time) (*entity.BookCopyResponse, error) {
+					Expect(bookCopy.BookID).To(Equal(uint(1)))
+					Expect(bookCopy.Barcode).To(Equal("0001"))
+					Expect(bookCopy.Condition).To(Equal(constant.CopyConditionGood))
+					Expect(bookCopy.Status).To(Equal(constant.CopyStatusAvailable))
+					Expect(pickupExpiresAt.Sub(now)).To(Equal(24 * time.Hour))
+					return &entity.BookCopyResponse{ID: 1, BookID: 1, Barcode: "0001", Status: bookCopy.Status}, nil
+				})
+			redisMock.EXPECT().Delete("latest_books").Return(nil)
+
+			bookCopy, err := s.AddBookCopy(entity.BookCopyCreateRequest{BookID: 1, Barcode: "0001"})
+			Expect(err).To(BeNil())
+			Expect(bookCopy.ID).To(Equal(uint(1)))
+		})
+
+		It("should return error when barcode already exists", func() {
+			postgresMock.EXPECT().CreateBookCopy(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errmap.ErrmapConflict)
+
+			bookCopy, err := s.AddBookCopy(entity.BookCopyCreateRequest{BookID: 1, Barcode: "0001"})
+			Expect(err).To(Equal(errmap.ErrmapConflict))
+			Expect(bookCopy).To(BeNil())
+		})
+	})
+
+	Context("ListBookCopies", func() {
+		It("should list copies of a book", func() {
+			expectedCopies := []entity.BookCopyResponse{{ID: 1, BookID: 1, Barcode: "0001", Status: constant.CopyStatusOnLoan}}
+
+			postgresMock.EXPECT().GetBookByID(uint(1)).Return(&entity.BookResponse{ID: 1}, nil)
+			postgresMock.EXPECT().ListBookCopiesByBookID(uint(1)).Return(expectedCopies, nil)
+
+			copies, err := s.ListBookCopies(1)
+			Expect(err).To(BeNil())
+			Expect(copies).To(Equal(expectedCopies))
+		})
+
+		It("should return error when book not found", func() {
+			postgresMock.EXPECT().GetBookByID(uint(999)).Return(nil, errmap.ErrmapNotFound)
+
+			copies, err := s.ListBookCopies(999)
+			Expect(err).To(Equal(errmap.ErrmapNotFound))
+			Expect(copies).To(BeNil())
+		})
+	})
+
+	Context("GetBookCopyByBarcode", func() {
+		It("should return error when barcode is unknown", func() {
+			postgresMock.EXPECT().GetBookCopyByBarcode("unknown").Return(nil, errmap.ErrmapNotFound)
+
+			bookCopy, err := s.GetBookCopyByBarcode("unknown")
+			Expect(err).To(Equal(errmap.ErrmapNotFound))
+			Expect(bookCopy).To(BeNil())
+		})
+	})
+
+	Context("UpdateBookCopy", func() {
+		It("should update shelf location and condition", func() {
+			postgresMock.EXPECT().UpdateBookCopy(entity.BookCopy{ID: 1, ShelfLocation: "A-1", Condition: constant.CopyConditionFair}).Return(nil)
+
+			err := s.UpdateBookCopy(entity.BookCopyUpdateRequest{ID: 1, ShelfLocation: "A-1", Condition: constant.CopyConditionFair})
+			Expect(err).To(BeNil())
+		})
+	})
+
+	Context("RetireBookCopy", func() {
+		It("should retire a copy", func() {
+			postgresMock.EXPECT().RetireBookCopy(uint(1)).Return(nil)
+			redisMock.EXPECT().Delete("latest_books").Return(errors.New("redis error"))
+
+			err := s.RetireBookCopy(1)
+			Expect(err).To(BeNil())
+		})
+
+		It("should return error when copy is not on the shelf", func() {
+			postgresMock.EXPECT().RetireBookCopy(uint(1)).Return(errmap.ErrmapConflict)
+
+			err := s.RetireBookCopy(1)
+			Expect(err).To(Equal(errmap.ErrmapConflict))
+		})
+	})
+})
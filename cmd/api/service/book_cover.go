@@ -0,0 +1,202 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	_ "image/gif" // registers the decoder of GIF covers
+	"image/jpeg"
+	_ "image/png" // registers the decoder of PNG covers
+	"io"
+	"net/http"
+	"time"
+
+	"go-library-service/cmd/api/entity"
+	errmap "go-library-service/internal/error_map"
+	"go-library-service/internal/thumbnail"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultMaxCoverSize = 5 << 20 // 5 MiB
+	// maxCoverPixels caps the dimensions of a cover, as a small file can still decode to a huge image
+	maxCoverPixels = 40_000_000
+
+	coverThumbnailWidth   = 200
+	coverThumbnailHeight  = 300
+	coverThumbnailQuality = 85
+)
+
+// coverExtensions are the file extensions of the image types a cover may be, by the type sniffed from its content
+var coverExtensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+}
+
+// maxCoverSize returns the configured cover size limit or the default one
+func (s *Service) maxCoverSize() int64 {
+	if s.conf.MaxCoverSize > 0 {
+		return s.conf.MaxCoverSize
+	}
+	return defaultMaxCoverSize
+}
+
+// UploadBookCover sets the cover of a book from an image, storing it with a thumbnail and replacing the cover
+// it had. The type of the image is told from its content, whatever the upload claims it to be.
+func (s *Service) UploadBookCover(bookID uint, file io.Reader) (*entity.BookCoverResponse, error) {
+	book, err := s.deps.PostgresRepo.GetBookByID(bookID)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return nil, errmap.ErrmapNotFound
+		}
+		log.Error(errors.Wrap(err, "[Service.UploadBookCover]: unable to get book"))
+		return nil, errors.Wrap(err, "[Service.UploadBookCover]: unable to get book")
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, s.maxCoverSize()+1))
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Service.UploadBookCover]: unable to read cover"))
+		return nil, errors.Wrap(err, "[Service.UploadBookCover]: unable to read cover")
+	}
+	if int64(len(data)) > s.maxCoverSize() {
+		return nil, errmap.ErrmapCoverTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	extension, ok := coverExtensions[contentType]
+	if !ok {
+		return nil, errmap.ErrmapUnsupportedCover
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errmap.ErrmapInvalidCover
+	}
+	if config.Width*config.Height > maxCoverPixels {
+		return nil, errmap.ErrmapCoverTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errmap.ErrmapInvalidCover
+	}
+
+	var thumb bytes.Buffer
+	if err := jpeg.Encode(&thumb, thumbnail.Fit(img, coverThumbnailWidth, coverThumbnailHeight), &jpeg.Options{Quality: coverThumbnailQuality}); err != nil {
+		log.Error(errors.Wrap(err, "[Service.UploadBookCover]: unable to encode thumbnail"))
+		return nil, errors.Wrap(err, "[Service.UploadBookCover]: unable to encode thumbnail")
+	}
+
+	// Keys change with the content, so a cached copy of a replaced cover is never served in its place
+	sum := sha256.Sum256(data)
+	name := fmt.Sprintf("covers/%d/%s", bookID, hex.EncodeToString(sum[:8]))
+	coverKey, thumbnailKey := name+"."+extension, name+"-thumb.jpg"
+	cover := entity.BookCover{CoverKey: &coverKey, CoverThumbnailKey: &thumbnailKey}
+	current := entity.BookCover{CoverKey: book.CoverKey, CoverThumbnailKey: book.CoverThumbnailKey}
+
+	if err := s.deps.BlobStorage.Put(coverKey, data, contentType); err != nil {
+		log.Error(errors.Wrap(err, "[Service.UploadBookCover]: unable to store cover"))
+		return nil, errors.Wrap(err, "[Service.UploadBookCover]: unable to store cover")
+	}
+	if err := s.deps.BlobStorage.Put(thumbnailKey, thumb.Bytes(), "image/jpeg"); err != nil {
+		s.deleteCoverBlobs(entity.BookCover{CoverKey: &coverKey}, current)
+		log.Error(errors.Wrap(err, "[Service.UploadBookCover]: unable to store thumbnail"))
+		return nil, errors.Wrap(err, "[Service.UploadBookCover]: unable to store thumbnail")
+	}
+
+	previous, err := s.deps.PostgresRepo.UpdateBookCover(bookID, cover, time.Now())
+	if err != nil {
+		s.deleteCoverBlobs(cover, current)
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return nil, errmap.ErrmapNotFound
+		}
+		log.Error(errors.Wrap(err, "[Service.UploadBookCover]: unable to update book"))
+		return nil, errors.Wrap(err, "[Service.UploadBookCover]: unable to update book")
+	}
+	s.deleteCoverBlobs(*previous, cover)
+
+	if err := s.deps.RedisRepo.Delete(cacheKeyLatestBooks); err != nil {
+		log.Error(errors.Wrap(err, "[Service.UploadBookCover]: unable to delete cache"))
+	}
+
+	return &entity.BookCoverResponse{
+		CoverURL:          s.deps.BlobStorage.URL(coverKey),
+		CoverThumbnailURL: s.deps.BlobStorage.URL(thumbnailKey),
+		ContentType:       contentType,
+		Width:             config.Width,
+		Height:            config.Height,
+	}, nil
+}
+
+// DeleteBookCover removes the cover of a book and its thumbnail
+func (s *Service) DeleteBookCover(bookID uint) error {
+	book, err := s.deps.PostgresRepo.GetBookByID(bookID)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return errmap.ErrmapNotFound
+		}
+		log.Error(errors.Wrap(err, "[Service.DeleteBookCover]: unable to get book"))
+		return errors.Wrap(err, "[Service.DeleteBookCover]: unable to get book")
+	}
+
+	if book.CoverKey == nil && book.CoverThumbnailKey == nil {
+		return errmap.ErrmapNotFound
+	}
+
+	previous, err := s.deps.PostgresRepo.UpdateBookCover(bookID, entity.BookCover{}, time.Now())
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return errmap.ErrmapNotFound
+		}
+		log.Error(errors.Wrap(err, "[Service.DeleteBookCover]: unable to update book"))
+		return errors.Wrap(err, "[Service.DeleteBookCover]: unable to update book")
+	}
+	s.deleteCoverBlobs(*previous, entity.BookCover{})
+
+	if err := s.deps.RedisRepo.Delete(cacheKeyLatestBooks); err != nil {
+		log.Error(errors.Wrap(err, "[Service.DeleteBookCover]: unable to delete cache"))
+	}
+
+	return nil
+}
+
+// deleteCoverBlobs deletes the blobs of a cover that another cover does not share. A blob left behind only
+// takes up space, so failures are logged rather than returned.
+func (s *Service) deleteCoverBlobs(cover, keep entity.BookCover) {
+	for _, key := range []*string{cover.CoverKey, cover.CoverThumbnailKey} {
+		if key == nil || sameKey(key, keep.CoverKey) || sameKey(key, keep.CoverThumbnailKey) {
+			continue
+		}
+		if err := s.deps.BlobStorage.Delete(*key); err != nil {
+			log.Error(errors.Wrapf(err, "[Service.deleteCoverBlobs]: unable to delete %s", *key))
+		}
+	}
+}
+
+func sameKey(a, b *string) bool {
+	return b != nil && *a == *b
+}
+
+// attachCoverURLs fills in where the cover and thumbnail of each book are served
+func (s *Service) attachCoverURLs(books []entity.BookResponse) {
+	for i := range books {
+		s.attachCoverURL(&books[i])
+	}
+}
+
+// attachCoverURL fills in where the cover and thumbnail of a book are served
+func (s *Service) attachCoverURL(book *entity.BookResponse) {
+	if book.CoverKey != nil {
+		url := s.deps.BlobStorage.URL(*book.CoverKey)
+		book.CoverURL = &url
+	}
+	if book.CoverThumbnailKey != nil {
+		url := s.deps.BlobStorage.URL(*book.CoverThumbnailKey)
+		book.CoverThumbnailURL = &url
+	}
+}
@@ -0,0 +1,230 @@
+package service_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"strings"
+
+	"go-library-service/cmd/api/entity"
+	service "go-library-service/cmd/api/service"
+	"go-library-service/cmd/api/service/mock"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Book Cover Service", func() {
+	var (
+		ctrl         *gomock.Controller
+		s            *service.Service
+		postgresMock *mock.MockPostgresRepository
+		redisMock    *mock.MockRedisRepository
+		blobMock     *mock.MockBlobStorage
+
+		cacheKeyLatestBooks = "latest_books"
+		cover               []byte
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		postgresMock = mock.NewMockPostgresRepository(ctrl)
+		redisMock = mock.NewMockRedisRepository(ctrl)
+		blobMock = mock.NewMockBlobStorage(ctrl)
+		s = service.NewService(&service.Dependencies{
+			PostgresRepo: postgresMock,
+			RedisRepo:    redisMock,
+			BlobStorage:  blobMock,
+		}, &service.Config{})
+		blobMock.EXPECT().URL(gomock.Any()).DoAndReturn(func(key string) string {
+			return "https://cdn.example.com/" + key
+		}).AnyTimes()
+
+		img := image.NewRGBA(image.Rect(0, 0, 600, 900))
+		for i := range img.Pix {
+			img.Pix[i] = 200
+		}
+		var buf bytes.Buffer
+		Expect(png.Encode(&buf, img)).To(Succeed())
+		cover = buf.Bytes()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("UploadBookCover", func() {
+		It("should store the cover with a thumbnail, replace the old one and clear the latest books cache", func() {
+			oldCover, oldThumbnail := "covers/1/old.jpg", "covers/1/old-thumb.jpg"
+			var coverKey, thumbnailKey string
+
+			postgresMock.EXPECT().GetBookByID(uint(1)).Return(&entity.BookResponse{ID: 1}, nil)
+			blobMock.EXPECT().Put(gomock.Any(), cover, "image/png").DoAndReturn(func(key string, _ []byte, _ string) error {
+				coverKey = key
+				return nil
+			})
+			blobMock.EXPECT().Put(gomock.Any(), gomock.Any(), "image/jpeg").DoAndReturn(func(key string, data []byte, _ string) error {
+				thumbnailKey = key
+				thumb, err := jpeg.DecodeConfig(bytes.NewReader(data))
+				Expect(err).NotTo(HaveOccurred())
+				Expect([]int{thumb.Width, thumb.Height}).To(Equal([]int{200, 300}))
+				return nil
+			})
+			postgresMock.EXPECT().UpdateBookCover(uint(1), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ uint, c entity.BookCover, _ interface{}) (*entity.BookCover, error) {
+					Expect(*c.CoverKey).To(Equal(coverKey))
+					Expect(*c.CoverThumbnailKey).To(Equal(thumbnailKey))
+					return &entity.BookCover{CoverKey: &oldCover, CoverThumbnailKey: &oldThumbnail}, nil
+				})
+			blobMock.EXPECT().Delete(oldCover).Return(nil)
+			blobMock.EXPECT().Delete(oldThumbnail).Return(errors.New("storage down"))
+			redisMock.EXPECT().Delete(cacheKeyLatestBooks).Return(nil)
+
+			res, err := s.UploadBookCover(1, bytes.NewReader(cover))
+			Expect(err).To(BeNil())
+			Expect(coverKey).To(MatchRegexp(`^covers/1/[0-9a-f]{16}\.png$`))
+			Expect(thumbnailKey).To(Equal(strings.TrimSuffix(coverKey, ".png") + "-thumb.jpg"))
+			Expect(*res).To(Equal(entity.BookCoverResponse{
+				CoverURL:          "https://cdn.example.com/" + coverKey,
+				CoverThumbnailURL: "https://cdn.example.com/" + thumbnailKey,
+				ContentType:       "image/png",
+				Width:             600,
+				Height:            900,
+			}))
+		})
+
+		It("should keep the blobs of the same cover uploaded again", func() {
+			var keys []string
+			postgresMock.EXPECT().GetBookByID(uint(1)).Return(&entity.BookResponse{ID: 1}, nil)
+			blobMock.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(key string, _ []byte, _ string) error {
+				keys = append(keys, key)
+				return nil
+			}).Times(2)
+			postgresMock.EXPECT().UpdateBookCover(uint(1), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ uint, c entity.BookCover, _ interface{}) (*entity.BookCover, error) {
+					return &c, nil
+				})
+			redisMock.EXPECT().Delete(cacheKeyLatestBooks).Return(nil)
+
+			_, err := s.UploadBookCover(1, bytes.NewReader(cover))
+			Expect(err).To(BeNil())
+			Expect(keys).To(HaveLen(2))
+		})
+
+		It("should return not found for a missing book", func() {
+			postgresMock.EXPECT().GetBookByID(uint(1)).Return(nil, errmap.ErrmapNotFound)
+
+			_, err := s.UploadBookCover(1, bytes.NewReader(cover))
+			Expect(err).To(Equal(errmap.ErrmapNotFound))
+		})
+
+		It("should refuse a cover over the size limit", func() {
+			s = service.NewService(&service.Dependencies{PostgresRepo: postgresMock, BlobStorage: blobMock}, &service.Config{MaxCoverSize: int64(len(cover) - 1)})
+			postgresMock.EXPECT().GetBookByID(uint(1)).Return(&entity.BookResponse{ID: 1}, nil)
+
+			_, err := s.UploadBookCover(1, bytes.NewReader(cover))
+			Expect(err).To(Equal(errmap.ErrmapCoverTooLarge))
+		})
+
+		It("should refuse a file that is not a supported image, whatever it is named", func() {
+			postgresMock.EXPECT().GetBookByID(uint(1)).Return(&entity.BookResponse{ID: 1}, nil)
+
+			_, err := s.UploadBookCover(1, strings.NewReader("%PDF-1.7 not a cover"))
+			Expect(err).To(Equal(errmap.ErrmapUnsupportedCover))
+		})
+
+		It("should refuse an image that does not decode", func() {
+			postgresMock.EXPECT().GetBookByID(uint(1)).Return(&entity.BookResponse{ID: 1}, nil)
+
+			_, err := s.UploadBookCover(1, bytes.NewReader(cover[:len(cover)/2]))
+			Expect(err).To(Equal(errmap.ErrmapInvalidCover))
+		})
+
+		It("should refuse an image with too many pixels", func() {
+			var buf bytes.Buffer
+			Expect(png.Encode(&buf, image.NewGray(image.Rect(0, 0, 1, 1)))).To(Succeed())
+			huge := buf.Bytes()
+			// Claim dimensions of 10000x10000 in the header without the pixels to back them
+			copy(huge[16:24], []byte{0, 0, 0x27, 0x10, 0, 0, 0x27, 0x10})
+			binary.BigEndian.PutUint32(huge[29:33], crc32.ChecksumIEEE(huge[12:29]))
+			postgresMock.EXPECT().GetBookByID(uint(1)).Return(&entity.BookResponse{ID: 1}, nil)
+
+			_, err := s.UploadBookCover(1, bytes.NewReader(huge))
+			Expect(err).To(Equal(errmap.ErrmapCoverTooLarge))
+		})
+
+		It("should delete the new blobs when the book cannot be updated", func() {
+			var keys []string
+			postgresMock.EXPECT().GetBookByID(uint(1)).Return(&entity.BookResponse{ID: 1}, nil)
+			blobMock.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(key string, _ []byte, _ string) error {
+				keys = append(keys, key)
+				return nil
+			}).Times(2)
+			postgresMock.EXPECT().UpdateBookCover(uint(1), gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
+			blobMock.EXPECT().Delete(gomock.Any()).DoAndReturn(func(key string) error {
+				Expect(keys).To(ContainElement(key))
+				return nil
+			}).Times(2)
+
+			_, err := s.UploadBookCover(1, bytes.NewReader(cover))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("DeleteBookCover", func() {
+		It("should clear the cover, delete its blobs and clear the latest books cache", func() {
+			coverKey, thumbnailKey := "covers/1/a.png", "covers/1/a-thumb.jpg"
+			postgresMock.EXPECT().GetBookByID(uint(1)).Return(&entity.BookResponse{ID: 1, CoverKey: &coverKey, CoverThumbnailKey: &thumbnailKey}, nil)
+			postgresMock.EXPECT().UpdateBookCover(uint(1), entity.BookCover{}, gomock.Any()).
+				Return(&entity.BookCover{CoverKey: &coverKey, CoverThumbnailKey: &thumbnailKey}, nil)
+			blobMock.EXPECT().Delete(coverKey).Return(nil)
+			blobMock.EXPECT().Delete(thumbnailKey).Return(nil)
+			redisMock.EXPECT().Delete(cacheKeyLatestBooks).Return(nil)
+
+			err := s.DeleteBookCover(1)
+			Expect(err).To(BeNil())
+		})
+
+		It("should return not found for a book without a cover", func() {
+			postgresMock.EXPECT().GetBookByID(uint(1)).Return(&entity.BookResponse{ID: 1}, nil)
+
+			err := s.DeleteBookCover(1)
+			Expect(err).To(Equal(errmap.ErrmapNotFound))
+		})
+	})
+
+	Context("Cover URLs", func() {
+		It("should fill in the cover URLs of a book", func() {
+			coverKey, thumbnailKey := "covers/1/a.png", "covers/1/a-thumb.jpg"
+			postgresMock.EXPECT().GetBookByID(uint(1)).Return(&entity.BookResponse{ID: 1, CoverKey: &coverKey, CoverThumbnailKey: &thumbnailKey}, nil)
+
+			book, err := s.GetBookByID(1)
+			Expect(err).To(BeNil())
+			Expect(*book.CoverURL).To(Equal("https://cdn.example.com/covers/1/a.png"))
+			Expect(*book.CoverThumbnailURL).To(Equal("https://cdn.example.com/covers/1/a-thumb.jpg"))
+		})
+
+		It("should cache the latest books with their cover URLs", func() {
+			coverKey := "covers/2/b.jpg"
+			req := entity.ListLatestBookRequest{}
+			redisMock.EXPECT().Get(cacheKeyLatestBooks).Return("", errors.New("cache miss"))
+			postgresMock.EXPECT().ListLatestBooks(req).Return([]entity.BookResponse{{ID: 1}, {ID: 2, CoverKey: &coverKey}}, nil)
+			redisMock.EXPECT().Set(cacheKeyLatestBooks, gomock.Any(), gomock.Any()).DoAndReturn(func(_ string, value interface{}, _ uint) error {
+				Expect(value).To(ContainSubstring(`"coverUrl":"https://cdn.example.com/covers/2/b.jpg"`))
+				Expect(value).NotTo(ContainSubstring("coverKey"))
+				return nil
+			})
+
+			books, err := s.ListLatestBooks(req)
+			Expect(err).To(BeNil())
+			Expect(books[0].CoverURL).To(BeNil())
+			Expect(*books[1].CoverURL).To(Equal("https://cdn.example.com/covers/2/b.jpg"))
+		})
+	})
+})
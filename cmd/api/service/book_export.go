@@ -0,0 +1,44 @@
+package service
+
+import (
+	"go-library-service/cmd/api/entity"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const exportBatchSize = 500
+
+// ExportBooks hands the books matching a listing to fn a batch at a time with the availability of their
+// copies, so they can be written out without holding every book at once
+func (s *Service) ExportBooks(req entity.ListBookRequest, fn func(books []entity.BookExport) error) error {
+	err := s.deps.PostgresRepo.EachBook(req, exportBatchSize, func(books []entity.BookResponse) error {
+		bookIDs := make([]uint, len(books))
+		for i, book := range books {
+			bookIDs[i] = book.ID
+		}
+
+		availability, err := s.deps.PostgresRepo.GetBookAvailability(bookIDs)
+		if err != nil {
+			return err
+		}
+
+		byBook := make(map[uint]entity.BookAvailability, len(availability))
+		for _, counts := range availability {
+			byBook[counts.BookID] = counts
+		}
+
+		s.attachCoverURLs(books)
+		exports := make([]entity.BookExport, len(books))
+		for i, book := range books {
+			exports[i] = entity.BookExport{BookResponse: book, Availability: byBook[book.ID]}
+		}
+		return fn(exports)
+	})
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Service.ExportBooks]: unable to export books"))
+		return errors.Wrap(err, "[Service.ExportBooks]: unable to export books")
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const defaultImportBatchSize = 100
+
+// ImportBooks writes the rows read from an import file, all of them in one go or a batch at a time
+// in batch mode. A batch is written only when every row in it is sound, so without batches a single
+// bad row leaves the catalog as it was.
+func (s *Service) ImportBooks(rows []entity.BookImportRow, req entity.BookImportRequest) (*entity.BookImportReport, error) {
+	report := &entity.BookImportReport{DryRun: req.DryRun, Rows: len(rows), Errors: []entity.BookImportRowError{}}
+
+	size := len(rows)
+	if req.Mode == constant.BookImportModeBatch {
+		size = req.BatchSize
+		if size < 1 {
+			size = defaultImportBatchSize
+		}
+	}
+
+	written := false
+	for start := 0; start < len(rows); start += size {
+		batch := rows[start:min(start+size, len(rows))]
+
+		items := make([]entity.BookImportItem, 0, len(batch))
+		for _, row := range batch {
+			item, problems, err := s.bookImportItem(row)
+			if err != nil {
+				log.Error(errors.Wrap(err, "[Service.ImportBooks]: unable to check row"))
+				return nil, errors.Wrap(err, "[Service.ImportBooks]: unable to check row")
+			}
+
+			if len(problems) > 0 {
+				report.Errors = append(report.Errors, entity.BookImportRowError{Line: row.Line, Errors: problems})
+				continue
+			}
+			items = append(items, item)
+		}
+
+		if len(items) < len(batch) {
+			report.Skipped += len(batch)
+			continue
+		}
+
+		actions, err := s.deps.PostgresRepo.ImportBooks(items, req.DryRun)
+		if err != nil {
+			var rowErr *errmap.ImportRowError
+			if !errors.As(err, &rowErr) {
+				log.Error(errors.Wrap(err, "[Service.ImportBooks]: unable to import books"))
+				return nil, errors.Wrap(err, "[Service.ImportBooks]: unable to import books")
+			}
+
+			log.Error(errors.Wrap(err, "[Service.ImportBooks]: unable to import row"))
+			report.Errors = append(report.Errors, entity.BookImportRowError{Line: rowErr.Line, Errors: []string{"unable to import row"}})
+			report.Skipped += len(batch)
+			continue
+		}
+
+		for i, action := range actions {
+			switch action {
+			case constant.BookImportCreated:
+				report.Created++
+			case constant.BookImportUpdated:
+				report.Updated++
+			case constant.BookImportArchived:
+				report.Errors = append(report.Errors, entity.BookImportRowError{Line: items[i].Line, Errors: []string{"isbn of an archived book"}})
+				report.Skipped++
+			case constant.BookImportNoStock:
+				report.Errors = append(report.Errors, entity.BookImportRowError{Line: items[i].Line, Errors: []string{"stock must be at least 1 for a new book"}})
+				report.Skipped++
+			}
+		}
+		written = true
+	}
+
+	if written && !req.DryRun {
+		if err := s.deps.RedisRepo.Delete(cacheKeyLatestBooks); err != nil {
+			log.Error(errors.Wrap(err, "[Service.ImportBooks]: unable to delete cache"))
+		}
+	}
+
+	return report, nil
+}
+
+// bookImportItem checks a row the way a book being created is checked, telling what is wrong with it.
+// A row with an ISBN may update a book instead, so whether it needs a stock is left to the import.
+func (s *Service) bookImportItem(row entity.BookImportRow) (entity.BookImportItem, []string, error) {
+	if len(row.Errors) > 0 {
+		return entity.BookImportItem{}, row.Errors, nil
+	}
+
+	book, err := requestedBook(row.Book)
+	if errors.Is(err, errmap.ErrmapInvalidISBN) {
+		return entity.BookImportItem{}, []string{"invalid isbn"}, nil
+	}
+	if err != nil {
+		return entity.BookImportItem{}, nil, err
+	}
+
+	if book.ISBN == nil && book.Stock < 1 {
+		return entity.BookImportItem{}, []string{"stock must be at least 1"}, nil
+	}
+
+	var problems []string
+	authors, err := s.bookAuthors(row.Book.Author, row.Book.AuthorIDs)
+	if errors.Is(err, errmap.ErrmapUnknownAuthor) {
+		problems = append(problems, "unknown author id")
+	} else if err != nil {
+		return entity.BookImportItem{}, nil, errors.Wrap(err, "[Service.bookImportItem]: unable to get authors")
+	}
+
+	err = s.checkCategories(row.Book.CategoryIDs)
+	if errors.Is(err, errmap.ErrmapUnknownCategory) {
+		problems = append(problems, "unknown category id")
+	} else if err != nil {
+		return entity.BookImportItem{}, nil, errors.Wrap(err, "[Service.bookImportItem]: unable to get categories")
+	}
+
+	return entity.BookImportItem{
+		Line:        row.Line,
+		Book:        book,
+		Columns:     row.Columns,
+		Authors:     authors,
+		CategoryIDs: row.Book.CategoryIDs,
+		Subjects:    subjectTags(row.Book.Subjects),
+	}, problems, nil
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/pkg/errors"
+)
+
+// maxBookImportRows caps the rows of one import file
+const maxBookImportRows = 10000
+
+// bookImportColumns fill in a create request from the columns of an import file, named like its JSON fields
+var bookImportColumns = map[string]func(book *entity.BookCreateRequest, value string) error{
+	"title":     func(book *entity.BookCreateRequest, value string) error { book.Title = value; return nil },
+	"author":    func(book *entity.BookCreateRequest, value string) error { book.Author = value; return nil },
+	"isbn":      func(book *entity.BookCreateRequest, value string) error { book.ISBN = value; return nil },
+	"publisher": func(book *entity.BookCreateRequest, value string) error { book.Publisher = value; return nil },
+	"edition":   func(book *entity.BookCreateRequest, value string) error { book.Edition = value; return nil },
+	"language":  func(book *entity.BookCreateRequest, value string) error { book.Language = value; return nil },
+	"authorids": func(book *entity.BookCreateRequest, value string) (err error) {
+		book.AuthorIDs, err = importIDs(value)
+		return err
+	},
+	"categoryids": func(book *entity.BookCreateRequest, value string) (err error) {
+		book.CategoryIDs, err = importIDs(value)
+		return err
+	},
+	"subjects": func(book *entity.BookCreateRequest, value string) error {
+		for _, subject := range strings.Split(value, constant.BookImportListSeparator) {
+			book.Subjects = append(book.Subjects, strings.TrimSpace(subject))
+		}
+		return nil
+	},
+	"price": func(book *entity.BookCreateRequest, value string) (err error) {
+		book.Price, err = strconv.ParseFloat(value, 64)
+		return err
+	},
+	"stock": func(book *entity.BookCreateRequest, value string) error {
+		stock, err := strconv.ParseUint(value, 10, 32)
+		book.Stock = uint(stock)
+		return err
+	},
+	"publicationyear": func(book *entity.BookCreateRequest, value string) error {
+		year, err := strconv.Atoi(value)
+		book.PublicationYear = &year
+		return err
+	},
+	"pagecount": func(book *entity.BookCreateRequest, value string) error {
+		pages, err := strconv.ParseUint(value, 10, 32)
+		pageCount := uint(pages)
+		book.PageCount = &pageCount
+		return err
+	},
+}
+
+// ReadBookImport reads the rows of a CSV import file whose first line names the columns, checking each
+// row with the rules of a create request. A file that cannot be read as a whole is an error, a bad row is not.
+func ReadBookImport(r io.Reader, validate *validator.Validate) ([]entity.BookImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.Wrap(errmap.ErrmapInvalidImport, "missing header line")
+	}
+	if err != nil {
+		return nil, errors.Wrap(errmap.ErrmapInvalidImport, err.Error())
+	}
+
+	columns := make([]string, len(header))
+	seen := map[string]bool{}
+	for i, name := range header {
+		column := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, ok := bookImportColumns[column]; !ok {
+			return nil, errors.Wrapf(errmap.ErrmapInvalidImport, "unknown column %q", name)
+		}
+		if seen[column] {
+			return nil, errors.Wrapf(errmap.ErrmapInvalidImport, "repeated column %q", name)
+		}
+		seen[column] = true
+		columns[i] = column
+	}
+
+	var rows []entity.BookImportRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(errmap.ErrmapInvalidImport, err.Error())
+		}
+
+		if len(rows) == maxBookImportRows {
+			return nil, errors.Wrapf(errmap.ErrmapInvalidImport, "more than %d rows", maxBookImportRows)
+		}
+
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, bookImportRow(line, header, columns, record, validate))
+	}
+
+	return rows, nil
+}
+
+// bookImportRow turns a record of an import file into a create request and lists what is wrong with it
+func bookImportRow(line int, header, columns, record []string, validate *validator.Validate) entity.BookImportRow {
+	row := entity.BookImportRow{Line: line, Columns: columns}
+	if len(record) != len(columns) {
+		row.Errors = []string{fmt.Sprintf("expected %d columns, found %d", len(columns), len(record))}
+		return row
+	}
+
+	for i, column := range columns {
+		value := strings.TrimSpace(record[i])
+		if value == "" {
+			continue
+		}
+		if err := bookImportColumns[column](&row.Book, value); err != nil {
+			row.Errors = append(row.Errors, fmt.Sprintf("%s: invalid value %q", strings.TrimSpace(header[i]), value))
+		}
+	}
+
+	checkBookImportRow(&row, validate)
+	return row
+}
+
+// checkBookImportRow checks the book of a row with the rules of a create request, but for the fields
+// the file may leave out. The stock is left to the import, as only a row that creates a book needs one.
+func checkBookImportRow(row *entity.BookImportRow, validate *validator.Validate, optional ...string) {
+	var validationErrors validator.ValidationErrors
+	if err := validate.StructExcept(row.Book, append([]string{"Stock"}, optional...)...); errors.As(err, &validationErrors) {
+		for _, fieldError := range validationErrors {
+			row.Errors = append(row.Errors, fieldError.Error())
+		}
+	} else if err != nil {
+		row.Errors = append(row.Errors, err.Error())
+	}
+}
+
+// importIDs reads a list of IDs from a column of an import file
+func importIDs(value string) ([]uint, error) {
+	var ids []uint
+	for _, part := range strings.Split(value, constant.BookImportListSeparator) {
+		id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, nil
+}
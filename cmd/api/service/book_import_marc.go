@@ -0,0 +1,164 @@
+package service
+
+import (
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	errmap "go-library-service/internal/error_map"
+	"go-library-service/internal/marc"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/pkg/errors"
+)
+
+var (
+	marcNumber = regexp.MustCompile(`\d+(?:\.\d+)?`)
+	marcYear   = regexp.MustCompile(`\d{4}`)
+)
+
+// ReadMARCBookImport reads the records of a MARC import file and checks each with the rules of a create request.
+// The rows are numbered by record. Few records carry a price, so a book created from one without it costs 0.
+func ReadMARCBookImport(r io.Reader, format string, validate *validator.Validate) ([]entity.BookImportRow, error) {
+	read := marc.NewReader(r).Read
+	if format == constant.MARCFormatMARCXML {
+		read = marc.NewXMLReader(r).Read
+	}
+
+	var rows []entity.BookImportRow
+	for number := 1; ; number++ {
+		record, err := read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrapf(errmap.ErrmapInvalidImport, "record %d: %v", number, err)
+		}
+
+		if len(rows) == maxBookImportRows {
+			return nil, errors.Wrapf(errmap.ErrmapInvalidImport, "more than %d records", maxBookImportRows)
+		}
+
+		book := marcBook(record)
+		row := entity.BookImportRow{Line: number, Book: book, Columns: marcColumns(book)}
+		checkBookImportRow(&row, validate, "Price")
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// marcBook reads a create request from a bibliographic record. MARC says nothing of copies,
+// so a new book gets one.
+func marcBook(record marc.Record) entity.BookCreateRequest {
+	book := entity.BookCreateRequest{Stock: 1}
+
+	if titles := record.DataFields("245"); len(titles) > 0 {
+		book.Title = trimISBD(strings.TrimSpace(titles[0].Subfield('a') + " " + titles[0].Subfield('b')))
+	}
+
+	var names []string
+	for _, field := range append(record.DataFields("100"), record.DataFields("700")...) {
+		if name := trimISBD(field.Subfield('a')); name != "" {
+			names = append(names, name)
+		}
+	}
+	book.Author = strings.Join(names, "; ")
+
+	for _, field := range record.DataFields("020") {
+		number, _, _ := strings.Cut(strings.TrimSpace(field.Subfield('a')), " ")
+		if book.ISBN == "" {
+			book.ISBN = number
+		}
+		if book.Price == 0 {
+			book.Price = marcPrice(field.Subfield('c'))
+		}
+	}
+	for _, field := range record.DataFields("365") {
+		if book.Price == 0 {
+			book.Price = marcPrice(field.Subfield('b'))
+		}
+	}
+
+	// The publication statement is 264 with a second indicator of 1 since RDA, 260 before it
+	publications := record.DataFields("260")
+	for _, field := range record.DataFields("264") {
+		if field.Indicator2 == '1' {
+			publications = append([]marc.Field{field}, publications...)
+		}
+	}
+	if len(publications) > 0 {
+		book.Publisher = trimISBD(publications[0].Subfield('b'))
+		if year := marcYear.FindString(publications[0].Subfield('c')); year != "" {
+			publicationYear, _ := strconv.Atoi(year)
+			book.PublicationYear = &publicationYear
+		}
+	}
+
+	fixed := record.ControlField("008")
+	if book.PublicationYear == nil && len(fixed) >= 11 && marcYear.MatchString(fixed[7:11]) {
+		publicationYear, _ := strconv.Atoi(fixed[7:11])
+		book.PublicationYear = &publicationYear
+	}
+	if len(fixed) >= 38 {
+		book.Language = marc.LanguageTag(fixed[35:38])
+	}
+	if languages := record.DataFields("041"); book.Language == "" && len(languages) > 0 {
+		book.Language = marc.LanguageTag(languages[0].Subfield('a'))
+	}
+
+	if editions := record.DataFields("250"); len(editions) > 0 {
+		book.Edition = trimISBD(editions[0].Subfield('a'))
+	}
+
+	if extents := record.DataFields("300"); len(extents) > 0 {
+		if pages, err := strconv.ParseUint(marcNumber.FindString(extents[0].Subfield('a')), 10, 32); err == nil && pages > 0 {
+			pageCount := uint(pages)
+			book.PageCount = &pageCount
+		}
+	}
+
+	for _, field := range append(record.DataFields("650"), record.DataFields("653")...) {
+		if subject := trimISBD(field.Subfield('a')); subject != "" {
+			book.Subjects = append(book.Subjects, subject)
+		}
+	}
+
+	return book
+}
+
+// marcColumns names the fields a record gave a book, like the columns of a CSV import, so a book with its ISBN
+// keeps what the record says nothing about
+func marcColumns(book entity.BookCreateRequest) []string {
+	columns := []string{"title", "author"}
+	given := map[string]bool{
+		"price":           book.Price != 0,
+		"isbn":            book.ISBN != "",
+		"publisher":       book.Publisher != "",
+		"publicationyear": book.PublicationYear != nil,
+		"edition":         book.Edition != "",
+		"language":        book.Language != "",
+		"pagecount":       book.PageCount != nil,
+		"subjects":        len(book.Subjects) > 0,
+	}
+	for _, column := range []string{"price", "isbn", "publisher", "publicationyear", "edition", "language", "pagecount", "subjects"} {
+		if given[column] {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// trimISBD drops the punctuation catalogers end subfields with, e.g. "Dune /" or "Herbert, Frank,"
+func trimISBD(value string) string {
+	return strings.TrimSpace(strings.TrimRight(strings.TrimSpace(value), " /:;,=."))
+}
+
+// marcPrice reads the amount of a price such as "THB 350" or "$12.50"
+func marcPrice(value string) float64 {
+	price, _ := strconv.ParseFloat(marcNumber.FindString(value), 64)
+	return price
+}
@@ -0,0 +1,230 @@
+package service_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	service "go-library-service/cmd/api/service"
+	"go-library-service/cmd/api/service/mock"
+	errmap "go-library-service/internal/error_map"
+	"go-library-service/internal/marc"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Book Import Service", func() {
+	var (
+		ctrl         *gomock.Controller
+		s            *service.Service
+		postgresMock *mock.MockPostgresRepository
+		redisMock    *mock.MockRedisRepository
+		dune         entity.BookImportRow
+		neuromancer  entity.BookImportRow
+		validate     *validator.Validate
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		postgresMock = mock.NewMockPostgresRepository(ctrl)
+		redisMock = mock.NewMockRedisRepository(ctrl)
+		s = service.NewService(&service.Dependencies{
+			PostgresRepo: postgresMock,
+			RedisRepo:    redisMock,
+		}, &service.Config{})
+		validate = validator.New()
+		Expect(service.RegisterValidations(validate)).To(Succeed())
+
+		dune = entity.BookImportRow{Line: 2, Book: entity.BookCreateRequest{
+			Title: "Dune", Author: "Frank Herbert", Price: 12.5, Stock: 2, ISBN: "0-441-17271-7",
+		}}
+		neuromancer = entity.BookImportRow{Line: 3, Book: entity.BookCreateRequest{
+			Title: "Neuromancer", Author: "William Gibson", Price: 9.5, Stock: 1,
+		}}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("ReadBookImport", func() {
+		It("should read rows by the column names of the header", func() {
+			csv := "\ufeffTitle,author,price,stock,isbn,publicationYear,categoryIds,subjects\n" +
+				"Dune,Frank Herbert,12.5,2,0-441-17271-7,1965,4;7,Space opera; Ecology\n"
+
+			rows, err := service.ReadBookImport(strings.NewReader(csv), validate)
+			Expect(err).NotTo(HaveOccurred())
+
+			year := 1965
+			Expect(rows).To(Equal([]entity.BookImportRow{{
+				Line: 2,
+				Book: entity.BookCreateRequest{
+					Title:           "Dune",
+					Author:          "Frank Herbert",
+					Price:           12.5,
+					Stock:           2,
+					ISBN:            "0-441-17271-7",
+					PublicationYear: &year,
+					CategoryIDs:     []uint{4, 7},
+					Subjects:        []string{"Space opera", "Ecology"},
+				},
+				Columns: []string{"title", "author", "price", "stock", "isbn", "publicationyear", "categoryids", "subjects"},
+			}}))
+		})
+
+		It("should list the problems of each bad row", func() {
+			csv := "title,author,price,stock,isbn\n" +
+				"Dune,Frank Herbert,cheap,2,\n" +
+				",Frank Herbert,12.5,2,978-0-441-17271-0\n" +
+				"Dune,Frank Herbert\n"
+
+			rows, err := service.ReadBookImport(strings.NewReader(csv), validate)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(rows).To(HaveLen(3))
+			Expect(rows[0].Line).To(Equal(2))
+			Expect(rows[0].Errors).To(ContainElement(`price: invalid value "cheap"`))
+			Expect(rows[1].Errors).To(HaveLen(2))
+			Expect(rows[1].Errors[0]).To(ContainSubstring("'Title' failed on the 'required' tag"))
+			Expect(rows[1].Errors[1]).To(ContainSubstring("'ISBN' failed on the 'bookisbn' tag"))
+			Expect(rows[2].Errors).To(Equal([]string{"expected 5 columns, found 2"}))
+		})
+
+		It("should leave the stock of a row to the import", func() {
+			rows, err := service.ReadBookImport(strings.NewReader("isbn,title,author,price\n0-441-17271-7,Dune,Frank Herbert,14\n"), validate)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rows).To(HaveLen(1))
+			Expect(rows[0].Errors).To(BeEmpty())
+		})
+
+		It("should refuse an unknown column", func() {
+			_, err := service.ReadBookImport(strings.NewReader("title,shelf\nDune,A1\n"), validate)
+			Expect(err).To(MatchError(errmap.ErrmapInvalidImport))
+			Expect(err.Error()).To(ContainSubstring(`unknown column "shelf"`))
+		})
+
+		It("should refuse an empty file", func() {
+			_, err := service.ReadBookImport(strings.NewReader(""), validate)
+			Expect(err).To(MatchError(errmap.ErrmapInvalidImport))
+		})
+	})
+
+	Context("ReadMARCBookImport", func() {
+		It("should read a record without a price", func() {
+			record := marc.Record{Leader: "00000nam a22000007a 4500"}
+			record.AddDataField("020", ' ', ' ', marc.Subfield{Code: 'a', Value: "9780441172719"})
+			record.AddDataField("100", '1', ' ', marc.Subfield{Code: 'a', Value: "Herbert, Frank,"})
+			record.AddDataField("245", '1', '0', marc.Subfield{Code: 'a', Value: "Dune /"})
+			var file bytes.Buffer
+			Expect(marc.NewWriter(&file).Write(record)).To(Succeed())
+
+			rows, err := service.ReadMARCBookImport(&file, constant.MARCFormatMARC21, validate)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rows).To(Equal([]entity.BookImportRow{{Line: 1, Book: entity.BookCreateRequest{
+				Title:  "Dune",
+				Author: "Herbert, Frank",
+				Stock:  1,
+				ISBN:   "9780441172719",
+			}, Columns: []string{"title", "author", "isbn"}}}))
+		})
+	})
+
+	Context("ImportBooks", func() {
+		It("should report a row with the isbn of an archived book", func() {
+			postgresMock.EXPECT().ImportBooks(gomock.Any(), false).
+				Return([]string{constant.BookImportArchived, constant.BookImportCreated}, nil)
+			redisMock.EXPECT().Delete("latest_books").Return(nil)
+
+			report, err := s.ImportBooks([]entity.BookImportRow{dune, neuromancer}, entity.BookImportRequest{})
+			Expect(err).To(BeNil())
+			Expect(report.Created).To(Equal(1))
+			Expect(report.Skipped).To(Equal(1))
+			Expect(report.Errors).To(Equal([]entity.BookImportRowError{{Line: 2, Errors: []string{"isbn of an archived book"}}}))
+		})
+
+		It("should report a new book without a stock", func() {
+			dune.Book.Stock = 0
+			neuromancer.Book.Stock = 0
+			postgresMock.EXPECT().ImportBooks(gomock.Any(), false).Return([]string{constant.BookImportNoStock}, nil)
+			redisMock.EXPECT().Delete("latest_books").Return(nil)
+
+			report, err := s.ImportBooks([]entity.BookImportRow{dune, neuromancer}, entity.BookImportRequest{Mode: constant.BookImportModeBatch, BatchSize: 1})
+			Expect(err).To(BeNil())
+			Expect(report.Created).To(Equal(0))
+			Expect(report.Skipped).To(Equal(2))
+			Expect(report.Errors).To(Equal([]entity.BookImportRowError{
+				{Line: 2, Errors: []string{"stock must be at least 1 for a new book"}},
+				{Line: 3, Errors: []string{"stock must be at least 1"}},
+			}))
+		})
+
+		It("should write every row in one go", func() {
+			postgresMock.EXPECT().ImportBooks(gomock.Any(), false).
+				DoAndReturn(func(items []entity.BookImportItem, _ bool) ([]string, error) {
+					Expect(items).To(HaveLen(2))
+					Expect(*items[0].Book.ISBN).To(Equal("9780441172719"))
+					Expect(items[1].Authors).To(Equal([]entity.Author{{Name: "William Gibson"}}))
+					return []string{constant.BookImportUpdated, constant.BookImportCreated}, nil
+				})
+			redisMock.EXPECT().Delete("latest_books").Return(nil)
+
+			report, err := s.ImportBooks([]entity.BookImportRow{dune, neuromancer}, entity.BookImportRequest{})
+			Expect(err).To(BeNil())
+			Expect(report).To(Equal(&entity.BookImportReport{Rows: 2, Created: 1, Updated: 1, Errors: []entity.BookImportRowError{}}))
+		})
+
+		It("should write nothing when a row is bad", func() {
+			neuromancer.Errors = []string{"price: invalid value \"cheap\""}
+
+			report, err := s.ImportBooks([]entity.BookImportRow{dune, neuromancer}, entity.BookImportRequest{})
+			Expect(err).To(BeNil())
+			Expect(report.Skipped).To(Equal(2))
+			Expect(report.Errors).To(Equal([]entity.BookImportRowError{{Line: 3, Errors: neuromancer.Errors}}))
+		})
+
+		It("should only leave out the batch of a bad row", func() {
+			dune.Book.AuthorIDs = []uint{9}
+			postgresMock.EXPECT().GetAuthorsByIDs([]uint{9}).Return(nil, nil)
+			postgresMock.EXPECT().ImportBooks(gomock.Len(1), false).Return([]string{constant.BookImportCreated}, nil)
+			redisMock.EXPECT().Delete("latest_books").Return(nil)
+
+			report, err := s.ImportBooks([]entity.BookImportRow{dune, neuromancer}, entity.BookImportRequest{Mode: constant.BookImportModeBatch, BatchSize: 1})
+			Expect(err).To(BeNil())
+			Expect(report.Created).To(Equal(1))
+			Expect(report.Skipped).To(Equal(1))
+			Expect(report.Errors).To(Equal([]entity.BookImportRowError{{Line: 2, Errors: []string{"unknown author id"}}}))
+		})
+
+		It("should report the row a batch failed on", func() {
+			postgresMock.EXPECT().ImportBooks(gomock.Any(), false).
+				Return(nil, &errmap.ImportRowError{Line: 3, Err: errors.New("deadlock detected")})
+
+			report, err := s.ImportBooks([]entity.BookImportRow{dune, neuromancer}, entity.BookImportRequest{})
+			Expect(err).To(BeNil())
+			Expect(report.Skipped).To(Equal(2))
+			Expect(report.Errors).To(Equal([]entity.BookImportRowError{{Line: 3, Errors: []string{"unable to import row"}}}))
+		})
+
+		It("should keep the cache on a dry run", func() {
+			postgresMock.EXPECT().ImportBooks(gomock.Len(2), true).Return([]string{constant.BookImportCreated, constant.BookImportCreated}, nil)
+
+			report, err := s.ImportBooks([]entity.BookImportRow{dune, neuromancer}, entity.BookImportRequest{DryRun: true})
+			Expect(err).To(BeNil())
+			Expect(report.DryRun).To(BeTrue())
+			Expect(report.Created).To(Equal(2))
+		})
+
+		It("should return error when the import cannot run", func() {
+			postgresMock.EXPECT().ImportBooks(gomock.Any(), false).Return(nil, errors.New("connection refused"))
+
+			report, err := s.ImportBooks([]entity.BookImportRow{dune}, entity.BookImportRequest{})
+			Expect(err).To(HaveOccurred())
+			Expect(report).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"go-library-service/cmd/api/entity"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// SearchBooks lists a slice of the books matching a query with how many match in all,
+// leaving out the listing when the slice starts past the last match or holds none
+func (s *Service) SearchBooks(req entity.SearchBookRequest) (*entity.SearchBookResult, error) {
+	total, err := s.deps.PostgresRepo.CountSearchBooks(req.Query)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Service.SearchBooks]: unable to count books"))
+		return nil, errors.Wrap(err, "[Service.SearchBooks]: unable to count books")
+	}
+
+	result := &entity.SearchBookResult{Books: []entity.BookResponse{}, Total: total}
+	if req.Limit == 0 || int64(req.Offset) >= total {
+		return result, nil
+	}
+
+	books, err := s.deps.PostgresRepo.SearchBooks(req)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Service.SearchBooks]: unable to get books"))
+		return nil, errors.Wrap(err, "[Service.SearchBooks]: unable to get books")
+	}
+	s.attachCoverURLs(books)
+	result.Books = books
+
+	return result, nil
+}
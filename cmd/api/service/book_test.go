@@ -2,7 +2,10 @@ package service_test
 
 import (
 	"encoding/json"
+	"errors"
+	"time"
 
+	"go-library-service/cmd/api/constant"
 	"go-library-service/cmd/api/entity"
 	service "go-library-service/cmd/api/service"
 	"go-library-service/cmd/api/service/mock"
@@ -47,12 +50,152 @@ var _ = Describe("Book Service", func() {
 
 	Context("CreateBook", func() {
 		It("should create a book successfully", func() {
-			postgresMock.EXPECT().CreateBook(gomock.Any()).Return(nil)
+			postgresMock.EXPECT().CreateBook(gomock.Any(), []entity.Author{{Name: "Test Author"}}, nil, nil).Return(nil)
 			redisMock.EXPECT().Delete(cacheKeyLatestBooks).Return(nil)
 
 			err := s.CreateBook(bookCreateRequest)
 			Expect(err).To(BeNil())
 		})
+
+		It("should split the author line into authors", func() {
+			bookCreateRequest.Author = "Terry Pratchett & Neil Gaiman"
+			postgresMock.EXPECT().CreateBook(gomock.Any(), []entity.Author{{Name: "Terry Pratchett"}, {Name: "Neil Gaiman"}}, nil, nil).Return(nil)
+			redisMock.EXPECT().Delete(cacheKeyLatestBooks).Return(nil)
+
+			err := s.CreateBook(bookCreateRequest)
+			Expect(err).To(BeNil())
+		})
+
+		It("should credit authors by ID in the given order", func() {
+			bookCreateRequest.Author = ""
+			bookCreateRequest.AuthorIDs = []uint{2, 1}
+			neil := entity.Author{ID: 2, Name: "Neil Gaiman"}
+			terry := entity.Author{ID: 1, Name: "Terry Pratchett"}
+			postgresMock.EXPECT().GetAuthorsByIDs([]uint{2, 1}).Return([]entity.Author{terry, neil}, nil)
+			postgresMock.EXPECT().CreateBook(gomock.Any(), []entity.Author{neil, terry}, nil, nil).Return(nil)
+			redisMock.EXPECT().Delete(cacheKeyLatestBooks).Return(nil)
+
+			err := s.CreateBook(bookCreateRequest)
+			Expect(err).To(BeNil())
+		})
+
+		It("should return error when an author ID is unknown", func() {
+			bookCreateRequest.AuthorIDs = []uint{1, 9}
+			postgresMock.EXPECT().GetAuthorsByIDs([]uint{1, 9}).Return([]entity.Author{{ID: 1, Name: "Terry Pratchett"}}, nil)
+
+			err := s.CreateBook(bookCreateRequest)
+			Expect(err).To(Equal(errmap.ErrmapUnknownAuthor))
+		})
+
+		It("should file the book under its categories with tidied subjects", func() {
+			bookCreateRequest.CategoryIDs = []uint{4}
+			bookCreateRequest.Subjects = []string{" Space  opera ", "space opera", "Ecology"}
+			postgresMock.EXPECT().GetCategoriesByIDs([]uint{4}).Return([]entity.Category{{ID: 4, Name: "Science fiction"}}, nil)
+			postgresMock.EXPECT().CreateBook(gomock.Any(), gomock.Any(), []uint{4}, []string{"Space opera", "Ecology"}).Return(nil)
+			redisMock.EXPECT().Delete(cacheKeyLatestBooks).Return(nil)
+
+			err := s.CreateBook(bookCreateRequest)
+			Expect(err).To(BeNil())
+		})
+
+		It("should return error when a category ID is unknown", func() {
+			bookCreateRequest.CategoryIDs = []uint{4, 9}
+			postgresMock.EXPECT().GetCategoriesByIDs([]uint{4, 9}).Return([]entity.Category{{ID: 4, Name: "Science fiction"}}, nil)
+
+			err := s.CreateBook(bookCreateRequest)
+			Expect(err).To(Equal(errmap.ErrmapUnknownCategory))
+		})
+
+		It("should store the isbn in its ISBN-13 form", func() {
+			bookCreateRequest.ISBN = "0-306-40615-2"
+			bookCreateRequest.Publisher = " Wiley "
+			postgresMock.EXPECT().CreateBook(gomock.Any(), gomock.Any(), nil, nil).
+				DoAndReturn(func(book entity.Book, _ []entity.Author, _ []uint, _ []string) error {
+					Expect(*book.ISBN).To(Equal("9780306406157"))
+					Expect(book.Publisher).To(Equal("Wiley"))
+					return nil
+				})
+			redisMock.EXPECT().Delete(cacheKeyLatestBooks).Return(nil)
+
+			err := s.CreateBook(bookCreateRequest)
+			Expect(err).To(BeNil())
+		})
+
+		It("should leave a blank isbn unset", func() {
+			postgresMock.EXPECT().CreateBook(gomock.Any(), gomock.Any(), nil, nil).
+				DoAndReturn(func(book entity.Book, _ []entity.Author, _ []uint, _ []string) error {
+					Expect(book.ISBN).To(BeNil())
+					return nil
+				})
+			redisMock.EXPECT().Delete(cacheKeyLatestBooks).Return(nil)
+
+			err := s.CreateBook(bookCreateRequest)
+			Expect(err).To(BeNil())
+		})
+
+		It("should return conflict when another book has the isbn", func() {
+			bookCreateRequest.ISBN = "9780306406157"
+			postgresMock.EXPECT().CreateBook(gomock.Any(), gomock.Any(), nil, nil).Return(errmap.ErrmapConflict)
+
+			err := s.CreateBook(bookCreateRequest)
+			Expect(err).To(Equal(errmap.ErrmapConflict))
+		})
+	})
+
+	Context("GetBookByISBN", func() {
+		It("should look the book up by its ISBN-13 form", func() {
+			expectedBook := &entity.BookResponse{ID: 1, Title: "Test Book"}
+			postgresMock.EXPECT().GetBookByISBN("9780306406157").Return(expectedBook, nil)
+
+			book, err := s.GetBookByISBN("0-306-40615-2")
+			Expect(err).To(BeNil())
+			Expect(book).To(Equal(expectedBook))
+		})
+
+		It("should return error for an invalid isbn", func() {
+			book, err := s.GetBookByISBN("0-306-40615-3")
+			Expect(err).To(Equal(errmap.ErrmapInvalidISBN))
+			Expect(book).To(BeNil())
+		})
+
+		It("should return error when no book has the isbn", func() {
+			postgresMock.EXPECT().GetBookByISBN("9780306406157").Return(nil, errmap.ErrmapNotFound)
+
+			book, err := s.GetBookByISBN("9780306406157")
+			Expect(err).To(Equal(errmap.ErrmapNotFound))
+			Expect(book).To(BeNil())
+		})
+	})
+
+	Context("ExportBooks", func() {
+		It("should hand over each batch with the availability of its books", func() {
+			search := "dune"
+			req := entity.ListBookRequest{Search: &search}
+			postgresMock.EXPECT().EachBook(req, 500, gomock.Any()).
+				DoAndReturn(func(_ entity.ListBookRequest, _ int, fn func(books []entity.BookResponse) error) error {
+					return fn([]entity.BookResponse{{ID: 1, Title: "Dune"}, {ID: 2, Title: "Dune Messiah"}})
+				})
+			postgresMock.EXPECT().GetBookAvailability([]uint{1, 2}).
+				Return([]entity.BookAvailability{{BookID: 1, Copies: 3, Available: 1, OnLoan: 2}}, nil)
+
+			var exported []entity.BookExport
+			err := s.ExportBooks(req, func(books []entity.BookExport) error {
+				exported = append(exported, books...)
+				return nil
+			})
+			Expect(err).To(BeNil())
+			Expect(exported).To(Equal([]entity.BookExport{
+				{BookResponse: entity.BookResponse{ID: 1, Title: "Dune"}, Availability: entity.BookAvailability{BookID: 1, Copies: 3, Available: 1, OnLoan: 2}},
+				{BookResponse: entity.BookResponse{ID: 2, Title: "Dune Messiah"}},
+			}))
+		})
+
+		It("should return error when the books cannot be read", func() {
+			postgresMock.EXPECT().EachBook(entity.ListBookRequest{}, 500, gomock.Any()).Return(errors.New("connection refused"))
+
+			err := s.ExportBooks(entity.ListBookRequest{}, func([]entity.BookExport) error { return nil })
+			Expect(err).To(HaveOccurred())
+		})
 	})
 
 	Context("GetBookByID", func() {
@@ -95,7 +238,7 @@ var _ = Describe("Book Service", func() {
 			}
 
 			postgresMock.EXPECT().GetBookByID(bookID).Return(expectedBook, nil)
-			postgresMock.EXPECT().UpdateBook(gomock.Any()).Return(nil)
+			postgresMock.EXPECT().UpdateBook(gomock.Any(), []entity.Author{{Name: "Test Author"}}, nil, nil).Return(nil)
 			redisMock.EXPECT().Delete(cacheKeyLatestBooks).Return(nil)
 
 			err := s.UpdateBook(entity.BookUpdateRequest{
@@ -103,7 +246,6 @@ var _ = Describe("Book Service", func() {
 				Title:  "Test Book",
 				Author: "Test Author",
 				Price:  29.99,
-				Stock:  10,
 			})
 			Expect(err).To(BeNil())
 		})
@@ -117,30 +259,9 @@ var _ = Describe("Book Service", func() {
 				Title:  "Test Book",
 				Author: "Test Author",
 				Price:  29.99,
-				Stock:  10,
 			})
 			Expect(err).To(Equal(errmap.ErrmapNotFound))
 		})
-
-		It("should return error when invalid stock", func() {
-			bookID := uint(1)
-			postgresMock.EXPECT().GetBookByID(bookID).Return(&entity.BookResponse{
-				ID:     bookID,
-				Title:  "Test Book",
-				Author: "Test Author",
-				Price:  29.99,
-				Stock:  10,
-			}, nil)
-
-			err := s.UpdateBook(entity.BookUpdateRequest{
-				ID:     bookID,
-				Title:  "Test Book",
-				Author: "Test Author",
-				Price:  29.99,
-				Stock:  0,
-			})
-			Expect(err).To(Equal(errmap.ErrmapInvalidStock))
-		})
 	})
 
 	Context("ListBook", func() {
@@ -153,25 +274,125 @@ var _ = Describe("Book Service", func() {
 				Stock:  5,
 			}}
 
-			postgresMock.EXPECT().ListBook(gomock.Any()).Return(expectedBooks, nil)
+			postgresMock.EXPECT().ListBook(gomock.Any()).Return(expectedBooks, nil, nil)
+			postgresMock.EXPECT().CountBooks(gomock.Any()).Return(int64(1), nil)
 
-			books, err := s.ListBook(entity.ListBookRequest{
+			result, err := s.ListBook(entity.ListBookRequest{
 				Page:  1,
 				Size:  10,
 			})
 			Expect(err).To(BeNil())
-			Expect(books).To(Equal(expectedBooks))
+			Expect(result.Books).To(Equal(expectedBooks))
+			Expect(result.Suggestion).To(BeNil())
+			Expect(result.Pagination).To(Equal(entity.Pagination{Page: 1, Size: 10, Total: &[]int64{1}[0], TotalPages: &[]int64{1}[0]}))
+		})
+
+		It("should count the pages of a listing", func() {
+			req := entity.ListBookRequest{Page: 2, Size: 10}
+			postgresMock.EXPECT().ListBook(req).Return([]entity.BookResponse{{ID: 11}}, nil, nil)
+			postgresMock.EXPECT().CountBooks(req).Return(int64(21), nil)
+
+			result, err := s.ListBook(req)
+			Expect(err).To(BeNil())
+			Expect(*result.Pagination.Total).To(Equal(int64(21)))
+			Expect(*result.Pagination.TotalPages).To(Equal(int64(3)))
+		})
+
+		It("should not count a listing paged by cursor", func() {
+			cursor := ""
+			nextCursor := "next"
+			req := entity.ListBookRequest{Size: 10, Cursor: &cursor}
+			postgresMock.EXPECT().ListBook(req).Return([]entity.BookResponse{{ID: 1}}, &nextCursor, nil)
+
+			result, err := s.ListBook(req)
+			Expect(err).To(BeNil())
+			Expect(result.Pagination).To(Equal(entity.Pagination{Size: 10, NextCursor: &nextCursor}))
+		})
+
+		It("should return invalid cursor when the cursor cannot be followed", func() {
+			cursor := "stale"
+			postgresMock.EXPECT().ListBook(gomock.Any()).Return(nil, nil, errmap.ErrmapInvalidCursor)
+
+			result, err := s.ListBook(entity.ListBookRequest{Size: 10, Cursor: &cursor})
+			Expect(err).To(Equal(errmap.ErrmapInvalidCursor))
+			Expect(result).To(BeNil())
 		})
 
 		It("should return empty when no records found", func() {
-			postgresMock.EXPECT().ListBook(gomock.Any()).Return([]entity.BookResponse{}, nil)
+			postgresMock.EXPECT().ListBook(gomock.Any()).Return([]entity.BookResponse{}, nil, nil)
+			postgresMock.EXPECT().CountBooks(gomock.Any()).Return(int64(0), nil)
 
-			books, err := s.ListBook(entity.ListBookRequest{
+			result, err := s.ListBook(entity.ListBookRequest{
 				Page:  1,
 				Size:  10,
 			})
 			Expect(err).To(BeNil())
-			Expect(books).To(BeEmpty())
+			Expect(result.Books).To(BeEmpty())
+		})
+
+		It("should suggest a spelling when a search finds nothing", func() {
+			search := "rowlnig"
+			suggestion := "J.K. Rowling"
+			postgresMock.EXPECT().ListBook(gomock.Any()).Return([]entity.BookResponse{}, nil, nil)
+			postgresMock.EXPECT().CountBooks(gomock.Any()).Return(int64(0), nil)
+			postgresMock.EXPECT().SuggestBookSearch(search).Return(&suggestion, nil)
+
+			result, err := s.ListBook(entity.ListBookRequest{Page: 1, Size: 10, Search: &search})
+			Expect(err).To(BeNil())
+			Expect(*result.Suggestion).To(Equal(suggestion))
+		})
+
+		It("should suggest a spelling alongside fuzzy matches", func() {
+			search := "rowlnig"
+			suggestion := "J.K. Rowling"
+			expectedBooks := []entity.BookResponse{{ID: 1, Title: "Book", Author: "J.K. Rowling"}}
+			postgresMock.EXPECT().ListBook(gomock.Any()).Return(expectedBooks, nil, nil)
+			postgresMock.EXPECT().CountBooks(gomock.Any()).Return(int64(1), nil)
+			postgresMock.EXPECT().SuggestBookSearch(search).Return(&suggestion, nil)
+
+			result, err := s.ListBook(entity.ListBookRequest{Page: 1, Size: 10, Search: &search, Mode: constant.SearchModeFuzzy})
+			Expect(err).To(BeNil())
+			Expect(result.Books).To(Equal(expectedBooks))
+			Expect(*result.Suggestion).To(Equal(suggestion))
+		})
+
+		It("should count facets when asked", func() {
+			req := entity.ListBookRequest{Page: 1, Size: 10, Facets: true}
+			expectedFacets := &entity.BookFacets{
+				Authors:      []entity.FacetCount{{Value: "Author", Count: 1}},
+				Availability: []entity.FacetCount{{Value: constant.BookAvailable, Count: 1}},
+				PriceRanges:  []entity.PriceRangeFacet{{Min: 0, Max: &[]float64{100}[0], Count: 1}},
+			}
+			postgresMock.EXPECT().ListBook(req).Return([]entity.BookResponse{{ID: 1}}, nil, nil)
+			postgresMock.EXPECT().CountBooks(req).Return(int64(1), nil)
+			postgresMock.EXPECT().GetBookFacets(req).Return(expectedFacets, nil)
+
+			result, err := s.ListBook(req)
+			Expect(err).To(BeNil())
+			Expect(result.Facets).To(Equal(expectedFacets))
+		})
+
+		It("should return error when counting facets fails", func() {
+			req := entity.ListBookRequest{Page: 1, Size: 10, Facets: true}
+			postgresMock.EXPECT().ListBook(req).Return([]entity.BookResponse{{ID: 1}}, nil, nil)
+			postgresMock.EXPECT().CountBooks(req).Return(int64(1), nil)
+			postgresMock.EXPECT().GetBookFacets(req).Return(nil, errors.New("database error"))
+
+			result, err := s.ListBook(req)
+			Expect(err).To(HaveOccurred())
+			Expect(result).To(BeNil())
+		})
+
+		It("should still list books when the suggestion fails", func() {
+			search := "rowlnig"
+			postgresMock.EXPECT().ListBook(gomock.Any()).Return([]entity.BookResponse{}, nil, nil)
+			postgresMock.EXPECT().CountBooks(gomock.Any()).Return(int64(0), nil)
+			postgresMock.EXPECT().SuggestBookSearch(search).Return(nil, errors.New("database error"))
+
+			result, err := s.ListBook(entity.ListBookRequest{Page: 1, Size: 10, Search: &search})
+			Expect(err).To(BeNil())
+			Expect(result.Books).To(BeEmpty())
+			Expect(result.Suggestion).To(BeNil())
 		})
 	})
 
@@ -188,7 +409,7 @@ var _ = Describe("Book Service", func() {
 			cachedData, _ := json.Marshal(expectedBooks)
 			redisMock.EXPECT().Get(cacheKeyLatestBooks).Return(string(cachedData), nil)
 
-			books, err := s.ListLatestBooks()
+			books, err := s.ListLatestBooks(entity.ListLatestBookRequest{})
 			Expect(err).To(BeNil())
 			Expect(books).To(Equal(expectedBooks))
 		})
@@ -203,21 +424,139 @@ var _ = Describe("Book Service", func() {
 			}}
 
 			redisMock.EXPECT().Get(cacheKeyLatestBooks).Return("", nil)
-			postgresMock.EXPECT().ListLatestBooks().Return(expectedBooks, nil)
+			postgresMock.EXPECT().ListLatestBooks(entity.ListLatestBookRequest{}).Return(expectedBooks, nil)
 			redisMock.EXPECT().Set(cacheKeyLatestBooks, gomock.Any(), uint(3600)).Return(nil)
 
-			books, err := s.ListLatestBooks()
+			books, err := s.ListLatestBooks(entity.ListLatestBookRequest{})
 			Expect(err).To(BeNil())
 			Expect(books).To(Equal(expectedBooks))
 		})
 
 		It("should handle cache unmarshal error", func() {
 			redisMock.EXPECT().Get(cacheKeyLatestBooks).Return("invalid-json", nil)
-			postgresMock.EXPECT().ListLatestBooks().Return([]entity.BookResponse{}, nil)
+			postgresMock.EXPECT().ListLatestBooks(entity.ListLatestBookRequest{}).Return([]entity.BookResponse{}, nil)
 			redisMock.EXPECT().Set(cacheKeyLatestBooks, gomock.Any(), uint(3600)).Return(nil)
 
-			_, err := s.ListLatestBooks()
+			_, err := s.ListLatestBooks(entity.ListLatestBookRequest{})
+			Expect(err).To(BeNil())
+		})
+
+		It("should bypass the cache for books in a category", func() {
+			categoryID := uint(4)
+			req := entity.ListLatestBookRequest{CategoryID: &categoryID}
+			postgresMock.EXPECT().ListLatestBooks(req).Return([]entity.BookResponse{}, nil)
+
+			books, err := s.ListLatestBooks(req)
+			Expect(err).To(BeNil())
+			Expect(books).To(BeEmpty())
+		})
+	})
+	Context("ArchiveBook", func() {
+		It("should archive a book and clear the latest books cache", func() {
+			postgresMock.EXPECT().ArchiveBook(uint(1), gomock.Any()).Return(nil)
+			redisMock.EXPECT().Delete(cacheKeyLatestBooks).Return(nil)
+
+			err := s.ArchiveBook(1)
 			Expect(err).To(BeNil())
 		})
+
+		It("should refuse to archive a book with active loans", func() {
+			postgresMock.EXPECT().ArchiveBook(uint(1), gomock.Any()).Return(errmap.ErrmapActiveLoans)
+
+			err := s.ArchiveBook(1)
+			Expect(err).To(Equal(errmap.ErrmapActiveLoans))
+		})
+	})
+
+	Context("RestoreBook", func() {
+		It("should restore an archived book and clear the latest books cache", func() {
+			archivedAt := time.Now()
+			postgresMock.EXPECT().GetBookByID(uint(1)).Return(&entity.BookResponse{ID: 1, DeletedAt: &archivedAt}, nil)
+			postgresMock.EXPECT().RestoreBook(uint(1), gomock.Any()).Return(nil)
+			redisMock.EXPECT().Delete(cacheKeyLatestBooks).Return(nil)
+
+			err := s.RestoreBook(1)
+			Expect(err).To(BeNil())
+		})
+
+		It("should return conflict when the book is not archived", func() {
+			postgresMock.EXPECT().GetBookByID(uint(1)).Return(&entity.BookResponse{ID: 1}, nil)
+
+			err := s.RestoreBook(1)
+			Expect(err).To(Equal(errmap.ErrmapConflict))
+		})
+	})
+
+	Context("ListBookChanges", func() {
+		It("should count the whole range for the first page", func() {
+			from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+			req := entity.ListBookChangeRequest{From: &from, Size: 100}
+			next := "next"
+			postgresMock.EXPECT().ListBookChanges(req).Return([]entity.BookResponse{{ID: 1, Title: "Dune"}}, &next, nil)
+			postgresMock.EXPECT().CountBookChanges(req).Return(int64(150), nil)
+
+			result, err := s.ListBookChanges(req)
+			Expect(err).To(BeNil())
+			Expect(result.Books).To(HaveLen(1))
+			Expect(*result.Next).To(Equal("next"))
+			Expect(*result.Total).To(Equal(int64(150)))
+		})
+
+		It("should not count again for a later page", func() {
+			req := entity.ListBookChangeRequest{Size: 100, Cursor: "next"}
+			postgresMock.EXPECT().ListBookChanges(req).Return([]entity.BookResponse{{ID: 2, Title: "Dune Messiah"}}, nil, nil)
+
+			result, err := s.ListBookChanges(req)
+			Expect(err).To(BeNil())
+			Expect(result.Next).To(BeNil())
+			Expect(result.Total).To(BeNil())
+		})
+
+		It("should return invalid cursor for a cursor it did not make", func() {
+			req := entity.ListBookChangeRequest{Size: 100, Cursor: "forged"}
+			postgresMock.EXPECT().ListBookChanges(req).Return(nil, nil, errmap.ErrmapInvalidCursor)
+
+			_, err := s.ListBookChanges(req)
+			Expect(err).To(Equal(errmap.ErrmapInvalidCursor))
+		})
+	})
+
+	Context("SearchBooks", func() {
+		query := entity.BookQuery{Field: constant.BookQueryFieldTitle, Relation: constant.BookQueryRelationContains, Term: "dune"}
+
+		It("should list the slice of the matches asked for with how many match in all", func() {
+			req := entity.SearchBookRequest{Query: query, Offset: 10, Limit: 10}
+			postgresMock.EXPECT().CountSearchBooks(query).Return(int64(12), nil)
+			postgresMock.EXPECT().SearchBooks(req).Return([]entity.BookResponse{{ID: 11, Title: "Dune"}, {ID: 12, Title: "Dune Messiah"}}, nil)
+
+			result, err := s.SearchBooks(req)
+			Expect(err).To(BeNil())
+			Expect(result.Books).To(HaveLen(2))
+			Expect(result.Total).To(Equal(int64(12)))
+		})
+
+		It("should only count when the slice starts past the last match", func() {
+			postgresMock.EXPECT().CountSearchBooks(query).Return(int64(12), nil)
+
+			result, err := s.SearchBooks(entity.SearchBookRequest{Query: query, Offset: 12, Limit: 10})
+			Expect(err).To(BeNil())
+			Expect(result.Books).To(BeEmpty())
+			Expect(result.Total).To(Equal(int64(12)))
+		})
+
+		It("should only count when asked for no books", func() {
+			postgresMock.EXPECT().CountSearchBooks(query).Return(int64(12), nil)
+
+			result, err := s.SearchBooks(entity.SearchBookRequest{Query: query, Limit: 0})
+			Expect(err).To(BeNil())
+			Expect(result.Books).To(BeEmpty())
+		})
+
+		It("should return an error when counting fails", func() {
+			postgresMock.EXPECT().CountSearchBooks(query).Return(int64(0), errors.New("connection refused"))
+
+			_, err := s.SearchBooks(entity.SearchBookRequest{Query: query, Limit: 10})
+			Expect(err).To(HaveOccurred())
+		})
 	})
-})
\ No newline at end of file
+})
@@ -11,8 +11,18 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+const (
+	defaultLoanPeriod  = 14 * 24 * time.Hour
+	defaultMaxRenewals = 2
+)
 
 func (s *Service) BorrowBook(req entity.BorrowBookRequest) (*entity.BorrowHistory, error) {
+	// A pickup gone stale may put the book back in stock or pass it to this user
+	if _, err := s.expireReadyHolds(&req.BookID); err != nil {
+		log.Error(errors.Wrap(err, "[Service.BorrowBook]: unable to expire holds"))
+		return nil, errors.Wrap(err, "[Service.BorrowBook]: unable to expire holds")
+	}
+
 	book, err := s.deps.PostgresRepo.GetBookByID(req.BookID)
 	if err != nil {
 		if errors.Is(err, errmap.ErrmapNotFound) {
@@ -21,20 +31,44 @@ func (s *Service) BorrowBook(req entity.BorrowBookRequest) (*entity.BorrowHistor
 		return nil, errors.Wrap(err, "[Service.BorrowBook]: unable to get book")
 	}
 
+	if book.DeletedAt != nil {
+		return nil, errmap.ErrmapNotFound
+	}
+
 	if book.Stock < 1 {
-		return nil, errmap.ErrmapInvalidStock
+		if _, err := s.deps.PostgresRepo.GetReadyHold(req.BookID, req.UserID); err != nil {
+			if errors.Is(err, errmap.ErrmapNotFound) {
+				return nil, errmap.ErrmapInvalidStock
+			}
+			return nil, errors.Wrap(err, "[Service.BorrowBook]: unable to get ready hold")
+		}
+	}
+
+	policy, err := s.userLoanPolicy(req.UserID)
+	if err != nil {
+		return nil, err
 	}
 
+	return s.lendBook(req.UserID, req.BookID, nil, policy)
+}
+
+// lendBook records a new borrow on the loan terms of the borrower, on any available copy when copyID is nil
+func (s *Service) lendBook(userID, bookID uint, copyID *uint, policy loanTerms) (*entity.BorrowHistory, error) {
 	borrowedAt := time.Now()
+	dueAt := borrowedAt.Add(policy.LoanPeriod)
 
 	history := &entity.BorrowHistory{
-		BookID:     req.BookID,
-		UserID:     req.UserID,
+		BookID:     bookID,
+		CopyID:     copyID,
+		UserID:     userID,
 		BorrowedAt: &borrowedAt,
+		DueAt:      &dueAt,
+		FineRate:   &policy.FineRatePerDay,
 		Status:     constant.BorrowStatusBorrowed,
 	}
 
-	history, err = s.deps.PostgresRepo.BorrowBook(history)
+	// The active loans are counted with the row of the borrower locked, so concurrent borrows cannot both pass the limit
+	history, err := s.deps.PostgresRepo.BorrowBook(history, policy.MaxActiveLoans)
 	if err != nil {
 		return nil, err
 	}
@@ -57,19 +91,91 @@ func (s *Service) ReturnBook(req entity.ReturnBookRequest) error {
 		return errmap.ErrmapConflict
 	}
 
-	if history.Status != constant.BorrowStatusBorrowed {
+	if history.Status != constant.BorrowStatusBorrowed && history.Status != constant.BorrowStatusOverdue {
 		log.Error(errors.Wrap(err, "[Service.ReturnBook]: book is not borrowed"))
 		return errmap.ErrmapConflict
 	}
 
-	if err := s.deps.PostgresRepo.ReturnBook(req.HistoryID, req.BookID, time.Now()); err != nil {
-		log.Error(errors.Wrap(err, "[Service.ReturnBook]: failed to return book"))
-		return errors.Wrap(err, "[Service.ReturnBook]: failed to return book")
+	// the repository refuses to return the borrow of another book
+	history.BookID = req.BookID
+	if _, err := s.returnBorrow(history); err != nil {
+		return err
 	}
 
 	return nil
 }
 
+// returnBorrow closes an active borrow and charges late fees when it comes back after the due date
+func (s *Service) returnBorrow(history *entity.BorrowHistoryResponse) (time.Time, error) {
+	returnedAt := time.Now()
+	if err := s.deps.PostgresRepo.ReturnBook(history.ID, history.BookID, returnedAt, returnedAt.Add(s.holdPickupWindow())); err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) || errors.Is(err, errmap.ErrmapConflict) {
+			return returnedAt, err
+		}
+		log.Error(errors.Wrap(err, "[Service.returnBorrow]: failed to return book"))
+		return returnedAt, errors.Wrap(err, "[Service.returnBorrow]: failed to return book")
+	}
+
+	if history.DueAt != nil && returnedAt.After(*history.DueAt) {
+		if _, err := s.deps.PostgresRepo.AccrueLateFees(returnedAt, s.fineRatePerDay(), &history.UserID); err != nil {
+			log.Error(errors.Wrap(err, "[Service.returnBorrow]: unable to accrue late fees"))
+		}
+	}
+
+	return returnedAt, nil
+}
+
+// RenewBook extends the due date of an active borrow
+func (s *Service) RenewBook(req entity.RenewBookRequest) (*entity.BorrowHistoryResponse, error) {
+	history, err := s.deps.PostgresRepo.GetBorrowHistoryByID(req.HistoryID)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return nil, errmap.ErrmapNotFound
+		}
+		log.Error(errors.Wrap(err, "[Service.RenewBook]: unable to get borrow history"))
+		return nil, errors.Wrap(err, "[Service.RenewBook]: unable to get borrow history")
+	}
+
+	if history.UserID != req.UserID || history.BookID != req.BookID {
+		return nil, errmap.ErrmapNotFound
+	}
+
+	if history.ReturnedAt != nil ||
+		(history.Status != constant.BorrowStatusBorrowed && history.Status != constant.BorrowStatusOverdue) {
+		return nil, errmap.ErrmapConflict
+	}
+
+	policy, err := s.userLoanPolicy(history.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if history.RenewalCount >= policy.MaxRenewals {
+		return nil, errmap.ErrmapRenewalLimit
+	}
+
+	dueAt, err := s.deps.PostgresRepo.RenewBook(history.ID, time.Now(), policy.LoanPeriod, policy.MaxRenewals, s.fineRatePerDay())
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapConflict) {
+			return nil, errmap.ErrmapConflict
+		}
+		if errors.Is(err, errmap.ErrmapRenewalLimit) {
+			return nil, errmap.ErrmapRenewalLimit
+		}
+		if errors.Is(err, errmap.ErrmapHoldPending) {
+			return nil, errmap.ErrmapHoldPending
+		}
+		log.Error(errors.Wrap(err, "[Service.RenewBook]: unable to renew book"))
+		return nil, errors.Wrap(err, "[Service.RenewBook]: unable to renew book")
+	}
+
+	history.DueAt = &dueAt
+	history.RenewalCount++
+	history.Status = constant.BorrowStatusBorrowed
+
+	return history, nil
+}
+
 func (s *Service) GetBookBorrowHistory(bookID uint) ([]entity.BorrowHistoryResponse, error) {
 	histories, err := s.deps.PostgresRepo.GetBorrowHistoryByBookID(bookID)
 	if err != nil {
@@ -78,3 +184,81 @@ func (s *Service) GetBookBorrowHistory(bookID uint) ([]entity.BorrowHistoryRespo
 
 	return histories, nil
 }
+
+// ListOverdueBorrows lists a page of unreturned borrows past their due date
+func (s *Service) ListOverdueBorrows(req entity.ListOverdueBorrowRequest) (*entity.ListOverdueBorrowResult, error) {
+	now := time.Now()
+
+	if _, err := s.deps.PostgresRepo.MarkOverdueBorrowHistories(now, req.UserID); err != nil {
+		log.Error(errors.Wrap(err, "[Service.ListOverdueBorrows]: unable to mark overdue borrows"))
+		return nil, errors.Wrap(err, "[Service.ListOverdueBorrows]: unable to mark overdue borrows")
+	}
+
+	histories, nextCursor, err := s.deps.PostgresRepo.ListOverdueBorrowHistories(req, now)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapInvalidCursor) {
+			return nil, errmap.ErrmapInvalidCursor
+		}
+		log.Error(errors.Wrap(err, "[Service.ListOverdueBorrows]: unable to list overdue borrows"))
+		return nil, errors.Wrap(err, "[Service.ListOverdueBorrows]: unable to list overdue borrows")
+	}
+
+	result := &entity.ListOverdueBorrowResult{Histories: histories, Pagination: cursorPage(req.Size, nextCursor)}
+
+	if req.Cursor == nil {
+		total, err := s.deps.PostgresRepo.CountOverdueBorrowHistories(req, now)
+		if err != nil {
+			log.Error(errors.Wrap(err, "[Service.ListOverdueBorrows]: unable to count overdue borrows"))
+			return nil, errors.Wrap(err, "[Service.ListOverdueBorrows]: unable to count overdue borrows")
+		}
+		result.Pagination = numberedPage(req.Page, req.Size, total)
+	}
+
+	return result, nil
+}
+
+// ListUserLoans lists the books a user currently has out
+func (s *Service) ListUserLoans(userID uint) ([]entity.UserLoanResponse, error) {
+	if _, err := s.deps.PostgresRepo.MarkOverdueBorrowHistories(time.Now(), &userID); err != nil {
+		log.Error(errors.Wrap(err, "[Service.ListUserLoans]: unable to mark overdue borrows"))
+		return nil, errors.Wrap(err, "[Service.ListUserLoans]: unable to mark overdue borrows")
+	}
+
+	loans, err := s.deps.PostgresRepo.ListActiveLoansByUserID(userID)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Service.ListUserLoans]: unable to list active loans"))
+		return nil, errors.Wrap(err, "[Service.ListUserLoans]: unable to list active loans")
+	}
+
+	return loans, nil
+}
+
+// ListUserBorrowHistory lists a page of the borrow history of a user
+func (s *Service) ListUserBorrowHistory(req entity.ListUserBorrowHistoryRequest) (*entity.ListUserBorrowHistoryResult, error) {
+	if _, err := s.deps.PostgresRepo.MarkOverdueBorrowHistories(time.Now(), &req.UserID); err != nil {
+		log.Error(errors.Wrap(err, "[Service.ListUserBorrowHistory]: unable to mark overdue borrows"))
+		return nil, errors.Wrap(err, "[Service.ListUserBorrowHistory]: unable to mark overdue borrows")
+	}
+
+	histories, nextCursor, err := s.deps.PostgresRepo.ListBorrowHistoriesByUserID(req)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapInvalidCursor) {
+			return nil, errmap.ErrmapInvalidCursor
+		}
+		log.Error(errors.Wrap(err, "[Service.ListUserBorrowHistory]: unable to list borrow history"))
+		return nil, errors.Wrap(err, "[Service.ListUserBorrowHistory]: unable to list borrow history")
+	}
+
+	result := &entity.ListUserBorrowHistoryResult{Histories: histories, Pagination: cursorPage(req.Size, nextCursor)}
+
+	if req.Cursor == nil {
+		total, err := s.deps.PostgresRepo.CountBorrowHistoriesByUserID(req)
+		if err != nil {
+			log.Error(errors.Wrap(err, "[Service.ListUserBorrowHistory]: unable to count borrow history"))
+			return nil, errors.Wrap(err, "[Service.ListUserBorrowHistory]: unable to count borrow history")
+		}
+		result.Pagination = numberedPage(req.Page, req.Size, total)
+	}
+
+	return result, nil
+}
@@ -1,6 +1,7 @@
 package service_test
 
 import (
+	"errors"
 	"time"
 
 	"go-library-service/cmd/api/constant"
@@ -34,6 +35,19 @@ var _ = Describe("Borrow History Service", func() {
 	})
 
 	Context("BorrowBook", func() {
+		BeforeEach(func() {
+			postgresMock.EXPECT().ExpireReadyHolds(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(0), nil)
+		})
+
+		It("should return not found for an archived book", func() {
+			archivedAt := time.Now()
+			postgresMock.EXPECT().GetBookByID(uint(1)).Return(&entity.BookResponse{ID: 1, Stock: 3, DeletedAt: &archivedAt}, nil)
+
+			history, err := s.BorrowBook(entity.BorrowBookRequest{BookID: 1, UserID: 1})
+			Expect(err).To(Equal(errmap.ErrmapNotFound))
+			Expect(history).To(BeNil())
+		})
+
 		It("should borrow a book successfully", func() {
 			bookID := uint(1)
 			userID := uint(1)
@@ -48,7 +62,8 @@ var _ = Describe("Borrow History Service", func() {
 			}
 
 			postgresMock.EXPECT().GetBookByID(bookID).Return(book, nil)
-			postgresMock.EXPECT().BorrowBook(gomock.Any()).Return(&entity.BorrowHistory{
+			postgresMock.EXPECT().GetUserByID(gomock.Any()).Return(&entity.UserResponse{ID: 1, Role: constant.UserTypeUser}, nil)
+			postgresMock.EXPECT().BorrowBook(gomock.Any(), uint(5)).Return(&entity.BorrowHistory{
 				ID:     1,
 				BookID: bookID,
 				UserID: userID,
@@ -63,6 +78,66 @@ var _ = Describe("Borrow History Service", func() {
 			Expect(history.Status).To(Equal(constant.BorrowStatusBorrowed))
 		})
 
+		It("should set the due date from the configured loan period", func() {
+			bookID := uint(1)
+			loanPeriod := 7 * 24 * time.Hour
+			s = service.NewService(&service.Dependencies{
+				PostgresRepo: postgresMock,
+			}, &service.Config{LoanPeriod: loanPeriod})
+
+			postgresMock.EXPECT().GetBookByID(bookID).Return(&entity.BookResponse{ID: bookID, Stock: 1}, nil)
+			postgresMock.EXPECT().GetUserByID(gomock.Any()).Return(&entity.UserResponse{ID: 1, Role: constant.UserTypeUser}, nil)
+			postgresMock.EXPECT().BorrowBook(gomock.Any(), uint(5)).DoAndReturn(func(history *entity.BorrowHistory, _ uint) (*entity.BorrowHistory, error) {
+				return history, nil
+			})
+
+			history, err := s.BorrowBook(entity.BorrowBookRequest{BookID: bookID, UserID: 1})
+			Expect(err).To(BeNil())
+			Expect(history.DueAt).NotTo(BeNil())
+			Expect(history.DueAt.Sub(*history.BorrowedAt)).To(Equal(loanPeriod))
+		})
+
+		It("should return error when the active loan limit is reached", func() {
+			bookID := uint(1)
+			userID := uint(1)
+
+			postgresMock.EXPECT().GetBookByID(bookID).Return(&entity.BookResponse{ID: bookID, Stock: 1}, nil)
+			postgresMock.EXPECT().GetUserByID(userID).Return(&entity.UserResponse{ID: userID, Role: constant.UserTypeUser}, nil)
+			postgresMock.EXPECT().BorrowBook(gomock.Any(), uint(5)).Return(nil, &errmap.LoanLimitError{Limit: constant.LoanLimitActiveLoans, Max: 5})
+
+			_, err := s.BorrowBook(entity.BorrowBookRequest{BookID: bookID, UserID: userID})
+			Expect(errors.Is(err, errmap.ErrmapLoanLimit)).To(BeTrue())
+
+			var limitErr *errmap.LoanLimitError
+			Expect(errors.As(err, &limitErr)).To(BeTrue())
+			Expect(limitErr.Limit).To(Equal(constant.LoanLimitActiveLoans))
+			Expect(limitErr.Max).To(Equal(uint(5)))
+		})
+
+		It("should apply the loan policy of the user role, zeros included", func() {
+			bookID := uint(1)
+			userID := uint(1)
+			maxActiveLoans, loanPeriod, fineRate := uint(20), 30*24*time.Hour, 0.0
+			s = service.NewService(&service.Dependencies{
+				PostgresRepo: postgresMock,
+			}, &service.Config{
+				LoanPolicies: map[string]service.LoanPolicy{
+					constant.UserTypeStaff: {MaxActiveLoans: &maxActiveLoans, LoanPeriod: &loanPeriod, FineRatePerDay: &fineRate},
+				},
+			})
+
+			postgresMock.EXPECT().GetBookByID(bookID).Return(&entity.BookResponse{ID: bookID, Stock: 1}, nil)
+			postgresMock.EXPECT().GetUserByID(userID).Return(&entity.UserResponse{ID: userID, Role: constant.UserTypeStaff}, nil)
+			postgresMock.EXPECT().BorrowBook(gomock.Any(), uint(20)).DoAndReturn(func(history *entity.BorrowHistory, _ uint) (*entity.BorrowHistory, error) {
+				return history, nil
+			})
+
+			history, err := s.BorrowBook(entity.BorrowBookRequest{BookID: bookID, UserID: userID})
+			Expect(err).To(BeNil())
+			Expect(history.DueAt.Sub(*history.BorrowedAt)).To(Equal(30 * 24 * time.Hour))
+			Expect(*history.FineRate).To(Equal(0.0))
+		})
+
 		It("should return error when book not found", func() {
 			req := entity.BorrowBookRequest{
 				BookID: 999,
@@ -88,10 +163,27 @@ var _ = Describe("Borrow History Service", func() {
 			}
 
 			postgresMock.EXPECT().GetBookByID(bookID).Return(book, nil)
+			postgresMock.EXPECT().GetReadyHold(bookID, uint(1)).Return(nil, errmap.ErrmapNotFound)
 
 			_, err := s.BorrowBook(req)
 			Expect(err).To(Equal(errmap.ErrmapInvalidStock))
 		})
+
+		It("should borrow an out of stock book reserved by a ready hold", func() {
+			bookID := uint(1)
+			userID := uint(1)
+
+			postgresMock.EXPECT().GetBookByID(bookID).Return(&entity.BookResponse{ID: bookID, Stock: 0}, nil)
+			postgresMock.EXPECT().GetReadyHold(bookID, userID).Return(&entity.Hold{ID: 1, BookID: bookID, UserID: userID, Status: constant.HoldStatusReady}, nil)
+			postgresMock.EXPECT().GetUserByID(gomock.Any()).Return(&entity.UserResponse{ID: 1, Role: constant.UserTypeUser}, nil)
+			postgresMock.EXPECT().BorrowBook(gomock.Any(), uint(5)).DoAndReturn(func(history *entity.BorrowHistory, _ uint) (*entity.BorrowHistory, error) {
+				return history, nil
+			})
+
+			history, err := s.BorrowBook(entity.BorrowBookRequest{BookID: bookID, UserID: userID})
+			Expect(err).To(BeNil())
+			Expect(history.Status).To(Equal(constant.BorrowStatusBorrowed))
+		})
 	})
 
 	Context("ReturnBook", func() {
@@ -110,12 +202,47 @@ var _ = Describe("Borrow History Service", func() {
 			}
 
 			postgresMock.EXPECT().GetBorrowHistoryByID(historyID).Return(history, nil)
-			postgresMock.EXPECT().ReturnBook(historyID, bookID, gomock.Any()).Return(nil)
+			postgresMock.EXPECT().ReturnBook(historyID, bookID, gomock.Any(), gomock.Any()).Return(nil)
 
 			err := s.ReturnBook(req)
 			Expect(err).To(BeNil())
 		})
 
+		It("should return an overdue book successfully", func() {
+			historyID := uint(1)
+			bookID := uint(1)
+
+			postgresMock.EXPECT().GetBorrowHistoryByID(historyID).Return(&entity.BorrowHistoryResponse{
+				ID:     historyID,
+				BookID: bookID,
+				Status: constant.BorrowStatusOverdue,
+			}, nil)
+			postgresMock.EXPECT().ReturnBook(historyID, bookID, gomock.Any(), gomock.Any()).Return(nil)
+
+			err := s.ReturnBook(entity.ReturnBookRequest{HistoryID: historyID, BookID: bookID})
+			Expect(err).To(BeNil())
+		})
+
+		It("should accrue late fees when the book is returned late", func() {
+			historyID := uint(1)
+			bookID := uint(1)
+			userID := uint(3)
+			dueAt := time.Now().Add(-72 * time.Hour)
+
+			postgresMock.EXPECT().GetBorrowHistoryByID(historyID).Return(&entity.BorrowHistoryResponse{
+				ID:     historyID,
+				BookID: bookID,
+				UserID: userID,
+				DueAt:  &dueAt,
+				Status: constant.BorrowStatusOverdue,
+			}, nil)
+			postgresMock.EXPECT().ReturnBook(historyID, bookID, gomock.Any(), gomock.Any()).Return(nil)
+			postgresMock.EXPECT().AccrueLateFees(gomock.Any(), gomock.Any(), &userID).Return(int64(1), nil)
+
+			err := s.ReturnBook(entity.ReturnBookRequest{HistoryID: historyID, BookID: bookID})
+			Expect(err).To(BeNil())
+		})
+
 		It("should return error when history not found", func() {
 			historyID := uint(999)
 			req := entity.ReturnBookRequest{
@@ -150,6 +277,21 @@ var _ = Describe("Borrow History Service", func() {
 			err := s.ReturnBook(req)
 			Expect(err).To(Equal(errmap.ErrmapConflict))
 		})
+
+		It("should return error when the borrow was closed since it was read", func() {
+			historyID := uint(1)
+			bookID := uint(1)
+
+			postgresMock.EXPECT().GetBorrowHistoryByID(historyID).Return(&entity.BorrowHistoryResponse{
+				ID:     historyID,
+				BookID: bookID,
+				Status: constant.BorrowStatusBorrowed,
+			}, nil)
+			postgresMock.EXPECT().ReturnBook(historyID, bookID, gomock.Any(), gomock.Any()).Return(errmap.ErrmapConflict)
+
+			err := s.ReturnBook(entity.ReturnBookRequest{HistoryID: historyID, BookID: bookID})
+			Expect(err).To(Equal(errmap.ErrmapConflict))
+		})
 	})
 
 	Context("GetBookBorrowHistory", func() {
@@ -190,4 +332,256 @@ var _ = Describe("Borrow History Service", func() {
 			Expect(histories).To(BeEmpty())
 		})
 	})
+
+	Context("ListOverdueBorrows", func() {
+		It("should mark and list overdue borrows", func() {
+			userID := uint(2)
+			req := entity.ListOverdueBorrowRequest{Page: 1, Size: 10, UserID: &userID}
+			dueAt := time.Now().Add(-48 * time.Hour)
+			expectedHistories := []entity.BorrowHistoryResponse{
+				{ID: 1, BookID: 1, UserID: userID, DueAt: &dueAt, Status: constant.BorrowStatusOverdue},
+			}
+
+			gomock.InOrder(
+				postgresMock.EXPECT().MarkOverdueBorrowHistories(gomock.Any(), &userID).Return(int64(1), nil),
+				postgresMock.EXPECT().ListOverdueBorrowHistories(req, gomock.Any()).Return(expectedHistories, nil, nil),
+				postgresMock.EXPECT().CountOverdueBorrowHistories(req, gomock.Any()).Return(int64(1), nil),
+			)
+
+			result, err := s.ListOverdueBorrows(req)
+			Expect(err).To(BeNil())
+			Expect(result.Histories).To(Equal(expectedHistories))
+			Expect(*result.Pagination.Total).To(Equal(int64(1)))
+		})
+
+		It("should page overdue borrows by cursor without counting them", func() {
+			cursor := ""
+			nextCursor := "next"
+			req := entity.ListOverdueBorrowRequest{Size: 10, Cursor: &cursor}
+
+			postgresMock.EXPECT().MarkOverdueBorrowHistories(gomock.Any(), gomock.Nil()).Return(int64(0), nil)
+			postgresMock.EXPECT().ListOverdueBorrowHistories(req, gomock.Any()).Return([]entity.BorrowHistoryResponse{{ID: 1}}, &nextCursor, nil)
+
+			result, err := s.ListOverdueBorrows(req)
+			Expect(err).To(BeNil())
+			Expect(result.Pagination).To(Equal(entity.Pagination{Size: 10, NextCursor: &nextCursor}))
+		})
+
+		It("should return error when marking overdue borrows fails", func() {
+			req := entity.ListOverdueBorrowRequest{Page: 1, Size: 10}
+
+			postgresMock.EXPECT().MarkOverdueBorrowHistories(gomock.Any(), gomock.Any()).Return(int64(0), errors.New("db error"))
+
+			result, err := s.ListOverdueBorrows(req)
+			Expect(err).NotTo(BeNil())
+			Expect(result).To(BeNil())
+		})
+	})
+
+	Context("ListUserLoans", func() {
+		It("should mark the overdue loans of the user and list them", func() {
+			userID := uint(2)
+			dueAt := time.Now().Add(24 * time.Hour)
+			expectedLoans := []entity.UserLoanResponse{
+				{ID: 1, BookID: 1, BookTitle: "Test Book", BookAuthor: "Test Author", UserID: userID, DueAt: &dueAt, Status: constant.BorrowStatusBorrowed},
+			}
+
+			gomock.InOrder(
+				postgresMock.EXPECT().MarkOverdueBorrowHistories(gomock.Any(), &userID).Return(int64(0), nil),
+				postgresMock.EXPECT().ListActiveLoansByUserID(userID).Return(expectedLoans, nil),
+			)
+
+			loans, err := s.ListUserLoans(userID)
+			Expect(err).To(BeNil())
+			Expect(loans).To(Equal(expectedLoans))
+		})
+
+		It("should return error when listing loans fails", func() {
+			postgresMock.EXPECT().MarkOverdueBorrowHistories(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+			postgresMock.EXPECT().ListActiveLoansByUserID(uint(2)).Return(nil, errors.New("db error"))
+
+			loans, err := s.ListUserLoans(2)
+			Expect(err).NotTo(BeNil())
+			Expect(loans).To(BeNil())
+		})
+	})
+
+	Context("ListUserBorrowHistory", func() {
+		It("should list borrow history of a user", func() {
+			req := entity.ListUserBorrowHistoryRequest{Page: 1, Size: 10, Status: constant.BorrowStatusReturned, UserID: 2}
+			expectedHistories := []entity.UserLoanResponse{
+				{ID: 1, BookID: 1, BookTitle: "Test Book", UserID: 2, Status: constant.BorrowStatusReturned},
+			}
+
+			postgresMock.EXPECT().MarkOverdueBorrowHistories(gomock.Any(), &req.UserID).Return(int64(0), nil)
+			postgresMock.EXPECT().ListBorrowHistoriesByUserID(req).Return(expectedHistories, nil, nil)
+			postgresMock.EXPECT().CountBorrowHistoriesByUserID(req).Return(int64(1), nil)
+
+			result, err := s.ListUserBorrowHistory(req)
+			Expect(err).To(BeNil())
+			Expect(result.Histories).To(Equal(expectedHistories))
+			Expect(*result.Pagination.TotalPages).To(Equal(int64(1)))
+		})
+
+		It("should return invalid cursor when the cursor cannot be followed", func() {
+			cursor := "stale"
+			req := entity.ListUserBorrowHistoryRequest{Size: 10, Cursor: &cursor, UserID: 2}
+
+			postgresMock.EXPECT().MarkOverdueBorrowHistories(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+			postgresMock.EXPECT().ListBorrowHistoriesByUserID(req).Return(nil, nil, errmap.ErrmapInvalidCursor)
+
+			result, err := s.ListUserBorrowHistory(req)
+			Expect(err).To(Equal(errmap.ErrmapInvalidCursor))
+			Expect(result).To(BeNil())
+		})
+
+		It("should return error when marking overdue borrows fails", func() {
+			req := entity.ListUserBorrowHistoryRequest{Page: 1, Size: 10, UserID: 2}
+
+			postgresMock.EXPECT().MarkOverdueBorrowHistories(gomock.Any(), gomock.Any()).Return(int64(0), errors.New("db error"))
+
+			result, err := s.ListUserBorrowHistory(req)
+			Expect(err).NotTo(BeNil())
+			Expect(result).To(BeNil())
+		})
+	})
+
+	Context("RenewBook", func() {
+		var (
+			historyID uint
+			bookID    uint
+			userID    uint
+			req       entity.RenewBookRequest
+		)
+
+		BeforeEach(func() {
+			historyID = uint(1)
+			bookID = uint(1)
+			userID = uint(1)
+			req = entity.RenewBookRequest{HistoryID: historyID, BookID: bookID, UserID: userID}
+		})
+
+		It("should extend the due date from the current due date", func() {
+			dueAt := time.Now().Add(24 * time.Hour)
+			postgresMock.EXPECT().GetBorrowHistoryByID(historyID).Return(&entity.BorrowHistoryResponse{
+				ID:     historyID,
+				BookID: bookID,
+				UserID: userID,
+				DueAt:  &dueAt,
+				Status: constant.BorrowStatusBorrowed,
+			}, nil)
+			postgresMock.EXPECT().GetUserByID(userID).Return(&entity.UserResponse{ID: userID, Role: constant.UserTypeUser}, nil)
+			postgresMock.EXPECT().RenewBook(historyID, gomock.Any(), 14*24*time.Hour, uint(2), 5.0).Return(dueAt.Add(14*24*time.Hour), nil)
+
+			history, err := s.RenewBook(req)
+			Expect(err).To(BeNil())
+			Expect(history.RenewalCount).To(Equal(uint(1)))
+			Expect(*history.DueAt).To(Equal(dueAt.Add(14 * 24 * time.Hour)))
+		})
+
+		It("should renew an overdue book from now", func() {
+			dueAt := time.Now().Add(-24 * time.Hour)
+			postgresMock.EXPECT().GetBorrowHistoryByID(historyID).Return(&entity.BorrowHistoryResponse{
+				ID:     historyID,
+				BookID: bookID,
+				UserID: userID,
+				DueAt:  &dueAt,
+				Status: constant.BorrowStatusOverdue,
+			}, nil)
+			postgresMock.EXPECT().GetUserByID(userID).Return(&entity.UserResponse{ID: userID, Role: constant.UserTypeUser}, nil)
+			postgresMock.EXPECT().RenewBook(historyID, gomock.Any(), 14*24*time.Hour, uint(2), 5.0).Return(time.Now().Add(14*24*time.Hour), nil)
+
+			history, err := s.RenewBook(req)
+			Expect(err).To(BeNil())
+			Expect(history.Status).To(Equal(constant.BorrowStatusBorrowed))
+			Expect(history.DueAt.After(time.Now())).To(BeTrue())
+		})
+
+		It("should return error when renewal limit is reached", func() {
+			postgresMock.EXPECT().GetBorrowHistoryByID(historyID).Return(&entity.BorrowHistoryResponse{
+				ID:           historyID,
+				BookID:       bookID,
+				UserID:       userID,
+				RenewalCount: 2,
+				Status:       constant.BorrowStatusBorrowed,
+			}, nil)
+			postgresMock.EXPECT().GetUserByID(userID).Return(&entity.UserResponse{ID: userID, Role: constant.UserTypeUser}, nil)
+
+			_, err := s.RenewBook(req)
+			Expect(err).To(Equal(errmap.ErrmapRenewalLimit))
+		})
+
+		It("should allow no renewals when the limit is configured as zero", func() {
+			maxRenewals := uint(0)
+			s = service.NewService(&service.Dependencies{
+				PostgresRepo: postgresMock,
+			}, &service.Config{MaxRenewals: &maxRenewals})
+
+			postgresMock.EXPECT().GetBorrowHistoryByID(historyID).Return(&entity.BorrowHistoryResponse{
+				ID:     historyID,
+				BookID: bookID,
+				UserID: userID,
+				Status: constant.BorrowStatusBorrowed,
+			}, nil)
+			postgresMock.EXPECT().GetUserByID(userID).Return(&entity.UserResponse{ID: userID, Role: constant.UserTypeUser}, nil)
+
+			_, err := s.RenewBook(req)
+			Expect(err).To(Equal(errmap.ErrmapRenewalLimit))
+		})
+
+		It("should return error when a concurrent renewal used up the limit", func() {
+			postgresMock.EXPECT().GetBorrowHistoryByID(historyID).Return(&entity.BorrowHistoryResponse{
+				ID:           historyID,
+				BookID:       bookID,
+				UserID:       userID,
+				RenewalCount: 1,
+				Status:       constant.BorrowStatusBorrowed,
+			}, nil)
+			postgresMock.EXPECT().GetUserByID(userID).Return(&entity.UserResponse{ID: userID, Role: constant.UserTypeUser}, nil)
+			postgresMock.EXPECT().RenewBook(historyID, gomock.Any(), 14*24*time.Hour, uint(2), 5.0).Return(time.Time{}, errmap.ErrmapRenewalLimit)
+
+			_, err := s.RenewBook(req)
+			Expect(err).To(Equal(errmap.ErrmapRenewalLimit))
+		})
+
+		It("should return error when another user is waiting for the book", func() {
+			postgresMock.EXPECT().GetBorrowHistoryByID(historyID).Return(&entity.BorrowHistoryResponse{
+				ID:     historyID,
+				BookID: bookID,
+				UserID: userID,
+				Status: constant.BorrowStatusBorrowed,
+			}, nil)
+			postgresMock.EXPECT().GetUserByID(userID).Return(&entity.UserResponse{ID: userID, Role: constant.UserTypeUser}, nil)
+			postgresMock.EXPECT().RenewBook(historyID, gomock.Any(), 14*24*time.Hour, uint(2), 5.0).Return(time.Time{}, errmap.ErrmapHoldPending)
+
+			_, err := s.RenewBook(req)
+			Expect(err).To(Equal(errmap.ErrmapHoldPending))
+		})
+
+		It("should return error when the borrow belongs to another user", func() {
+			postgresMock.EXPECT().GetBorrowHistoryByID(historyID).Return(&entity.BorrowHistoryResponse{
+				ID:     historyID,
+				BookID: bookID,
+				UserID: 2,
+				Status: constant.BorrowStatusBorrowed,
+			}, nil)
+
+			_, err := s.RenewBook(req)
+			Expect(err).To(Equal(errmap.ErrmapNotFound))
+		})
+
+		It("should return error when book is already returned", func() {
+			returnedAt := time.Now()
+			postgresMock.EXPECT().GetBorrowHistoryByID(historyID).Return(&entity.BorrowHistoryResponse{
+				ID:         historyID,
+				BookID:     bookID,
+				UserID:     userID,
+				ReturnedAt: &returnedAt,
+				Status:     constant.BorrowStatusReturned,
+			}, nil)
+
+			_, err := s.RenewBook(req)
+			Expect(err).To(Equal(errmap.ErrmapConflict))
+		})
+	})
 })
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"go-library-service/cmd/api/entity"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// CreateCategory creates a category
+func (s *Service) CreateCategory(req entity.CategoryCreateRequest) (*entity.CategoryResponse, error) {
+	category, err := s.deps.PostgresRepo.CreateCategory(&entity.Category{
+		Name:     strings.TrimSpace(req.Name),
+		Code:     strings.TrimSpace(req.Code),
+		ParentID: req.ParentID,
+	})
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapUnknownCategory) {
+			return nil, errmap.ErrmapUnknownCategory
+		}
+		if errors.Is(err, errmap.ErrmapConflict) {
+			return nil, errmap.ErrmapConflict
+		}
+		log.Error(errors.Wrap(err, "[Service.CreateCategory]: unable to create category"))
+		return nil, errors.Wrap(err, "[Service.CreateCategory]: unable to create category")
+	}
+
+	category.Children = []entity.CategoryResponse{}
+	return category, nil
+}
+
+// ListCategories lists the category tree, each level by name
+func (s *Service) ListCategories() ([]entity.CategoryResponse, error) {
+	categories, err := s.deps.PostgresRepo.ListCategories()
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Service.ListCategories]: unable to get categories"))
+		return nil, errors.Wrap(err, "[Service.ListCategories]: unable to get categories")
+	}
+
+	return categoryTree(categories), nil
+}
+
+// UpdateCategory renames a category or moves it under another parent
+func (s *Service) UpdateCategory(req entity.CategoryUpdateRequest) error {
+	err := s.deps.PostgresRepo.UpdateCategory(entity.Category{
+		ID:       req.ID,
+		Name:     strings.TrimSpace(req.Name),
+		Code:     strings.TrimSpace(req.Code),
+		ParentID: req.ParentID,
+	}, time.Now())
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return errmap.ErrmapNotFound
+		}
+		if errors.Is(err, errmap.ErrmapUnknownCategory) {
+			return errmap.ErrmapUnknownCategory
+		}
+		if errors.Is(err, errmap.ErrmapCategoryCycle) {
+			return errmap.ErrmapCategoryCycle
+		}
+		if errors.Is(err, errmap.ErrmapConflict) {
+			return errmap.ErrmapConflict
+		}
+		log.Error(errors.Wrap(err, "[Service.UpdateCategory]: unable to update category"))
+		return errors.Wrap(err, "[Service.UpdateCategory]: unable to update category")
+	}
+
+	if err := s.deps.RedisRepo.Delete(cacheKeyLatestBooks); err != nil {
+		log.Error(errors.Wrap(err, "[Service.UpdateCategory]: unable to delete cache"))
+	}
+
+	return nil
+}
+
+// DeleteCategory deletes a category without subcategories or books
+func (s *Service) DeleteCategory(categoryID uint) error {
+	if err := s.deps.PostgresRepo.DeleteCategory(categoryID); err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return errmap.ErrmapNotFound
+		}
+		if errors.Is(err, errmap.ErrmapConflict) {
+			return errmap.ErrmapConflict
+		}
+		log.Error(errors.Wrap(err, "[Service.DeleteCategory]: unable to delete category"))
+		return errors.Wrap(err, "[Service.DeleteCategory]: unable to delete category")
+	}
+
+	return nil
+}
+
+// checkCategories makes sure every category a book is filed under exists
+func (s *Service) checkCategories(categoryIDs []uint) error {
+	if len(categoryIDs) == 0 {
+		return nil
+	}
+
+	categories, err := s.deps.PostgresRepo.GetCategoriesByIDs(categoryIDs)
+	if err != nil {
+		return err
+	}
+
+	found := map[uint]bool{}
+	for _, category := range categories {
+		found[category.ID] = true
+	}
+
+	for _, categoryID := range categoryIDs {
+		if !found[categoryID] {
+			return errmap.ErrmapUnknownCategory
+		}
+	}
+	return nil
+}
+
+// categoryTree nests categories under their parents, keeping the order they come in at each level
+func categoryTree(categories []entity.CategoryResponse) []entity.CategoryResponse {
+	children := map[uint][]entity.CategoryResponse{}
+	var roots []entity.CategoryResponse
+	for _, category := range categories {
+		if category.ParentID == nil {
+			roots = append(roots, category)
+			continue
+		}
+		children[*category.ParentID] = append(children[*category.ParentID], category)
+	}
+
+	var nest func(level []entity.CategoryResponse) []entity.CategoryResponse
+	nest = func(level []entity.CategoryResponse) []entity.CategoryResponse {
+		nested := make([]entity.CategoryResponse, len(level))
+		for i, category := range level {
+			category.Children = nest(children[category.ID])
+			nested[i] = category
+		}
+		return nested
+	}
+	return nest(roots)
+}
+
+// subjectTags tidies the subjects given for a book, dropping repeats that differ only in case.
+// Missing subjects stay nil so the book keeps the ones it has.
+func subjectTags(subjects []string) []string {
+	if subjects == nil {
+		return nil
+	}
+
+	tags := []string{}
+	seen := map[string]bool{}
+	for _, subject := range subjects {
+		subject = strings.Join(strings.Fields(subject), " ")
+		key := strings.ToLower(subject)
+		if subject == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		tags = append(tags, subject)
+	}
+	return tags
+}
@@ -0,0 +1,107 @@
+package service_test
+
+import (
+	"go-library-service/cmd/api/entity"
+	service "go-library-service/cmd/api/service"
+	"go-library-service/cmd/api/service/mock"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Category Service", func() {
+	var (
+		ctrl         *gomock.Controller
+		s            *service.Service
+		postgresMock *mock.MockPostgresRepository
+		redisMock    *mock.MockRedisRepository
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		postgresMock = mock.NewMockPostgresRepository(ctrl)
+		redisMock = mock.NewMockRedisRepository(ctrl)
+		s = service.NewService(&service.Dependencies{
+			PostgresRepo: postgresMock,
+			RedisRepo:    redisMock,
+		}, &service.Config{})
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("CreateCategory", func() {
+		It("should create a category under its parent", func() {
+			parentID := uint(1)
+			postgresMock.EXPECT().
+				CreateCategory(&entity.Category{Name: "Science fiction", Code: "813.0876", ParentID: &parentID}).
+				Return(&entity.CategoryResponse{ID: 2, Name: "Science fiction", Code: "813.0876", ParentID: &parentID}, nil)
+
+			category, err := s.CreateCategory(entity.CategoryCreateRequest{Name: " Science fiction ", Code: "813.0876", ParentID: &parentID})
+			Expect(err).To(BeNil())
+			Expect(category.ID).To(Equal(uint(2)))
+			Expect(category.Children).To(BeEmpty())
+		})
+
+		It("should return error when the parent is unknown", func() {
+			parentID := uint(9)
+			postgresMock.EXPECT().CreateCategory(gomock.Any()).Return(nil, errmap.ErrmapUnknownCategory)
+
+			category, err := s.CreateCategory(entity.CategoryCreateRequest{Name: "Science fiction", ParentID: &parentID})
+			Expect(err).To(Equal(errmap.ErrmapUnknownCategory))
+			Expect(category).To(BeNil())
+		})
+	})
+
+	Context("ListCategories", func() {
+		It("should nest categories under their parents", func() {
+			fiction, sf := uint(1), uint(2)
+			postgresMock.EXPECT().ListCategories().Return([]entity.CategoryResponse{
+				{ID: 1, Name: "Fiction"},
+				{ID: 4, Name: "Hard science fiction", ParentID: &sf},
+				{ID: 3, Name: "History"},
+				{ID: 2, Name: "Science fiction", ParentID: &fiction},
+			}, nil)
+
+			categories, err := s.ListCategories()
+			Expect(err).To(BeNil())
+			Expect(categories).To(HaveLen(2))
+			Expect(categories[0].Name).To(Equal("Fiction"))
+			Expect(categories[0].Children).To(HaveLen(1))
+			Expect(categories[0].Children[0].Children[0].Name).To(Equal("Hard science fiction"))
+			Expect(categories[0].Children[0].Children[0].Children).To(BeEmpty())
+			Expect(categories[1].Name).To(Equal("History"))
+		})
+	})
+
+	Context("UpdateCategory", func() {
+		It("should move the category and drop the latest books cache", func() {
+			parentID := uint(1)
+			postgresMock.EXPECT().UpdateCategory(entity.Category{ID: 2, Name: "Science fiction", ParentID: &parentID}, gomock.Any()).Return(nil)
+			redisMock.EXPECT().Delete("latest_books").Return(nil)
+
+			err := s.UpdateCategory(entity.CategoryUpdateRequest{ID: 2, Name: "Science fiction", ParentID: &parentID})
+			Expect(err).To(BeNil())
+		})
+
+		It("should refuse to move a category under itself", func() {
+			parentID := uint(4)
+			postgresMock.EXPECT().UpdateCategory(gomock.Any(), gomock.Any()).Return(errmap.ErrmapCategoryCycle)
+
+			err := s.UpdateCategory(entity.CategoryUpdateRequest{ID: 2, Name: "Science fiction", ParentID: &parentID})
+			Expect(err).To(Equal(errmap.ErrmapCategoryCycle))
+		})
+	})
+
+	Context("DeleteCategory", func() {
+		It("should return conflict while the category is in use", func() {
+			postgresMock.EXPECT().DeleteCategory(uint(1)).Return(errmap.ErrmapConflict)
+
+			err := s.DeleteCategory(1)
+			Expect(err).To(Equal(errmap.ErrmapConflict))
+		})
+	})
+})
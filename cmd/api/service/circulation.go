@@ -0,0 +1,201 @@
+package service
+
+import (
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// Checkout lends a scanned copy to a patron on behalf of the staff at the desk
+func (s *Service) Checkout(req entity.CheckoutRequest) (*entity.CirculationReceiptResponse, error) {
+	user, err := s.circulationPatron(req)
+	if err != nil {
+		return nil, err
+	}
+
+	bookCopy, err := s.deps.PostgresRepo.GetBookCopyByBarcode(req.Barcode)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return nil, errmap.ErrmapNotFound
+		}
+		log.Error(errors.Wrap(err, "[Service.Checkout]: unable to get book copy"))
+		return nil, errors.Wrap(err, "[Service.Checkout]: unable to get book copy")
+	}
+
+	// A copy left on the hold shelf too long goes to the next in the queue or back on the shelf
+	if bookCopy.Status == constant.CopyStatusOnHold {
+		expired, err := s.expireReadyHolds(&bookCopy.BookID)
+		if err != nil {
+			log.Error(errors.Wrap(err, "[Service.Checkout]: unable to expire holds"))
+			return nil, errors.Wrap(err, "[Service.Checkout]: unable to expire holds")
+		}
+
+		if expired > 0 {
+			if bookCopy, err = s.deps.PostgresRepo.GetBookCopyByBarcode(req.Barcode); err != nil {
+				log.Error(errors.Wrap(err, "[Service.Checkout]: unable to get book copy"))
+				return nil, errors.Wrap(err, "[Service.Checkout]: unable to get book copy")
+			}
+		}
+	}
+
+	// A copy on the hold shelf can only go to the patron it was set aside for
+	if bookCopy.Status == constant.CopyStatusOnHold {
+		hold, err := s.deps.PostgresRepo.GetReadyHold(bookCopy.BookID, user.ID)
+		if err != nil && !errors.Is(err, errmap.ErrmapNotFound) {
+			log.Error(errors.Wrap(err, "[Service.Checkout]: unable to get ready hold"))
+			return nil, errors.Wrap(err, "[Service.Checkout]: unable to get ready hold")
+		}
+		if hold == nil || hold.CopyID == nil || *hold.CopyID != bookCopy.ID {
+			return nil, errmap.ErrmapInvalidStock
+		}
+	} else if bookCopy.Status != constant.CopyStatusAvailable {
+		return nil, errmap.ErrmapInvalidStock
+	}
+
+	history, err := s.lendBook(user.ID, bookCopy.BookID, &bookCopy.ID, s.loanPolicy(user.Role))
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapInvalidStock) || errors.Is(err, errmap.ErrmapLoanLimit) {
+			return nil, err
+		}
+		log.Error(errors.Wrap(err, "[Service.Checkout]: unable to borrow book"))
+		return nil, errors.Wrap(err, "[Service.Checkout]: unable to borrow book")
+	}
+
+	return &entity.CirculationReceiptResponse{
+		HistoryID:  history.ID,
+		UserID:     user.ID,
+		UserName:   user.Name,
+		BookID:     bookCopy.BookID,
+		BookTitle:  bookCopy.BookTitle,
+		CopyID:     bookCopy.ID,
+		Barcode:    bookCopy.Barcode,
+		BorrowedAt: history.BorrowedAt,
+		DueAt:      history.DueAt,
+		Status:     history.Status,
+		CopyStatus: constant.CopyStatusOnLoan,
+	}, nil
+}
+
+// Checkin takes back a copy at the desk without the patron's borrow history ID
+func (s *Service) Checkin(req entity.CheckinRequest) (*entity.CirculationReceiptResponse, error) {
+	history, err := s.circulationLoan(req)
+	if err != nil {
+		return nil, err
+	}
+
+	returnedAt, err := s.returnBorrow(history)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := &entity.CirculationReceiptResponse{
+		HistoryID:  history.ID,
+		UserID:     history.UserID,
+		BookID:     history.BookID,
+		BorrowedAt: &history.BorrowedAt,
+		DueAt:      history.DueAt,
+		ReturnedAt: &returnedAt,
+		Status:     constant.BorrowStatusReturned,
+	}
+
+	// The receipt is best effort once the return went through
+	if user, err := s.deps.PostgresRepo.GetUserByID(history.UserID); err == nil {
+		receipt.UserName = user.Name
+	} else {
+		log.Error(errors.Wrap(err, "[Service.Checkin]: unable to get user"))
+	}
+
+	if history.CopyID != nil {
+		if bookCopy, err := s.deps.PostgresRepo.GetBookCopyByID(*history.CopyID); err == nil {
+			receipt.BookTitle = bookCopy.BookTitle
+			receipt.CopyID = bookCopy.ID
+			receipt.Barcode = bookCopy.Barcode
+			receipt.CopyStatus = bookCopy.Status
+		} else {
+			log.Error(errors.Wrap(err, "[Service.Checkin]: unable to get book copy"))
+		}
+	}
+
+	return receipt, nil
+}
+
+// AssignCardNumber gives a user a library card number
+func (s *Service) AssignCardNumber(req entity.UserCardRequest) error {
+	if err := s.deps.PostgresRepo.UpdateUserCardNumber(req.ID, req.CardNumber); err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return errmap.ErrmapNotFound
+		}
+		if errors.Is(err, errmap.ErrmapConflict) {
+			return errmap.ErrmapConflict
+		}
+		log.Error(errors.Wrap(err, "[Service.AssignCardNumber]: unable to update user"))
+		return errors.Wrap(err, "[Service.AssignCardNumber]: unable to update user")
+	}
+
+	return nil
+}
+
+// circulationPatron finds the patron of a checkout by user ID or card number
+func (s *Service) circulationPatron(req entity.CheckoutRequest) (*entity.UserResponse, error) {
+	var (
+		user *entity.UserResponse
+		err  error
+	)
+	if req.CardNumber != "" {
+		user, err = s.deps.PostgresRepo.GetUserByCardNumber(req.CardNumber)
+	} else {
+		user, err = s.deps.PostgresRepo.GetUserByID(req.UserID)
+	}
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return nil, errmap.ErrmapNotFound
+		}
+		log.Error(errors.Wrap(err, "[Service.circulationPatron]: unable to get user"))
+		return nil, errors.Wrap(err, "[Service.circulationPatron]: unable to get user")
+	}
+
+	return user, nil
+}
+
+// circulationLoan finds the active borrow of a check-in by copy barcode, or by book when a single copy is out
+func (s *Service) circulationLoan(req entity.CheckinRequest) (*entity.BorrowHistoryResponse, error) {
+	if req.Barcode != "" {
+		bookCopy, err := s.deps.PostgresRepo.GetBookCopyByBarcode(req.Barcode)
+		if err != nil {
+			if errors.Is(err, errmap.ErrmapNotFound) {
+				return nil, errmap.ErrmapNotFound
+			}
+			log.Error(errors.Wrap(err, "[Service.circulationLoan]: unable to get book copy"))
+			return nil, errors.Wrap(err, "[Service.circulationLoan]: unable to get book copy")
+		}
+
+		history, err := s.deps.PostgresRepo.GetActiveBorrowHistoryByCopyID(bookCopy.ID)
+		if err != nil {
+			if errors.Is(err, errmap.ErrmapNotFound) {
+				return nil, errmap.ErrmapConflict
+			}
+			log.Error(errors.Wrap(err, "[Service.circulationLoan]: unable to get borrow history"))
+			return nil, errors.Wrap(err, "[Service.circulationLoan]: unable to get borrow history")
+		}
+
+		return history, nil
+	}
+
+	histories, err := s.deps.PostgresRepo.ListActiveBorrowHistoriesByBookID(req.BookID)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Service.circulationLoan]: unable to list borrow histories"))
+		return nil, errors.Wrap(err, "[Service.circulationLoan]: unable to list borrow histories")
+	}
+
+	switch len(histories) {
+	case 0:
+		return nil, errmap.ErrmapConflict
+	case 1:
+		return &histories[0], nil
+	default:
+		return nil, errmap.ErrmapAmbiguousCopy
+	}
+}
@@ -0,0 +1,193 @@
+package service_test
+
+import (
+	"time"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	service "go-library-service/cmd/api/service"
+	"go-library-service/cmd/api/service/mock"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Circulation Service", func() {
+	var (
+		ctrl         *gomock.Controller
+		s            *service.Service
+		postgresMock *mock.MockPostgresRepository
+		copyID       uint
+		cardNumber   string
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		postgresMock = mock.NewMockPostgresRepository(ctrl)
+		s = service.NewService(&service.Dependencies{
+			PostgresRepo: postgresMock,
+		}, &service.Config{LoanPeriod: 7 * 24 * time.Hour})
+		copyID = uint(10)
+		cardNumber = "C-0001"
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("Checkout", func() {
+		It("should lend the scanned copy to the patron found by card number", func() {
+			postgresMock.EXPECT().GetUserByCardNumber(cardNumber).Return(&entity.UserResponse{ID: 2, Name: "Patron", Role: constant.UserTypeUser}, nil)
+			postgresMock.EXPECT().GetBookCopyByBarcode("0001").Return(&entity.BookCopyResponse{
+				ID: copyID, BookID: 1, BookTitle: "Test Book", Barcode: "0001", Status: constant.CopyStatusAvailable,
+			}, nil)
+			postgresMock.EXPECT().BorrowBook(gomock.Any(), uint(5)).DoAndReturn(func(history *entity.BorrowHistory, _ uint) (*entity.BorrowHistory, error) {
+				Expect(history.UserID).To(Equal(uint(2)))
+				Expect(history.BookID).To(Equal(uint(1)))
+				Expect(*history.CopyID).To(Equal(copyID))
+				Expect(history.DueAt.Sub(*history.BorrowedAt)).To(Equal(7 * 24 * time.Hour))
+				history.ID = 5
+				return history, nil
+			})
+
+			receipt, err := s.Checkout(entity.CheckoutRequest{CardNumber: cardNumber, Barcode: "0001"})
+			Expect(err).To(BeNil())
+			Expect(receipt.HistoryID).To(Equal(uint(5)))
+			Expect(receipt.UserName).To(Equal("Patron"))
+			Expect(receipt.BookTitle).To(Equal("Test Book"))
+			Expect(receipt.DueAt).NotTo(BeNil())
+		})
+
+		It("should return error when the patron is unknown", func() {
+			postgresMock.EXPECT().GetUserByID(uint(999)).Return(nil, errmap.ErrmapNotFound)
+
+			receipt, err := s.Checkout(entity.CheckoutRequest{UserID: 999, Barcode: "0001"})
+			Expect(err).To(Equal(errmap.ErrmapNotFound))
+			Expect(receipt).To(BeNil())
+		})
+
+		It("should return error when the copy is on loan", func() {
+			postgresMock.EXPECT().GetUserByID(uint(2)).Return(&entity.UserResponse{ID: 2, Role: constant.UserTypeUser}, nil)
+			postgresMock.EXPECT().GetBookCopyByBarcode("0001").Return(&entity.BookCopyResponse{ID: copyID, BookID: 1, Status: constant.CopyStatusOnLoan}, nil)
+
+			receipt, err := s.Checkout(entity.CheckoutRequest{UserID: 2, Barcode: "0001"})
+			Expect(err).To(Equal(errmap.ErrmapInvalidStock))
+			Expect(receipt).To(BeNil())
+		})
+
+		It("should return error when the copy is held for another patron", func() {
+			postgresMock.EXPECT().GetUserByID(uint(2)).Return(&entity.UserResponse{ID: 2, Role: constant.UserTypeUser}, nil)
+			postgresMock.EXPECT().GetBookCopyByBarcode("0001").Return(&entity.BookCopyResponse{ID: copyID, BookID: 1, Status: constant.CopyStatusOnHold}, nil)
+			postgresMock.EXPECT().ExpireReadyHolds(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(0), nil)
+			postgresMock.EXPECT().GetReadyHold(uint(1), uint(2)).Return(nil, errmap.ErrmapNotFound)
+
+			receipt, err := s.Checkout(entity.CheckoutRequest{UserID: 2, Barcode: "0001"})
+			Expect(err).To(Equal(errmap.ErrmapInvalidStock))
+			Expect(receipt).To(BeNil())
+		})
+
+		It("should lend a held copy to the patron it was set aside for", func() {
+			postgresMock.EXPECT().GetUserByID(uint(2)).Return(&entity.UserResponse{ID: 2, Role: constant.UserTypeUser}, nil)
+			postgresMock.EXPECT().GetBookCopyByBarcode("0001").Return(&entity.BookCopyResponse{ID: copyID, BookID: 1, Status: constant.CopyStatusOnHold}, nil)
+			postgresMock.EXPECT().ExpireReadyHolds(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(0), nil)
+			postgresMock.EXPECT().GetReadyHold(uint(1), uint(2)).Return(&entity.Hold{ID: 3, BookID: 1, UserID: 2, CopyID: &copyID}, nil)
+			postgresMock.EXPECT().BorrowBook(gomock.Any(), uint(5)).DoAndReturn(func(history *entity.BorrowHistory, _ uint) (*entity.BorrowHistory, error) {
+				return history, nil
+			})
+
+			receipt, err := s.Checkout(entity.CheckoutRequest{UserID: 2, Barcode: "0001"})
+			Expect(err).To(BeNil())
+			Expect(receipt.CopyID).To(Equal(copyID))
+		})
+
+		It("should lend a copy whose stale pickup expired back on the shelf", func() {
+			bookID := uint(1)
+			postgresMock.EXPECT().GetUserByID(uint(2)).Return(&entity.UserResponse{ID: 2, Role: constant.UserTypeUser}, nil)
+			gomock.InOrder(
+				postgresMock.EXPECT().GetBookCopyByBarcode("0001").Return(&entity.BookCopyResponse{ID: copyID, BookID: 1, Status: constant.CopyStatusOnHold}, nil),
+				postgresMock.EXPECT().ExpireReadyHolds(gomock.Any(), gomock.Any(), &bookID).Return(int64(1), nil),
+				postgresMock.EXPECT().GetBookCopyByBarcode("0001").Return(&entity.BookCopyResponse{ID: copyID, BookID: 1, Status: constant.CopyStatusAvailable}, nil),
+			)
+			postgresMock.EXPECT().BorrowBook(gomock.Any(), uint(5)).DoAndReturn(func(history *entity.BorrowHistory, _ uint) (*entity.BorrowHistory, error) {
+				return history, nil
+			})
+
+			receipt, err := s.Checkout(entity.CheckoutRequest{UserID: 2, Barcode: "0001"})
+			Expect(err).To(BeNil())
+			Expect(receipt.CopyID).To(Equal(copyID))
+		})
+
+		It("should return error when the loan limit is reached", func() {
+			postgresMock.EXPECT().GetUserByID(uint(2)).Return(&entity.UserResponse{ID: 2, Role: constant.UserTypeUser}, nil)
+			postgresMock.EXPECT().GetBookCopyByBarcode("0001").Return(&entity.BookCopyResponse{ID: copyID, BookID: 1, Status: constant.CopyStatusAvailable}, nil)
+			postgresMock.EXPECT().BorrowBook(gomock.Any(), uint(5)).Return(nil, &errmap.LoanLimitError{Limit: constant.LoanLimitActiveLoans, Max: 5})
+
+			receipt, err := s.Checkout(entity.CheckoutRequest{UserID: 2, Barcode: "0001"})
+			Expect(err).To(MatchError(errmap.ErrmapLoanLimit))
+			Expect(receipt).To(BeNil())
+		})
+	})
+
+	Context("Checkin", func() {
+		It("should return the loan of the scanned copy", func() {
+			dueAt := time.Now().Add(24 * time.Hour)
+			history := &entity.BorrowHistoryResponse{ID: 5, BookID: 1, CopyID: &copyID, UserID: 2, DueAt: &dueAt, Status: constant.BorrowStatusBorrowed}
+
+			postgresMock.EXPECT().GetBookCopyByBarcode("0001").Return(&entity.BookCopyResponse{ID: copyID, BookID: 1}, nil)
+			postgresMock.EXPECT().GetActiveBorrowHistoryByCopyID(copyID).Return(history, nil)
+			postgresMock.EXPECT().ReturnBook(uint(5), uint(1), gomock.Any(), gomock.Any()).Return(nil)
+			postgresMock.EXPECT().GetUserByID(uint(2)).Return(&entity.UserResponse{ID: 2, Name: "Patron"}, nil)
+			postgresMock.EXPECT().GetBookCopyByID(copyID).Return(&entity.BookCopyResponse{
+				ID: copyID, BookID: 1, BookTitle: "Test Book", Barcode: "0001", Status: constant.CopyStatusOnHold,
+			}, nil)
+
+			receipt, err := s.Checkin(entity.CheckinRequest{Barcode: "0001"})
+			Expect(err).To(BeNil())
+			Expect(receipt.Status).To(Equal(constant.BorrowStatusReturned))
+			Expect(receipt.ReturnedAt).NotTo(BeNil())
+			Expect(receipt.CopyStatus).To(Equal(constant.CopyStatusOnHold))
+		})
+
+		It("should accrue late fees when the copy comes back late", func() {
+			dueAt := time.Now().Add(-48 * time.Hour)
+			history := entity.BorrowHistoryResponse{ID: 5, BookID: 1, UserID: 2, DueAt: &dueAt, Status: constant.BorrowStatusOverdue}
+
+			postgresMock.EXPECT().ListActiveBorrowHistoriesByBookID(uint(1)).Return([]entity.BorrowHistoryResponse{history}, nil)
+			postgresMock.EXPECT().ReturnBook(uint(5), uint(1), gomock.Any(), gomock.Any()).Return(nil)
+			postgresMock.EXPECT().AccrueLateFees(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(1), nil)
+			postgresMock.EXPECT().GetUserByID(uint(2)).Return(&entity.UserResponse{ID: 2, Name: "Patron"}, nil)
+
+			receipt, err := s.Checkin(entity.CheckinRequest{BookID: 1})
+			Expect(err).To(BeNil())
+			Expect(receipt.HistoryID).To(Equal(uint(5)))
+		})
+
+		It("should return error when more than one copy of the book is on loan", func() {
+			postgresMock.EXPECT().ListActiveBorrowHistoriesByBookID(uint(1)).Return([]entity.BorrowHistoryResponse{{ID: 5}, {ID: 6}}, nil)
+
+			receipt, err := s.Checkin(entity.CheckinRequest{BookID: 1})
+			Expect(err).To(Equal(errmap.ErrmapAmbiguousCopy))
+			Expect(receipt).To(BeNil())
+		})
+
+		It("should return error when the copy is not on loan", func() {
+			postgresMock.EXPECT().GetBookCopyByBarcode("0001").Return(&entity.BookCopyResponse{ID: copyID, BookID: 1}, nil)
+			postgresMock.EXPECT().GetActiveBorrowHistoryByCopyID(copyID).Return(nil, errmap.ErrmapNotFound)
+
+			receipt, err := s.Checkin(entity.CheckinRequest{Barcode: "0001"})
+			Expect(err).To(Equal(errmap.ErrmapConflict))
+			Expect(receipt).To(BeNil())
+		})
+	})
+
+	Context("AssignCardNumber", func() {
+		It("should return error when the card number is taken", func() {
+			postgresMock.EXPECT().UpdateUserCardNumber(uint(2), cardNumber).Return(errmap.ErrmapConflict)
+
+			err := s.AssignCardNumber(entity.UserCardRequest{ID: 2, CardNumber: cardNumber})
+			Expect(err).To(Equal(errmap.ErrmapConflict))
+		})
+	})
+})
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"math"
+	"time"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultFineRatePerDay = 5.0
+)
+
+// GetUserFees accrues the late fees of a user and returns the balance with the ledger
+func (s *Service) GetUserFees(userID uint) (*entity.FeeBalanceResponse, error) {
+	if _, err := s.deps.PostgresRepo.AccrueLateFees(time.Now(), s.fineRatePerDay(), &userID); err != nil {
+		log.Error(errors.Wrap(err, "[Service.GetUserFees]: unable to accrue late fees"))
+		return nil, errors.Wrap(err, "[Service.GetUserFees]: unable to accrue late fees")
+	}
+
+	balance, err := s.deps.PostgresRepo.GetFeeBalance(userID)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Service.GetUserFees]: unable to get balance"))
+		return nil, errors.Wrap(err, "[Service.GetUserFees]: unable to get balance")
+	}
+
+	transactions, err := s.deps.PostgresRepo.ListFeeTransactionsByUserID(userID)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Service.GetUserFees]: unable to list fee transactions"))
+		return nil, errors.Wrap(err, "[Service.GetUserFees]: unable to list fee transactions")
+	}
+
+	return &entity.FeeBalanceResponse{
+		UserID:       userID,
+		Balance:      roundAmount(balance),
+		Transactions: transactions,
+	}, nil
+}
+
+// RecordFeePayment records a payment against the balance of a user
+func (s *Service) RecordFeePayment(req entity.FeePaymentRequest) error {
+	amount := roundAmount(req.Amount)
+	if amount <= 0 {
+		return errmap.ErrmapInvalidAmount
+	}
+
+	transaction := &entity.FeeTransaction{
+		UserID:          req.UserID,
+		BorrowHistoryID: req.BorrowHistoryID,
+		Type:            constant.FeeTypePayment,
+		Amount:          -amount,
+		Note:            req.Note,
+		CreatedBy:       &req.StaffID,
+	}
+
+	if err := s.deps.PostgresRepo.CreateFeeCredit(transaction); err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return errmap.ErrmapNotFound
+		}
+		if errors.Is(err, errmap.ErrmapInvalidAmount) {
+			return errmap.ErrmapInvalidAmount
+		}
+		log.Error(errors.Wrap(err, "[Service.RecordFeePayment]: unable to record payment"))
+		return errors.Wrap(err, "[Service.RecordFeePayment]: unable to record payment")
+	}
+
+	return nil
+}
+
+// WaiveFees waives part of the balance of a user
+func (s *Service) WaiveFees(req entity.FeeWaiverRequest) error {
+	amount := roundAmount(req.Amount)
+	if amount <= 0 {
+		return errmap.ErrmapInvalidAmount
+	}
+
+	transaction := &entity.FeeTransaction{
+		UserID:          req.UserID,
+		BorrowHistoryID: req.BorrowHistoryID,
+		Type:            constant.FeeTypeWaiver,
+		Amount:          -amount,
+		Note:            req.Note,
+		CreatedBy:       &req.StaffID,
+	}
+
+	if err := s.deps.PostgresRepo.CreateFeeCredit(transaction); err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return errmap.ErrmapNotFound
+		}
+		if errors.Is(err, errmap.ErrmapInvalidAmount) {
+			return errmap.ErrmapInvalidAmount
+		}
+		log.Error(errors.Wrap(err, "[Service.WaiveFees]: unable to waive fees"))
+		return errors.Wrap(err, "[Service.WaiveFees]: unable to waive fees")
+	}
+
+	return nil
+}
+
+// ChargeReplacementCost charges the borrower of a lost or damaged book its price, once per borrow
+func (s *Service) ChargeReplacementCost(req entity.ReplacementChargeRequest) (*entity.FeeTransaction, error) {
+	history, err := s.deps.PostgresRepo.GetBorrowHistoryByID(req.BorrowHistoryID)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return nil, errmap.ErrmapNotFound
+		}
+		log.Error(errors.Wrap(err, "[Service.ChargeReplacementCost]: unable to get borrow history"))
+		return nil, errors.Wrap(err, "[Service.ChargeReplacementCost]: unable to get borrow history")
+	}
+
+	transaction, err := s.replacementCharge(history, req.StaffID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The borrow is checked to be lost or damaged and not yet charged with its row locked
+	if err := s.deps.PostgresRepo.ChargeReplacementCost(transaction); err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return nil, errmap.ErrmapNotFound
+		}
+		if errors.Is(err, errmap.ErrmapConflict) {
+			return nil, errmap.ErrmapConflict
+		}
+		if errors.Is(err, errmap.ErrmapBorrowNotLost) {
+			return nil, errmap.ErrmapBorrowNotLost
+		}
+		log.Error(errors.Wrap(err, "[Service.ChargeReplacementCost]: unable to charge replacement cost"))
+		return nil, errors.Wrap(err, "[Service.ChargeReplacementCost]: unable to charge replacement cost")
+	}
+
+	return transaction, nil
+}
+
+// replacementCharge makes the charge of the price of the book of a borrow
+func (s *Service) replacementCharge(history *entity.BorrowHistoryResponse, staffID uint) (*entity.FeeTransaction, error) {
+	book, err := s.deps.PostgresRepo.GetBookByID(history.BookID)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return nil, errmap.ErrmapNotFound
+		}
+		log.Error(errors.Wrap(err, "[Service.replacementCharge]: unable to get book"))
+		return nil, errors.Wrap(err, "[Service.replacementCharge]: unable to get book")
+	}
+
+	return &entity.FeeTransaction{
+		UserID:          history.UserID,
+		BorrowHistoryID: &history.ID,
+		Type:            constant.FeeTypeReplacement,
+		Amount:          roundAmount(book.Price),
+		Note:            "replacement cost of " + book.Title,
+		CreatedBy:       &staffID,
+	}, nil
+}
+
+// AccrueLateFees charges the late fees of every late borrow
+func (s *Service) AccrueLateFees() (*entity.AccrueLateFeesResponse, error) {
+	charged, err := s.deps.PostgresRepo.AccrueLateFees(time.Now(), s.fineRatePerDay(), nil)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Service.AccrueLateFees]: unable to accrue late fees"))
+		return nil, errors.Wrap(err, "[Service.AccrueLateFees]: unable to accrue late fees")
+	}
+
+	return &entity.AccrueLateFeesResponse{Charged: charged}, nil
+}
+
+// roundAmount rounds an amount of money to two decimals
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
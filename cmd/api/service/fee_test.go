@@ -0,0 +1,148 @@
+package service_test
+
+import (
+	"time"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	service "go-library-service/cmd/api/service"
+	"go-library-service/cmd/api/service/mock"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Fee Service", func() {
+	var (
+		ctrl         *gomock.Controller
+		s            *service.Service
+		postgresMock *mock.MockPostgresRepository
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		postgresMock = mock.NewMockPostgresRepository(ctrl)
+		s = service.NewService(&service.Dependencies{
+			PostgresRepo: postgresMock,
+		}, &service.Config{FineRatePerDay: 10})
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("GetUserFees", func() {
+		It("should accrue late fees before returning the balance", func() {
+			userID := uint(2)
+			transactions := []entity.FeeTransactionResponse{
+				{ID: 1, UserID: userID, Type: constant.FeeTypeLateFee, Amount: 30},
+			}
+
+			gomock.InOrder(
+				postgresMock.EXPECT().AccrueLateFees(gomock.Any(), 10.0, &userID).Return(int64(1), nil),
+				postgresMock.EXPECT().GetFeeBalance(userID).Return(30.0, nil),
+				postgresMock.EXPECT().ListFeeTransactionsByUserID(userID).Return(transactions, nil),
+			)
+
+			fees, err := s.GetUserFees(userID)
+			Expect(err).To(BeNil())
+			Expect(fees.Balance).To(Equal(30.0))
+			Expect(fees.Transactions).To(Equal(transactions))
+		})
+	})
+
+	Context("RecordFeePayment", func() {
+		It("should record a payment as a negative ledger entry", func() {
+			postgresMock.EXPECT().CreateFeeCredit(gomock.Any()).DoAndReturn(func(transaction *entity.FeeTransaction) error {
+				Expect(transaction.UserID).To(Equal(uint(2)))
+				Expect(transaction.Type).To(Equal(constant.FeeTypePayment))
+				Expect(transaction.Amount).To(Equal(-12.5))
+				Expect(*transaction.CreatedBy).To(Equal(uint(1)))
+				return nil
+			})
+
+			err := s.RecordFeePayment(entity.FeePaymentRequest{UserID: 2, Amount: 12.499, StaffID: 1})
+			Expect(err).To(BeNil())
+		})
+
+		It("should return error when payment exceeds the balance", func() {
+			postgresMock.EXPECT().CreateFeeCredit(gomock.Any()).Return(errmap.ErrmapInvalidAmount)
+
+			err := s.RecordFeePayment(entity.FeePaymentRequest{UserID: 2, Amount: 100, StaffID: 1})
+			Expect(err).To(Equal(errmap.ErrmapInvalidAmount))
+		})
+
+		It("should return error for non positive amount", func() {
+			err := s.RecordFeePayment(entity.FeePaymentRequest{UserID: 2, Amount: 0.001, StaffID: 1})
+			Expect(err).To(Equal(errmap.ErrmapInvalidAmount))
+		})
+	})
+
+	Context("WaiveFees", func() {
+		It("should record a waiver as a negative ledger entry", func() {
+			historyID := uint(5)
+			postgresMock.EXPECT().CreateFeeCredit(gomock.Any()).DoAndReturn(func(transaction *entity.FeeTransaction) error {
+				Expect(transaction.Type).To(Equal(constant.FeeTypeWaiver))
+				Expect(transaction.Amount).To(Equal(-20.0))
+				Expect(*transaction.BorrowHistoryID).To(Equal(historyID))
+				return nil
+			})
+
+			err := s.WaiveFees(entity.FeeWaiverRequest{UserID: 2, BorrowHistoryID: &historyID, Amount: 20, Note: "first offence", StaffID: 1})
+			Expect(err).To(BeNil())
+		})
+
+		It("should return error when user not found", func() {
+			postgresMock.EXPECT().CreateFeeCredit(gomock.Any()).Return(errmap.ErrmapNotFound)
+
+			err := s.WaiveFees(entity.FeeWaiverRequest{UserID: 999, Amount: 20, Note: "note", StaffID: 1})
+			Expect(err).To(Equal(errmap.ErrmapNotFound))
+		})
+	})
+
+	Context("ChargeReplacementCost", func() {
+		It("should charge the price of the book", func() {
+			history := &entity.BorrowHistoryResponse{ID: 3, BookID: 4, UserID: 2, BorrowedAt: time.Now()}
+
+			postgresMock.EXPECT().GetBorrowHistoryByID(uint(3)).Return(history, nil)
+			postgresMock.EXPECT().GetBookByID(uint(4)).Return(&entity.BookResponse{ID: 4, Title: "Book", Price: 350}, nil)
+			postgresMock.EXPECT().ChargeReplacementCost(gomock.Any()).Return(nil)
+
+			transaction, err := s.ChargeReplacementCost(entity.ReplacementChargeRequest{BorrowHistoryID: 3, StaffID: 1})
+			Expect(err).To(BeNil())
+			Expect(transaction.UserID).To(Equal(uint(2)))
+			Expect(transaction.Amount).To(Equal(350.0))
+			Expect(transaction.Type).To(Equal(constant.FeeTypeReplacement))
+		})
+
+		It("should return error when already charged", func() {
+			postgresMock.EXPECT().GetBorrowHistoryByID(uint(3)).Return(&entity.BorrowHistoryResponse{ID: 3, BookID: 4, UserID: 2}, nil)
+			postgresMock.EXPECT().GetBookByID(uint(4)).Return(&entity.BookResponse{ID: 4, Title: "Book", Price: 350}, nil)
+			postgresMock.EXPECT().ChargeReplacementCost(gomock.Any()).Return(errmap.ErrmapConflict)
+
+			_, err := s.ChargeReplacementCost(entity.ReplacementChargeRequest{BorrowHistoryID: 3, StaffID: 1})
+			Expect(err).To(Equal(errmap.ErrmapConflict))
+		})
+
+		It("should return error when the book is not lost or damaged", func() {
+			postgresMock.EXPECT().GetBorrowHistoryByID(uint(3)).Return(&entity.BorrowHistoryResponse{ID: 3, BookID: 4, UserID: 2}, nil)
+			postgresMock.EXPECT().GetBookByID(uint(4)).Return(&entity.BookResponse{ID: 4, Title: "Book", Price: 350}, nil)
+			postgresMock.EXPECT().ChargeReplacementCost(gomock.Any()).Return(errmap.ErrmapBorrowNotLost)
+
+			_, err := s.ChargeReplacementCost(entity.ReplacementChargeRequest{BorrowHistoryID: 3, StaffID: 1})
+			Expect(err).To(Equal(errmap.ErrmapBorrowNotLost))
+		})
+	})
+
+	Context("AccrueLateFees", func() {
+		It("should accrue late fees of everyone", func() {
+			postgresMock.EXPECT().AccrueLateFees(gomock.Any(), 10.0, nil).Return(int64(4), nil)
+
+			result, err := s.AccrueLateFees()
+			Expect(err).To(BeNil())
+			Expect(result.Charged).To(Equal(int64(4)))
+		})
+	})
+})
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"time"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultHoldPickupWindow = 3 * 24 * time.Hour
+)
+
+// PlaceHold queues a user for an out of stock book
+func (s *Service) PlaceHold(req entity.PlaceHoldRequest) (*entity.HoldResponse, error) {
+	// A pickup gone stale may put the book back in stock
+	if _, err := s.expireReadyHolds(&req.BookID); err != nil {
+		log.Error(errors.Wrap(err, "[Service.PlaceHold]: unable to expire holds"))
+		return nil, errors.Wrap(err, "[Service.PlaceHold]: unable to expire holds")
+	}
+
+	book, err := s.deps.PostgresRepo.GetBookByID(req.BookID)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return nil, errmap.ErrmapNotFound
+		}
+		log.Error(errors.Wrap(err, "[Service.PlaceHold]: unable to get book"))
+		return nil, errors.Wrap(err, "[Service.PlaceHold]: unable to get book")
+	}
+
+	if book.DeletedAt != nil {
+		return nil, errmap.ErrmapNotFound
+	}
+
+	if book.Stock > 0 {
+		return nil, errmap.ErrmapBookAvailable
+	}
+
+	hold, err := s.deps.PostgresRepo.CreateHold(&entity.Hold{
+		BookID: req.BookID,
+		UserID: req.UserID,
+		Status: constant.HoldStatusWaiting,
+	})
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapConflict) {
+			return nil, errmap.ErrmapConflict
+		}
+		if errors.Is(err, errmap.ErrmapBookAvailable) {
+			return nil, errmap.ErrmapBookAvailable
+		}
+		log.Error(errors.Wrap(err, "[Service.PlaceHold]: unable to create hold"))
+		return nil, errors.Wrap(err, "[Service.PlaceHold]: unable to create hold")
+	}
+
+	return hold, nil
+}
+
+// ListUserHolds expires stale pickups and lists the active holds of a user
+func (s *Service) ListUserHolds(userID uint) ([]entity.HoldResponse, error) {
+	if _, err := s.expireReadyHolds(nil); err != nil {
+		log.Error(errors.Wrap(err, "[Service.ListUserHolds]: unable to expire holds"))
+		return nil, errors.Wrap(err, "[Service.ListUserHolds]: unable to expire holds")
+	}
+
+	holds, err := s.deps.PostgresRepo.ListHoldsByUserID(userID)
+	if err != nil {
+		log.Error(errors.Wrap(err, "[Service.ListUserHolds]: unable to list holds"))
+		return nil, errors.Wrap(err, "[Service.ListUserHolds]: unable to list holds")
+	}
+
+	return holds, nil
+}
+
+// expireReadyHolds expires the holds of a book, or of every book when bookID is nil, that were not picked
+// up in time, so its copies go to the next in the queue or back on the shelf
+func (s *Service) expireReadyHolds(bookID *uint) (int64, error) {
+	now := time.Now()
+	return s.deps.PostgresRepo.ExpireReadyHolds(now, now.Add(s.holdPickupWindow()), bookID)
+}
+
+// CancelHold cancels an active hold of a user
+func (s *Service) CancelHold(req entity.CancelHoldRequest) error {
+	hold, err := s.deps.PostgresRepo.GetHoldByID(req.HoldID)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return errmap.ErrmapNotFound
+		}
+		log.Error(errors.Wrap(err, "[Service.CancelHold]: unable to get hold"))
+		return errors.Wrap(err, "[Service.CancelHold]: unable to get hold")
+	}
+
+	if hold.UserID != req.UserID {
+		return errmap.ErrmapNotFound
+	}
+
+	if hold.Status != constant.HoldStatusWaiting && hold.Status != constant.HoldStatusReady {
+		return errmap.ErrmapConflict
+	}
+
+	now := time.Now()
+	if err := s.deps.PostgresRepo.CancelHold(hold.ID, now, now.Add(s.holdPickupWindow())); err != nil {
+		if errors.Is(err, errmap.ErrmapConflict) {
+			return errmap.ErrmapConflict
+		}
+		log.Error(errors.Wrap(err, "[Service.CancelHold]: unable to cancel hold"))
+		return errors.Wrap(err, "[Service.CancelHold]: unable to cancel hold")
+	}
+
+	return nil
+}
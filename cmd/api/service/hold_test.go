@@ -0,0 +1,125 @@
+package service_test
+
+import (
+	"time"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	service "go-library-service/cmd/api/service"
+	"go-library-service/cmd/api/service/mock"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Hold Service", func() {
+	var (
+		ctrl         *gomock.Controller
+		s            *service.Service
+		postgresMock *mock.MockPostgresRepository
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		postgresMock = mock.NewMockPostgresRepository(ctrl)
+		s = service.NewService(&service.Dependencies{
+			PostgresRepo: postgresMock,
+		}, &service.Config{HoldPickupWindow: 24 * time.Hour})
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("PlaceHold", func() {
+		BeforeEach(func() {
+			postgresMock.EXPECT().ExpireReadyHolds(gomock.Any(), gomock.Any(), gomock.Not(gomock.Nil())).Return(int64(0), nil)
+		})
+
+		It("should place a waiting hold on an out of stock book", func() {
+			bookID := uint(1)
+			userID := uint(2)
+
+			postgresMock.EXPECT().GetBookByID(bookID).Return(&entity.BookResponse{ID: bookID, Stock: 0}, nil)
+			postgresMock.EXPECT().CreateHold(gomock.Any()).DoAndReturn(func(hold *entity.Hold) (*entity.HoldResponse, error) {
+				Expect(hold.BookID).To(Equal(bookID))
+				Expect(hold.UserID).To(Equal(userID))
+				Expect(hold.Status).To(Equal(constant.HoldStatusWaiting))
+				return &entity.HoldResponse{ID: 1, BookID: bookID, UserID: userID, Status: hold.Status, QueuePosition: 3}, nil
+			})
+
+			hold, err := s.PlaceHold(entity.PlaceHoldRequest{BookID: bookID, UserID: userID})
+			Expect(err).To(BeNil())
+			Expect(hold.QueuePosition).To(Equal(3))
+		})
+
+		It("should return error when book is in stock", func() {
+			postgresMock.EXPECT().GetBookByID(uint(1)).Return(&entity.BookResponse{ID: 1, Stock: 2}, nil)
+
+			_, err := s.PlaceHold(entity.PlaceHoldRequest{BookID: 1, UserID: 2})
+			Expect(err).To(Equal(errmap.ErrmapBookAvailable))
+		})
+
+		It("should return error when book not found", func() {
+			postgresMock.EXPECT().GetBookByID(uint(999)).Return(nil, errmap.ErrmapNotFound)
+
+			_, err := s.PlaceHold(entity.PlaceHoldRequest{BookID: 999, UserID: 2})
+			Expect(err).To(Equal(errmap.ErrmapNotFound))
+		})
+
+		It("should return error when user already holds the book", func() {
+			postgresMock.EXPECT().GetBookByID(uint(1)).Return(&entity.BookResponse{ID: 1, Stock: 0}, nil)
+			postgresMock.EXPECT().CreateHold(gomock.Any()).Return(nil, errmap.ErrmapConflict)
+
+			_, err := s.PlaceHold(entity.PlaceHoldRequest{BookID: 1, UserID: 2})
+			Expect(err).To(Equal(errmap.ErrmapConflict))
+		})
+	})
+
+	Context("ListUserHolds", func() {
+		It("should expire stale pickups before listing holds", func() {
+			userID := uint(2)
+			expectedHolds := []entity.HoldResponse{
+				{ID: 1, BookID: 1, UserID: userID, Status: constant.HoldStatusWaiting, QueuePosition: 1},
+			}
+
+			gomock.InOrder(
+				postgresMock.EXPECT().ExpireReadyHolds(gomock.Any(), gomock.Any(), nil).DoAndReturn(func(now, pickupExpiresAt time.Time, _ *uint) (int64, error) {
+					Expect(pickupExpiresAt.Sub(now)).To(Equal(24 * time.Hour))
+					return 0, nil
+				}),
+				postgresMock.EXPECT().ListHoldsByUserID(userID).Return(expectedHolds, nil),
+			)
+
+			holds, err := s.ListUserHolds(userID)
+			Expect(err).To(BeNil())
+			Expect(holds).To(Equal(expectedHolds))
+		})
+	})
+
+	Context("CancelHold", func() {
+		It("should cancel an active hold", func() {
+			postgresMock.EXPECT().GetHoldByID(uint(1)).Return(&entity.Hold{ID: 1, BookID: 1, UserID: 2, Status: constant.HoldStatusReady}, nil)
+			postgresMock.EXPECT().CancelHold(uint(1), gomock.Any(), gomock.Any()).Return(nil)
+
+			err := s.CancelHold(entity.CancelHoldRequest{HoldID: 1, UserID: 2})
+			Expect(err).To(BeNil())
+		})
+
+		It("should return error when hold belongs to another user", func() {
+			postgresMock.EXPECT().GetHoldByID(uint(1)).Return(&entity.Hold{ID: 1, BookID: 1, UserID: 3, Status: constant.HoldStatusWaiting}, nil)
+
+			err := s.CancelHold(entity.CancelHoldRequest{HoldID: 1, UserID: 2})
+			Expect(err).To(Equal(errmap.ErrmapNotFound))
+		})
+
+		It("should return error when hold is no longer active", func() {
+			postgresMock.EXPECT().GetHoldByID(uint(1)).Return(&entity.Hold{ID: 1, BookID: 1, UserID: 2, Status: constant.HoldStatusFulfilled}, nil)
+
+			err := s.CancelHold(entity.CancelHoldRequest{HoldID: 1, UserID: 2})
+			Expect(err).To(Equal(errmap.ErrmapConflict))
+		})
+	})
+})
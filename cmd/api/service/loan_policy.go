@@ -0,0 +1,82 @@
+package service
+
+import (
+	"time"
+
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultMaxActiveLoans = 5
+)
+
+// LoanPolicy overrides the circulation limits of Config for a user role, nil fields fall back to the defaults
+type LoanPolicy struct {
+	MaxActiveLoans *uint
+	LoanPeriod     *time.Duration
+	MaxRenewals    *uint
+	FineRatePerDay *float64
+}
+
+// loanTerms are the circulation limits applied to a borrower
+type loanTerms struct {
+	MaxActiveLoans uint
+	LoanPeriod     time.Duration
+	MaxRenewals    uint
+	FineRatePerDay float64
+}
+
+// maxActiveLoans returns the configured active loan limit or the default one
+func (s *Service) maxActiveLoans() uint {
+	if s.conf.MaxActiveLoans > 0 {
+		return s.conf.MaxActiveLoans
+	}
+	return defaultMaxActiveLoans
+}
+
+// loanPolicy resolves the loan terms of a user role
+func (s *Service) loanPolicy(role string) loanTerms {
+	terms := loanTerms{
+		MaxActiveLoans: s.maxActiveLoans(),
+		LoanPeriod:     s.loanPeriod(),
+		MaxRenewals:    s.maxRenewals(),
+		FineRatePerDay: s.fineRatePerDay(),
+	}
+
+	override, ok := s.conf.LoanPolicies[role]
+	if !ok {
+		return terms
+	}
+
+	if override.MaxActiveLoans != nil {
+		terms.MaxActiveLoans = *override.MaxActiveLoans
+	}
+	if override.LoanPeriod != nil {
+		terms.LoanPeriod = *override.LoanPeriod
+	}
+	if override.MaxRenewals != nil {
+		terms.MaxRenewals = *override.MaxRenewals
+	}
+	if override.FineRatePerDay != nil {
+		terms.FineRatePerDay = *override.FineRatePerDay
+	}
+
+	return terms
+}
+
+// userLoanPolicy looks up the role of a user and resolves its loan terms
+func (s *Service) userLoanPolicy(userID uint) (loanTerms, error) {
+	user, err := s.deps.PostgresRepo.GetUserByID(userID)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return loanTerms{}, errmap.ErrmapNotFound
+		}
+		log.Error(errors.Wrap(err, "[Service.userLoanPolicy]: unable to get user"))
+		return loanTerms{}, errors.Wrap(err, "[Service.userLoanPolicy]: unable to get user")
+	}
+
+	return s.loanPolicy(user.Role), nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"time"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// MarkBookLost closes an active borrow as lost, optionally billing the replacement cost
+func (s *Service) MarkBookLost(req entity.LostBookRequest) (*entity.BorrowHistoryResponse, error) {
+	history, err := s.activeBorrowHistory(req.HistoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	replacement, err := s.requestedReplacementCharge(history, req.ChargeReplacement, req.StaffID)
+	if err != nil {
+		return nil, errors.Wrap(err, "[Service.MarkBookLost]: unable to bill the patron")
+	}
+
+	lostAt := time.Now()
+	if err := s.deps.PostgresRepo.MarkBorrowLost(history.ID, lostAt, replacement); err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) || errors.Is(err, errmap.ErrmapConflict) {
+			return nil, err
+		}
+		log.Error(errors.Wrap(err, "[Service.MarkBookLost]: unable to mark book lost"))
+		return nil, errors.Wrap(err, "[Service.MarkBookLost]: unable to mark book lost")
+	}
+
+	history.Status = constant.BorrowStatusLost
+	history.LostAt = &lostAt
+
+	s.accrueClosedBorrow(history, lostAt)
+
+	return history, nil
+}
+
+// ReturnBookDamaged closes an active borrow whose book came back damaged, optionally billing the replacement cost
+func (s *Service) ReturnBookDamaged(req entity.DamagedBookRequest) (*entity.BorrowHistoryResponse, error) {
+	history, err := s.activeBorrowHistory(req.HistoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	replacement, err := s.requestedReplacementCharge(history, req.ChargeReplacement, req.StaffID)
+	if err != nil {
+		return nil, errors.Wrap(err, "[Service.ReturnBookDamaged]: unable to bill the patron")
+	}
+
+	returnedAt := time.Now()
+	if err := s.deps.PostgresRepo.ReturnBookDamaged(history.ID, returnedAt, replacement); err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) || errors.Is(err, errmap.ErrmapConflict) {
+			return nil, err
+		}
+		log.Error(errors.Wrap(err, "[Service.ReturnBookDamaged]: unable to return book"))
+		return nil, errors.Wrap(err, "[Service.ReturnBookDamaged]: unable to return book")
+	}
+
+	history.Status = constant.BorrowStatusDamaged
+	history.ReturnedAt = &returnedAt
+
+	s.accrueClosedBorrow(history, returnedAt)
+
+	return history, nil
+}
+
+// MarkBookFound returns a lost book that turned up and waives the replacement cost still owed for it
+func (s *Service) MarkBookFound(req entity.FoundBookRequest) (*entity.BorrowHistoryResponse, error) {
+	history, err := s.deps.PostgresRepo.GetBorrowHistoryByID(req.HistoryID)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return nil, errmap.ErrmapNotFound
+		}
+		log.Error(errors.Wrap(err, "[Service.MarkBookFound]: unable to get borrow history"))
+		return nil, errors.Wrap(err, "[Service.MarkBookFound]: unable to get borrow history")
+	}
+
+	if history.Status != constant.BorrowStatusLost {
+		return nil, errmap.ErrmapConflict
+	}
+
+	// Only what is still owed on the replacement is waived, a replacement already paid is refunded at the desk
+	waiver := &entity.FeeTransaction{
+		UserID:          history.UserID,
+		BorrowHistoryID: &history.ID,
+		Type:            constant.FeeTypeWaiver,
+		Note:            "lost book found",
+		CreatedBy:       &req.StaffID,
+	}
+
+	foundAt := time.Now()
+	if err := s.deps.PostgresRepo.MarkBorrowFound(history.ID, foundAt, foundAt.Add(s.holdPickupWindow()), waiver); err != nil {
+		if errors.Is(err, errmap.ErrmapConflict) {
+			return nil, errmap.ErrmapConflict
+		}
+		log.Error(errors.Wrap(err, "[Service.MarkBookFound]: unable to mark book found"))
+		return nil, errors.Wrap(err, "[Service.MarkBookFound]: unable to mark book found")
+	}
+
+	history.Status = constant.BorrowStatusReturned
+	history.ReturnedAt = &foundAt
+
+	return history, nil
+}
+
+// activeBorrowHistory gets a borrow that has not been returned yet
+func (s *Service) activeBorrowHistory(historyID uint) (*entity.BorrowHistoryResponse, error) {
+	history, err := s.deps.PostgresRepo.GetBorrowHistoryByID(historyID)
+	if err != nil {
+		if errors.Is(err, errmap.ErrmapNotFound) {
+			return nil, errmap.ErrmapNotFound
+		}
+		log.Error(errors.Wrap(err, "[Service.activeBorrowHistory]: unable to get borrow history"))
+		return nil, errors.Wrap(err, "[Service.activeBorrowHistory]: unable to get borrow history")
+	}
+
+	if history.ReturnedAt != nil ||
+		(history.Status != constant.BorrowStatusBorrowed && history.Status != constant.BorrowStatusOverdue) {
+		return nil, errmap.ErrmapConflict
+	}
+
+	return history, nil
+}
+
+// requestedReplacementCharge makes the replacement charge of a borrow when staff asked for it to be billed
+func (s *Service) requestedReplacementCharge(history *entity.BorrowHistoryResponse, chargeReplacement bool, staffID uint) (*entity.FeeTransaction, error) {
+	if !chargeReplacement {
+		return nil, nil
+	}
+	return s.replacementCharge(history, staffID)
+}
+
+// accrueClosedBorrow settles the late fee of a borrow that ended late
+func (s *Service) accrueClosedBorrow(history *entity.BorrowHistoryResponse, closedAt time.Time) {
+	if history.DueAt == nil || !closedAt.After(*history.DueAt) {
+		return
+	}
+
+	if _, err := s.deps.PostgresRepo.AccrueLateFees(closedAt, s.fineRatePerDay(), &history.UserID); err != nil {
+		log.Error(errors.Wrap(err, "[Service.accrueClosedBorrow]: unable to accrue late fees"))
+	}
+}
@@ -0,0 +1,118 @@
+package service_test
+
+import (
+	"time"
+
+	"go-library-service/cmd/api/constant"
+	"go-library-service/cmd/api/entity"
+	service "go-library-service/cmd/api/service"
+	"go-library-service/cmd/api/service/mock"
+	errmap "go-library-service/internal/error_map"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Lost Item Service", func() {
+	var (
+		ctrl         *gomock.Controller
+		s            *service.Service
+		postgresMock *mock.MockPostgresRepository
+		historyID    uint
+		staffID      uint
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		postgresMock = mock.NewMockPostgresRepository(ctrl)
+		s = service.NewService(&service.Dependencies{
+			PostgresRepo: postgresMock,
+		}, &service.Config{})
+		historyID = uint(5)
+		staffID = uint(9)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("MarkBookLost", func() {
+		It("should mark the borrow lost and charge the replacement cost", func() {
+			dueAt := time.Now().Add(-48 * time.Hour)
+			history := &entity.BorrowHistoryResponse{ID: historyID, BookID: 1, UserID: 2, DueAt: &dueAt, Status: constant.BorrowStatusOverdue}
+
+			postgresMock.EXPECT().GetBorrowHistoryByID(historyID).Return(history, nil)
+			postgresMock.EXPECT().GetBookByID(uint(1)).Return(&entity.BookResponse{ID: 1, Title: "Test Book", Price: 250}, nil)
+			postgresMock.EXPECT().MarkBorrowLost(historyID, gomock.Any(), gomock.Any()).DoAndReturn(func(_ uint, _ time.Time, transaction *entity.FeeTransaction) error {
+				Expect(transaction.Type).To(Equal(constant.FeeTypeReplacement))
+				Expect(transaction.Amount).To(Equal(250.0))
+				Expect(*transaction.BorrowHistoryID).To(Equal(historyID))
+				Expect(*transaction.CreatedBy).To(Equal(staffID))
+				return nil
+			})
+			postgresMock.EXPECT().AccrueLateFees(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(1), nil)
+
+			lost, err := s.MarkBookLost(entity.LostBookRequest{HistoryID: historyID, ChargeReplacement: true, StaffID: staffID})
+			Expect(err).To(BeNil())
+			Expect(lost.Status).To(Equal(constant.BorrowStatusLost))
+			Expect(lost.LostAt).NotTo(BeNil())
+		})
+
+		It("should return error when the book was already returned", func() {
+			returnedAt := time.Now()
+			postgresMock.EXPECT().GetBorrowHistoryByID(historyID).Return(&entity.BorrowHistoryResponse{
+				ID: historyID, ReturnedAt: &returnedAt, Status: constant.BorrowStatusReturned,
+			}, nil)
+
+			lost, err := s.MarkBookLost(entity.LostBookRequest{HistoryID: historyID})
+			Expect(err).To(Equal(errmap.ErrmapConflict))
+			Expect(lost).To(BeNil())
+		})
+	})
+
+	Context("ReturnBookDamaged", func() {
+		It("should return the book damaged without billing", func() {
+			dueAt := time.Now().Add(24 * time.Hour)
+			postgresMock.EXPECT().GetBorrowHistoryByID(historyID).Return(&entity.BorrowHistoryResponse{
+				ID: historyID, BookID: 1, UserID: 2, DueAt: &dueAt, Status: constant.BorrowStatusBorrowed,
+			}, nil)
+			postgresMock.EXPECT().ReturnBookDamaged(historyID, gomock.Any(), nil).Return(nil)
+
+			damaged, err := s.ReturnBookDamaged(entity.DamagedBookRequest{HistoryID: historyID})
+			Expect(err).To(BeNil())
+			Expect(damaged.Status).To(Equal(constant.BorrowStatusDamaged))
+			Expect(damaged.ReturnedAt).NotTo(BeNil())
+		})
+	})
+
+	Context("MarkBookFound", func() {
+		It("should waive the replacement cost still owed", func() {
+			postgresMock.EXPECT().GetBorrowHistoryByID(historyID).Return(&entity.BorrowHistoryResponse{
+				ID: historyID, BookID: 1, UserID: 2, Status: constant.BorrowStatusLost,
+			}, nil)
+			postgresMock.EXPECT().MarkBorrowFound(historyID, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ uint, _, _ time.Time, waiver *entity.FeeTransaction) error {
+					Expect(waiver.UserID).To(Equal(uint(2)))
+					Expect(waiver.Type).To(Equal(constant.FeeTypeWaiver))
+					Expect(*waiver.BorrowHistoryID).To(Equal(historyID))
+					Expect(*waiver.CreatedBy).To(Equal(staffID))
+					return nil
+				})
+
+			found, err := s.MarkBookFound(entity.FoundBookRequest{HistoryID: historyID, StaffID: staffID})
+			Expect(err).To(BeNil())
+			Expect(found.Status).To(Equal(constant.BorrowStatusReturned))
+		})
+
+		It("should return error when the book is not lost", func() {
+			postgresMock.EXPECT().GetBorrowHistoryByID(historyID).Return(&entity.BorrowHistoryResponse{
+				ID: historyID, Status: constant.BorrowStatusBorrowed,
+			}, nil)
+
+			found, err := s.MarkBookFound(entity.FoundBookRequest{HistoryID: historyID})
+			Expect(err).To(Equal(errmap.ErrmapConflict))
+			Expect(found).To(BeNil())
+		})
+	})
+})
@@ -35,195 +35,1054 @@ func (m *MockPostgresRepository) EXPECT() *MockPostgresRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AccrueLateFees mocks base method.
+func (m *MockPostgresRepository) AccrueLateFees(now time.Time, ratePerDay float64, userID *uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccrueLateFees", now, ratePerDay, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccrueLateFees indicates an expected call of AccrueLateFees.
+func (mr *MockPostgresRepositoryMockRecorder) AccrueLateFees(now, ratePerDay, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccrueLateFees", reflect.TypeOf((*MockPostgresRepository)(nil).AccrueLateFees), now, ratePerDay, userID)
+}
+
+// ArchiveBook mocks base method.
+func (m *MockPostgresRepository) ArchiveBook(bookID uint, archivedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveBook", bookID, archivedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ArchiveBook indicates an expected call of ArchiveBook.
+func (mr *MockPostgresRepositoryMockRecorder) ArchiveBook(bookID, archivedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveBook", reflect.TypeOf((*MockPostgresRepository)(nil).ArchiveBook), bookID, archivedAt)
+}
+
 // BorrowBook mocks base method.
-func (m *MockPostgresRepository) BorrowBook(history *entity.BorrowHistory) (*entity.BorrowHistory, error) {
+func (m *MockPostgresRepository) BorrowBook(history *entity.BorrowHistory, maxActiveLoans uint) (*entity.BorrowHistory, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "BorrowBook", history)
+	ret := m.ctrl.Call(m, "BorrowBook", history, maxActiveLoans)
 	ret0, _ := ret[0].(*entity.BorrowHistory)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// BorrowBook indicates an expected call of BorrowBook.
-func (mr *MockPostgresRepositoryMockRecorder) BorrowBook(history interface{}) *gomock.Call {
+// BorrowBook indicates an expected call of BorrowBook.
+func (mr *MockPostgresRepositoryMockRecorder) BorrowBook(history, maxActiveLoans interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BorrowBook", reflect.TypeOf((*MockPostgresRepository)(nil).BorrowBook), history, maxActiveLoans)
+}
+
+// CancelHold mocks base method.
+func (m *MockPostgresRepository) CancelHold(holdID uint, now, pickupExpiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelHold", holdID, now, pickupExpiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelHold indicates an expected call of CancelHold.
+func (mr *MockPostgresRepositoryMockRecorder) CancelHold(holdID, now, pickupExpiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelHold", reflect.TypeOf((*MockPostgresRepository)(nil).CancelHold), holdID, now, pickupExpiresAt)
+}
+
+// ChargeReplacementCost mocks base method.
+func (m *MockPostgresRepository) ChargeReplacementCost(charge *entity.FeeTransaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChargeReplacementCost", charge)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChargeReplacementCost indicates an expected call of ChargeReplacementCost.
+func (mr *MockPostgresRepositoryMockRecorder) ChargeReplacementCost(charge interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChargeReplacementCost", reflect.TypeOf((*MockPostgresRepository)(nil).ChargeReplacementCost), charge)
+}
+
+// CountAuthors mocks base method.
+func (m *MockPostgresRepository) CountAuthors(req entity.ListAuthorRequest) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAuthors", req)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAuthors indicates an expected call of CountAuthors.
+func (mr *MockPostgresRepositoryMockRecorder) CountAuthors(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAuthors", reflect.TypeOf((*MockPostgresRepository)(nil).CountAuthors), req)
+}
+
+// CountBookChanges mocks base method.
+func (m *MockPostgresRepository) CountBookChanges(req entity.ListBookChangeRequest) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountBookChanges", req)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountBookChanges indicates an expected call of CountBookChanges.
+func (mr *MockPostgresRepositoryMockRecorder) CountBookChanges(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountBookChanges", reflect.TypeOf((*MockPostgresRepository)(nil).CountBookChanges), req)
+}
+
+// CountBooks mocks base method.
+func (m *MockPostgresRepository) CountBooks(req entity.ListBookRequest) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountBooks", req)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountBooks indicates an expected call of CountBooks.
+func (mr *MockPostgresRepositoryMockRecorder) CountBooks(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountBooks", reflect.TypeOf((*MockPostgresRepository)(nil).CountBooks), req)
+}
+
+// CountBorrowHistoriesByUserID mocks base method.
+func (m *MockPostgresRepository) CountBorrowHistoriesByUserID(req entity.ListUserBorrowHistoryRequest) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountBorrowHistoriesByUserID", req)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountBorrowHistoriesByUserID indicates an expected call of CountBorrowHistoriesByUserID.
+func (mr *MockPostgresRepositoryMockRecorder) CountBorrowHistoriesByUserID(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountBorrowHistoriesByUserID", reflect.TypeOf((*MockPostgresRepository)(nil).CountBorrowHistoriesByUserID), req)
+}
+
+// CountOverdueBorrowHistories mocks base method.
+func (m *MockPostgresRepository) CountOverdueBorrowHistories(req entity.ListOverdueBorrowRequest, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountOverdueBorrowHistories", req, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOverdueBorrowHistories indicates an expected call of CountOverdueBorrowHistories.
+func (mr *MockPostgresRepositoryMockRecorder) CountOverdueBorrowHistories(req, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOverdueBorrowHistories", reflect.TypeOf((*MockPostgresRepository)(nil).CountOverdueBorrowHistories), req, now)
+}
+
+// CountSearchBooks mocks base method.
+func (m *MockPostgresRepository) CountSearchBooks(query entity.BookQuery) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSearchBooks", query)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSearchBooks indicates an expected call of CountSearchBooks.
+func (mr *MockPostgresRepositoryMockRecorder) CountSearchBooks(query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSearchBooks", reflect.TypeOf((*MockPostgresRepository)(nil).CountSearchBooks), query)
+}
+
+// CreateAuthor mocks base method.
+func (m *MockPostgresRepository) CreateAuthor(author *entity.Author) (*entity.AuthorResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuthor", author)
+	ret0, _ := ret[0].(*entity.AuthorResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuthor indicates an expected call of CreateAuthor.
+func (mr *MockPostgresRepositoryMockRecorder) CreateAuthor(author interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuthor", reflect.TypeOf((*MockPostgresRepository)(nil).CreateAuthor), author)
+}
+
+// CreateBook mocks base method.
+func (m *MockPostgresRepository) CreateBook(book entity.Book, authors []entity.Author, categoryIDs []uint, subjects []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBook", book, authors, categoryIDs, subjects)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBook indicates an expected call of CreateBook.
+func (mr *MockPostgresRepositoryMockRecorder) CreateBook(book, authors, categoryIDs, subjects interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBook", reflect.TypeOf((*MockPostgresRepository)(nil).CreateBook), book, authors, categoryIDs, subjects)
+}
+
+// CreateBookCopy mocks base method.
+func (m *MockPostgresRepository) CreateBookCopy(bookCopy *entity.BookCopy, now, pickupExpiresAt time.Time) (*entity.BookCopyResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBookCopy", bookCopy, now, pickupExpiresAt)
+	ret0, _ := ret[0].(*entity.BookCopyResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBookCopy indicates an expected call of CreateBookCopy.
+func (mr *MockPostgresRepositoryMockRecorder) CreateBookCopy(bookCopy, now, pickupExpiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBookCopy", reflect.TypeOf((*MockPostgresRepository)(nil).CreateBookCopy), bookCopy, now, pickupExpiresAt)
+}
+
+// CreateCategory mocks base method.
+func (m *MockPostgresRepository) CreateCategory(category *entity.Category) (*entity.CategoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCategory", category)
+	ret0, _ := ret[0].(*entity.CategoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCategory indicates an expected call of CreateCategory.
+func (mr *MockPostgresRepositoryMockRecorder) CreateCategory(category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCategory", reflect.TypeOf((*MockPostgresRepository)(nil).CreateCategory), category)
+}
+
+// CreateFeeCredit mocks base method.
+func (m *MockPostgresRepository) CreateFeeCredit(transaction *entity.FeeTransaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFeeCredit", transaction)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateFeeCredit indicates an expected call of CreateFeeCredit.
+func (mr *MockPostgresRepositoryMockRecorder) CreateFeeCredit(transaction interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFeeCredit", reflect.TypeOf((*MockPostgresRepository)(nil).CreateFeeCredit), transaction)
+}
+
+// CreateHold mocks base method.
+func (m *MockPostgresRepository) CreateHold(hold *entity.Hold) (*entity.HoldResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHold", hold)
+	ret0, _ := ret[0].(*entity.HoldResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHold indicates an expected call of CreateHold.
+func (mr *MockPostgresRepositoryMockRecorder) CreateHold(hold interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHold", reflect.TypeOf((*MockPostgresRepository)(nil).CreateHold), hold)
+}
+
+// CreateUser mocks base method.
+func (m *MockPostgresRepository) CreateUser(user entity.User) (*uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", user)
+	ret0, _ := ret[0].(*uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockPostgresRepositoryMockRecorder) CreateUser(user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockPostgresRepository)(nil).CreateUser), user)
+}
+
+// DeleteAuthor mocks base method.
+func (m *MockPostgresRepository) DeleteAuthor(authorID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAuthor", authorID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAuthor indicates an expected call of DeleteAuthor.
+func (mr *MockPostgresRepositoryMockRecorder) DeleteAuthor(authorID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAuthor", reflect.TypeOf((*MockPostgresRepository)(nil).DeleteAuthor), authorID)
+}
+
+// DeleteCategory mocks base method.
+func (m *MockPostgresRepository) DeleteCategory(categoryID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCategory", categoryID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCategory indicates an expected call of DeleteCategory.
+func (mr *MockPostgresRepositoryMockRecorder) DeleteCategory(categoryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCategory", reflect.TypeOf((*MockPostgresRepository)(nil).DeleteCategory), categoryID)
+}
+
+// DeleteUser mocks base method.
+func (m *MockPostgresRepository) DeleteUser(userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockPostgresRepositoryMockRecorder) DeleteUser(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockPostgresRepository)(nil).DeleteUser), userID)
+}
+
+// EachBook mocks base method.
+func (m *MockPostgresRepository) EachBook(req entity.ListBookRequest, size int, fn func([]entity.BookResponse) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EachBook", req, size, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EachBook indicates an expected call of EachBook.
+func (mr *MockPostgresRepositoryMockRecorder) EachBook(req, size, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EachBook", reflect.TypeOf((*MockPostgresRepository)(nil).EachBook), req, size, fn)
+}
+
+// ExpireReadyHolds mocks base method.
+func (m *MockPostgresRepository) ExpireReadyHolds(now, pickupExpiresAt time.Time, bookID *uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireReadyHolds", now, pickupExpiresAt, bookID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireReadyHolds indicates an expected call of ExpireReadyHolds.
+func (mr *MockPostgresRepositoryMockRecorder) ExpireReadyHolds(now, pickupExpiresAt, bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireReadyHolds", reflect.TypeOf((*MockPostgresRepository)(nil).ExpireReadyHolds), now, pickupExpiresAt, bookID)
+}
+
+// GetActiveBorrowHistoryByCopyID mocks base method.
+func (m *MockPostgresRepository) GetActiveBorrowHistoryByCopyID(copyID uint) (*entity.BorrowHistoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveBorrowHistoryByCopyID", copyID)
+	ret0, _ := ret[0].(*entity.BorrowHistoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveBorrowHistoryByCopyID indicates an expected call of GetActiveBorrowHistoryByCopyID.
+func (mr *MockPostgresRepositoryMockRecorder) GetActiveBorrowHistoryByCopyID(copyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveBorrowHistoryByCopyID", reflect.TypeOf((*MockPostgresRepository)(nil).GetActiveBorrowHistoryByCopyID), copyID)
+}
+
+// GetAuthorByID mocks base method.
+func (m *MockPostgresRepository) GetAuthorByID(authorID uint) (*entity.AuthorResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorByID", authorID)
+	ret0, _ := ret[0].(*entity.AuthorResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthorByID indicates an expected call of GetAuthorByID.
+func (mr *MockPostgresRepositoryMockRecorder) GetAuthorByID(authorID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorByID", reflect.TypeOf((*MockPostgresRepository)(nil).GetAuthorByID), authorID)
+}
+
+// GetAuthorsByIDs mocks base method.
+func (m *MockPostgresRepository) GetAuthorsByIDs(authorIDs []uint) ([]entity.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorsByIDs", authorIDs)
+	ret0, _ := ret[0].([]entity.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthorsByIDs indicates an expected call of GetAuthorsByIDs.
+func (mr *MockPostgresRepositoryMockRecorder) GetAuthorsByIDs(authorIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorsByIDs", reflect.TypeOf((*MockPostgresRepository)(nil).GetAuthorsByIDs), authorIDs)
+}
+
+// GetBookAvailability mocks base method.
+func (m *MockPostgresRepository) GetBookAvailability(bookIDs []uint) ([]entity.BookAvailability, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookAvailability", bookIDs)
+	ret0, _ := ret[0].([]entity.BookAvailability)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookAvailability indicates an expected call of GetBookAvailability.
+func (mr *MockPostgresRepositoryMockRecorder) GetBookAvailability(bookIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookAvailability", reflect.TypeOf((*MockPostgresRepository)(nil).GetBookAvailability), bookIDs)
+}
+
+// GetBookByID mocks base method.
+func (m *MockPostgresRepository) GetBookByID(bookID uint) (*entity.BookResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookByID", bookID)
+	ret0, _ := ret[0].(*entity.BookResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookByID indicates an expected call of GetBookByID.
+func (mr *MockPostgresRepositoryMockRecorder) GetBookByID(bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookByID", reflect.TypeOf((*MockPostgresRepository)(nil).GetBookByID), bookID)
+}
+
+// GetBookByISBN mocks base method.
+func (m *MockPostgresRepository) GetBookByISBN(isbn string) (*entity.BookResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookByISBN", isbn)
+	ret0, _ := ret[0].(*entity.BookResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookByISBN indicates an expected call of GetBookByISBN.
+func (mr *MockPostgresRepositoryMockRecorder) GetBookByISBN(isbn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookByISBN", reflect.TypeOf((*MockPostgresRepository)(nil).GetBookByISBN), isbn)
+}
+
+// GetBookCopyByBarcode mocks base method.
+func (m *MockPostgresRepository) GetBookCopyByBarcode(barcode string) (*entity.BookCopyResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookCopyByBarcode", barcode)
+	ret0, _ := ret[0].(*entity.BookCopyResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookCopyByBarcode indicates an expected call of GetBookCopyByBarcode.
+func (mr *MockPostgresRepositoryMockRecorder) GetBookCopyByBarcode(barcode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookCopyByBarcode", reflect.TypeOf((*MockPostgresRepository)(nil).GetBookCopyByBarcode), barcode)
+}
+
+// GetBookCopyByID mocks base method.
+func (m *MockPostgresRepository) GetBookCopyByID(copyID uint) (*entity.BookCopyResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookCopyByID", copyID)
+	ret0, _ := ret[0].(*entity.BookCopyResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookCopyByID indicates an expected call of GetBookCopyByID.
+func (mr *MockPostgresRepositoryMockRecorder) GetBookCopyByID(copyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookCopyByID", reflect.TypeOf((*MockPostgresRepository)(nil).GetBookCopyByID), copyID)
+}
+
+// GetBookFacets mocks base method.
+func (m *MockPostgresRepository) GetBookFacets(req entity.ListBookRequest) (*entity.BookFacets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookFacets", req)
+	ret0, _ := ret[0].(*entity.BookFacets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookFacets indicates an expected call of GetBookFacets.
+func (mr *MockPostgresRepositoryMockRecorder) GetBookFacets(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookFacets", reflect.TypeOf((*MockPostgresRepository)(nil).GetBookFacets), req)
+}
+
+// GetBorrowHistoryByBookID mocks base method.
+func (m *MockPostgresRepository) GetBorrowHistoryByBookID(bookID uint) ([]entity.BorrowHistoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBorrowHistoryByBookID", bookID)
+	ret0, _ := ret[0].([]entity.BorrowHistoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBorrowHistoryByBookID indicates an expected call of GetBorrowHistoryByBookID.
+func (mr *MockPostgresRepositoryMockRecorder) GetBorrowHistoryByBookID(bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBorrowHistoryByBookID", reflect.TypeOf((*MockPostgresRepository)(nil).GetBorrowHistoryByBookID), bookID)
+}
+
+// GetBorrowHistoryByID mocks base method.
+func (m *MockPostgresRepository) GetBorrowHistoryByID(id uint) (*entity.BorrowHistoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBorrowHistoryByID", id)
+	ret0, _ := ret[0].(*entity.BorrowHistoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBorrowHistoryByID indicates an expected call of GetBorrowHistoryByID.
+func (mr *MockPostgresRepositoryMockRecorder) GetBorrowHistoryByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBorrowHistoryByID", reflect.TypeOf((*MockPostgresRepository)(nil).GetBorrowHistoryByID), id)
+}
+
+// GetCategoriesByIDs mocks base method.
+func (m *MockPostgresRepository) GetCategoriesByIDs(categoryIDs []uint) ([]entity.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategoriesByIDs", categoryIDs)
+	ret0, _ := ret[0].([]entity.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategoriesByIDs indicates an expected call of GetCategoriesByIDs.
+func (mr *MockPostgresRepositoryMockRecorder) GetCategoriesByIDs(categoryIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategoriesByIDs", reflect.TypeOf((*MockPostgresRepository)(nil).GetCategoriesByIDs), categoryIDs)
+}
+
+// GetEarliestBookChange mocks base method.
+func (m *MockPostgresRepository) GetEarliestBookChange() (*time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEarliestBookChange")
+	ret0, _ := ret[0].(*time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEarliestBookChange indicates an expected call of GetEarliestBookChange.
+func (mr *MockPostgresRepositoryMockRecorder) GetEarliestBookChange() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEarliestBookChange", reflect.TypeOf((*MockPostgresRepository)(nil).GetEarliestBookChange))
+}
+
+// GetFeeBalance mocks base method.
+func (m *MockPostgresRepository) GetFeeBalance(userID uint) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeeBalance", userID)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeeBalance indicates an expected call of GetFeeBalance.
+func (mr *MockPostgresRepositoryMockRecorder) GetFeeBalance(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeeBalance", reflect.TypeOf((*MockPostgresRepository)(nil).GetFeeBalance), userID)
+}
+
+// GetHoldByID mocks base method.
+func (m *MockPostgresRepository) GetHoldByID(holdID uint) (*entity.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHoldByID", holdID)
+	ret0, _ := ret[0].(*entity.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHoldByID indicates an expected call of GetHoldByID.
+func (mr *MockPostgresRepositoryMockRecorder) GetHoldByID(holdID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHoldByID", reflect.TypeOf((*MockPostgresRepository)(nil).GetHoldByID), holdID)
+}
+
+// GetReadyHold mocks base method.
+func (m *MockPostgresRepository) GetReadyHold(bookID, userID uint) (*entity.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReadyHold", bookID, userID)
+	ret0, _ := ret[0].(*entity.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReadyHold indicates an expected call of GetReadyHold.
+func (mr *MockPostgresRepositoryMockRecorder) GetReadyHold(bookID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReadyHold", reflect.TypeOf((*MockPostgresRepository)(nil).GetReadyHold), bookID, userID)
+}
+
+// GetUserByCardNumber mocks base method.
+func (m *MockPostgresRepository) GetUserByCardNumber(cardNumber string) (*entity.UserResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByCardNumber", cardNumber)
+	ret0, _ := ret[0].(*entity.UserResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByCardNumber indicates an expected call of GetUserByCardNumber.
+func (mr *MockPostgresRepositoryMockRecorder) GetUserByCardNumber(cardNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByCardNumber", reflect.TypeOf((*MockPostgresRepository)(nil).GetUserByCardNumber), cardNumber)
+}
+
+// GetUserByID mocks base method.
+func (m *MockPostgresRepository) GetUserByID(userID uint) (*entity.UserResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByID", userID)
+	ret0, _ := ret[0].(*entity.UserResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockPostgresRepositoryMockRecorder) GetUserByID(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockPostgresRepository)(nil).GetUserByID), userID)
+}
+
+// GetUserByUsername mocks base method.
+func (m *MockPostgresRepository) GetUserByUsername(username string) (*entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByUsername", username)
+	ret0, _ := ret[0].(*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByUsername indicates an expected call of GetUserByUsername.
+func (mr *MockPostgresRepositoryMockRecorder) GetUserByUsername(username interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BorrowBook", reflect.TypeOf((*MockPostgresRepository)(nil).BorrowBook), history)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByUsername", reflect.TypeOf((*MockPostgresRepository)(nil).GetUserByUsername), username)
 }
 
-// CreateBook mocks base method.
-func (m *MockPostgresRepository) CreateBook(book entity.Book) error {
+// ImportBooks mocks base method.
+func (m *MockPostgresRepository) ImportBooks(items []entity.BookImportItem, dryRun bool) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateBook", book)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "ImportBooks", items, dryRun)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// CreateBook indicates an expected call of CreateBook.
-func (mr *MockPostgresRepositoryMockRecorder) CreateBook(book interface{}) *gomock.Call {
+// ImportBooks indicates an expected call of ImportBooks.
+func (mr *MockPostgresRepositoryMockRecorder) ImportBooks(items, dryRun interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBook", reflect.TypeOf((*MockPostgresRepository)(nil).CreateBook), book)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportBooks", reflect.TypeOf((*MockPostgresRepository)(nil).ImportBooks), items, dryRun)
 }
 
-// CreateUser mocks base method.
-func (m *MockPostgresRepository) CreateUser(user entity.User) (*uint, error) {
+// ListActiveBorrowHistoriesByBookID mocks base method.
+func (m *MockPostgresRepository) ListActiveBorrowHistoriesByBookID(bookID uint) ([]entity.BorrowHistoryResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUser", user)
-	ret0, _ := ret[0].(*uint)
+	ret := m.ctrl.Call(m, "ListActiveBorrowHistoriesByBookID", bookID)
+	ret0, _ := ret[0].([]entity.BorrowHistoryResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateUser indicates an expected call of CreateUser.
-func (mr *MockPostgresRepositoryMockRecorder) CreateUser(user interface{}) *gomock.Call {
+// ListActiveBorrowHistoriesByBookID indicates an expected call of ListActiveBorrowHistoriesByBookID.
+func (mr *MockPostgresRepositoryMockRecorder) ListActiveBorrowHistoriesByBookID(bookID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockPostgresRepository)(nil).CreateUser), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveBorrowHistoriesByBookID", reflect.TypeOf((*MockPostgresRepository)(nil).ListActiveBorrowHistoriesByBookID), bookID)
 }
 
-// DeleteUser mocks base method.
-func (m *MockPostgresRepository) DeleteUser(userID uint) error {
+// ListActiveLoansByUserID mocks base method.
+func (m *MockPostgresRepository) ListActiveLoansByUserID(userID uint) ([]entity.UserLoanResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteUser", userID)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "ListActiveLoansByUserID", userID)
+	ret0, _ := ret[0].([]entity.UserLoanResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteUser indicates an expected call of DeleteUser.
-func (mr *MockPostgresRepositoryMockRecorder) DeleteUser(userID interface{}) *gomock.Call {
+// ListActiveLoansByUserID indicates an expected call of ListActiveLoansByUserID.
+func (mr *MockPostgresRepositoryMockRecorder) ListActiveLoansByUserID(userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockPostgresRepository)(nil).DeleteUser), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveLoansByUserID", reflect.TypeOf((*MockPostgresRepository)(nil).ListActiveLoansByUserID), userID)
 }
 
-// GetBookByID mocks base method.
-func (m *MockPostgresRepository) GetBookByID(bookID uint) (*entity.BookResponse, error) {
+// ListAuthors mocks base method.
+func (m *MockPostgresRepository) ListAuthors(req entity.ListAuthorRequest) ([]entity.AuthorResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBookByID", bookID)
-	ret0, _ := ret[0].(*entity.BookResponse)
+	ret := m.ctrl.Call(m, "ListAuthors", req)
+	ret0, _ := ret[0].([]entity.AuthorResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetBookByID indicates an expected call of GetBookByID.
-func (mr *MockPostgresRepositoryMockRecorder) GetBookByID(bookID interface{}) *gomock.Call {
+// ListAuthors indicates an expected call of ListAuthors.
+func (mr *MockPostgresRepositoryMockRecorder) ListAuthors(req interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookByID", reflect.TypeOf((*MockPostgresRepository)(nil).GetBookByID), bookID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuthors", reflect.TypeOf((*MockPostgresRepository)(nil).ListAuthors), req)
 }
 
-// GetBorrowHistoryByBookID mocks base method.
-func (m *MockPostgresRepository) GetBorrowHistoryByBookID(bookID uint) ([]entity.BorrowHistoryResponse, error) {
+// ListBook mocks base method.
+func (m *MockPostgresRepository) ListBook(req entity.ListBookRequest) ([]entity.BookResponse, *string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBorrowHistoryByBookID", bookID)
-	ret0, _ := ret[0].([]entity.BorrowHistoryResponse)
+	ret := m.ctrl.Call(m, "ListBook", req)
+	ret0, _ := ret[0].([]entity.BookResponse)
+	ret1, _ := ret[1].(*string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListBook indicates an expected call of ListBook.
+func (mr *MockPostgresRepositoryMockRecorder) ListBook(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBook", reflect.TypeOf((*MockPostgresRepository)(nil).ListBook), req)
+}
+
+// ListBookChanges mocks base method.
+func (m *MockPostgresRepository) ListBookChanges(req entity.ListBookChangeRequest) ([]entity.BookResponse, *string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBookChanges", req)
+	ret0, _ := ret[0].([]entity.BookResponse)
+	ret1, _ := ret[1].(*string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListBookChanges indicates an expected call of ListBookChanges.
+func (mr *MockPostgresRepositoryMockRecorder) ListBookChanges(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBookChanges", reflect.TypeOf((*MockPostgresRepository)(nil).ListBookChanges), req)
+}
+
+// ListBookCopiesByBookID mocks base method.
+func (m *MockPostgresRepository) ListBookCopiesByBookID(bookID uint) ([]entity.BookCopyResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBookCopiesByBookID", bookID)
+	ret0, _ := ret[0].([]entity.BookCopyResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetBorrowHistoryByBookID indicates an expected call of GetBorrowHistoryByBookID.
-func (mr *MockPostgresRepositoryMockRecorder) GetBorrowHistoryByBookID(bookID interface{}) *gomock.Call {
+// ListBookCopiesByBookID indicates an expected call of ListBookCopiesByBookID.
+func (mr *MockPostgresRepositoryMockRecorder) ListBookCopiesByBookID(bookID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBorrowHistoryByBookID", reflect.TypeOf((*MockPostgresRepository)(nil).GetBorrowHistoryByBookID), bookID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBookCopiesByBookID", reflect.TypeOf((*MockPostgresRepository)(nil).ListBookCopiesByBookID), bookID)
 }
 
-// GetBorrowHistoryByID mocks base method.
-func (m *MockPostgresRepository) GetBorrowHistoryByID(id uint) (*entity.BorrowHistoryResponse, error) {
+// ListBooksByAuthorID mocks base method.
+func (m *MockPostgresRepository) ListBooksByAuthorID(authorID uint) ([]entity.BookResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBorrowHistoryByID", id)
-	ret0, _ := ret[0].(*entity.BorrowHistoryResponse)
+	ret := m.ctrl.Call(m, "ListBooksByAuthorID", authorID)
+	ret0, _ := ret[0].([]entity.BookResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetBorrowHistoryByID indicates an expected call of GetBorrowHistoryByID.
-func (mr *MockPostgresRepositoryMockRecorder) GetBorrowHistoryByID(id interface{}) *gomock.Call {
+// ListBooksByAuthorID indicates an expected call of ListBooksByAuthorID.
+func (mr *MockPostgresRepositoryMockRecorder) ListBooksByAuthorID(authorID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBorrowHistoryByID", reflect.TypeOf((*MockPostgresRepository)(nil).GetBorrowHistoryByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBooksByAuthorID", reflect.TypeOf((*MockPostgresRepository)(nil).ListBooksByAuthorID), authorID)
 }
 
-// GetUserByID mocks base method.
-func (m *MockPostgresRepository) GetUserByID(userID uint) (*entity.UserResponse, error) {
+// ListBorrowHistoriesByUserID mocks base method.
+func (m *MockPostgresRepository) ListBorrowHistoriesByUserID(req entity.ListUserBorrowHistoryRequest) ([]entity.UserLoanResponse, *string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserByID", userID)
-	ret0, _ := ret[0].(*entity.UserResponse)
+	ret := m.ctrl.Call(m, "ListBorrowHistoriesByUserID", req)
+	ret0, _ := ret[0].([]entity.UserLoanResponse)
+	ret1, _ := ret[1].(*string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListBorrowHistoriesByUserID indicates an expected call of ListBorrowHistoriesByUserID.
+func (mr *MockPostgresRepositoryMockRecorder) ListBorrowHistoriesByUserID(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBorrowHistoriesByUserID", reflect.TypeOf((*MockPostgresRepository)(nil).ListBorrowHistoriesByUserID), req)
+}
+
+// ListCategories mocks base method.
+func (m *MockPostgresRepository) ListCategories() ([]entity.CategoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCategories")
+	ret0, _ := ret[0].([]entity.CategoryResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserByID indicates an expected call of GetUserByID.
-func (mr *MockPostgresRepositoryMockRecorder) GetUserByID(userID interface{}) *gomock.Call {
+// ListCategories indicates an expected call of ListCategories.
+func (mr *MockPostgresRepositoryMockRecorder) ListCategories() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockPostgresRepository)(nil).GetUserByID), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCategories", reflect.TypeOf((*MockPostgresRepository)(nil).ListCategories))
 }
 
-// GetUserByUsername mocks base method.
-func (m *MockPostgresRepository) GetUserByUsername(username string) (*entity.User, error) {
+// ListFeeTransactionsByUserID mocks base method.
+func (m *MockPostgresRepository) ListFeeTransactionsByUserID(userID uint) ([]entity.FeeTransactionResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserByUsername", username)
-	ret0, _ := ret[0].(*entity.User)
+	ret := m.ctrl.Call(m, "ListFeeTransactionsByUserID", userID)
+	ret0, _ := ret[0].([]entity.FeeTransactionResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserByUsername indicates an expected call of GetUserByUsername.
-func (mr *MockPostgresRepositoryMockRecorder) GetUserByUsername(username interface{}) *gomock.Call {
+// ListFeeTransactionsByUserID indicates an expected call of ListFeeTransactionsByUserID.
+func (mr *MockPostgresRepositoryMockRecorder) ListFeeTransactionsByUserID(userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByUsername", reflect.TypeOf((*MockPostgresRepository)(nil).GetUserByUsername), username)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFeeTransactionsByUserID", reflect.TypeOf((*MockPostgresRepository)(nil).ListFeeTransactionsByUserID), userID)
 }
 
-// ListBook mocks base method.
-func (m *MockPostgresRepository) ListBook(req entity.ListBookRequest) ([]entity.BookResponse, error) {
+// ListHoldsByUserID mocks base method.
+func (m *MockPostgresRepository) ListHoldsByUserID(userID uint) ([]entity.HoldResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListBook", req)
-	ret0, _ := ret[0].([]entity.BookResponse)
+	ret := m.ctrl.Call(m, "ListHoldsByUserID", userID)
+	ret0, _ := ret[0].([]entity.HoldResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListBook indicates an expected call of ListBook.
-func (mr *MockPostgresRepositoryMockRecorder) ListBook(req interface{}) *gomock.Call {
+// ListHoldsByUserID indicates an expected call of ListHoldsByUserID.
+func (mr *MockPostgresRepositoryMockRecorder) ListHoldsByUserID(userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBook", reflect.TypeOf((*MockPostgresRepository)(nil).ListBook), req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHoldsByUserID", reflect.TypeOf((*MockPostgresRepository)(nil).ListHoldsByUserID), userID)
 }
 
 // ListLatestBooks mocks base method.
-func (m *MockPostgresRepository) ListLatestBooks() ([]entity.BookResponse, error) {
+func (m *MockPostgresRepository) ListLatestBooks(req entity.ListLatestBookRequest) ([]entity.BookResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListLatestBooks")
+	ret := m.ctrl.Call(m, "ListLatestBooks", req)
 	ret0, _ := ret[0].([]entity.BookResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListLatestBooks indicates an expected call of ListLatestBooks.
-func (mr *MockPostgresRepositoryMockRecorder) ListLatestBooks() *gomock.Call {
+func (mr *MockPostgresRepositoryMockRecorder) ListLatestBooks(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLatestBooks", reflect.TypeOf((*MockPostgresRepository)(nil).ListLatestBooks), req)
+}
+
+// ListOverdueBorrowHistories mocks base method.
+func (m *MockPostgresRepository) ListOverdueBorrowHistories(req entity.ListOverdueBorrowRequest, now time.Time) ([]entity.BorrowHistoryResponse, *string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOverdueBorrowHistories", req, now)
+	ret0, _ := ret[0].([]entity.BorrowHistoryResponse)
+	ret1, _ := ret[1].(*string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListOverdueBorrowHistories indicates an expected call of ListOverdueBorrowHistories.
+func (mr *MockPostgresRepositoryMockRecorder) ListOverdueBorrowHistories(req, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOverdueBorrowHistories", reflect.TypeOf((*MockPostgresRepository)(nil).ListOverdueBorrowHistories), req, now)
+}
+
+// MarkBorrowFound mocks base method.
+func (m *MockPostgresRepository) MarkBorrowFound(historyID uint, foundAt, pickupExpiresAt time.Time, waiver *entity.FeeTransaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkBorrowFound", historyID, foundAt, pickupExpiresAt, waiver)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkBorrowFound indicates an expected call of MarkBorrowFound.
+func (mr *MockPostgresRepositoryMockRecorder) MarkBorrowFound(historyID, foundAt, pickupExpiresAt, waiver interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkBorrowFound", reflect.TypeOf((*MockPostgresRepository)(nil).MarkBorrowFound), historyID, foundAt, pickupExpiresAt, waiver)
+}
+
+// MarkBorrowLost mocks base method.
+func (m *MockPostgresRepository) MarkBorrowLost(historyID uint, lostAt time.Time, replacement *entity.FeeTransaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkBorrowLost", historyID, lostAt, replacement)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkBorrowLost indicates an expected call of MarkBorrowLost.
+func (mr *MockPostgresRepositoryMockRecorder) MarkBorrowLost(historyID, lostAt, replacement interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkBorrowLost", reflect.TypeOf((*MockPostgresRepository)(nil).MarkBorrowLost), historyID, lostAt, replacement)
+}
+
+// MarkOverdueBorrowHistories mocks base method.
+func (m *MockPostgresRepository) MarkOverdueBorrowHistories(now time.Time, userID *uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOverdueBorrowHistories", now, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkOverdueBorrowHistories indicates an expected call of MarkOverdueBorrowHistories.
+func (mr *MockPostgresRepositoryMockRecorder) MarkOverdueBorrowHistories(now, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLatestBooks", reflect.TypeOf((*MockPostgresRepository)(nil).ListLatestBooks))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOverdueBorrowHistories", reflect.TypeOf((*MockPostgresRepository)(nil).MarkOverdueBorrowHistories), now, userID)
+}
+
+// RenewBook mocks base method.
+func (m *MockPostgresRepository) RenewBook(historyID uint, now time.Time, loanPeriod time.Duration, maxRenewals uint, ratePerDay float64) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenewBook", historyID, now, loanPeriod, maxRenewals, ratePerDay)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenewBook indicates an expected call of RenewBook.
+func (mr *MockPostgresRepositoryMockRecorder) RenewBook(historyID, now, loanPeriod, maxRenewals, ratePerDay interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewBook", reflect.TypeOf((*MockPostgresRepository)(nil).RenewBook), historyID, now, loanPeriod, maxRenewals, ratePerDay)
+}
+
+// RestoreBook mocks base method.
+func (m *MockPostgresRepository) RestoreBook(bookID uint, restoredAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreBook", bookID, restoredAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreBook indicates an expected call of RestoreBook.
+func (mr *MockPostgresRepositoryMockRecorder) RestoreBook(bookID, restoredAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreBook", reflect.TypeOf((*MockPostgresRepository)(nil).RestoreBook), bookID, restoredAt)
+}
+
+// RetireBookCopy mocks base method.
+func (m *MockPostgresRepository) RetireBookCopy(copyID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetireBookCopy", copyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetireBookCopy indicates an expected call of RetireBookCopy.
+func (mr *MockPostgresRepositoryMockRecorder) RetireBookCopy(copyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetireBookCopy", reflect.TypeOf((*MockPostgresRepository)(nil).RetireBookCopy), copyID)
 }
 
 // ReturnBook mocks base method.
-func (m *MockPostgresRepository) ReturnBook(historyID, BookID uint, returnedAt time.Time) error {
+func (m *MockPostgresRepository) ReturnBook(historyID, BookID uint, returnedAt, pickupExpiresAt time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ReturnBook", historyID, BookID, returnedAt)
+	ret := m.ctrl.Call(m, "ReturnBook", historyID, BookID, returnedAt, pickupExpiresAt)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // ReturnBook indicates an expected call of ReturnBook.
-func (mr *MockPostgresRepositoryMockRecorder) ReturnBook(historyID, BookID, returnedAt interface{}) *gomock.Call {
+func (mr *MockPostgresRepositoryMockRecorder) ReturnBook(historyID, BookID, returnedAt, pickupExpiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReturnBook", reflect.TypeOf((*MockPostgresRepository)(nil).ReturnBook), historyID, BookID, returnedAt, pickupExpiresAt)
+}
+
+// ReturnBookDamaged mocks base method.
+func (m *MockPostgresRepository) ReturnBookDamaged(historyID uint, returnedAt time.Time, replacement *entity.FeeTransaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReturnBookDamaged", historyID, returnedAt, replacement)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReturnBookDamaged indicates an expected call of ReturnBookDamaged.
+func (mr *MockPostgresRepositoryMockRecorder) ReturnBookDamaged(historyID, returnedAt, replacement interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReturnBook", reflect.TypeOf((*MockPostgresRepository)(nil).ReturnBook), historyID, BookID, returnedAt)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReturnBookDamaged", reflect.TypeOf((*MockPostgresRepository)(nil).ReturnBookDamaged), historyID, returnedAt, replacement)
+}
+
+// SearchBooks mocks base method.
+func (m *MockPostgresRepository) SearchBooks(req entity.SearchBookRequest) ([]entity.BookResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchBooks", req)
+	ret0, _ := ret[0].([]entity.BookResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchBooks indicates an expected call of SearchBooks.
+func (mr *MockPostgresRepositoryMockRecorder) SearchBooks(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchBooks", reflect.TypeOf((*MockPostgresRepository)(nil).SearchBooks), req)
+}
+
+// SuggestBookSearch mocks base method.
+func (m *MockPostgresRepository) SuggestBookSearch(text string) (*string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SuggestBookSearch", text)
+	ret0, _ := ret[0].(*string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SuggestBookSearch indicates an expected call of SuggestBookSearch.
+func (mr *MockPostgresRepositoryMockRecorder) SuggestBookSearch(text interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuggestBookSearch", reflect.TypeOf((*MockPostgresRepository)(nil).SuggestBookSearch), text)
+}
+
+// UpdateAuthor mocks base method.
+func (m *MockPostgresRepository) UpdateAuthor(author entity.Author, updatedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAuthor", author, updatedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAuthor indicates an expected call of UpdateAuthor.
+func (mr *MockPostgresRepositoryMockRecorder) UpdateAuthor(author, updatedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAuthor", reflect.TypeOf((*MockPostgresRepository)(nil).UpdateAuthor), author, updatedAt)
 }
 
 // UpdateBook mocks base method.
-func (m *MockPostgresRepository) UpdateBook(book entity.Book) error {
+func (m *MockPostgresRepository) UpdateBook(book entity.Book, authors []entity.Author, categoryIDs []uint, subjects []string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateBook", book)
+	ret := m.ctrl.Call(m, "UpdateBook", book, authors, categoryIDs, subjects)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateBook indicates an expected call of UpdateBook.
-func (mr *MockPostgresRepositoryMockRecorder) UpdateBook(book interface{}) *gomock.Call {
+func (mr *MockPostgresRepositoryMockRecorder) UpdateBook(book, authors, categoryIDs, subjects interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBook", reflect.TypeOf((*MockPostgresRepository)(nil).UpdateBook), book, authors, categoryIDs, subjects)
+}
+
+// UpdateBookCopy mocks base method.
+func (m *MockPostgresRepository) UpdateBookCopy(bookCopy entity.BookCopy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBookCopy", bookCopy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBookCopy indicates an expected call of UpdateBookCopy.
+func (mr *MockPostgresRepositoryMockRecorder) UpdateBookCopy(bookCopy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBookCopy", reflect.TypeOf((*MockPostgresRepository)(nil).UpdateBookCopy), bookCopy)
+}
+
+// UpdateBookCover mocks base method.
+func (m *MockPostgresRepository) UpdateBookCover(bookID uint, cover entity.BookCover, updatedAt time.Time) (*entity.BookCover, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBookCover", bookID, cover, updatedAt)
+	ret0, _ := ret[0].(*entity.BookCover)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateBookCover indicates an expected call of UpdateBookCover.
+func (mr *MockPostgresRepositoryMockRecorder) UpdateBookCover(bookID, cover, updatedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBookCover", reflect.TypeOf((*MockPostgresRepository)(nil).UpdateBookCover), bookID, cover, updatedAt)
+}
+
+// UpdateCategory mocks base method.
+func (m *MockPostgresRepository) UpdateCategory(category entity.Category, updatedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCategory", category, updatedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCategory indicates an expected call of UpdateCategory.
+func (mr *MockPostgresRepositoryMockRecorder) UpdateCategory(category, updatedAt interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBook", reflect.TypeOf((*MockPostgresRepository)(nil).UpdateBook), book)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCategory", reflect.TypeOf((*MockPostgresRepository)(nil).UpdateCategory), category, updatedAt)
 }
 
 // UpdateUser mocks base method.
@@ -240,6 +1099,20 @@ func (mr *MockPostgresRepositoryMockRecorder) UpdateUser(user interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockPostgresRepository)(nil).UpdateUser), user)
 }
 
+// UpdateUserCardNumber mocks base method.
+func (m *MockPostgresRepository) UpdateUserCardNumber(userID uint, cardNumber string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserCardNumber", userID, cardNumber)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserCardNumber indicates an expected call of UpdateUserCardNumber.
+func (mr *MockPostgresRepositoryMockRecorder) UpdateUserCardNumber(userID, cardNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserCardNumber", reflect.TypeOf((*MockPostgresRepository)(nil).UpdateUserCardNumber), userID, cardNumber)
+}
+
 // MockRedisRepository is a mock of RedisRepository interface.
 type MockRedisRepository struct {
 	ctrl     *gomock.Controller
@@ -306,6 +1179,71 @@ func (mr *MockRedisRepositoryMockRecorder) Set(key, value, expiration interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockRedisRepository)(nil).Set), key, value, expiration)
 }
 
+// MockBlobStorage is a mock of BlobStorage interface.
+type MockBlobStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlobStorageMockRecorder
+}
+
+// MockBlobStorageMockRecorder is the mock recorder for MockBlobStorage.
+type MockBlobStorageMockRecorder struct {
+	mock *MockBlobStorage
+}
+
+// NewMockBlobStorage creates a new mock instance.
+func NewMockBlobStorage(ctrl *gomock.Controller) *MockBlobStorage {
+	mock := &MockBlobStorage{ctrl: ctrl}
+	mock.recorder = &MockBlobStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlobStorage) EXPECT() *MockBlobStorageMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockBlobStorage) Delete(key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockBlobStorageMockRecorder) Delete(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBlobStorage)(nil).Delete), key)
+}
+
+// Put mocks base method.
+func (m *MockBlobStorage) Put(key string, data []byte, contentType string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", key, data, contentType)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockBlobStorageMockRecorder) Put(key, data, contentType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockBlobStorage)(nil).Put), key, data, contentType)
+}
+
+// URL mocks base method.
+func (m *MockBlobStorage) URL(key string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "URL", key)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// URL indicates an expected call of URL.
+func (mr *MockBlobStorageMockRecorder) URL(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "URL", reflect.TypeOf((*MockBlobStorage)(nil).URL), key)
+}
+
 // MockBcryptService is a mock of BcryptService interface.
 type MockBcryptService struct {
 	ctrl     *gomock.Controller
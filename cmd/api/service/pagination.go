@@ -0,0 +1,19 @@
+package service
+
+import "go-library-service/cmd/api/entity"
+
+// numberedPage describes a page by number out of the total matches of its listing
+func numberedPage(page, size int, total int64) entity.Pagination {
+	totalPages := (total + int64(size) - 1) / int64(size)
+	return entity.Pagination{
+		Page:       page,
+		Size:       size,
+		Total:      &total,
+		TotalPages: &totalPages,
+	}
+}
+
+// cursorPage describes a page after a cursor, which is not counted to keep deep pages cheap
+func cursorPage(size int, nextCursor *string) entity.Pagination {
+	return entity.Pagination{Size: size, NextCursor: nextCursor}
+}
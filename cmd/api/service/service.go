@@ -18,11 +18,25 @@ type Dependencies struct {
 	BcryptService BcryptService
 	PostgresRepo PostgresRepository
 	RedisRepo RedisRepository
+	BlobStorage BlobStorage
 }
 
 // Config is a configuration of service
 type Config struct {
-	
+	// LoanPeriod is how long a book may be kept before it is due, defaults to 14 days
+	LoanPeriod time.Duration
+	// MaxRenewals is how many times a loan may be renewed, zero allows none, defaults to 2 when nil
+	MaxRenewals *uint
+	// HoldPickupWindow is how long a copy stays reserved for a ready hold, defaults to 3 days
+	HoldPickupWindow time.Duration
+	// FineRatePerDay is the late fee charged for each day a book is overdue, defaults to 5
+	FineRatePerDay float64
+	// MaxActiveLoans is how many books a user may have out at once, defaults to 5
+	MaxActiveLoans uint
+	// LoanPolicies overrides the defaults above per user role
+	LoanPolicies map[string]LoanPolicy
+	// MaxCoverSize is the largest cover image that may be uploaded in bytes, defaults to 5 MiB
+	MaxCoverSize int64
 }
 
 // PostgresRepository is a repository for postgres
@@ -33,20 +47,88 @@ type PostgresRepository interface{
 	GetUserByUsername(username string) (*entity.User, error)
 	UpdateUser(user entity.User) error
 	DeleteUser(userID uint) error
+	GetUserByCardNumber(cardNumber string) (*entity.UserResponse, error)
+	UpdateUserCardNumber(userID uint, cardNumber string) error
 
 	// Book
-	CreateBook(book entity.Book) error
+	CreateBook(book entity.Book, authors []entity.Author, categoryIDs []uint, subjects []string) error
 	GetBookByID(bookID uint) (*entity.BookResponse, error)
-	UpdateBook(book entity.Book) error
-	ListBook(req entity.ListBookRequest) ([]entity.BookResponse, error)
-	ListLatestBooks() ([]entity.BookResponse, error)
+	GetBookByISBN(isbn string) (*entity.BookResponse, error)
+	UpdateBook(book entity.Book, authors []entity.Author, categoryIDs []uint, subjects []string) error
+	ImportBooks(items []entity.BookImportItem, dryRun bool) ([]string, error)
+	EachBook(req entity.ListBookRequest, size int, fn func(books []entity.BookResponse) error) error
+	GetBookAvailability(bookIDs []uint) ([]entity.BookAvailability, error)
+	ListBook(req entity.ListBookRequest) ([]entity.BookResponse, *string, error)
+	CountBooks(req entity.ListBookRequest) (int64, error)
+	SuggestBookSearch(text string) (*string, error)
+	GetBookFacets(req entity.ListBookRequest) (*entity.BookFacets, error)
+	ListLatestBooks(req entity.ListLatestBookRequest) ([]entity.BookResponse, error)
+	ArchiveBook(bookID uint, archivedAt time.Time) error
+	RestoreBook(bookID uint, restoredAt time.Time) error
+	ListBookChanges(req entity.ListBookChangeRequest) ([]entity.BookResponse, *string, error)
+	CountBookChanges(req entity.ListBookChangeRequest) (int64, error)
+	GetEarliestBookChange() (*time.Time, error)
+	SearchBooks(req entity.SearchBookRequest) ([]entity.BookResponse, error)
+	CountSearchBooks(query entity.BookQuery) (int64, error)
+	UpdateBookCover(bookID uint, cover entity.BookCover, updatedAt time.Time) (*entity.BookCover, error)
 
+	// Author
+	CreateAuthor(author *entity.Author) (*entity.AuthorResponse, error)
+	GetAuthorByID(authorID uint) (*entity.AuthorResponse, error)
+	GetAuthorsByIDs(authorIDs []uint) ([]entity.Author, error)
+	ListAuthors(req entity.ListAuthorRequest) ([]entity.AuthorResponse, error)
+	CountAuthors(req entity.ListAuthorRequest) (int64, error)
+	UpdateAuthor(author entity.Author, updatedAt time.Time) error
+	DeleteAuthor(authorID uint) error
+	ListBooksByAuthorID(authorID uint) ([]entity.BookResponse, error)
+
+	// Category
+	CreateCategory(category *entity.Category) (*entity.CategoryResponse, error)
+	ListCategories() ([]entity.CategoryResponse, error)
+	GetCategoriesByIDs(categoryIDs []uint) ([]entity.Category, error)
+	UpdateCategory(category entity.Category, updatedAt time.Time) error
+	DeleteCategory(categoryID uint) error
+
+	// BookCopy
+	CreateBookCopy(bookCopy *entity.BookCopy, now, pickupExpiresAt time.Time) (*entity.BookCopyResponse, error)
+	GetBookCopyByID(copyID uint) (*entity.BookCopyResponse, error)
+	GetBookCopyByBarcode(barcode string) (*entity.BookCopyResponse, error)
+	ListBookCopiesByBookID(bookID uint) ([]entity.BookCopyResponse, error)
+	UpdateBookCopy(bookCopy entity.BookCopy) error
+	RetireBookCopy(copyID uint) error
 
 	// BorrowHistory
-	BorrowBook(history *entity.BorrowHistory) (*entity.BorrowHistory, error)
-	ReturnBook(historyID, BookID uint, returnedAt time.Time) error
+	BorrowBook(history *entity.BorrowHistory, maxActiveLoans uint) (*entity.BorrowHistory, error)
+	ReturnBook(historyID, BookID uint, returnedAt, pickupExpiresAt time.Time) error
 	GetBorrowHistoryByBookID(bookID uint) ([]entity.BorrowHistoryResponse, error)
 	GetBorrowHistoryByID(id uint) (*entity.BorrowHistoryResponse, error)
+	RenewBook(historyID uint, now time.Time, loanPeriod time.Duration, maxRenewals uint, ratePerDay float64) (time.Time, error)
+	MarkOverdueBorrowHistories(now time.Time, userID *uint) (int64, error)
+	ListOverdueBorrowHistories(req entity.ListOverdueBorrowRequest, now time.Time) ([]entity.BorrowHistoryResponse, *string, error)
+	CountOverdueBorrowHistories(req entity.ListOverdueBorrowRequest, now time.Time) (int64, error)
+	ListActiveLoansByUserID(userID uint) ([]entity.UserLoanResponse, error)
+	ListBorrowHistoriesByUserID(req entity.ListUserBorrowHistoryRequest) ([]entity.UserLoanResponse, *string, error)
+	CountBorrowHistoriesByUserID(req entity.ListUserBorrowHistoryRequest) (int64, error)
+	GetActiveBorrowHistoryByCopyID(copyID uint) (*entity.BorrowHistoryResponse, error)
+	ListActiveBorrowHistoriesByBookID(bookID uint) ([]entity.BorrowHistoryResponse, error)
+	MarkBorrowLost(historyID uint, lostAt time.Time, replacement *entity.FeeTransaction) error
+	ReturnBookDamaged(historyID uint, returnedAt time.Time, replacement *entity.FeeTransaction) error
+	MarkBorrowFound(historyID uint, foundAt, pickupExpiresAt time.Time, waiver *entity.FeeTransaction) error
+
+	// Hold
+	CreateHold(hold *entity.Hold) (*entity.HoldResponse, error)
+	GetHoldByID(holdID uint) (*entity.Hold, error)
+	GetReadyHold(bookID, userID uint) (*entity.Hold, error)
+	ListHoldsByUserID(userID uint) ([]entity.HoldResponse, error)
+	CancelHold(holdID uint, now, pickupExpiresAt time.Time) error
+	ExpireReadyHolds(now, pickupExpiresAt time.Time, bookID *uint) (int64, error)
+
+	// Fee
+	ChargeReplacementCost(charge *entity.FeeTransaction) error
+	CreateFeeCredit(transaction *entity.FeeTransaction) error
+	GetFeeBalance(userID uint) (float64, error)
+	ListFeeTransactionsByUserID(userID uint) ([]entity.FeeTransactionResponse, error)
+	AccrueLateFees(now time.Time, ratePerDay float64, userID *uint) (int64, error)
 }
 
 // RedisRepository is a repository for redis
@@ -56,6 +138,13 @@ type RedisRepository interface{
 	Delete(key string) error
 }
 
+// BlobStorage stores files such as book covers under keys and tells where each is served
+type BlobStorage interface{
+	Put(key string, data []byte, contentType string) error
+	Delete(key string) error
+	URL(key string) string
+}
+
 // BcryptService is a service for bcrypt
 type BcryptService interface{
 	Hash(password string) (string, error)
@@ -68,4 +157,36 @@ func NewService(deps *Dependencies, conf *Config) *Service {
 		deps: deps,
 		conf: conf,
 	}
+}
+
+// loanPeriod returns the configured loan period or the default one
+func (s *Service) loanPeriod() time.Duration {
+	if s.conf.LoanPeriod > 0 {
+		return s.conf.LoanPeriod
+	}
+	return defaultLoanPeriod
+}
+
+// maxRenewals returns the configured renewal limit or the default one
+func (s *Service) maxRenewals() uint {
+	if s.conf.MaxRenewals != nil {
+		return *s.conf.MaxRenewals
+	}
+	return defaultMaxRenewals
+}
+
+// holdPickupWindow returns the configured hold pickup window or the default one
+func (s *Service) holdPickupWindow() time.Duration {
+	if s.conf.HoldPickupWindow > 0 {
+		return s.conf.HoldPickupWindow
+	}
+	return defaultHoldPickupWindow
+}
+
+// fineRatePerDay returns the configured late fee rate or the default one
+func (s *Service) fineRatePerDay() float64 {
+	if s.conf.FineRatePerDay > 0 {
+		return s.conf.FineRatePerDay
+	}
+	return defaultFineRatePerDay
 }
\ No newline at end of file
@@ -0,0 +1,15 @@
+package service
+
+import (
+	"go-library-service/internal/isbn"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// RegisterValidations adds the validation tags the requests use beyond the built-in ones
+func RegisterValidations(v *validator.Validate) error {
+	// bookisbn accepts an ISBN-10 or ISBN-13 with a correct check digit, hyphens and spaces allowed
+	return v.RegisterValidation("bookisbn", func(fl validator.FieldLevel) bool {
+		return isbn.Valid(fl.Field().String())
+	})
+}
@@ -0,0 +1,100 @@
+// Command import loads books into the catalog from a CSV file, the same way as POST /management/books/import.
+//
+//	go run ./cmd/import -mode batch -batch-size 200 -dry-run books.csv
+//
+// It connects with the PG_* and REDIS_* envs of the API and prints the import report as JSON.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"go-library-service/cmd/api/entity"
+	"go-library-service/cmd/api/repository"
+	"go-library-service/cmd/api/service"
+	"go-library-service/internal/utils"
+
+	"github.com/go-playground/validator/v10"
+	log "github.com/sirupsen/logrus"
+)
+
+func initService() *service.Service {
+	postgresRepo, err := repository.NewPostgresRepository(repository.PostgresConfig{
+		Host:     utils.RequiredEnv("PG_HOST"),
+		Port:     utils.RequiredEnv("PG_PORT"),
+		User:     utils.RequiredEnv("PG_USER"),
+		Password: utils.RequiredEnv("PG_PASSWORD"),
+		DBName:   utils.RequiredEnv("PG_NAME"),
+	})
+	if err != nil {
+		log.Fatal("Failed to connect to database: ", err)
+	}
+
+	redisRepo, err := repository.NewRedisRepository(repository.RedisConfig{
+		Host:     utils.RequiredEnv("REDIS_HOST"),
+		Port:     utils.RequiredEnv("REDIS_PORT"),
+		Password: utils.RequiredEnv("REDIS_PASSWORD"),
+	})
+	if err != nil {
+		log.Fatal("Failed to connect to redis: ", err)
+	}
+
+	return service.NewService(&service.Dependencies{
+		PostgresRepo: postgresRepo,
+		RedisRepo:    redisRepo,
+	}, &service.Config{})
+}
+
+func main() {
+	var req entity.BookImportRequest
+	flag.BoolVar(&req.DryRun, "dry-run", false, "check and write every row, then roll it all back")
+	flag.StringVar(&req.Mode, "mode", "all", "all for one transaction, batch for one per batch")
+	flag.IntVar(&req.BatchSize, "batch-size", 100, "rows per transaction in batch mode")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] file.csv\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	validate := validator.New()
+	if err := service.RegisterValidations(validate); err != nil {
+		log.Fatal("Failed to register validations: ", err)
+	}
+
+	if err := validate.Struct(req); err != nil {
+		log.Fatal("Invalid flags: ", err)
+	}
+
+	file, err := os.Open(flag.Arg(0))
+	if err != nil {
+		log.Fatal("Failed to open file: ", err)
+	}
+	defer file.Close()
+
+	rows, err := service.ReadBookImport(file, validate)
+	if err != nil {
+		log.Fatal("Failed to read file: ", err)
+	}
+
+	report, err := initService().ImportBooks(rows, req)
+	if err != nil {
+		log.Fatal("Failed to import books: ", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Fatal("Failed to print report: ", err)
+	}
+
+	if len(report.Errors) > 0 {
+		os.Exit(1)
+	}
+}
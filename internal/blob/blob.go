@@ -0,0 +1,20 @@
+// Package blob stores files under keys, such as book covers, and tells the URL each of them is served at
+package blob
+
+import (
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidKey is a key that is empty, absolute or steps out of the storage with ..
+var ErrInvalidKey = errors.New("invalid blob key")
+
+// validKey tells whether a key is a clean relative path, the same on every storage
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	return path.Clean(key) == key && key != "." && !strings.HasPrefix(key, "../") && key != ".."
+}
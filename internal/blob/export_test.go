@@ -0,0 +1,3 @@
+package blob
+
+var SignRequest = signRequest
@@ -0,0 +1,70 @@
+package blob
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// LocalStorage stores blobs as files within a directory, served from a base URL
+type LocalStorage struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalStorage creates a storage of the files within a directory, served from a base URL
+func NewLocalStorage(dir, baseURL string) *LocalStorage {
+	return &LocalStorage{dir: dir, baseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// Put writes a blob, replacing the one under its key. It is written aside first, so a blob is never seen half written.
+func (s *LocalStorage) Put(key string, data []byte, contentType string) error {
+	if !validKey(key) {
+		return errors.Wrapf(ErrInvalidKey, "[LocalStorage.Put]: %q", key)
+	}
+
+	name := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return errors.Wrap(err, "[LocalStorage.Put]: unable to create directory")
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(name), ".blob-*")
+	if err != nil {
+		return errors.Wrap(err, "[LocalStorage.Put]: unable to create file")
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return errors.Wrap(err, "[LocalStorage.Put]: unable to write file")
+	}
+	if err := file.Close(); err != nil {
+		return errors.Wrap(err, "[LocalStorage.Put]: unable to write file")
+	}
+	if err := os.Chmod(file.Name(), 0o644); err != nil {
+		return errors.Wrap(err, "[LocalStorage.Put]: unable to set file mode")
+	}
+	if err := os.Rename(file.Name(), name); err != nil {
+		return errors.Wrap(err, "[LocalStorage.Put]: unable to move file into place")
+	}
+	return nil
+}
+
+// Delete removes a blob, if there is one under its key
+func (s *LocalStorage) Delete(key string) error {
+	if !validKey(key) {
+		return errors.Wrapf(ErrInvalidKey, "[LocalStorage.Delete]: %q", key)
+	}
+
+	if err := os.Remove(filepath.Join(s.dir, filepath.FromSlash(key))); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "[LocalStorage.Delete]: unable to remove file")
+	}
+	return nil
+}
+
+// URL tells where a blob is served
+func (s *LocalStorage) URL(key string) string {
+	return s.baseURL + "/" + key
+}
@@ -0,0 +1,198 @@
+package blob
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// S3Config is where an S3 compatible storage is and the credentials requests to it are signed with
+type S3Config struct {
+	Endpoint        string // such as https://s3.eu-west-1.amazonaws.com or http://localhost:9000
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	PublicURL       string // where the objects of the bucket are served, the endpoint itself when empty
+}
+
+// S3Storage stores blobs as objects in a bucket of an S3 compatible storage, addressed by path so that
+// stand-ins such as MinIO work without a DNS name per bucket
+type S3Storage struct {
+	config S3Config
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewS3Storage creates a storage of the objects in a bucket
+func NewS3Storage(config S3Config) *S3Storage {
+	config.Endpoint = strings.TrimSuffix(config.Endpoint, "/")
+	config.PublicURL = strings.TrimSuffix(config.PublicURL, "/")
+	if config.PublicURL == "" {
+		config.PublicURL = config.Endpoint + "/" + config.Bucket
+	}
+	return &S3Storage{config: config, client: &http.Client{Timeout: 30 * time.Second}, now: time.Now}
+}
+
+// Put uploads a blob, replacing the one under its key
+func (s *S3Storage) Put(key string, data []byte, contentType string) error {
+	if !validKey(key) {
+		return errors.Wrapf(ErrInvalidKey, "[S3Storage.Put]: %q", key)
+	}
+
+	req, err := http.NewRequest(http.MethodPut, s.objectURL(key), bytes.NewReader(data))
+	if err != nil {
+		return errors.Wrap(err, "[S3Storage.Put]: unable to create request")
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	if err := s.do(req, data, http.StatusOK); err != nil {
+		return errors.Wrap(err, "[S3Storage.Put]: unable to upload object")
+	}
+	return nil
+}
+
+// Delete removes a blob, if there is one under its key
+func (s *S3Storage) Delete(key string) error {
+	if !validKey(key) {
+		return errors.Wrapf(ErrInvalidKey, "[S3Storage.Delete]: %q", key)
+	}
+
+	req, err := http.NewRequest(http.MethodDelete, s.objectURL(key), nil)
+	if err != nil {
+		return errors.Wrap(err, "[S3Storage.Delete]: unable to create request")
+	}
+
+	// Deleting an object that is not there succeeds on S3 itself, but not on every stand-in
+	if err := s.do(req, nil, http.StatusNoContent, http.StatusOK, http.StatusNotFound); err != nil {
+		return errors.Wrap(err, "[S3Storage.Delete]: unable to delete object")
+	}
+	return nil
+}
+
+// URL tells where a blob is served
+func (s *S3Storage) URL(key string) string {
+	return s.config.PublicURL + "/" + key
+}
+
+func (s *S3Storage) objectURL(key string) string {
+	return s.config.Endpoint + "/" + url.PathEscape(s.config.Bucket) + "/" + escapePath(key)
+}
+
+// do signs and sends a request, failing unless it is answered with one of the statuses expected
+func (s *S3Storage) do(req *http.Request, payload []byte, statuses ...int) error {
+	sum := sha256.Sum256(payload)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(sum[:]))
+	req.Header.Set("X-Amz-Date", s.now().UTC().Format(amzDateFormat))
+	signRequest(req, s.config.Region, s.config.AccessKeyID, s.config.SecretAccessKey)
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	for _, status := range statuses {
+		if res.StatusCode == status {
+			return nil
+		}
+	}
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+	return errors.Errorf("unexpected status %s: %s", res.Status, strings.TrimSpace(string(body)))
+}
+
+const amzDateFormat = "20060102T150405Z"
+
+// signRequest signs a request with AWS Signature Version 4, over its host and every header it has. The
+// request must have its X-Amz-Date and X-Amz-Content-Sha256 headers set.
+func signRequest(req *http.Request, region, accessKeyID, secretAccessKey string) {
+	amzDate := req.Header.Get("X-Amz-Date")
+	date := amzDate[:8]
+	scope := date + "/" + region + "/s3/aws4_request"
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		escapePath(req.URL.Path),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		req.Header.Get("X-Amz-Content-Sha256"),
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+secretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+accessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// escapePath escapes each segment of a path the way AWS does, leaving only unreserved characters as they are
+func escapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		segments[i] = escapeAWS(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// canonicalQuery writes the parameters of a query sorted by name and then value
+func canonicalQuery(query url.Values) string {
+	params := make([]string, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			params = append(params, escapeAWS(name)+"="+escapeAWS(value))
+		}
+	}
+	sort.Strings(params)
+	return strings.Join(params, "&")
+}
+
+func escapeAWS(s string) string {
+	var escaped strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || strings.IndexByte("-_.~", c) >= 0 {
+			escaped.WriteByte(c)
+		} else {
+			escaped.WriteString("%" + strings.ToUpper(hex.EncodeToString([]byte{c})))
+		}
+	}
+	return escaped.String()
+}
@@ -0,0 +1,369 @@
+// Package cql parses queries in the Contextual Query Language, the query language of SRU, into a tree
+// of search clauses joined by boolean operators. What an index or relation means is left to the caller.
+package cql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ServerChoice is the index of a search clause that names none, searched however the server sees fit
+const ServerChoice = "cql.serverChoice"
+
+// Boolean operators, always lowercase in a parsed query
+const (
+	And  = "and"
+	Or   = "or"
+	Not  = "not" // matches what the left matches and the right does not
+	Prox = "prox"
+)
+
+// Query is a parsed query with the context set prefixes it assigns and the keys it sorts by
+type Query struct {
+	Prefixes []Prefix
+	Root     Node
+	SortKeys []SortKey
+}
+
+// Node is a search clause or a boolean of two nodes
+type Node interface {
+	cqlNode()
+}
+
+// SearchClause matches a search term against an index. Named relations are lowercase, and the term keeps
+// its backslash escapes apart from escaped quotes, so masking characters can still be told from literal ones.
+type SearchClause struct {
+	Index    string
+	Relation Relation
+	Term     string
+}
+
+// Relation is how a search clause compares its term to its index
+type Relation struct {
+	Name      string
+	Modifiers []Modifier
+}
+
+// Boolean joins two nodes by an operator
+type Boolean struct {
+	Operator  string
+	Modifiers []Modifier
+	Left      Node
+	Right     Node
+}
+
+// Modifier qualifies a relation, boolean or sort key, with a value when it has a comparison
+type Modifier struct {
+	Name       string
+	Comparison string
+	Value      string
+}
+
+// Prefix assigns a context set to a prefix of index names, or sets the default one when it has no name
+type Prefix struct {
+	Name string
+	URI  string
+}
+
+// SortKey is an index a query sorts by
+type SortKey struct {
+	Index     string
+	Modifiers []Modifier
+}
+
+func (SearchClause) cqlNode() {}
+func (Boolean) cqlNode()      {}
+
+// SyntaxError is a query that is not CQL, with where in the query it stops making sense
+type SyntaxError struct {
+	Pos     int
+	Message string
+}
+
+// Error describes the error
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("cql: %s at position %d", e.Message, e.Pos)
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenLeftParen
+	tokenRightParen
+	tokenSlash
+	tokenComparison // =, ==, <>, <, >, <= or >=
+	tokenWord       // a string of characters up to a space or special character
+	tokenString     // a quoted string
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// word tells whether a token is the unquoted word given, in any case
+func (t token) word(word string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, word)
+}
+
+// term tells whether a token can be a search term or index
+func (t token) term() bool {
+	return t.kind == tokenWord || t.kind == tokenString
+}
+
+// tokenize splits a query into tokens, ending with an EOF token
+func tokenize(query string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{tokenLeftParen, "(", i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{tokenRightParen, ")", i})
+			i++
+		case c == '/':
+			tokens = append(tokens, token{tokenSlash, "/", i})
+			i++
+		case c == '=' || c == '<' || c == '>':
+			length := 1
+			if i+1 < len(query) {
+				switch query[i : i+2] {
+				case "==", "<>", "<=", ">=":
+					length = 2
+				}
+			}
+			tokens = append(tokens, token{tokenComparison, query[i : i+length], i})
+			i += length
+		case c == '"':
+			var text strings.Builder
+			start := i
+			for i++; ; i++ {
+				if i >= len(query) {
+					return nil, &SyntaxError{start, "unterminated quoted string"}
+				}
+				if query[i] == '"' {
+					i++
+					break
+				}
+				if query[i] == '\\' && i+1 < len(query) {
+					// An escaped quote is part of the string, any other escape is kept for the term to interpret
+					if query[i+1] != '"' {
+						text.WriteByte('\\')
+					}
+					i++
+				}
+				text.WriteByte(query[i])
+			}
+			tokens = append(tokens, token{tokenString, text.String(), start})
+		default:
+			start := i
+			for i < len(query) && !strings.ContainsRune(" \t\n\r()/=<>\"", rune(query[i])) {
+				i++
+			}
+			tokens = append(tokens, token{tokenWord, query[start:i], start})
+		}
+	}
+	return append(tokens, token{tokenEOF, "", len(query)}), nil
+}
+
+// parser reads a query a token at a time
+type parser struct {
+	tokens []token
+	next   int
+	query  Query
+}
+
+// Parse parses a query
+func Parse(query string) (*Query, error) {
+	tokens, err := tokenize(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseQuery()
+	if err != nil {
+		return nil, err
+	}
+	p.query.Root = root
+
+	if p.peek().word("sortby") {
+		p.advance()
+		if err := p.parseSortKeys(); err != nil {
+			return nil, err
+		}
+	}
+
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, &SyntaxError{t.pos, fmt.Sprintf("unexpected %q", t.text)}
+	}
+	return &p.query, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) advance() token {
+	t := p.tokens[p.next]
+	if t.kind != tokenEOF {
+		p.next++
+	}
+	return t
+}
+
+// expectTerm reads a search term, index or other string
+func (p *parser) expectTerm(what string) (string, error) {
+	t := p.peek()
+	if !t.term() {
+		return "", &SyntaxError{t.pos, "expected " + what}
+	}
+	p.advance()
+	return t.text, nil
+}
+
+// parseQuery reads prefix assignments and then search clauses joined by booleans, left to right
+func (p *parser) parseQuery() (Node, error) {
+	for t := p.peek(); t.kind == tokenComparison && t.text == ">"; t = p.peek() {
+		p.advance()
+		prefix, err := p.parsePrefix()
+		if err != nil {
+			return nil, err
+		}
+		p.query.Prefixes = append(p.query.Prefixes, prefix)
+	}
+
+	left, err := p.parseSearchClause()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		operator := strings.ToLower(p.peek().text)
+		if p.peek().kind != tokenWord || (operator != And && operator != Or && operator != Not && operator != Prox) {
+			return left, nil
+		}
+		p.advance()
+
+		modifiers, err := p.parseModifiers()
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := p.parseSearchClause()
+		if err != nil {
+			return nil, err
+		}
+		left = Boolean{Operator: operator, Modifiers: modifiers, Left: left, Right: right}
+	}
+}
+
+// parsePrefix reads a prefix assignment after its >, either name = uri or a uri alone
+func (p *parser) parsePrefix() (Prefix, error) {
+	first, err := p.expectTerm("a context set")
+	if err != nil {
+		return Prefix{}, err
+	}
+
+	if t := p.peek(); t.kind == tokenComparison && t.text == "=" {
+		p.advance()
+		uri, err := p.expectTerm("a context set")
+		if err != nil {
+			return Prefix{}, err
+		}
+		return Prefix{Name: first, URI: uri}, nil
+	}
+	return Prefix{URI: first}, nil
+}
+
+// parseSearchClause reads a query in parentheses, or a search term with or without an index and relation
+func (p *parser) parseSearchClause() (Node, error) {
+	if p.peek().kind == tokenLeftParen {
+		p.advance()
+		node, err := p.parseQuery()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.peek(); t.kind != tokenRightParen {
+			return nil, &SyntaxError{t.pos, "expected )"}
+		}
+		p.advance()
+		return node, nil
+	}
+
+	first, err := p.expectTerm("a search term")
+	if err != nil {
+		return nil, err
+	}
+
+	// Two strings in a row are an index and a named relation, as a term is never followed by another
+	t := p.peek()
+	named := t.kind == tokenWord && !t.word(And) && !t.word(Or) && !t.word(Not) && !t.word(Prox) && !t.word("sortby")
+	if t.kind != tokenComparison && !named {
+		return SearchClause{Index: ServerChoice, Relation: Relation{Name: "="}, Term: first}, nil
+	}
+	p.advance()
+
+	relation := Relation{Name: t.text}
+	if named {
+		relation.Name = strings.ToLower(t.text)
+	}
+	if relation.Modifiers, err = p.parseModifiers(); err != nil {
+		return nil, err
+	}
+
+	term, err := p.expectTerm("a search term")
+	if err != nil {
+		return nil, err
+	}
+	return SearchClause{Index: first, Relation: relation, Term: term}, nil
+}
+
+// parseModifiers reads the modifiers of a relation, boolean or sort key, each after a slash
+func (p *parser) parseModifiers() ([]Modifier, error) {
+	var modifiers []Modifier
+	for p.peek().kind == tokenSlash {
+		p.advance()
+		name, err := p.expectTerm("a modifier")
+		if err != nil {
+			return nil, err
+		}
+
+		modifier := Modifier{Name: name}
+		if t := p.peek(); t.kind == tokenComparison {
+			p.advance()
+			modifier.Comparison = t.text
+			if modifier.Value, err = p.expectTerm("a modifier value"); err != nil {
+				return nil, err
+			}
+		}
+		modifiers = append(modifiers, modifier)
+	}
+	return modifiers, nil
+}
+
+// parseSortKeys reads the keys after sortby up to the end of the query
+func (p *parser) parseSortKeys() error {
+	for p.peek().kind != tokenEOF {
+		index, err := p.expectTerm("a sort key")
+		if err != nil {
+			return err
+		}
+		modifiers, err := p.parseModifiers()
+		if err != nil {
+			return err
+		}
+		p.query.SortKeys = append(p.query.SortKeys, SortKey{Index: index, Modifiers: modifiers})
+	}
+
+	if len(p.query.SortKeys) == 0 {
+		return &SyntaxError{p.peek().pos, "expected a sort key"}
+	}
+	return nil
+}
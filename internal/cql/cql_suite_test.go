@@ -0,0 +1,13 @@
+package cql_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestCQL(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "CQL Suite")
+}
@@ -0,0 +1,129 @@
+package cql_test
+
+import (
+	"go-library-service/internal/cql"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CQL", func() {
+	clause := func(index, relation, term string) cql.SearchClause {
+		return cql.SearchClause{Index: index, Relation: cql.Relation{Name: relation}, Term: term}
+	}
+
+	It("should search the server's choice of index for a term alone", func() {
+		query, err := cql.Parse("dune")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(query.Root).To(Equal(clause(cql.ServerChoice, "=", "dune")))
+	})
+
+	It("should parse an index, relation and quoted term", func() {
+		query, err := cql.Parse(`dc.title = "children of dune"`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(query.Root).To(Equal(clause("dc.title", "=", "children of dune")))
+	})
+
+	It("should lowercase named relations and read their modifiers", func() {
+		query, err := cql.Parse(`title ALL/stem/locale=en "dune messiah"`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(query.Root).To(Equal(cql.SearchClause{
+			Index: "title",
+			Relation: cql.Relation{Name: "all", Modifiers: []cql.Modifier{
+				{Name: "stem"},
+				{Name: "locale", Comparison: "=", Value: "en"},
+			}},
+			Term: "dune messiah",
+		}))
+	})
+
+	It("should parse symbolic relations", func() {
+		for _, relation := range []string{"=", "==", "<>", "<", ">", "<=", ">="} {
+			query, err := cql.Parse("year" + relation + "1965")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(query.Root).To(Equal(clause("year", relation, "1965")))
+		}
+	})
+
+	It("should join clauses left to right and group them by parentheses", func() {
+		query, err := cql.Parse(`title=dune AND author=herbert or (subject=space NOT subject=horror)`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(query.Root).To(Equal(cql.Boolean{
+			Operator: cql.Or,
+			Left: cql.Boolean{
+				Operator: cql.And,
+				Left:     clause("title", "=", "dune"),
+				Right:    clause("author", "=", "herbert"),
+			},
+			Right: cql.Boolean{
+				Operator: cql.Not,
+				Left:     clause("subject", "=", "space"),
+				Right:    clause("subject", "=", "horror"),
+			},
+		}))
+	})
+
+	It("should read boolean modifiers", func() {
+		query, err := cql.Parse(`dune prox/unit=word/distance<3 messiah`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(query.Root).To(Equal(cql.Boolean{
+			Operator: cql.Prox,
+			Modifiers: []cql.Modifier{
+				{Name: "unit", Comparison: "=", Value: "word"},
+				{Name: "distance", Comparison: "<", Value: "3"},
+			},
+			Left:  clause(cql.ServerChoice, "=", "dune"),
+			Right: clause(cql.ServerChoice, "=", "messiah"),
+		}))
+	})
+
+	It("should keep escapes in terms apart from escaped quotes", func() {
+		query, err := cql.Parse(`title="say \"hi\" \* \\"`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(query.Root).To(Equal(clause("title", "=", `say "hi" \* \\`)))
+	})
+
+	It("should treat quoted keywords as terms", func() {
+		query, err := cql.Parse(`"and" and "sortby"`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(query.Root).To(Equal(cql.Boolean{
+			Operator: cql.And,
+			Left:     clause(cql.ServerChoice, "=", "and"),
+			Right:    clause(cql.ServerChoice, "=", "sortby"),
+		}))
+	})
+
+	It("should parse prefix assignments and sort keys", func() {
+		query, err := cql.Parse(`> dc = "info:srw/cql-context-set/1/dc-v1.1" > "info:x" dc.title=dune sortby dc.date/sort.descending title`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(query.Prefixes).To(Equal([]cql.Prefix{
+			{Name: "dc", URI: "info:srw/cql-context-set/1/dc-v1.1"},
+			{URI: "info:x"},
+		}))
+		Expect(query.Root).To(Equal(clause("dc.title", "=", "dune")))
+		Expect(query.SortKeys).To(Equal([]cql.SortKey{
+			{Index: "dc.date", Modifiers: []cql.Modifier{{Name: "sort.descending"}}},
+			{Index: "title"},
+		}))
+	})
+
+	It("should report where a query stops making sense", func() {
+		for query, pos := range map[string]int{
+			"":                  0,
+			"title =":           7,
+			"title = dune and":  16,
+			"(title = dune":     13,
+			"title = dune)":     12,
+			`title = "dune`:     8,
+			"dune sortby":       11,
+			"title =/":          8,
+			`dune "messiah"`:    5,
+			"title = dune or )": 16,
+		} {
+			_, err := cql.Parse(query)
+			var syntaxErr *cql.SyntaxError
+			Expect(err).To(BeAssignableToTypeOf(syntaxErr), query)
+			Expect(err.(*cql.SyntaxError).Pos).To(Equal(pos), query)
+		}
+	})
+})
@@ -0,0 +1,26 @@
+// Package dublincore describes items in the fifteen elements of simple Dublin Core, the element set
+// catalogs exchange records in when they share no richer format
+package dublincore
+
+// Namespace is the namespace of the elements
+const Namespace = "http://purl.org/dc/elements/1.1/"
+
+// Record describes an item in the elements of simple Dublin Core, each of which may repeat.
+// A format wraps the elements in a root element of its own.
+type Record struct {
+	Title       []string `xml:"dc:title"`
+	Creator     []string `xml:"dc:creator"`
+	Subject     []string `xml:"dc:subject"`
+	Description []string `xml:"dc:description"`
+	Publisher   []string `xml:"dc:publisher"`
+	Contributor []string `xml:"dc:contributor"`
+	Date        []string `xml:"dc:date"`
+	Type        []string `xml:"dc:type"`
+	Format      []string `xml:"dc:format"`
+	Identifier  []string `xml:"dc:identifier"`
+	Source      []string `xml:"dc:source"`
+	Language    []string `xml:"dc:language"`
+	Relation    []string `xml:"dc:relation"`
+	Coverage    []string `xml:"dc:coverage"`
+	Rights      []string `xml:"dc:rights"`
+}
@@ -0,0 +1,13 @@
+package dublincore_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestDublinCore(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Dublin Core Suite")
+}
@@ -0,0 +1,29 @@
+package dublincore_test
+
+import (
+	"encoding/xml"
+
+	"go-library-service/internal/dublincore"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Dublin Core", func() {
+	It("should write the elements in order, leaving out the empty ones", func() {
+		data, err := xml.Marshal(struct {
+			XMLName xml.Name `xml:"dc"`
+			dublincore.Record
+		}{Record: dublincore.Record{
+			Title:      []string{"Dune"},
+			Creator:    []string{"Frank Herbert"},
+			Identifier: []string{"urn:isbn:9780441172719", "http://library.test/api/books/1"},
+			Language:   []string{"en"},
+		}})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(string(data)).To(Equal(`<dc><dc:title>Dune</dc:title><dc:creator>Frank Herbert</dc:creator>` +
+			`<dc:identifier>urn:isbn:9780441172719</dc:identifier><dc:identifier>http://library.test/api/books/1</dc:identifier>` +
+			`<dc:language>en</dc:language></dc>`))
+	})
+})
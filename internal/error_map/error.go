@@ -1,10 +1,58 @@
 package errmap
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrmapConflict = errors.New("conflict")
 	ErrmapNotFound = errors.New("not found")
 	ErrmapInvalidPassword = errors.New("invalid password")
 	ErrmapInvalidStock = errors.New("invalid stock")
-)
\ No newline at end of file
+	ErrmapRenewalLimit = errors.New("renewal limit reached")
+	ErrmapHoldPending = errors.New("hold pending")
+	ErrmapBookAvailable = errors.New("book available")
+	ErrmapInvalidAmount = errors.New("invalid amount")
+	ErrmapBorrowNotLost = errors.New("book is not lost or damaged")
+	ErrmapLoanLimit = errors.New("loan limit reached")
+	ErrmapAmbiguousCopy = errors.New("more than one copy matches")
+	ErrmapActiveLoans = errors.New("book has active loans")
+	ErrmapInvalidCursor = errors.New("invalid cursor")
+	ErrmapUnknownAuthor = errors.New("unknown author")
+	ErrmapUnknownCategory = errors.New("unknown category")
+	ErrmapCategoryCycle = errors.New("category cannot be moved under itself")
+	ErrmapInvalidISBN = errors.New("invalid isbn")
+	ErrmapInvalidImport = errors.New("invalid import file")
+	ErrmapCoverTooLarge = errors.New("cover too large")
+	ErrmapUnsupportedCover = errors.New("unsupported cover type")
+	ErrmapInvalidCover = errors.New("invalid cover image")
+)
+
+// LoanLimitError tells which loan policy limit a borrower has reached
+type LoanLimitError struct {
+	Limit string
+	Max   uint
+}
+
+func (e *LoanLimitError) Error() string {
+	return fmt.Sprintf("limit of %d %s reached", e.Max, e.Limit)
+}
+
+// Is matches ErrmapLoanLimit so callers can check the error without its details
+func (e *LoanLimitError) Is(target error) bool {
+	return target == ErrmapLoanLimit
+}
+// ImportRowError tells which row of an import could not be written
+type ImportRowError struct {
+	Line int
+	Err  error
+}
+
+func (e *ImportRowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *ImportRowError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,90 @@
+// Package isbn checks International Standard Book Numbers and brings them to one form
+package isbn
+
+import "strings"
+
+// Normalize checks an ISBN-10 or ISBN-13, written with or without hyphens and spaces,
+// and returns it as the 13 digits of an ISBN-13
+func Normalize(value string) (string, bool) {
+	digits := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(value)))
+
+	switch len(digits) {
+	case 10:
+		if !validISBN10(digits) {
+			return "", false
+		}
+		isbn13 := "978" + digits[:9]
+		return isbn13 + string(checkDigit13(isbn13)), true
+	case 13:
+		if !allDigits(digits) || checkDigit13(digits[:12]) != digits[12] {
+			return "", false
+		}
+		return digits, true
+	}
+	return "", false
+}
+
+// Valid tells whether a value is an ISBN-10 or ISBN-13 with a correct check digit
+func Valid(value string) bool {
+	_, ok := Normalize(value)
+	return ok
+}
+
+// ISBN10 returns the ISBN-10 form of an ISBN-13, which only those starting with 978 have
+func ISBN10(isbn13 string) (string, bool) {
+	normalized, ok := Normalize(isbn13)
+	if !ok || !strings.HasPrefix(normalized, "978") {
+		return "", false
+	}
+
+	digits := normalized[3:12]
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += (10 - i) * int(digits[i]-'0')
+	}
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return digits + "X", true
+	}
+	return digits + string(rune('0'+check)), true
+}
+
+// validISBN10 checks the weighted sum of an ISBN-10, whose check digit may be X for ten
+func validISBN10(digits string) bool {
+	sum := 0
+	for i, r := range digits {
+		var d int
+		switch {
+		case r >= '0' && r <= '9':
+			d = int(r - '0')
+		case r == 'X' && i == 9:
+			d = 10
+		default:
+			return false
+		}
+		sum += (10 - i) * d
+	}
+	return sum%11 == 0
+}
+
+// checkDigit13 computes the check digit following the first 12 digits of an ISBN-13
+func checkDigit13(digits string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		d := int(digits[i] - '0')
+		if i%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+func allDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
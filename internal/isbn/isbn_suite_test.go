@@ -0,0 +1,13 @@
+package isbn_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestISBN(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "ISBN Suite")
+}
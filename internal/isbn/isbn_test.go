@@ -0,0 +1,55 @@
+package isbn_test
+
+import (
+	"go-library-service/internal/isbn"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ISBN", func() {
+	DescribeTable("Normalize",
+		func(value, expected string) {
+			normalized, ok := isbn.Normalize(value)
+			Expect(ok).To(BeTrue())
+			Expect(normalized).To(Equal(expected))
+		},
+		Entry("isbn-13", "9780306406157", "9780306406157"),
+		Entry("hyphenated isbn-13", "978-0-306-40615-7", "9780306406157"),
+		Entry("isbn-10", "0306406152", "9780306406157"),
+		Entry("isbn-10 with spaces", " 0 306 40615 2 ", "9780306406157"),
+		Entry("isbn-10 checked by x", "0-8044-2957-X", "9780804429573"),
+		Entry("lowercase x", "080442957x", "9780804429573"),
+		Entry("979 prefix", "979-10-90636-07-1", "9791090636071"),
+	)
+
+	DescribeTable("ISBN10",
+		func(value, expected string) {
+			isbn10, ok := isbn.ISBN10(value)
+			Expect(ok).To(BeTrue())
+			Expect(isbn10).To(Equal(expected))
+		},
+		Entry("isbn-13", "9780306406157", "0306406152"),
+		Entry("checked by x", "9780804429573", "080442957X"),
+		Entry("hyphenated", "978-0-441-17271-9", "0441172717"),
+	)
+
+	It("should have no ISBN-10 for a 979 prefix", func() {
+		_, ok := isbn.ISBN10("9791090636071")
+		Expect(ok).To(BeFalse())
+	})
+
+	DescribeTable("invalid numbers",
+		func(value string) {
+			_, ok := isbn.Normalize(value)
+			Expect(ok).To(BeFalse())
+			Expect(isbn.Valid(value)).To(BeFalse())
+		},
+		Entry("wrong isbn-13 check digit", "9780306406158"),
+		Entry("wrong isbn-10 check digit", "0306406153"),
+		Entry("x before the check digit", "030640X152"),
+		Entry("letters", "97803064061AB"),
+		Entry("too short", "12345"),
+		Entry("empty", ""),
+	)
+})
@@ -0,0 +1,207 @@
+package marc
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+)
+
+const (
+	leaderLength      = 24
+	directoryEntry    = 12
+	fieldTerminator   = 0x1E
+	recordTerminator  = 0x1D
+	subfieldDelimiter = 0x1F
+)
+
+// Reader reads records from an ISO 2709 exchange file
+type Reader struct {
+	r *bufio.Reader
+}
+
+// NewReader creates a reader of ISO 2709 records
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Read reads the next record, returning io.EOF when there are no more
+func (r *Reader) Read() (Record, error) {
+	// Some files put a line break between records
+	for {
+		b, err := r.r.ReadByte()
+		if err != nil {
+			return Record{}, err
+		}
+		if b != '\n' && b != '\r' {
+			if err := r.r.UnreadByte(); err != nil {
+				return Record{}, err
+			}
+			break
+		}
+	}
+
+	prefix := make([]byte, 5)
+	if _, err := io.ReadFull(r.r, prefix); err != nil {
+		return Record{}, fmt.Errorf("%w: truncated leader", ErrInvalidRecord)
+	}
+
+	length, ok := decimal(prefix)
+	if !ok || length < leaderLength+1 {
+		return Record{}, fmt.Errorf("%w: bad record length %q", ErrInvalidRecord, prefix)
+	}
+
+	data := make([]byte, length)
+	copy(data, prefix)
+	if _, err := io.ReadFull(r.r, data[5:]); err != nil {
+		return Record{}, fmt.Errorf("%w: record shorter than its length %d", ErrInvalidRecord, length)
+	}
+
+	return decodeRecord(data)
+}
+
+// decodeRecord splits a record into its leader, directory and fields
+func decodeRecord(data []byte) (Record, error) {
+	if data[len(data)-1] != recordTerminator {
+		return Record{}, fmt.Errorf("%w: missing record terminator", ErrInvalidRecord)
+	}
+
+	leader := data[:leaderLength]
+	base, ok := decimal(leader[12:17])
+	if !ok || base <= leaderLength || base > len(data) {
+		return Record{}, fmt.Errorf("%w: bad base address %q", ErrInvalidRecord, leader[12:17])
+	}
+
+	// Leader/09 is a for Unicode and blank for MARC-8, whose diacritics would be garbled when read as UTF-8
+	if leader[9] != 'a' {
+		return Record{}, ErrMARC8
+	}
+
+	directory := data[leaderLength : base-1]
+	if data[base-1] != fieldTerminator || len(directory)%directoryEntry != 0 {
+		return Record{}, fmt.Errorf("%w: bad directory", ErrInvalidRecord)
+	}
+
+	record := Record{Leader: string(leader)}
+	for i := 0; i < len(directory); i += directoryEntry {
+		entry := directory[i : i+directoryEntry]
+		tag := string(entry[:3])
+		length, lengthOK := decimal(entry[3:7])
+		start, startOK := decimal(entry[7:12])
+		if !lengthOK || !startOK || length < 1 || base+start+length > len(data)-1 {
+			return Record{}, fmt.Errorf("%w: bad directory entry for %s", ErrInvalidRecord, tag)
+		}
+
+		value := data[base+start : base+start+length]
+		if value[len(value)-1] != fieldTerminator {
+			return Record{}, fmt.Errorf("%w: field %s is not terminated", ErrInvalidRecord, tag)
+		}
+		value = value[:len(value)-1]
+
+		if IsControl(tag) {
+			record.Fields = append(record.Fields, Field{Tag: tag, Value: string(value)})
+			continue
+		}
+
+		field, err := decodeDataField(tag, value)
+		if err != nil {
+			return Record{}, err
+		}
+		record.Fields = append(record.Fields, field)
+	}
+
+	return record, nil
+}
+
+// decimal reads a number of the leader or directory, which are written with digits only
+func decimal(b []byte) (int, bool) {
+	n := 0
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		n = n*10 + int(c-'0')
+	}
+	return n, true
+}
+
+// decodeDataField reads the indicators and subfields of a data field
+func decodeDataField(tag string, value []byte) (Field, error) {
+	if len(value) < 2 {
+		return Field{}, fmt.Errorf("%w: field %s has no indicators", ErrInvalidRecord, tag)
+	}
+
+	field := Field{Tag: tag, Indicator1: value[0], Indicator2: value[1]}
+	for _, part := range bytes.Split(value[2:], []byte{subfieldDelimiter}) {
+		if len(part) == 0 {
+			continue
+		}
+		field.Subfields = append(field.Subfields, Subfield{Code: part[0], Value: string(part[1:])})
+	}
+	return field, nil
+}
+
+// Writer writes records to an ISO 2709 exchange file
+type Writer struct {
+	w io.Writer
+}
+
+// NewWriter creates a writer of ISO 2709 records
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Write writes a record, working out the lengths and addresses of its leader and directory
+func (w *Writer) Write(record Record) error {
+	var directory, fields bytes.Buffer
+	for _, field := range record.Fields {
+		start := fields.Len()
+		if IsControl(field.Tag) {
+			fields.WriteString(field.Value)
+		} else {
+			fields.WriteByte(indicator(field.Indicator1))
+			fields.WriteByte(indicator(field.Indicator2))
+			for _, subfield := range field.Subfields {
+				fields.WriteByte(subfieldDelimiter)
+				fields.WriteByte(subfield.Code)
+				fields.WriteString(subfield.Value)
+			}
+		}
+		fields.WriteByte(fieldTerminator)
+
+		if len(field.Tag) != 3 || fields.Len()-start > 9999 {
+			return fmt.Errorf("%w: field %q cannot be written", ErrInvalidRecord, field.Tag)
+		}
+		fmt.Fprintf(&directory, "%s%04d%05d", field.Tag, fields.Len()-start, start)
+	}
+	directory.WriteByte(fieldTerminator)
+	fields.WriteByte(recordTerminator)
+
+	base := leaderLength + directory.Len()
+	length := base + fields.Len()
+	if length > 99999 {
+		return fmt.Errorf("%w: record longer than 99999 bytes", ErrInvalidRecord)
+	}
+
+	leader := []byte(fmt.Sprintf("%-24.24s", record.Leader))
+	copy(leader[0:5], fmt.Sprintf("%05d", length))
+	leader[9] = 'a'
+	copy(leader[10:12], "22")
+	copy(leader[12:17], fmt.Sprintf("%05d", base))
+	copy(leader[20:24], "4500")
+
+	for _, part := range [][]byte{leader, directory.Bytes(), fields.Bytes()} {
+		if _, err := w.w.Write(part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indicator writes a missing indicator as a blank
+func indicator(b byte) byte {
+	if b == 0 {
+		return ' '
+	}
+	return b
+}
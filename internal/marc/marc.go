@@ -0,0 +1,119 @@
+// Package marc reads and writes bibliographic records in MARC 21, both as ISO 2709 exchange files
+// and as MARCXML
+package marc
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalidRecord is returned for a record that does not follow the MARC structure
+var ErrInvalidRecord = errors.New("invalid marc record")
+
+// ErrMARC8 is returned for an exchange record whose leader says it is encoded in MARC-8 rather than Unicode
+var ErrMARC8 = errors.New("marc-8 encoded record, only unicode records are supported")
+
+// Record is a MARC record, its fields in the order they are written
+type Record struct {
+	Leader string
+	Fields []Field
+}
+
+// Field is a control field, 001 to 009, which only has a value, or a data field with indicators and subfields
+type Field struct {
+	Tag        string
+	Value      string
+	Indicator1 byte
+	Indicator2 byte
+	Subfields  []Subfield
+}
+
+// Subfield is a coded part of a data field
+type Subfield struct {
+	Code  byte
+	Value string
+}
+
+// IsControl tells whether a tag is one of a control field
+func IsControl(tag string) bool {
+	return strings.HasPrefix(tag, "00")
+}
+
+// AddControlField appends a control field
+func (r *Record) AddControlField(tag, value string) {
+	r.Fields = append(r.Fields, Field{Tag: tag, Value: value})
+}
+
+// AddDataField appends a data field, leaving out subfields without a value. A field left with no
+// subfields is not added at all.
+func (r *Record) AddDataField(tag string, indicator1, indicator2 byte, subfields ...Subfield) {
+	field := Field{Tag: tag, Indicator1: indicator1, Indicator2: indicator2}
+	for _, subfield := range subfields {
+		if subfield.Value != "" {
+			field.Subfields = append(field.Subfields, subfield)
+		}
+	}
+
+	if len(field.Subfields) > 0 {
+		r.Fields = append(r.Fields, field)
+	}
+}
+
+// ControlField returns the value of the first control field with a tag
+func (r Record) ControlField(tag string) string {
+	for _, field := range r.Fields {
+		if field.Tag == tag {
+			return field.Value
+		}
+	}
+	return ""
+}
+
+// DataFields returns the data fields with a tag in order
+func (r Record) DataFields(tag string) []Field {
+	var fields []Field
+	for _, field := range r.Fields {
+		if field.Tag == tag {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// Subfield returns the value of the first subfield with a code
+func (f Field) Subfield(code byte) string {
+	for _, subfield := range f.Subfields {
+		if subfield.Code == code {
+			return subfield.Value
+		}
+	}
+	return ""
+}
+
+// languageCodes maps the primary subtag of a BCP 47 language tag to its MARC language code
+var languageCodes = map[string]string{
+	"ar": "ara", "de": "ger", "en": "eng", "es": "spa", "fr": "fre", "hi": "hin", "id": "ind",
+	"it": "ita", "ja": "jpn", "km": "khm", "ko": "kor", "la": "lat", "lo": "lao", "ms": "may",
+	"my": "bur", "nl": "dut", "pt": "por", "ru": "rus", "sv": "swe", "th": "tha", "vi": "vie",
+	"zh": "chi",
+}
+
+// LanguageCode returns the MARC code of a BCP 47 language tag, und when it is not known
+func LanguageCode(tag string) string {
+	primary, _, _ := strings.Cut(strings.ToLower(tag), "-")
+	if code, ok := languageCodes[primary]; ok {
+		return code
+	}
+	return "und"
+}
+
+// LanguageTag returns the BCP 47 language tag of a MARC language code, empty when it is not known
+func LanguageTag(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	for tag, known := range languageCodes {
+		if known == code {
+			return tag
+		}
+	}
+	return ""
+}
@@ -0,0 +1,13 @@
+package marc_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestMARC(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "MARC Suite")
+}
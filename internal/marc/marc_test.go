@@ -0,0 +1,184 @@
+package marc_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"os"
+	"strings"
+
+	"go-library-service/internal/marc"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// readAll reads every record of a fixture file
+func readAll(read func() (marc.Record, error)) []marc.Record {
+	var records []marc.Record
+	for {
+		record, err := read()
+		if err == io.EOF {
+			return records
+		}
+		Expect(err).NotTo(HaveOccurred())
+		records = append(records, record)
+	}
+}
+
+var _ = Describe("MARC", func() {
+	var (
+		iso2709 []byte
+		marcXML []byte
+	)
+
+	BeforeEach(func() {
+		var err error
+		iso2709, err = os.ReadFile("testdata/books.mrc")
+		Expect(err).NotTo(HaveOccurred())
+		marcXML, err = os.ReadFile("testdata/books.xml")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Context("Reader", func() {
+		It("should read the records of an exchange file", func() {
+			records := readAll(marc.NewReader(bytes.NewReader(iso2709)).Read)
+			Expect(records).To(HaveLen(2))
+
+			dune := records[0]
+			Expect(dune.Leader).To(Equal("00334nam a22001457c 4500"))
+			Expect(dune.ControlField("001")).To(Equal("1"))
+			Expect(dune.DataFields("245")).To(Equal([]marc.Field{{
+				Tag: "245", Indicator1: '1', Indicator2: '0',
+				Subfields: []marc.Subfield{{Code: 'a', Value: "Dune"}, {Code: 'c', Value: "Frank Herbert"}},
+			}}))
+			Expect(dune.DataFields("653")).To(HaveLen(2))
+			Expect(dune.DataFields("264")[0].Subfield('b')).To(Equal("Chilton Books"))
+
+			Expect(records[1].DataFields("100")[0].Subfield('a')).To(Equal("ปราบดา หยุ่น"))
+		})
+
+		It("should skip line breaks between records", func() {
+			spaced := bytes.Replace(iso2709, []byte{0x1D}, []byte{0x1D, '\r', '\n'}, -1)
+			Expect(readAll(marc.NewReader(bytes.NewReader(spaced)).Read)).To(HaveLen(2))
+		})
+
+		DescribeTable("broken records",
+			func(breakRecord func(record string) string) {
+				_, err := marc.NewReader(strings.NewReader(breakRecord(string(iso2709[:334])))).Read()
+				Expect(err).To(MatchError(marc.ErrInvalidRecord))
+			},
+			Entry("length that is not a number", func(record string) string { return "abcde" + record[5:] }),
+			Entry("record shorter than its length", func(record string) string { return record[:24] }),
+			Entry("missing record terminator", func(record string) string { return record[:333] + "x" }),
+			Entry("directory pointing past the record", func(record string) string {
+				return strings.Replace(record, "001000200000", "001900200000", 1)
+			}),
+			Entry("directory pointing before the record", func(record string) string {
+				return strings.Replace(record, "001000200000", "0010002-9999", 1)
+			}),
+			Entry("directory length with a sign", func(record string) string {
+				return strings.Replace(record, "001000200000", "001+00200000", 1)
+			}),
+			Entry("base address with a sign", func(record string) string { return record[:12] + "+0145" + record[17:] }),
+			Entry("length with a sign", func(record string) string { return "+0334" + record[5:] }),
+		)
+
+		It("should refuse a MARC-8 record", func() {
+			record := string(iso2709[:334])
+			_, err := marc.NewReader(strings.NewReader(record[:9] + " " + record[10:])).Read()
+			Expect(err).To(MatchError(marc.ErrMARC8))
+		})
+	})
+
+	Context("Writer", func() {
+		It("should write records byte for byte as they were read", func() {
+			records := readAll(marc.NewReader(bytes.NewReader(iso2709)).Read)
+
+			var written bytes.Buffer
+			writer := marc.NewWriter(&written)
+			for _, record := range records {
+				record.Leader = "     nam a       7c     "
+				Expect(writer.Write(record)).To(Succeed())
+			}
+			Expect(written.Bytes()).To(Equal(iso2709))
+		})
+	})
+
+	Context("XMLReader", func() {
+		It("should read the same records as the exchange file", func() {
+			fromXML := readAll(marc.NewXMLReader(bytes.NewReader(marcXML)).Read)
+			Expect(fromXML).To(Equal(readAll(marc.NewReader(bytes.NewReader(iso2709)).Read)))
+		})
+
+		It("should read a record outside a collection", func() {
+			record := `<record xmlns="http://www.loc.gov/MARC21/slim"><leader>00000nam a2200000 c 4500</leader>` +
+				`<datafield tag="245" ind1="0" ind2="0"><subfield code="a">Dune</subfield></datafield></record>`
+			records := readAll(marc.NewXMLReader(strings.NewReader(record)).Read)
+			Expect(records).To(HaveLen(1))
+			Expect(records[0].DataFields("245")[0].Subfield('a')).To(Equal("Dune"))
+		})
+
+		It("should refuse a subfield without a one letter code", func() {
+			record := `<record><datafield tag="245" ind1="0" ind2="0"><subfield code="ab">Dune</subfield></datafield></record>`
+			_, err := marc.NewXMLReader(strings.NewReader(record)).Read()
+			Expect(err).To(MatchError(marc.ErrInvalidRecord))
+		})
+	})
+
+	Context("XMLWriter", func() {
+		It("should write a collection like the fixture", func() {
+			var written bytes.Buffer
+			writer := marc.NewXMLWriter(&written)
+			for _, record := range readAll(marc.NewReader(bytes.NewReader(iso2709)).Read) {
+				Expect(writer.Write(record)).To(Succeed())
+			}
+			Expect(writer.Close()).To(Succeed())
+			Expect(written.String()).To(Equal(string(marcXML)))
+		})
+
+		It("should write an empty collection", func() {
+			var written bytes.Buffer
+			Expect(marc.NewXMLWriter(&written).Close()).To(Succeed())
+			Expect(readAll(marc.NewXMLReader(&written).Read)).To(BeEmpty())
+		})
+
+		It("should write a record element of its own outside a collection", func() {
+			records := readAll(marc.NewReader(bytes.NewReader(iso2709)).Read)
+			data, err := xml.Marshal(records[0])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(HavePrefix(`<record xmlns="http://www.loc.gov/MARC21/slim"><leader>`))
+
+			Expect(readAll(marc.NewXMLReader(bytes.NewReader(data)).Read)).To(Equal(records[:1]))
+		})
+	})
+
+	Context("Record", func() {
+		It("should leave out empty subfields and fields", func() {
+			var record marc.Record
+			record.AddDataField("250", ' ', ' ', marc.Subfield{Code: 'a', Value: ""})
+			record.AddDataField("020", ' ', ' ', marc.Subfield{Code: 'a', Value: ""}, marc.Subfield{Code: 'c', Value: "12.50"})
+			Expect(record.Fields).To(Equal([]marc.Field{{
+				Tag: "020", Indicator1: ' ', Indicator2: ' ', Subfields: []marc.Subfield{{Code: 'c', Value: "12.50"}},
+			}}))
+		})
+	})
+
+	DescribeTable("LanguageCode",
+		func(tag, code string) {
+			Expect(marc.LanguageCode(tag)).To(Equal(code))
+		},
+		Entry("english", "en", "eng"),
+		Entry("regional tag", "th-TH", "tha"),
+		Entry("unknown", "tlh", "und"),
+	)
+
+	DescribeTable("LanguageTag",
+		func(code, tag string) {
+			Expect(marc.LanguageTag(code)).To(Equal(tag))
+		},
+		Entry("english", "eng", "en"),
+		Entry("thai", " THA", "th"),
+		Entry("unknown", "und", ""),
+	)
+})
@@ -0,0 +1,173 @@
+package marc
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// Namespace is the namespace of MARCXML
+const Namespace = "http://www.loc.gov/MARC21/slim"
+
+type xmlRecord struct {
+	XMLName       xml.Name          `xml:"record"`
+	Xmlns         string            `xml:"xmlns,attr,omitempty"`
+	Leader        string            `xml:"leader"`
+	ControlFields []xmlControlField `xml:"controlfield"`
+	DataFields    []xmlDataField    `xml:"datafield"`
+}
+
+type xmlControlField struct {
+	Tag   string `xml:"tag,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlDataField struct {
+	Tag       string        `xml:"tag,attr"`
+	Ind1      string        `xml:"ind1,attr"`
+	Ind2      string        `xml:"ind2,attr"`
+	Subfields []xmlSubfield `xml:"subfield"`
+}
+
+type xmlSubfield struct {
+	Code  string `xml:"code,attr"`
+	Value string `xml:",chardata"`
+}
+
+// XMLReader reads the records of a MARCXML collection, or a single record
+type XMLReader struct {
+	d *xml.Decoder
+}
+
+// NewXMLReader creates a reader of MARCXML records
+func NewXMLReader(r io.Reader) *XMLReader {
+	return &XMLReader{d: xml.NewDecoder(r)}
+}
+
+// Read reads the next record, returning io.EOF when there are no more
+func (r *XMLReader) Read() (Record, error) {
+	for {
+		token, err := r.d.Token()
+		if err == io.EOF {
+			return Record{}, io.EOF
+		}
+		if err != nil {
+			return Record{}, fmt.Errorf("%w: %v", ErrInvalidRecord, err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "record" {
+			continue
+		}
+
+		var decoded xmlRecord
+		if err := r.d.DecodeElement(&decoded, &start); err != nil {
+			return Record{}, fmt.Errorf("%w: %v", ErrInvalidRecord, err)
+		}
+		return fromXML(decoded)
+	}
+}
+
+// fromXML turns a decoded MARCXML record into a record, control fields first
+func fromXML(decoded xmlRecord) (Record, error) {
+	record := Record{Leader: decoded.Leader}
+	for _, field := range decoded.ControlFields {
+		record.AddControlField(field.Tag, field.Value)
+	}
+
+	for _, field := range decoded.DataFields {
+		if len(field.Tag) != 3 || len(field.Ind1) > 1 || len(field.Ind2) > 1 {
+			return Record{}, fmt.Errorf("%w: bad data field %q", ErrInvalidRecord, field.Tag)
+		}
+
+		dataField := Field{Tag: field.Tag, Indicator1: firstByte(field.Ind1), Indicator2: firstByte(field.Ind2)}
+		for _, subfield := range field.Subfields {
+			if len(subfield.Code) != 1 {
+				return Record{}, fmt.Errorf("%w: bad subfield code %q in %s", ErrInvalidRecord, subfield.Code, field.Tag)
+			}
+			dataField.Subfields = append(dataField.Subfields, Subfield{Code: subfield.Code[0], Value: subfield.Value})
+		}
+		record.Fields = append(record.Fields, dataField)
+	}
+
+	return record, nil
+}
+
+// XMLWriter writes records to a MARCXML collection
+type XMLWriter struct {
+	w       io.Writer
+	started bool
+}
+
+// NewXMLWriter creates a writer of a MARCXML collection, to be closed once every record is written
+func NewXMLWriter(w io.Writer) *XMLWriter {
+	return &XMLWriter{w: w}
+}
+
+// Write writes a record to the collection
+func (w *XMLWriter) Write(record Record) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+
+	// A new encoder for each record keeps the records from being set apart by blank lines
+	e := xml.NewEncoder(w.w)
+	e.Indent("  ", "  ")
+	if err := e.Encode(toXML(record)); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w.w, "\n")
+	return err
+}
+
+// MarshalXML writes a record as a MARCXML record element of its own, outside of a collection
+func (r Record) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	encoded := toXML(r)
+	encoded.Xmlns = Namespace
+	return e.Encode(encoded)
+}
+
+// toXML lays a record out as MARCXML
+func toXML(record Record) xmlRecord {
+	encoded := xmlRecord{Leader: record.Leader}
+	for _, field := range record.Fields {
+		if IsControl(field.Tag) {
+			encoded.ControlFields = append(encoded.ControlFields, xmlControlField{Tag: field.Tag, Value: field.Value})
+			continue
+		}
+
+		dataField := xmlDataField{Tag: field.Tag, Ind1: string(indicator(field.Indicator1)), Ind2: string(indicator(field.Indicator2))}
+		for _, subfield := range field.Subfields {
+			dataField.Subfields = append(dataField.Subfields, xmlSubfield{Code: string(subfield.Code), Value: subfield.Value})
+		}
+		encoded.DataFields = append(encoded.DataFields, dataField)
+	}
+	return encoded
+}
+
+// Close ends the collection
+func (w *XMLWriter) Close() error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w.w, "</collection>\n")
+	return err
+}
+
+// start opens the collection before the first record
+func (w *XMLWriter) start() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+	_, err := io.WriteString(w.w, xml.Header+`<collection xmlns="`+Namespace+`">`+"\n")
+	return err
+}
+
+// firstByte reads a one character indicator, a blank when there is none
+func firstByte(value string) byte {
+	if value == "" {
+		return ' '
+	}
+	return value[0]
+}
@@ -0,0 +1,370 @@
+// Package oaipmh speaks the Open Archives Initiative Protocol for Metadata Harvesting 2.0: it reads the
+// arguments of a request, writes the response and its Dublin Core records, and carries the state
+// of an incomplete list in a resumption token
+package oaipmh
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"go-library-service/internal/dublincore"
+)
+
+// Namespaces and schemas of a response and its records
+const (
+	Namespace      = "http://www.openarchives.org/OAI/2.0/"
+	Schema         = "http://www.openarchives.org/OAI/2.0/OAI-PMH.xsd"
+	XSINamespace   = "http://www.w3.org/2001/XMLSchema-instance"
+	OAIDCNamespace = "http://www.openarchives.org/OAI/2.0/oai_dc/"
+	OAIDCSchema    = "http://www.openarchives.org/OAI/2.0/oai_dc.xsd"
+)
+
+// ProtocolVersion is the version of the protocol spoken
+const ProtocolVersion = "2.0"
+
+// OAIDC is the prefix of the Dublin Core metadata format every repository supports
+const OAIDC = "oai_dc"
+
+// Verbs of a request
+const (
+	VerbIdentify            = "Identify"
+	VerbListMetadataFormats = "ListMetadataFormats"
+	VerbListSets            = "ListSets"
+	VerbGetRecord           = "GetRecord"
+	VerbListIdentifiers     = "ListIdentifiers"
+	VerbListRecords         = "ListRecords"
+)
+
+// Codes of the errors a request can be answered with
+const (
+	ErrBadArgument             = "badArgument"
+	ErrBadResumptionToken      = "badResumptionToken"
+	ErrBadVerb                 = "badVerb"
+	ErrCannotDisseminateFormat = "cannotDisseminateFormat"
+	ErrIDDoesNotExist          = "idDoesNotExist"
+	ErrNoRecordsMatch          = "noRecordsMatch"
+	ErrNoMetadataFormats       = "noMetadataFormats"
+	ErrNoSetHierarchy          = "noSetHierarchy"
+)
+
+// Ways a repository keeps track of deleted records
+const (
+	DeletedRecordNo         = "no"
+	DeletedRecordTransient  = "transient"
+	DeletedRecordPersistent = "persistent"
+)
+
+// StatusDeleted marks the header of a deleted record
+const StatusDeleted = "deleted"
+
+// Granularities of datestamps
+const (
+	GranularityDay    = "YYYY-MM-DD"
+	GranularitySecond = "YYYY-MM-DDThh:mm:ssZ"
+)
+
+const (
+	dayLayout    = "2006-01-02"
+	secondLayout = "2006-01-02T15:04:05Z"
+)
+
+// verbArguments are the arguments each verb takes, true for those it requires. A resumption token
+// is exclusive, taking the place of every other argument.
+var verbArguments = map[string]map[string]bool{
+	VerbIdentify:            {},
+	VerbListMetadataFormats: {"identifier": false},
+	VerbListSets:            {"resumptionToken": false},
+	VerbGetRecord:           {"identifier": true, "metadataPrefix": true},
+	VerbListIdentifiers:     {"metadataPrefix": true, "from": false, "until": false, "set": false, "resumptionToken": false},
+	VerbListRecords:         {"metadataPrefix": true, "from": false, "until": false, "set": false, "resumptionToken": false},
+}
+
+// Error is an error a request is answered with instead of the response to its verb
+type Error struct {
+	Code    string `xml:"code,attr"`
+	Message string `xml:",chardata"`
+}
+
+// Error describes the error
+func (e Error) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// Request echoes a request in its response: its arguments when they were valid, and the base URL
+type Request struct {
+	Verb            string `xml:"verb,attr,omitempty"`
+	Identifier      string `xml:"identifier,attr,omitempty"`
+	MetadataPrefix  string `xml:"metadataPrefix,attr,omitempty"`
+	From            string `xml:"from,attr,omitempty"`
+	Until           string `xml:"until,attr,omitempty"`
+	Set             string `xml:"set,attr,omitempty"`
+	ResumptionToken string `xml:"resumptionToken,attr,omitempty"`
+	URL             string `xml:",chardata"`
+}
+
+// ParseRequest reads the verb and arguments of a request, with the errors that make it invalid.
+// An invalid request is echoed without its arguments.
+func ParseRequest(args url.Values) (Request, []Error) {
+	verbs := args["verb"]
+	if len(verbs) != 1 {
+		return Request{}, []Error{{ErrBadVerb, "verb is missing or repeated"}}
+	}
+
+	allowed, ok := verbArguments[verbs[0]]
+	if !ok {
+		return Request{}, []Error{{ErrBadVerb, "verb " + verbs[0] + " is not a verb of OAI-PMH"}}
+	}
+
+	var errs []Error
+	for name, values := range args {
+		if name == "verb" {
+			continue
+		}
+		if _, ok := allowed[name]; !ok {
+			errs = append(errs, Error{ErrBadArgument, "argument " + name + " is not allowed for " + verbs[0]})
+		} else if len(values) > 1 {
+			errs = append(errs, Error{ErrBadArgument, "argument " + name + " is repeated"})
+		}
+	}
+
+	if _, ok := args["resumptionToken"]; ok {
+		if len(args) > 2 {
+			errs = append(errs, Error{ErrBadArgument, "resumptionToken is an exclusive argument"})
+		}
+	} else {
+		for name, required := range allowed {
+			if required && args.Get(name) == "" {
+				errs = append(errs, Error{ErrBadArgument, "argument " + name + " is required for " + verbs[0]})
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool { return errs[i].Message < errs[j].Message })
+		return Request{}, errs
+	}
+
+	return Request{
+		Verb:            verbs[0],
+		Identifier:      args.Get("identifier"),
+		MetadataPrefix:  args.Get("metadataPrefix"),
+		From:            args.Get("from"),
+		Until:           args.Get("until"),
+		Set:             args.Get("set"),
+		ResumptionToken: args.Get("resumptionToken"),
+	}, nil
+}
+
+// FormatDatestamp writes a time as a datestamp to the second
+func FormatDatestamp(t time.Time) string {
+	return t.UTC().Format(secondLayout)
+}
+
+// parseDatestamp reads a datestamp to the day or to the second, with how long it lasts
+func parseDatestamp(value string) (time.Time, time.Duration, error) {
+	if t, err := time.Parse(dayLayout, value); err == nil {
+		return t, 24 * time.Hour, nil
+	}
+	t, err := time.Parse(secondLayout, value)
+	return t, time.Second, err
+}
+
+// ParseDateRange reads the from and until arguments of a selective harvest into the time the records
+// changed at or after and the time they changed before, either nil when not given. Until includes the
+// whole day or second it names.
+func ParseDateRange(from, until string) (*time.Time, *time.Time, error) {
+	var start, before *time.Time
+	var startLength, untilLength time.Duration
+
+	if from != "" {
+		t, length, err := parseDatestamp(from)
+		if err != nil {
+			return nil, nil, Error{ErrBadArgument, "from is not a datestamp"}
+		}
+		start, startLength = &t, length
+	}
+
+	if until != "" {
+		t, length, err := parseDatestamp(until)
+		if err != nil {
+			return nil, nil, Error{ErrBadArgument, "until is not a datestamp"}
+		}
+		t = t.Add(length)
+		before, untilLength = &t, length
+	}
+
+	if start != nil && before != nil {
+		if startLength != untilLength {
+			return nil, nil, Error{ErrBadArgument, "from and until have different granularities"}
+		}
+		if !start.Before(*before) {
+			return nil, nil, Error{ErrBadArgument, "from is later than until"}
+		}
+	}
+
+	return start, before, nil
+}
+
+// Token is the state of an incomplete list a resumption token carries, so the rest of the list
+// follows from the token alone
+type Token struct {
+	MetadataPrefix string `json:"m"`
+	From           string `json:"f,omitempty"`
+	Until          string `json:"u,omitempty"`
+	Set            string `json:"s,omitempty"`
+	Cursor         string `json:"c"`           // where the list continues, opaque to the protocol
+	Position       int    `json:"p"`           // how many records came before
+	Total          *int64 `json:"t,omitempty"` // size of the whole list when it was counted
+}
+
+// Encode writes the token as a resumption token
+func (t Token) Encode() string {
+	data, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// ParseToken reads a resumption token
+func ParseToken(value string) (Token, error) {
+	var token Token
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || json.Unmarshal(data, &token) != nil || token.MetadataPrefix == "" || token.Position < 0 {
+		return Token{}, Error{ErrBadResumptionToken, "resumptionToken is invalid or has expired"}
+	}
+	return token, nil
+}
+
+// Response is the document a request is answered with, either the errors or the response to its verb
+type Response struct {
+	XMLName             xml.Name             `xml:"OAI-PMH"`
+	ResponseDate        string               `xml:"responseDate"`
+	Request             Request              `xml:"request"`
+	Errors              []Error              `xml:"error"`
+	Identify            *Identify            `xml:"Identify"`
+	ListMetadataFormats *ListMetadataFormats `xml:"ListMetadataFormats"`
+	ListSets            *ListSets            `xml:"ListSets"`
+	GetRecord           *GetRecord           `xml:"GetRecord"`
+	ListIdentifiers     *ListIdentifiers     `xml:"ListIdentifiers"`
+	ListRecords         *ListRecords         `xml:"ListRecords"`
+}
+
+// Marshal writes a response as an XML document with its namespaces
+func (r Response) Marshal() ([]byte, error) {
+	type response struct {
+		Response
+		Xmlns          string `xml:"xmlns,attr"`
+		XmlnsXSI       string `xml:"xmlns:xsi,attr"`
+		SchemaLocation string `xml:"xsi:schemaLocation,attr"`
+	}
+
+	data, err := xml.MarshalIndent(response{r, Namespace, XSINamespace, Namespace + " " + Schema}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// Identify describes a repository
+type Identify struct {
+	RepositoryName    string   `xml:"repositoryName"`
+	BaseURL           string   `xml:"baseURL"`
+	ProtocolVersion   string   `xml:"protocolVersion"`
+	AdminEmail        []string `xml:"adminEmail"`
+	EarliestDatestamp string   `xml:"earliestDatestamp"`
+	DeletedRecord     string   `xml:"deletedRecord"`
+	Granularity       string   `xml:"granularity"`
+}
+
+// ListMetadataFormats lists the formats records are available in
+type ListMetadataFormats struct {
+	Formats []MetadataFormat `xml:"metadataFormat"`
+}
+
+// MetadataFormat is a format records are available in
+type MetadataFormat struct {
+	Prefix    string `xml:"metadataPrefix"`
+	Schema    string `xml:"schema"`
+	Namespace string `xml:"metadataNamespace"`
+}
+
+// DublinCoreFormat is the Dublin Core metadata format
+var DublinCoreFormat = MetadataFormat{Prefix: OAIDC, Schema: OAIDCSchema, Namespace: OAIDCNamespace}
+
+// ListSets lists the sets records are organized in
+type ListSets struct {
+	Sets []Set `xml:"set"`
+}
+
+// Set is a group of records. A set whose spec has colons is within the set named by the part before the last one.
+type Set struct {
+	Spec string `xml:"setSpec"`
+	Name string `xml:"setName"`
+}
+
+// GetRecord holds the record asked for
+type GetRecord struct {
+	Record Record `xml:"record"`
+}
+
+// ListIdentifiers lists the headers of records, a part of the list at a time
+type ListIdentifiers struct {
+	Headers         []Header         `xml:"header"`
+	ResumptionToken *ResumptionToken `xml:"resumptionToken"`
+}
+
+// ListRecords lists records, a part of the list at a time
+type ListRecords struct {
+	Records         []Record         `xml:"record"`
+	ResumptionToken *ResumptionToken `xml:"resumptionToken"`
+}
+
+// ResumptionToken continues an incomplete list. It is empty in the last part of a list.
+type ResumptionToken struct {
+	CompleteListSize *int64 `xml:"completeListSize,attr,omitempty"`
+	Cursor           int    `xml:"cursor,attr"`
+	Value            string `xml:",chardata"`
+}
+
+// Record is the header of an item and its metadata, which a deleted item has none of
+type Record struct {
+	Header   Header    `xml:"header"`
+	Metadata *Metadata `xml:"metadata"`
+}
+
+// Header identifies an item, when it last changed and the sets it is in
+type Header struct {
+	Status     string   `xml:"status,attr,omitempty"`
+	Identifier string   `xml:"identifier"`
+	Datestamp  string   `xml:"datestamp"`
+	SetSpecs   []string `xml:"setSpec"`
+}
+
+// Metadata is an item in a metadata format
+type Metadata struct {
+	DublinCore *DublinCore `xml:"oai_dc:dc"`
+}
+
+// DublinCore is a record in the oai_dc format, the elements of simple Dublin Core
+type DublinCore struct {
+	dublincore.Record
+}
+
+// MarshalXML writes the elements with the namespaces and schema of the oai_dc format
+func (d DublinCore) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Attr = append(start.Attr,
+		xml.Attr{Name: xml.Name{Local: "xmlns:oai_dc"}, Value: OAIDCNamespace},
+		xml.Attr{Name: xml.Name{Local: "xmlns:dc"}, Value: dublincore.Namespace},
+		xml.Attr{Name: xml.Name{Local: "xmlns:xsi"}, Value: XSINamespace},
+		xml.Attr{Name: xml.Name{Local: "xsi:schemaLocation"}, Value: OAIDCNamespace + " " + OAIDCSchema},
+	)
+	return e.EncodeElement(d.Record, start)
+}
+
+// SetSpec joins the specs of a set and the sets it is within, outermost first
+func SetSpec(path ...string) string {
+	return strings.Join(path, ":")
+}
@@ -0,0 +1,13 @@
+package oaipmh_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestOAIPMH(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "OAI-PMH Suite")
+}
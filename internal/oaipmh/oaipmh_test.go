@@ -0,0 +1,149 @@
+package oaipmh_test
+
+import (
+	"encoding/xml"
+	"net/url"
+	"time"
+
+	"go-library-service/internal/dublincore"
+	"go-library-service/internal/oaipmh"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OAI-PMH", func() {
+	Context("ParseRequest", func() {
+		It("should read the arguments of a verb", func() {
+			request, errs := oaipmh.ParseRequest(url.Values{
+				"verb":           {"ListRecords"},
+				"metadataPrefix": {"oai_dc"},
+				"from":           {"2026-01-01"},
+				"set":            {"4:7"},
+			})
+			Expect(errs).To(BeEmpty())
+			Expect(request).To(Equal(oaipmh.Request{Verb: "ListRecords", MetadataPrefix: "oai_dc", From: "2026-01-01", Set: "4:7"}))
+		})
+
+		It("should take a resumption token in place of the other arguments", func() {
+			request, errs := oaipmh.ParseRequest(url.Values{"verb": {"ListIdentifiers"}, "resumptionToken": {"abc"}})
+			Expect(errs).To(BeEmpty())
+			Expect(request.ResumptionToken).To(Equal("abc"))
+
+			_, errs = oaipmh.ParseRequest(url.Values{"verb": {"ListIdentifiers"}, "resumptionToken": {"abc"}, "metadataPrefix": {"oai_dc"}})
+			Expect(errs).To(Equal([]oaipmh.Error{{Code: oaipmh.ErrBadArgument, Message: "resumptionToken is an exclusive argument"}}))
+		})
+
+		It("should reject a missing or unknown verb", func() {
+			_, errs := oaipmh.ParseRequest(url.Values{})
+			Expect(errs[0].Code).To(Equal(oaipmh.ErrBadVerb))
+
+			_, errs = oaipmh.ParseRequest(url.Values{"verb": {"ListBooks"}})
+			Expect(errs[0].Code).To(Equal(oaipmh.ErrBadVerb))
+
+			_, errs = oaipmh.ParseRequest(url.Values{"verb": {"Identify", "ListSets"}})
+			Expect(errs[0].Code).To(Equal(oaipmh.ErrBadVerb))
+		})
+
+		It("should reject illegal, repeated and missing arguments", func() {
+			_, errs := oaipmh.ParseRequest(url.Values{
+				"verb":       {"GetRecord"},
+				"identifier": {"oai:library.test:books/1", "oai:library.test:books/2"},
+				"set":        {"4"},
+			})
+			Expect(errs).To(Equal([]oaipmh.Error{
+				{Code: oaipmh.ErrBadArgument, Message: "argument identifier is repeated"},
+				{Code: oaipmh.ErrBadArgument, Message: "argument metadataPrefix is required for GetRecord"},
+				{Code: oaipmh.ErrBadArgument, Message: "argument set is not allowed for GetRecord"},
+			}))
+		})
+	})
+
+	Context("ParseDateRange", func() {
+		It("should include the whole day or second until names", func() {
+			from, before, err := oaipmh.ParseDateRange("2026-01-01", "2026-01-31")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*from).To(Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)))
+			Expect(*before).To(Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)))
+
+			from, before, err = oaipmh.ParseDateRange("", "2026-01-31T10:00:00Z")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(from).To(BeNil())
+			Expect(*before).To(Equal(time.Date(2026, 1, 31, 10, 0, 1, 0, time.UTC)))
+		})
+
+		It("should reject a range it cannot harvest", func() {
+			for _, dates := range [][2]string{
+				{"yesterday", ""},
+				{"", "2026-01-31T10:00"},
+				{"2026-01-01", "2026-01-31T10:00:00Z"},
+				{"2026-02-01", "2026-01-31"},
+			} {
+				_, _, err := oaipmh.ParseDateRange(dates[0], dates[1])
+				Expect(err).To(MatchError(HavePrefix(oaipmh.ErrBadArgument)), dates[0]+" "+dates[1])
+			}
+		})
+	})
+
+	Context("Token", func() {
+		It("should read back the state it carries", func() {
+			total := int64(250)
+			token := oaipmh.Token{MetadataPrefix: "oai_dc", From: "2026-01-01", Set: "4", Cursor: "eyJvIjoiMSJ9", Position: 100, Total: &total}
+
+			parsed, err := oaipmh.ParseToken(token.Encode())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsed).To(Equal(token))
+		})
+
+		It("should reject a token it did not issue", func() {
+			for _, value := range []string{"", "not a token", "e30"} {
+				_, err := oaipmh.ParseToken(value)
+				Expect(err).To(Equal(oaipmh.Error{Code: oaipmh.ErrBadResumptionToken, Message: "resumptionToken is invalid or has expired"}))
+			}
+		})
+	})
+
+	It("should write a response with its records in Dublin Core", func() {
+		data, err := oaipmh.Response{
+			ResponseDate: oaipmh.FormatDatestamp(time.Date(2026, 1, 2, 15, 4, 5, 0, time.FixedZone("ICT", 7*60*60))),
+			Request:      oaipmh.Request{Verb: oaipmh.VerbGetRecord, Identifier: "oai:library.test:books/1", MetadataPrefix: oaipmh.OAIDC, URL: "http://library.test/api/oai"},
+			GetRecord: &oaipmh.GetRecord{Record: oaipmh.Record{
+				Header: oaipmh.Header{Identifier: "oai:library.test:books/1", Datestamp: "2026-01-01T08:00:00Z", SetSpecs: []string{"4:7"}},
+				Metadata: &oaipmh.Metadata{DublinCore: &oaipmh.DublinCore{Record: dublincore.Record{
+					Title:   []string{"Dune"},
+					Creator: []string{"Frank Herbert"},
+				}}},
+			}},
+		}.Marshal()
+		Expect(err).NotTo(HaveOccurred())
+
+		document := string(data)
+		Expect(document).To(HavePrefix(xml.Header + `<OAI-PMH xmlns="http://www.openarchives.org/OAI/2.0/" ` +
+			`xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" ` +
+			`xsi:schemaLocation="http://www.openarchives.org/OAI/2.0/ http://www.openarchives.org/OAI/2.0/OAI-PMH.xsd">`))
+		Expect(document).To(ContainSubstring(`<responseDate>2026-01-02T08:04:05Z</responseDate>`))
+		Expect(document).To(ContainSubstring(`<request verb="GetRecord" identifier="oai:library.test:books/1" metadataPrefix="oai_dc">http://library.test/api/oai</request>`))
+		Expect(document).To(ContainSubstring(`<oai_dc:dc xmlns:oai_dc="http://www.openarchives.org/OAI/2.0/oai_dc/" xmlns:dc="http://purl.org/dc/elements/1.1/" ` +
+			`xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" ` +
+			`xsi:schemaLocation="http://www.openarchives.org/OAI/2.0/oai_dc/ http://www.openarchives.org/OAI/2.0/oai_dc.xsd">`))
+		Expect(document).To(ContainSubstring(`<dc:title>Dune</dc:title>`))
+		Expect(document).To(ContainSubstring(`<setSpec>4:7</setSpec>`))
+		Expect(document).NotTo(ContainSubstring(`<error`))
+	})
+
+	It("should write a deleted record without metadata and the last part of a list with an empty token", func() {
+		total := int64(101)
+		data, err := oaipmh.Response{
+			Request: oaipmh.Request{Verb: oaipmh.VerbListIdentifiers, ResumptionToken: "abc"},
+			ListIdentifiers: &oaipmh.ListIdentifiers{
+				Headers:         []oaipmh.Header{{Status: oaipmh.StatusDeleted, Identifier: "oai:library.test:books/2", Datestamp: "2026-01-01T08:00:00Z"}},
+				ResumptionToken: &oaipmh.ResumptionToken{CompleteListSize: &total, Cursor: 100},
+			},
+		}.Marshal()
+		Expect(err).NotTo(HaveOccurred())
+
+		document := string(data)
+		Expect(document).To(ContainSubstring(`<header status="deleted">`))
+		Expect(document).To(ContainSubstring(`<resumptionToken completeListSize="101" cursor="100"></resumptionToken>`))
+	})
+})
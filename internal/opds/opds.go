@@ -0,0 +1,221 @@
+// Package opds describes the catalog feeds e-reader apps browse, OPDS 1.2 as Atom and OPDS 2.0 as JSON,
+// and the OpenSearch description that tells them how to search
+package opds
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// Namespaces of the Atom feeds
+const (
+	AtomNamespace       = "http://www.w3.org/2005/Atom"
+	Namespace           = "http://opds-spec.org/2010/catalog"
+	DCNamespace         = "http://purl.org/dc/terms/"
+	OpenSearchNamespace = "http://a9.com/-/spec/opensearch/1.1/"
+)
+
+// Media types of the feeds and what they link to
+const (
+	NavigationType  = "application/atom+xml;profile=opds-catalog;kind=navigation"
+	AcquisitionType = "application/atom+xml;profile=opds-catalog;kind=acquisition"
+	OpenSearchType  = "application/opensearchdescription+xml"
+	CatalogType     = "application/opds+json"
+)
+
+// Link relations of the feeds
+const (
+	RelSelf       = "self"
+	RelStart      = "start"
+	RelUp         = "up"
+	RelSearch     = "search"
+	RelSubsection = "subsection"
+	RelFirst      = "first"
+	RelPrevious   = "previous"
+	RelNext       = "next"
+	RelLast       = "last"
+	RelNew        = "http://opds-spec.org/sort/new"
+	RelBorrow     = "http://opds-spec.org/acquisition/borrow"
+)
+
+// Availability states of a borrow link
+const (
+	Available   = "available"
+	Unavailable = "unavailable"
+)
+
+// Feed is an OPDS 1.2 catalog feed, either navigation, whose entries lead to other feeds,
+// or acquisition, whose entries are books
+type Feed struct {
+	XMLName      xml.Name  `xml:"feed"`
+	ID           string    `xml:"id"`
+	Title        string    `xml:"title"`
+	Updated      time.Time `xml:"updated"`
+	Links        []Link    `xml:"link"`
+	TotalResults *int64    `xml:"opensearch:totalResults,omitempty"`
+	ItemsPerPage int       `xml:"opensearch:itemsPerPage,omitempty"`
+	StartIndex   int       `xml:"opensearch:startIndex,omitempty"`
+	Entries      []Entry   `xml:"entry"`
+}
+
+// Entry is a feed entry, a book or a way to another feed
+type Entry struct {
+	Title      string     `xml:"title"`
+	ID         string     `xml:"id"`
+	Updated    time.Time  `xml:"updated"`
+	Authors    []Person   `xml:"author"`
+	Identifier string     `xml:"dc:identifier,omitempty"`
+	Publisher  string     `xml:"dc:publisher,omitempty"`
+	Issued     string     `xml:"dc:issued,omitempty"`
+	Language   string     `xml:"dc:language,omitempty"`
+	Categories []Category `xml:"category"`
+	Content    *Content   `xml:"content"`
+	Links      []Link     `xml:"link"`
+}
+
+// Person is an author of an entry
+type Person struct {
+	Name string `xml:"name"`
+}
+
+// Category files an entry under a term of a scheme
+type Category struct {
+	Scheme string `xml:"scheme,attr,omitempty"`
+	Term   string `xml:"term,attr"`
+	Label  string `xml:"label,attr,omitempty"`
+}
+
+// Content describes an entry in text
+type Content struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// Link leads from a feed or entry to a feed, a search or a way to get a book
+type Link struct {
+	Rel          string        `xml:"rel,attr,omitempty"`
+	Href         string        `xml:"href,attr"`
+	Type         string        `xml:"type,attr,omitempty"`
+	Title        string        `xml:"title,attr,omitempty"`
+	Availability *Availability `xml:"opds:availability"`
+}
+
+// Availability tells whether a book can be borrowed now
+type Availability struct {
+	Status string `xml:"status,attr"`
+}
+
+// Marshal writes a feed as an XML document with its namespaces
+func (f Feed) Marshal() ([]byte, error) {
+	type feed struct {
+		Feed
+		Xmlns           string `xml:"xmlns,attr"`
+		XmlnsOPDS       string `xml:"xmlns:opds,attr"`
+		XmlnsDC         string `xml:"xmlns:dc,attr"`
+		XmlnsOpenSearch string `xml:"xmlns:opensearch,attr"`
+	}
+	return marshal(feed{f, AtomNamespace, Namespace, DCNamespace, OpenSearchNamespace})
+}
+
+// OpenSearchDescription tells apps how to search a catalog
+type OpenSearchDescription struct {
+	XMLName        xml.Name  `xml:"OpenSearchDescription"`
+	Xmlns          string    `xml:"xmlns,attr"`
+	ShortName      string    `xml:"ShortName"`
+	Description    string    `xml:"Description"`
+	InputEncoding  string    `xml:"InputEncoding"`
+	OutputEncoding string    `xml:"OutputEncoding"`
+	URL            SearchURL `xml:"Url"`
+}
+
+// SearchURL is the template of a search, its terms in place of {searchTerms}
+type SearchURL struct {
+	Type     string `xml:"type,attr"`
+	Template string `xml:"template,attr"`
+}
+
+// Marshal writes a description as an XML document
+func (d OpenSearchDescription) Marshal() ([]byte, error) {
+	d.Xmlns = OpenSearchNamespace
+	return marshal(d)
+}
+
+// marshal writes an XML document with its declaration
+func marshal(v interface{}) ([]byte, error) {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// Catalog is an OPDS 2.0 feed, with links to other feeds under navigation and books under publications
+type Catalog struct {
+	Metadata     CatalogMetadata `json:"metadata"`
+	Links        []CatalogLink   `json:"links"`
+	Navigation   []CatalogLink   `json:"navigation,omitempty"`
+	Publications []Publication   `json:"publications,omitempty"`
+}
+
+// CatalogMetadata describes a catalog feed and the page of it
+type CatalogMetadata struct {
+	Title         string     `json:"title"`
+	Modified      *time.Time `json:"modified,omitempty"`
+	NumberOfItems *int64     `json:"numberOfItems,omitempty"`
+	ItemsPerPage  int        `json:"itemsPerPage,omitempty"`
+	CurrentPage   int        `json:"currentPage,omitempty"`
+}
+
+// CatalogLink leads from a feed or publication to a feed, a search or a way to get a book.
+// A templated link takes the terms of a search in place of its variables.
+type CatalogLink struct {
+	Href       string          `json:"href"`
+	Type       string          `json:"type,omitempty"`
+	Rel        string          `json:"rel,omitempty"`
+	Title      string          `json:"title,omitempty"`
+	Templated  bool            `json:"templated,omitempty"`
+	Properties *LinkProperties `json:"properties,omitempty"`
+}
+
+// LinkProperties tells more of what is behind a link
+type LinkProperties struct {
+	Availability *State `json:"availability,omitempty"`
+}
+
+// State is whether a book can be borrowed now
+type State struct {
+	State string `json:"state"`
+}
+
+// Publication is a book of a catalog feed
+type Publication struct {
+	Metadata PublicationMetadata `json:"metadata"`
+	Links    []CatalogLink       `json:"links"`
+	Images   []CatalogLink       `json:"images,omitempty"`
+}
+
+// PublicationMetadata describes a book
+type PublicationMetadata struct {
+	Type          string        `json:"@type"`
+	Identifier    string        `json:"identifier"`
+	Title         string        `json:"title"`
+	Author        []Contributor `json:"author,omitempty"`
+	Publisher     string        `json:"publisher,omitempty"`
+	Published     string        `json:"published,omitempty"`
+	Language      string        `json:"language,omitempty"`
+	Modified      *time.Time    `json:"modified,omitempty"`
+	NumberOfPages *uint         `json:"numberOfPages,omitempty"`
+	Subject       []Subject     `json:"subject,omitempty"`
+}
+
+// Contributor is a person credited for a book
+type Contributor struct {
+	Name string `json:"name"`
+}
+
+// Subject is what a book is about, with the code of the scheme it comes from when it has one
+type Subject struct {
+	Name   string `json:"name"`
+	Code   string `json:"code,omitempty"`
+	Scheme string `json:"scheme,omitempty"`
+}
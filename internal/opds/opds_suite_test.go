@@ -0,0 +1,13 @@
+package opds_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestOPDS(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "OPDS Suite")
+}
@@ -0,0 +1,67 @@
+package opds_test
+
+import (
+	"encoding/xml"
+	"time"
+
+	"go-library-service/internal/opds"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OPDS", func() {
+	It("should write a feed with its namespaces", func() {
+		total := int64(41)
+		data, err := opds.Feed{
+			ID:           "http://library.test/api/opds/books",
+			Title:        "All books",
+			Updated:      time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC),
+			Links:        []opds.Link{{Rel: opds.RelSelf, Href: "http://library.test/api/opds/books", Type: opds.AcquisitionType}},
+			TotalResults: &total,
+			ItemsPerPage: 20,
+			StartIndex:   1,
+			Entries: []opds.Entry{{
+				Title:      "Dune",
+				ID:         "http://library.test/api/books/1",
+				Updated:    time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC),
+				Authors:    []opds.Person{{Name: "Frank Herbert"}},
+				Identifier: "urn:isbn:9780441172719",
+				Links: []opds.Link{{
+					Rel:          opds.RelBorrow,
+					Href:         "http://library.test/api/books/1/borrow",
+					Availability: &opds.Availability{Status: opds.Available},
+				}},
+			}},
+		}.Marshal()
+		Expect(err).NotTo(HaveOccurred())
+
+		document := string(data)
+		Expect(document).To(HavePrefix(xml.Header + `<feed xmlns="http://www.w3.org/2005/Atom" xmlns:opds="http://opds-spec.org/2010/catalog" ` +
+			`xmlns:dc="http://purl.org/dc/terms/" xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">`))
+		Expect(document).To(ContainSubstring(`<opensearch:totalResults>41</opensearch:totalResults>`))
+		Expect(document).To(ContainSubstring(`<dc:identifier>urn:isbn:9780441172719</dc:identifier>`))
+		Expect(document).To(ContainSubstring(`<opds:availability status="available"></opds:availability>`))
+		Expect(document).NotTo(ContainSubstring(`<content`))
+
+		// Read back as plain Atom, the feed keeps its entries
+		var feed struct {
+			Entries []struct {
+				Title string `xml:"title"`
+			} `xml:"http://www.w3.org/2005/Atom entry"`
+		}
+		Expect(xml.Unmarshal(data, &feed)).To(Succeed())
+		Expect(feed.Entries).To(HaveLen(1))
+	})
+
+	It("should write an OpenSearch description", func() {
+		data, err := opds.OpenSearchDescription{
+			ShortName: "Library",
+			URL:       opds.SearchURL{Type: opds.AcquisitionType, Template: "http://library.test/api/opds/books?q={searchTerms}"},
+		}.Marshal()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(string(data)).To(ContainSubstring(`<OpenSearchDescription xmlns="http://a9.com/-/spec/opensearch/1.1/">`))
+		Expect(string(data)).To(ContainSubstring(`template="http://library.test/api/opds/books?q={searchTerms}"`))
+	})
+})
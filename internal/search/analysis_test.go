@@ -0,0 +1,81 @@
+package search_test
+
+import (
+	"go-library-service/internal/search"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Analysis", func() {
+	DescribeTable("Normalize",
+		func(input, expected string) {
+			Expect(search.Normalize(input)).To(Equal(expected))
+		},
+		Entry("lower-cases", "Dune", "dune"),
+		Entry("strips latin accents", "Café Gödel", "cafe godel"),
+		Entry("folds thai tone marks", "น้ำ", "นำ"),
+		Entry("folds thanthakhat and mai taikhu", "ศาสตร์ เป็น", "ศาสตร เปน"),
+		Entry("turns thai digits into ascii", "๒๕๖๗", "2567"),
+	)
+
+	DescribeTable("Romanize",
+		func(input, expected string) {
+			Expect(search.Romanize(input)).To(Equal(expected))
+		},
+		Entry("written vowels and finals", "ความรัก", "khwamrak"),
+		Entry("implicit o", "คน", "khon"),
+		Entry("leading vowel", "เรียน", "rian"),
+		Entry("leading vowel with tone mark", "เรื่อง", "rueang"),
+		Entry("mai taikhu", "เป็น", "pen"),
+		Entry("silent ห", "หนังสือ", "nangsue"),
+		Entry("ไ with ย", "ภาษาไทย", "phasathai"),
+		Entry("ร หัน", "วรรณกรรม", "wankam"),
+		Entry("sara am", "น้ำ", "nam"),
+	)
+
+	DescribeTable("PhoneticKey",
+		func(input, expected string) {
+			Expect(search.PhoneticKey(input)).To(Equal(expected))
+		},
+		Entry("drops aspiration", "khwam", "kwam"),
+		Entry("merges long vowels", "kwaam", "kwam"),
+		Entry("merges ue spellings", "rueang", "ruang"),
+		Entry("merges j and ch", "jai", "cai"),
+		Entry("ignores spaces and case", "Khwam Rak", "kwamrak"),
+	)
+
+	Context("Document", func() {
+		It("should weigh fields and index words in order", func() {
+			document := search.Document(
+				search.Field{Text: "Harry Potter", Weight: 'A'},
+				search.Field{Text: "J.K. Rowling", Weight: 'B'},
+			)
+			Expect(document).To(Equal("'harry':1A 'j':4B 'k':5B 'potter':2A 'rowling':6B"))
+		})
+
+		It("should index thai as cluster pairs followed by romanized trigrams", func() {
+			document := search.Document(search.Field{Text: "ความรัก", Weight: 'A'})
+			Expect(document).To(Equal("'~amr':7A '~kwa':5A '~mra':8A '~rak':9A '~wam':6A 'ควา':1A 'มรั':3A 'รัก':4A 'วาม':2A"))
+		})
+
+		It("should merge the positions of repeated words", func() {
+			document := search.Document(search.Field{Text: "Dune, dune", Weight: 'A'})
+			Expect(document).To(Equal("'dune':1A,2A"))
+		})
+
+		It("should quote lexemes safely", func() {
+			document := search.Document(search.Field{Text: `O'Brien \ Co`, Weight: 'A'})
+			Expect(document).To(Equal("'brien':2A 'co':3A 'o':1A"))
+		})
+	})
+
+	It("should find a thai word inside a title with the query it parses to", func() {
+		document := search.Document(search.Field{Text: "เรื่องความรักของฉัน", Weight: 'A'})
+		query := search.Parse("ความรัก").TSQuery()
+		Expect(query).To(Equal("('ควา' <-> 'วาม' <-> 'มรั' <-> 'รัก')"))
+		for _, lexeme := range []string{"'ควา':4A", "'วาม':5A", "'มรั':6A", "'รัก':7A"} {
+			Expect(document).To(ContainSubstring(lexeme))
+		}
+	})
+})
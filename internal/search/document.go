@@ -0,0 +1,80 @@
+package search
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Version changes whenever documents are analyzed differently, so stored ones can be rebuilt
+const Version = 3
+
+// Field is a piece of text to index with the weight its matches rank at, 'A' being highest
+type Field struct {
+	Text   string
+	Weight byte
+}
+
+// Document analyzes fields into a tsvector literal. Latin words are indexed as
+// written, Thai as cluster pairs and, after them, trigrams of its romanization.
+func Document(fields ...Field) string {
+	positions := map[string][]string{}
+	position := 0
+
+	add := func(token string, weight byte) {
+		position++
+		if position > 16383 {
+			return
+		}
+		positions[token] = append(positions[token], strconv.Itoa(position)+string(weight))
+	}
+
+	for _, field := range fields {
+		var romanized [][]string
+		for _, seg := range segments(field.Text) {
+			if !seg.thai {
+				if word := Normalize(seg.text); word != "" {
+					add(word, field.Weight)
+				}
+				continue
+			}
+
+			for _, token := range thaiTokens(Normalize(seg.text)) {
+				add(token, field.Weight)
+			}
+			romanized = append(romanized, romanizedTokens(PhoneticKey(Romanize(seg.text))))
+		}
+
+		for _, tokens := range romanized {
+			for _, token := range tokens {
+				add(token, field.Weight)
+			}
+			// Keeps phrases from matching across two Thai runs
+			position++
+		}
+
+		// Keeps phrases from matching across two fields
+		position++
+	}
+
+	tokens := make([]string, 0, len(positions))
+	for token := range positions {
+		tokens = append(tokens, token)
+	}
+	sort.Strings(tokens)
+
+	var builder strings.Builder
+	for i, token := range tokens {
+		if i > 0 {
+			builder.WriteString(" ")
+		}
+		builder.WriteString(quoteLexeme(token) + ":" + strings.Join(positions[token], ","))
+	}
+	return builder.String()
+}
+
+// quoteLexeme quotes a token for a tsvector or tsquery literal
+func quoteLexeme(token string) string {
+	token = strings.ReplaceAll(token, `\`, `\\`)
+	return "'" + strings.ReplaceAll(token, "'", "''") + "'"
+}
@@ -0,0 +1,39 @@
+package search
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// creditSeparator splits a credit line between its names. Commas are left alone
+// because they also turn a name around, as in "Rowling, J. K."
+var creditSeparator = regexp.MustCompile(`(?i)\s*(?:;|&|\band\b)\s*|\s+และ\s+`)
+
+// SplitNames splits a credit line such as "Terry Pratchett & Neil Gaiman" into its names,
+// dropping blanks and repeats of a name
+func SplitNames(credit string) []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, name := range creditSeparator.Split(credit, -1) {
+		name = strings.Join(strings.Fields(name), " ")
+		key := NameKey(name)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+// NameKey folds the spellings of a personal name to one key. Case, accents, punctuation
+// and the order of the parts are ignored, so "J.K. Rowling" and "Rowling, J. K." share a key.
+func NameKey(name string) string {
+	parts := strings.FieldsFunc(Normalize(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && !unicode.Is(unicode.Mn, r)
+	})
+	sort.Strings(parts)
+	return strings.Join(parts, " ")
+}
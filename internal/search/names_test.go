@@ -0,0 +1,39 @@
+package search_test
+
+import (
+	"go-library-service/internal/search"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Names", func() {
+	DescribeTable("NameKey",
+		func(a, b string) {
+			Expect(search.NameKey(a)).To(Equal(search.NameKey(b)))
+		},
+		Entry("inverted with initials", "J.K. Rowling", "Rowling, J. K."),
+		Entry("case and accents", "Gabriel García Márquez", "gabriel garcia marquez"),
+		Entry("extra spaces", "  Ursula   K. Le Guin ", "Ursula K. Le Guin"),
+		Entry("thai tone marks", "ศรีบูรพา", "ศรีบูรพา"),
+	)
+
+	It("should tell different names apart", func() {
+		Expect(search.NameKey("Terry Pratchett")).NotTo(Equal(search.NameKey("Terry Brooks")))
+		Expect(search.NameKey("...")).To(BeEmpty())
+	})
+
+	DescribeTable("SplitNames",
+		func(credit string, expected []string) {
+			Expect(search.SplitNames(credit)).To(Equal(expected))
+		},
+		Entry("one name", "Frank Herbert", []string{"Frank Herbert"}),
+		Entry("an inverted name stays whole", "Rowling, J. K.", []string{"Rowling, J. K."}),
+		Entry("ampersand", "Terry Pratchett & Neil Gaiman", []string{"Terry Pratchett", "Neil Gaiman"}),
+		Entry("and", "Douglas Preston and Lincoln Child", []string{"Douglas Preston", "Lincoln Child"}),
+		Entry("semicolons", "Rowling, J. K.; GrandPré, Mary", []string{"Rowling, J. K.", "GrandPré, Mary"}),
+		Entry("thai and", "สุนทรภู่ และ ศรีบูรพา", []string{"สุนทรภู่", "ศรีบูรพา"}),
+		Entry("repeats and blanks", "Neil Gaiman;  ; neil  gaiman", []string{"Neil Gaiman"}),
+		Entry("and inside a word", "Alexander Dumas", []string{"Alexander Dumas"}),
+	)
+})
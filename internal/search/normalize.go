@@ -0,0 +1,80 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalize lower-cases text, strips Latin accents, folds Thai tone marks and
+// turns Thai digits into ASCII so spelling variants index and search alike
+func Normalize(text string) string {
+	var builder strings.Builder
+	for _, r := range norm.NFD.String(text) {
+		switch {
+		case isThaiToneMark(r):
+			continue
+		case r >= '๐' && r <= '๙':
+			builder.WriteRune('0' + r - '๐')
+		case unicode.Is(unicode.Mn, r) && !isThai(r):
+			continue
+		default:
+			builder.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return norm.NFC.String(builder.String())
+}
+
+// isThai reports whether r is in the Thai block
+func isThai(r rune) bool {
+	return r >= 0x0E01 && r <= 0x0E5B
+}
+
+// isThaiToneMark reports whether r only changes the tone or silences a letter,
+// which spellers often get wrong: mai taikhu, the four tone marks and thanthakhat
+func isThaiToneMark(r rune) bool {
+	return r >= 0x0E47 && r <= 0x0E4C
+}
+
+// segment is a run of Latin letters and digits or a run of Thai text
+type segment struct {
+	text string
+	thai bool
+}
+
+// segments splits text into words, keeping Thai runs, which have no spaces, whole
+func segments(text string) []segment {
+	var result []segment
+	var current strings.Builder
+	thai := false
+
+	flush := func() {
+		if current.Len() > 0 {
+			result = append(result, segment{text: current.String(), thai: thai})
+		}
+		current.Reset()
+	}
+
+	for _, r := range text {
+		switch {
+		case isThai(r) && !unicode.IsDigit(r) && r != 'ๆ' && r != 'ฯ':
+			if !thai {
+				flush()
+			}
+			thai = true
+			current.WriteRune(r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r):
+			if thai {
+				flush()
+			}
+			thai = false
+			current.WriteRune(r)
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	return result
+}
@@ -0,0 +1,220 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Term is one clause of a catalog search
+type Term struct {
+	// Words are matched next to each other, in order
+	Words []string
+	// Prefix matches the last word as a prefix, from a trailing "*"
+	Prefix bool
+	// Negated excludes books matching the term, from a leading "-"
+	Negated bool
+	// Or joins the term to the previous one with OR instead of AND
+	Or bool
+}
+
+// Query is a parsed catalog search
+type Query struct {
+	Terms []Term
+}
+
+// Parse reads a search string made of words, "quoted phrases", prefix* terms,
+// -excluded terms and OR between terms
+func Parse(input string) Query {
+	var query Query
+	or := false
+
+	for _, token := range tokenize(input) {
+		if !token.quoted && token.text == "OR" {
+			or = len(query.Terms) > 0
+			continue
+		}
+
+		term := Term{Or: or, Prefix: token.prefix}
+		text := token.text
+
+		if !token.quoted {
+			if strings.HasPrefix(text, "-") {
+				term.Negated = true
+				text = strings.TrimLeft(text, "-")
+			}
+			if strings.HasSuffix(text, "*") {
+				term.Prefix = true
+				text = strings.TrimRight(text, "*")
+			}
+		}
+
+		term.Words = words(text)
+		if len(lexemes(term)) == 0 {
+			continue
+		}
+
+		// A lone excluded term would match nothing, so it joins with AND
+		if term.Negated {
+			term.Or = false
+		}
+
+		query.Terms = append(query.Terms, term)
+		or = false
+	}
+
+	return query
+}
+
+// Empty reports whether the query has nothing to search for
+func (q Query) Empty() bool {
+	for _, term := range q.Terms {
+		if !term.Negated {
+			return false
+		}
+	}
+	return true
+}
+
+// TSQuery renders the query as a tsquery literal, analyzed the way Document indexes books
+func (q Query) TSQuery() string {
+	if q.Empty() {
+		return ""
+	}
+
+	var builder strings.Builder
+	for i, term := range q.Terms {
+		if i > 0 {
+			if term.Or {
+				builder.WriteString(" | ")
+			} else {
+				builder.WriteString(" & ")
+			}
+		}
+
+		if term.Negated {
+			builder.WriteString("!")
+		}
+
+		match := phrase(lexemes(term))
+		if alternative := romanizedLexemes(term); len(alternative) > 0 {
+			match = "(" + match + " | " + phrase(alternative) + ")"
+		}
+		builder.WriteString(match)
+	}
+
+	return builder.String()
+}
+
+// lexeme is a token of a search term, matched as a prefix when it may be cut short
+type lexeme struct {
+	text   string
+	prefix bool
+}
+
+// lexemes analyzes the words of a term into the tokens Document would index for them
+func lexemes(term Term) []lexeme {
+	var result []lexeme
+	for _, word := range term.Words {
+		for _, seg := range segments(word) {
+			if !seg.thai {
+				if text := Normalize(seg.text); text != "" {
+					result = append(result, lexeme{text: text})
+				}
+				continue
+			}
+
+			tokens := thaiTokens(Normalize(seg.text))
+			for _, token := range tokens {
+				// A lone cluster is the start of the cluster pairs it belongs to
+				result = append(result, lexeme{text: token, prefix: len(tokens) == 1})
+			}
+		}
+	}
+
+	if term.Prefix && len(result) > 0 {
+		result[len(result)-1].prefix = true
+	}
+	return result
+}
+
+// romanizedLexemes matches a term typed in Latin letters against the romanization of Thai text
+func romanizedLexemes(term Term) []lexeme {
+	folded := Normalize(strings.Join(term.Words, ""))
+	for _, r := range folded {
+		if r < 'a' || r > 'z' {
+			return nil
+		}
+	}
+
+	var result []lexeme
+	for _, token := range romanizedTokens(PhoneticKey(folded)) {
+		result = append(result, lexeme{text: token})
+	}
+	return result
+}
+
+// phrase joins lexemes that must follow each other
+func phrase(lexemes []lexeme) string {
+	parts := make([]string, len(lexemes))
+	for i, lexeme := range lexemes {
+		parts[i] = quoteLexeme(lexeme.text)
+		if lexeme.prefix {
+			parts[i] += ":*"
+		}
+	}
+
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return "(" + strings.Join(parts, " <-> ") + ")"
+}
+
+type token struct {
+	text   string
+	quoted bool
+	prefix bool
+}
+
+// tokenize splits the input on spaces, keeping quoted phrases together
+func tokenize(input string) []token {
+	var tokens []token
+	var current strings.Builder
+	quoted := false
+	closed := false
+
+	flush := func() {
+		if current.Len() > 0 || quoted {
+			tokens = append(tokens, token{text: current.String(), quoted: quoted})
+		}
+		current.Reset()
+	}
+
+	for _, r := range input {
+		justClosed := closed
+		closed = false
+
+		switch {
+		case r == '*' && justClosed:
+			// "a phrase"* matches its last word as a prefix
+			tokens[len(tokens)-1].prefix = true
+		case r == '"':
+			flush()
+			closed = quoted
+			quoted = !quoted
+		case unicode.IsSpace(r) && !quoted:
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+// words lower-cases text and splits it into the letters and digits tsquery accepts
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.Is(unicode.Mn, r)
+	})
+}
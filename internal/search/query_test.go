@@ -0,0 +1,73 @@
+package search_test
+
+import (
+	"go-library-service/internal/search"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Query", func() {
+	DescribeTable("Parse",
+		func(input string, expected []search.Term) {
+			Expect(search.Parse(input).Terms).To(Equal(expected))
+		},
+		Entry("words are separate terms", "Harry Potter", []search.Term{
+			{Words: []string{"harry"}},
+			{Words: []string{"potter"}},
+		}),
+		Entry("quoted phrase", `"harry potter" rowling`, []search.Term{
+			{Words: []string{"harry", "potter"}},
+			{Words: []string{"rowling"}},
+		}),
+		Entry("prefix", "pott*", []search.Term{{Words: []string{"pott"}, Prefix: true}}),
+		Entry("prefix on a phrase", `"harry pott"*`, []search.Term{{Words: []string{"harry", "pott"}, Prefix: true}}),
+		Entry("star after a spaced phrase is ignored", `"dune" *`, []search.Term{{Words: []string{"dune"}}}),
+		Entry("OR between terms", "tolkien OR lewis", []search.Term{
+			{Words: []string{"tolkien"}},
+			{Words: []string{"lewis"}, Or: true},
+		}),
+		Entry("excluded term", "tolkien -hobbit", []search.Term{
+			{Words: []string{"tolkien"}},
+			{Words: []string{"hobbit"}, Negated: true},
+		}),
+		Entry("punctuation inside a word", "o'reilly", []search.Term{{Words: []string{"o", "reilly"}}}),
+		Entry("tsquery operators are dropped", "a & (b | !c)", []search.Term{
+			{Words: []string{"a"}},
+			{Words: []string{"b"}},
+			{Words: []string{"c"}},
+		}),
+		Entry("leading OR is ignored", "OR tolkien", []search.Term{{Words: []string{"tolkien"}}}),
+		Entry("lowercase or is a word", "war or peace", []search.Term{
+			{Words: []string{"war"}},
+			{Words: []string{"or"}},
+			{Words: []string{"peace"}},
+		}),
+		Entry("unclosed quote", `"lord of the`, []search.Term{{Words: []string{"lord", "of", "the"}}}),
+		Entry("tone marks alone are dropped", "่ ้", nil),
+	)
+
+	DescribeTable("TSQuery",
+		func(input, expected string) {
+			Expect(search.Parse(input).TSQuery()).To(Equal(expected))
+		},
+		Entry("short words have no romanized match", "on it", "'on' & 'it'"),
+		Entry("words also match romanized Thai", "dune", "('dune' | ('~dun' <-> '~une'))"),
+		Entry("phrases are romanized whole", `"khwam rak"`, "(('khwam' <-> 'rak') | ('~kwa' <-> '~wam' <-> '~amr' <-> '~mra' <-> '~rak'))"),
+		Entry("prefix and exclusion", "tol* -hob", "('tol':* | '~tol') & !('hob' | '~hob')"),
+		Entry("OR", "war OR 1984", "('war' | '~war') | '1984'"),
+		Entry("digits are not romanized", "1984", "'1984'"),
+		Entry("accents are folded", "Café", "('cafe' | ('~caf' <-> '~afe'))"),
+		Entry("thai is matched as cluster pairs", "ความรัก", "('ควา' <-> 'วาม' <-> 'มรั' <-> 'รัก')"),
+		Entry("thai tone marks are folded", "แฮร์รี่", "('แฮร' <-> 'รรี')"),
+		Entry("a lone thai cluster is a prefix", "รั", "'รั':*"),
+		Entry("thai digits become ascii", "๑๙๘๔", "'1984'"),
+		Entry("only excluded terms", "-hobbit", ""),
+		Entry("blank input", "   ", ""),
+	)
+
+	It("should report an empty query", func() {
+		Expect(search.Parse(`"" *`).Empty()).To(BeTrue())
+		Expect(search.Parse("dune").Empty()).To(BeFalse())
+	})
+})
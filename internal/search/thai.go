@@ -0,0 +1,268 @@
+package search
+
+import "strings"
+
+// Thai has no spaces between words. Rather than a dictionary, runs of Thai
+// are cut into character clusters, the smallest units a word boundary can
+// fall between, and indexed as overlapping pairs of clusters. A word of any
+// length is then found as a phrase of its cluster pairs.
+
+// isThaiConsonant reports whether r is a Thai consonant, including ฤ and ฦ
+func isThaiConsonant(r rune) bool {
+	return r >= 'ก' && r <= 'ฮ'
+}
+
+// isLeadingVowel reports whether r is written before the consonant it follows in speech
+func isLeadingVowel(r rune) bool {
+	return r >= 'เ' && r <= 'ไ'
+}
+
+// thaiClusters cuts a run of Thai into consonants with the vowels and marks around them
+func thaiClusters(text string) []string {
+	var clusters []string
+	var current []rune
+
+	for _, r := range text {
+		startsCluster := isLeadingVowel(r) || isThaiConsonant(r)
+		afterLeadingVowel := len(current) == 1 && isLeadingVowel(current[0])
+		if startsCluster && len(current) > 0 && !afterLeadingVowel {
+			clusters = append(clusters, string(current))
+			current = nil
+		}
+		current = append(current, r)
+	}
+	if len(current) > 0 {
+		clusters = append(clusters, string(current))
+	}
+
+	return clusters
+}
+
+// thaiTokens turns a folded run of Thai into its overlapping cluster pairs
+func thaiTokens(text string) []string {
+	clusters := thaiClusters(text)
+	if len(clusters) < 2 {
+		return clusters
+	}
+
+	tokens := make([]string, 0, len(clusters)-1)
+	for i := 0; i+1 < len(clusters); i++ {
+		tokens = append(tokens, clusters[i]+clusters[i+1])
+	}
+	return tokens
+}
+
+var (
+	thaiInitials = map[rune]string{
+		'ก': "k", 'ข': "kh", 'ฃ': "kh", 'ค': "kh", 'ฅ': "kh", 'ฆ': "kh", 'ง': "ng",
+		'จ': "ch", 'ฉ': "ch", 'ช': "ch", 'ซ': "s", 'ฌ': "ch", 'ญ': "y",
+		'ฎ': "d", 'ฏ': "t", 'ฐ': "th", 'ฑ': "th", 'ฒ': "th", 'ณ': "n",
+		'ด': "d", 'ต': "t", 'ถ': "th", 'ท': "th", 'ธ': "th", 'น': "n",
+		'บ': "b", 'ป': "p", 'ผ': "ph", 'ฝ': "f", 'พ': "ph", 'ฟ': "f", 'ภ': "ph", 'ม': "m",
+		'ย': "y", 'ร': "r", 'ล': "l", 'ว': "w", 'ศ': "s", 'ษ': "s", 'ส': "s",
+		'ห': "h", 'ฬ': "l", 'อ': "", 'ฮ': "h",
+	}
+	thaiFinals = map[rune]string{
+		'ก': "k", 'ข': "k", 'ค': "k", 'ฆ': "k", 'ง': "ng",
+		'จ': "t", 'ช': "t", 'ซ': "t", 'ฌ': "t", 'ญ': "n", 'ฎ': "t", 'ฏ': "t", 'ฐ': "t", 'ฑ': "t", 'ฒ': "t", 'ณ': "n",
+		'ด': "t", 'ต': "t", 'ถ': "t", 'ท': "t", 'ธ': "t", 'น': "n",
+		'บ': "p", 'ป': "p", 'พ': "p", 'ฟ': "p", 'ภ': "p", 'ม': "m",
+		'ย': "i", 'ร': "n", 'ล': "n", 'ว': "o", 'ศ': "t", 'ษ': "t", 'ส': "t", 'ฬ': "n",
+	}
+	thaiVowels = map[rune]string{
+		'ะ': "a", 'ั': "a", 'า': "a", 'ำ': "am", 'ิ': "i", 'ี': "i", 'ึ': "ue", 'ื': "ue", 'ุ': "u", 'ู': "u",
+	}
+	thaiLeadingVowels = map[rune]string{
+		'เ': "e", 'แ': "ae", 'โ': "o", 'ใ': "ai", 'ไ': "ai",
+	}
+)
+
+// Romanize transliterates Thai into Latin letters after the Royal Thai General
+// System. Syllable boundaries are guessed, so the result is meant for matching
+// romanized searches, not for display.
+func Romanize(text string) string {
+	runes := silenceThanthakhat([]rune(text))
+	var parts []string
+	lead := ""      // a leading vowel waiting for its consonant
+	voiced := false // the syllable being read already has its vowel
+
+	// after returns the index of the rune after i, skipping tone marks
+	after := func(i int) int {
+		j := i + 1
+		for j < len(runes) && isThaiToneMark(runes[j]) {
+			j++
+		}
+		return j
+	}
+
+	// next returns the rune after i, skipping tone marks
+	next := func(i int) rune {
+		if j := after(i); j < len(runes) {
+			return runes[j]
+		}
+		return 0
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		following := next(i)
+
+		switch {
+		case isLeadingVowel(r):
+			lead = thaiLeadingVowels[r]
+			voiced = false
+
+		case r == 'ฤ':
+			parts = append(parts, "rue")
+			voiced = true
+
+		case r == 'ฦ':
+			parts = append(parts, "lue")
+			voiced = true
+
+		case isThaiConsonant(r) && lead != "":
+			parts = append(parts, thaiInitials[r])
+			// A second consonant before the vowel sign is part of the onset, as in เปร
+			if (following == 'ร' || following == 'ล' || following == 'ว') && next(after(i)) != 0 && !isThaiConsonant(next(after(i))) {
+				i = after(i)
+				parts = append(parts, thaiInitials[following])
+				following = next(i)
+			}
+			vowel := lead
+			switch {
+			case following == 'า':
+				vowel, i = "ao", after(i)
+			case following == 'ี' && next(after(i)) == 'ย':
+				vowel, i = "ia", after(after(i))
+			case following == 'ื' && next(after(i)) == 'อ':
+				vowel, i = "uea", after(after(i))
+			case lead == "e" && following == 'อ':
+				vowel, i = "oe", after(i)
+			case following == 'ะ':
+				i = after(i)
+			}
+			parts = append(parts, vowel)
+			lead = ""
+			voiced = true
+
+		case isThaiConsonant(r) && voiced && thaiVowels[following] == "" && following != 'อ':
+			// ย after ไ or ใ only lengthens the vowel
+			if r != 'ย' || !strings.HasSuffix(parts[len(parts)-1], "ai") {
+				parts = append(parts, thaiFinals[r])
+			}
+			voiced = false
+
+		case r == 'ร' && following == 'ร' && len(parts) > 0 && !voiced:
+			// ร หัน reads as a, or an when nothing closes the syllable
+			i = after(i)
+			closing := next(i)
+			if isThaiConsonant(closing) && thaiVowels[next(after(i))] == "" {
+				parts = append(parts, "a"+thaiFinals[closing])
+				i = after(i)
+			} else {
+				parts = append(parts, "an")
+			}
+
+		case r == 'ห' && !voiced && isThaiSonorant(following):
+			// ห before a sonorant only sets the tone, as in หนัง
+
+		case r == 'อ' && len(parts) > 0 && !voiced && thaiVowels[following] == "":
+			// อ after a bare consonant is the vowel o
+			parts = append(parts, "o")
+			voiced = true
+
+		case isThaiConsonant(r):
+			parts = append(parts, thaiInitials[r])
+			if thaiVowels[following] == "" && following != 'อ' && following != 'ั' {
+				// No vowel is written, an o is heard before a closing consonant
+				rorHan := following == 'ร' && next(after(i)) == 'ร'
+				if following == 0 || (isThaiConsonant(following) && thaiVowels[next(after(i))] == "" && !rorHan) {
+					parts = append(parts, "o")
+					voiced = true
+				}
+			}
+
+		case r == 'ั' && following == 'ว':
+			parts = append(parts, "ua")
+			i = after(i)
+			voiced = true
+
+		case thaiVowels[r] != "":
+			parts = append(parts, thaiVowels[r])
+			voiced = r != 'ำ'
+		}
+	}
+
+	return strings.Join(parts, "")
+}
+
+// silenceThanthakhat drops the consonants thanthakhat marks as silent, with any vowel sign on them
+func silenceThanthakhat(runes []rune) []rune {
+	result := make([]rune, 0, len(runes))
+	for i := 0; i < len(runes); i++ {
+		j := i + 1
+		for j < len(runes) && (runes[j] == 'ิ' || runes[j] == 'ุ') {
+			j++
+		}
+		if isThaiConsonant(runes[i]) && j < len(runes) && runes[j] == '์' {
+			i = j
+			continue
+		}
+		result = append(result, runes[i])
+	}
+	return result
+}
+
+// isThaiSonorant reports whether r is a low consonant that ห can lead
+func isThaiSonorant(r rune) bool {
+	return strings.ContainsRune("งญนมยรลว", r)
+}
+
+// phoneticFolds merge spellings that romanization schemes disagree on
+var phoneticFolds = strings.NewReplacer(
+	"kh", "k", "ph", "p", "th", "t", "ch", "c", "j", "c",
+	"ue", "u", "eu", "u", "ae", "e", "oe", "o", "ay", "ai",
+	"v", "w", "q", "k", "z", "s",
+)
+
+// PhoneticKey reduces a romanized word to a key shared by its common spellings,
+// so "khwam", "kwam" and "kwaam" agree
+func PhoneticKey(text string) string {
+	var letters strings.Builder
+	for _, r := range strings.ToLower(text) {
+		if r >= 'a' && r <= 'z' {
+			letters.WriteRune(r)
+		}
+	}
+
+	folded := phoneticFolds.Replace(letters.String())
+
+	var key strings.Builder
+	var previous rune
+	for _, r := range folded {
+		if r != previous {
+			key.WriteRune(r)
+		}
+		previous = r
+	}
+	return key.String()
+}
+
+// romanizedPrefix marks index tokens taken from romanized Thai, apart from the words searched as written
+const romanizedPrefix = "~"
+
+// romanizedTokens splits a phonetic key into overlapping trigrams, so a
+// romanized search matches wherever its syllables fall in a Thai run. Keys
+// shorter than a trigram would match too much to be worth indexing.
+func romanizedTokens(key string) []string {
+	runes := []rune(key)
+	if len(runes) < 3 {
+		return nil
+	}
+
+	tokens := make([]string, 0, len(runes)-2)
+	for i := 0; i+3 <= len(runes); i++ {
+		tokens = append(tokens, romanizedPrefix+string(runes[i:i+3]))
+	}
+	return tokens
+}
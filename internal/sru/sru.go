@@ -0,0 +1,317 @@
+// Package sru writes the responses of Search/Retrieve via URL 2.0: the records a search retrieves, the
+// diagnostics a request is answered with when it cannot be, and the ZeeRex record explaining a server
+package sru
+
+import (
+	"encoding/xml"
+	"strconv"
+
+	"go-library-service/internal/dublincore"
+)
+
+// Namespaces of a response and its records
+const (
+	ResponseNamespace   = "http://docs.oasis-open.org/ns/search-ws/sruResponse"
+	DiagnosticNamespace = "http://docs.oasis-open.org/ns/search-ws/diagnostic"
+	ExplainNamespace    = "http://explain.z3950.org/dtd/2.0/"
+	DCNamespace         = "info:srw/schema/1/dc-schema"
+)
+
+// Version is the version of the protocol spoken
+const Version = "2.0"
+
+// Operations of a request
+const (
+	OperationSearchRetrieve = "searchRetrieve"
+	OperationExplain        = "explain"
+)
+
+// Schemas records are available in
+const (
+	DCSchema      = "info:srw/schema/1/dc-v1.1"
+	MARCXMLSchema = "info:srw/schema/1/marcxml-v1.1"
+	ExplainSchema = "http://explain.z3950.org/dtd/2.0/"
+)
+
+// Ways record data is written, inline as XML or escaped as a string
+const (
+	EscapingXML    = "xml"
+	EscapingString = "string"
+)
+
+// Codes of the diagnostics a request can be answered with
+const (
+	ErrGeneral                     = 1
+	ErrUnsupportedOperation        = 4
+	ErrUnsupportedVersion          = 5
+	ErrUnsupportedParameterValue   = 6
+	ErrMissingParameter            = 7
+	ErrUnsupportedParameter        = 8
+	ErrQuerySyntax                 = 10
+	ErrUnsupportedContextSet       = 15
+	ErrUnsupportedIndex            = 16
+	ErrUnsupportedRelation         = 19
+	ErrUnsupportedRelationModifier = 20
+	ErrEmptyTerm                   = 27
+	ErrInvalidTerm                 = 36
+	ErrUnsupportedBoolean          = 37
+	ErrUnsupportedBooleanModifier  = 46
+	ErrFirstRecordOutOfRange       = 61
+	ErrUnknownSchema               = 66
+	ErrUnsupportedEscaping         = 71
+	ErrSortUnsupported             = 80
+)
+
+// diagnosticMessages are the messages of the diagnostics in the SRU diagnostics list
+var diagnosticMessages = map[int]string{
+	ErrGeneral:                     "General system error",
+	ErrUnsupportedOperation:        "Unsupported operation",
+	ErrUnsupportedVersion:          "Unsupported version",
+	ErrUnsupportedParameterValue:   "Unsupported parameter value",
+	ErrMissingParameter:            "Mandatory parameter not supplied",
+	ErrUnsupportedParameter:        "Unsupported parameter",
+	ErrQuerySyntax:                 "Query syntax error",
+	ErrUnsupportedContextSet:       "Unsupported context set",
+	ErrUnsupportedIndex:            "Unsupported index",
+	ErrUnsupportedRelation:         "Unsupported relation",
+	ErrUnsupportedRelationModifier: "Unsupported relation modifier",
+	ErrEmptyTerm:                   "Empty term unsupported",
+	ErrInvalidTerm:                 "Term in invalid format for index or relation",
+	ErrUnsupportedBoolean:          "Unsupported boolean operator",
+	ErrUnsupportedBooleanModifier:  "Unsupported boolean modifier",
+	ErrFirstRecordOutOfRange:       "First record position out of range",
+	ErrUnknownSchema:               "Unknown schema for retrieval",
+	ErrUnsupportedEscaping:         "Unsupported record packing",
+	ErrSortUnsupported:             "Sort not supported",
+}
+
+// Diagnostic is why a request could not be answered, with details such as the parameter or index at fault
+type Diagnostic struct {
+	URI     string `xml:"diag:uri"`
+	Details string `xml:"diag:details,omitempty"`
+	Message string `xml:"diag:message,omitempty"`
+}
+
+// NewDiagnostic creates the diagnostic of a code in the SRU diagnostics list
+func NewDiagnostic(code int, details string) Diagnostic {
+	return Diagnostic{
+		URI:     "info:srw/diagnostic/1/" + strconv.Itoa(code),
+		Details: details,
+		Message: diagnosticMessages[code],
+	}
+}
+
+// Error describes the diagnostic
+func (d Diagnostic) Error() string {
+	if d.Details == "" {
+		return d.URI + ": " + d.Message
+	}
+	return d.URI + ": " + d.Message + ": " + d.Details
+}
+
+// Diagnostics are the diagnostics of a response, left out of it when there are none
+type Diagnostics []Diagnostic
+
+// MarshalXML writes the diagnostics within an element of their own
+func (d Diagnostics) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(struct {
+		Diagnostics []Diagnostic `xml:"diag:diagnostic"`
+	}{d}, start)
+}
+
+// SearchRetrieveResponse is the document a search is answered with, the records it retrieves or the
+// diagnostics it failed with
+type SearchRetrieveResponse struct {
+	XMLName            xml.Name    `xml:"searchRetrieveResponse"`
+	Version            string      `xml:"version"`
+	NumberOfRecords    int64       `xml:"numberOfRecords"`
+	Records            Records     `xml:"records,omitempty"`
+	NextRecordPosition int         `xml:"nextRecordPosition,omitempty"`
+	Diagnostics        Diagnostics `xml:"diagnostics,omitempty"`
+}
+
+// Marshal writes a response as an XML document with its namespaces
+func (r SearchRetrieveResponse) Marshal() ([]byte, error) {
+	type response struct {
+		SearchRetrieveResponse
+		Xmlns     string `xml:"xmlns,attr"`
+		XmlnsDiag string `xml:"xmlns:diag,attr"`
+	}
+	return marshal(response{r, ResponseNamespace, DiagnosticNamespace})
+}
+
+// ExplainResponse is the document a server is explained with
+type ExplainResponse struct {
+	XMLName     xml.Name    `xml:"explainResponse"`
+	Version     string      `xml:"version"`
+	Record      Record      `xml:"record"`
+	Diagnostics Diagnostics `xml:"diagnostics,omitempty"`
+}
+
+// Marshal writes a response as an XML document with its namespaces
+func (r ExplainResponse) Marshal() ([]byte, error) {
+	type response struct {
+		ExplainResponse
+		Xmlns     string `xml:"xmlns,attr"`
+		XmlnsDiag string `xml:"xmlns:diag,attr"`
+	}
+	return marshal(response{r, ResponseNamespace, DiagnosticNamespace})
+}
+
+func marshal(v any) ([]byte, error) {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// Records are the records of a response, left out of it when there are none
+type Records []Record
+
+// MarshalXML writes the records within an element of their own
+func (r Records) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(struct {
+		Records []Record `xml:"record"`
+	}{r}, start)
+}
+
+// Record is a record in a schema at a position of the result. Its data is anything that marshals to XML,
+// written inline or, when escaped as a string, as the text of the record data.
+type Record struct {
+	Schema   string
+	Escaping string
+	Data     any
+	Position int
+}
+
+// MarshalXML writes the record with its data escaped as asked
+func (r Record) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type record struct {
+		Schema   string     `xml:"recordSchema"`
+		Escaping string     `xml:"recordXMLEscaping"`
+		Data     recordData `xml:"recordData"`
+		Position int        `xml:"recordPosition,omitempty"`
+	}
+	return e.EncodeElement(record{r.Schema, r.Escaping, recordData{r.Data, r.Escaping}, r.Position}, start)
+}
+
+type recordData struct {
+	value    any
+	escaping string
+}
+
+func (d recordData) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if d.escaping == EscapingString {
+		data, err := xml.Marshal(d.value)
+		if err != nil {
+			return err
+		}
+		return e.EncodeElement(string(data), start)
+	}
+
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	if err := e.Encode(d.value); err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
+}
+
+// DublinCore is a record in the Dublin Core schema
+type DublinCore struct {
+	dublincore.Record
+}
+
+// MarshalXML writes the elements with the namespaces of the Dublin Core schema
+func (d DublinCore) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = xml.Name{Local: "srw_dc:dc"}
+	start.Attr = append(start.Attr,
+		xml.Attr{Name: xml.Name{Local: "xmlns:srw_dc"}, Value: DCNamespace},
+		xml.Attr{Name: xml.Name{Local: "xmlns:dc"}, Value: dublincore.Namespace},
+	)
+	return e.EncodeElement(d.Record, start)
+}
+
+// Explain is the ZeeRex record of a server: where it is, what it holds, and the indexes, schemas and
+// settings a search can use
+type Explain struct {
+	ServerInfo   ServerInfo   `xml:"serverInfo"`
+	DatabaseInfo DatabaseInfo `xml:"databaseInfo"`
+	IndexInfo    IndexInfo    `xml:"indexInfo"`
+	SchemaInfo   SchemaInfo   `xml:"schemaInfo"`
+	ConfigInfo   ConfigInfo   `xml:"configInfo"`
+}
+
+// MarshalXML writes the record with its namespace
+func (x Explain) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type explain Explain
+	start.Name = xml.Name{Local: "explain"}
+	start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "xmlns"}, Value: ExplainNamespace})
+	return e.EncodeElement(explain(x), start)
+}
+
+// ServerInfo is where a server is
+type ServerInfo struct {
+	Protocol  string `xml:"protocol,attr"`
+	Version   string `xml:"version,attr"`
+	Transport string `xml:"transport,attr"`
+	Host      string `xml:"host"`
+	Port      int    `xml:"port"`
+	Database  string `xml:"database"`
+}
+
+// DatabaseInfo is what a server holds
+type DatabaseInfo struct {
+	Title       string `xml:"title"`
+	Description string `xml:"description,omitempty"`
+}
+
+// IndexInfo lists the context sets and indexes a search can use
+type IndexInfo struct {
+	Sets    []ContextSet `xml:"set"`
+	Indexes []Index      `xml:"index"`
+}
+
+// ContextSet is a set of indexes and relations, named by the prefix of its indexes
+type ContextSet struct {
+	Name       string `xml:"name,attr"`
+	Identifier string `xml:"identifier,attr"`
+}
+
+// Index is an index a search can use, under each of its names
+type Index struct {
+	Title string      `xml:"title"`
+	Names []IndexName `xml:"map>name"`
+}
+
+// IndexName is the name of an index in a context set
+type IndexName struct {
+	Set  string `xml:"set,attr"`
+	Name string `xml:",chardata"`
+}
+
+// SchemaInfo lists the schemas records are available in
+type SchemaInfo struct {
+	Schemas []Schema `xml:"schema"`
+}
+
+// Schema is a schema records are available in, by the short name a request can use for it
+type Schema struct {
+	Identifier string `xml:"identifier,attr"`
+	Name       string `xml:"name,attr"`
+	Title      string `xml:"title"`
+}
+
+// ConfigInfo lists the defaults and limits of a server
+type ConfigInfo struct {
+	Defaults []Setting `xml:"default"`
+	Settings []Setting `xml:"setting"`
+}
+
+// Setting is a default or limit of a server
+type Setting struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
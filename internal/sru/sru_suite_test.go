@@ -0,0 +1,13 @@
+package sru_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestSRU(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "SRU Suite")
+}
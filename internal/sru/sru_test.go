@@ -0,0 +1,95 @@
+package sru_test
+
+import (
+	"encoding/xml"
+
+	"go-library-service/internal/dublincore"
+	"go-library-service/internal/sru"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SRU", func() {
+	dc := sru.DublinCore{Record: dublincore.Record{Title: []string{"Dune & Messiah"}}}
+
+	It("should write the records a search retrieves", func() {
+		data, err := sru.SearchRetrieveResponse{
+			Version:            sru.Version,
+			NumberOfRecords:    12,
+			Records:            []sru.Record{{Schema: sru.DCSchema, Escaping: sru.EscapingXML, Data: dc, Position: 1}},
+			NextRecordPosition: 2,
+		}.Marshal()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(string(data)).To(HavePrefix(xml.Header))
+		Expect(string(data)).To(ContainSubstring(`<searchRetrieveResponse xmlns="` + sru.ResponseNamespace + `" xmlns:diag="` + sru.DiagnosticNamespace + `">`))
+		Expect(string(data)).To(ContainSubstring(`<numberOfRecords>12</numberOfRecords>`))
+		Expect(string(data)).To(ContainSubstring(`<recordSchema>info:srw/schema/1/dc-v1.1</recordSchema>`))
+		Expect(string(data)).To(ContainSubstring(`<srw_dc:dc xmlns:srw_dc="info:srw/schema/1/dc-schema" xmlns:dc="http://purl.org/dc/elements/1.1/">`))
+		Expect(string(data)).To(ContainSubstring(`<dc:title>Dune &amp; Messiah</dc:title>`))
+		Expect(string(data)).To(ContainSubstring(`<recordPosition>1</recordPosition>`))
+		Expect(string(data)).To(ContainSubstring(`<nextRecordPosition>2</nextRecordPosition>`))
+		Expect(string(data)).NotTo(ContainSubstring(`diagnostics`))
+	})
+
+	It("should escape record data as a string when asked", func() {
+		data, err := xml.Marshal(sru.Record{Schema: sru.DCSchema, Escaping: sru.EscapingString, Data: dc, Position: 3})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(string(data)).To(Equal(`<Record><recordSchema>info:srw/schema/1/dc-v1.1</recordSchema>` +
+			`<recordXMLEscaping>string</recordXMLEscaping>` +
+			`<recordData>&lt;srw_dc:dc xmlns:srw_dc=&#34;info:srw/schema/1/dc-schema&#34; xmlns:dc=&#34;http://purl.org/dc/elements/1.1/&#34;&gt;` +
+			`&lt;dc:title&gt;Dune &amp;amp; Messiah&lt;/dc:title&gt;&lt;/srw_dc:dc&gt;</recordData>` +
+			`<recordPosition>3</recordPosition></Record>`))
+	})
+
+	It("should write the diagnostics a search failed with and no records", func() {
+		data, err := sru.SearchRetrieveResponse{
+			Version:     sru.Version,
+			Diagnostics: []sru.Diagnostic{sru.NewDiagnostic(sru.ErrUnsupportedIndex, "dc.coverage")},
+		}.Marshal()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(string(data)).NotTo(ContainSubstring(`<records>`))
+		Expect(string(data)).To(ContainSubstring(`<diagnostics>
+    <diag:diagnostic>
+      <diag:uri>info:srw/diagnostic/1/16</diag:uri>
+      <diag:details>dc.coverage</diag:details>
+      <diag:message>Unsupported index</diag:message>
+    </diag:diagnostic>
+  </diagnostics>`))
+	})
+
+	It("should describe a diagnostic as an error", func() {
+		Expect(sru.NewDiagnostic(sru.ErrMissingParameter, "query").Error()).
+			To(Equal("info:srw/diagnostic/1/7: Mandatory parameter not supplied: query"))
+		Expect(sru.NewDiagnostic(sru.ErrSortUnsupported, "").Error()).
+			To(Equal("info:srw/diagnostic/1/80: Sort not supported"))
+	})
+
+	It("should explain a server in a ZeeRex record", func() {
+		data, err := sru.ExplainResponse{
+			Version: sru.Version,
+			Record: sru.Record{Schema: sru.ExplainSchema, Escaping: sru.EscapingXML, Data: sru.Explain{
+				ServerInfo:   sru.ServerInfo{Protocol: "SRU", Version: sru.Version, Transport: "http", Host: "library.test", Port: 80, Database: "api/sru"},
+				DatabaseInfo: sru.DatabaseInfo{Title: "Library"},
+				IndexInfo: sru.IndexInfo{
+					Sets:    []sru.ContextSet{{Name: "dc", Identifier: "info:srw/cql-context-set/1/dc-v1.1"}},
+					Indexes: []sru.Index{{Title: "Title", Names: []sru.IndexName{{Set: "dc", Name: "title"}}}},
+				},
+				SchemaInfo: sru.SchemaInfo{Schemas: []sru.Schema{{Identifier: sru.DCSchema, Name: "dc", Title: "Dublin Core"}}},
+				ConfigInfo: sru.ConfigInfo{Defaults: []sru.Setting{{Type: "numberOfRecords", Value: "10"}}},
+			}},
+		}.Marshal()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(string(data)).To(ContainSubstring(`<explainResponse xmlns="` + sru.ResponseNamespace + `"`))
+		Expect(string(data)).To(ContainSubstring(`<explain xmlns="http://explain.z3950.org/dtd/2.0/">`))
+		Expect(string(data)).To(ContainSubstring(`<serverInfo protocol="SRU" version="2.0" transport="http">`))
+		Expect(string(data)).To(ContainSubstring(`<set name="dc" identifier="info:srw/cql-context-set/1/dc-v1.1"></set>`))
+		Expect(string(data)).To(ContainSubstring(`<name set="dc">title</name>`))
+		Expect(string(data)).To(ContainSubstring(`<schema identifier="info:srw/schema/1/dc-v1.1" name="dc">`))
+		Expect(string(data)).To(ContainSubstring(`<default type="numberOfRecords">10</default>`))
+	})
+})
@@ -0,0 +1,64 @@
+// Package thumbnail scales images down for previews, averaging the pixels each thumbnail pixel covers
+package thumbnail
+
+import (
+	"image"
+	"image/color"
+)
+
+// Fit scales an image down to fit within a width and height, keeping its aspect ratio. An image that already
+// fits keeps its size. Transparent areas come out white, so the thumbnail can be written as a JPEG.
+func Fit(src image.Image, width, height int) *image.RGBA {
+	bounds := src.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+
+	dstWidth, dstHeight := srcWidth, srcHeight
+	if dstWidth > width {
+		dstWidth, dstHeight = width, max(1, srcHeight*width/srcWidth)
+	}
+	if dstHeight > height {
+		dstWidth, dstHeight = max(1, srcWidth*height/srcHeight), height
+	}
+
+	// The source is sampled in place rather than flattened into a copy, which for a large cover would take
+	// several times the memory of the thumbnail
+	pixel := rgba64At(src)
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < dstHeight; y++ {
+		y0, y1 := y*srcHeight/dstHeight, max((y+1)*srcHeight/dstHeight, y*srcHeight/dstHeight+1)
+		for x := 0; x < dstWidth; x++ {
+			x0, x1 := x*srcWidth/dstWidth, max((x+1)*srcWidth/dstWidth, x*srcWidth/dstWidth+1)
+
+			var sum [3]int
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					c := pixel(bounds.Min.X+sx, bounds.Min.Y+sy)
+					// Colors are premultiplied, so laying them over white adds the white the alpha lets through
+					white := 0xffff - int(c.A)
+					sum[0] += int(c.R) + white
+					sum[1] += int(c.G) + white
+					sum[2] += int(c.B) + white
+				}
+			}
+
+			n := (y1 - y0) * (x1 - x0) * 0x101
+			offset := y*dst.Stride + x*4
+			for i := range sum {
+				dst.Pix[offset+i] = uint8((sum[i] + n/2) / n)
+			}
+			dst.Pix[offset+3] = 0xff
+		}
+	}
+	return dst
+}
+
+// rgba64At reads the pixels of an image, without allocating a color for each pixel when the image allows it
+func rgba64At(src image.Image) func(x, y int) color.RGBA64 {
+	if img, ok := src.(image.RGBA64Image); ok {
+		return img.RGBA64At
+	}
+	return func(x, y int) color.RGBA64 {
+		r, g, b, a := src.At(x, y).RGBA()
+		return color.RGBA64{R: uint16(r), G: uint16(g), B: uint16(b), A: uint16(a)}
+	}
+}
@@ -0,0 +1,71 @@
+package thumbnail_test
+
+import (
+	"image"
+	"image/color"
+
+	"go-library-service/internal/thumbnail"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Thumbnail", func() {
+	It("should scale an image down to fit, keeping its aspect ratio", func() {
+		src := image.NewRGBA(image.Rect(0, 0, 600, 900))
+		Expect(thumbnail.Fit(src, 200, 300).Bounds()).To(Equal(image.Rect(0, 0, 200, 300)))
+
+		wide := image.NewRGBA(image.Rect(0, 0, 1000, 500))
+		Expect(thumbnail.Fit(wide, 200, 300).Bounds()).To(Equal(image.Rect(0, 0, 200, 100)))
+
+		tall := image.NewRGBA(image.Rect(0, 0, 100, 3000))
+		Expect(thumbnail.Fit(tall, 200, 300).Bounds()).To(Equal(image.Rect(0, 0, 10, 300)))
+	})
+
+	It("should keep the size of an image that already fits", func() {
+		src := image.NewRGBA(image.Rect(10, 10, 110, 60))
+		Expect(thumbnail.Fit(src, 200, 300).Bounds()).To(Equal(image.Rect(0, 0, 100, 50)))
+	})
+
+	It("should average the pixels each thumbnail pixel covers", func() {
+		src := image.NewRGBA(image.Rect(0, 0, 4, 2))
+		for y := 0; y < 2; y++ {
+			for x := 0; x < 4; x++ {
+				if x%2 == 0 {
+					src.Set(x, y, color.RGBA{R: 200, G: 0, B: 100, A: 255})
+				} else {
+					src.Set(x, y, color.RGBA{R: 0, G: 100, B: 100, A: 255})
+				}
+			}
+		}
+
+		dst := thumbnail.Fit(src, 2, 1)
+		Expect(dst.Bounds()).To(Equal(image.Rect(0, 0, 2, 1)))
+		Expect(dst.RGBAAt(0, 0)).To(Equal(color.RGBA{R: 100, G: 50, B: 100, A: 255}))
+		Expect(dst.RGBAAt(1, 0)).To(Equal(color.RGBA{R: 100, G: 50, B: 100, A: 255}))
+	})
+
+	It("should read images of any type from their bounds", func() {
+		src := image.NewGray(image.Rect(5, 5, 9, 7))
+		for x := 5; x < 9; x++ {
+			src.SetGray(x, 5, color.Gray{Y: 40})
+			src.SetGray(x, 6, color.Gray{Y: 80})
+		}
+
+		dst := thumbnail.Fit(src, 2, 1)
+		Expect(dst.RGBAAt(0, 0)).To(Equal(color.RGBA{R: 60, G: 60, B: 60, A: 255}))
+		Expect(dst.RGBAAt(1, 0)).To(Equal(color.RGBA{R: 60, G: 60, B: 60, A: 255}))
+	})
+
+	It("should blend translucent pixels over white", func() {
+		src := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+		src.SetNRGBA(0, 0, color.NRGBA{R: 0, G: 0, B: 0, A: 128})
+		Expect(thumbnail.Fit(src, 1, 1).RGBAAt(0, 0)).To(Equal(color.RGBA{R: 127, G: 127, B: 127, A: 255}))
+	})
+
+	It("should turn transparent areas white", func() {
+		src := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+		dst := thumbnail.Fit(src, 1, 1)
+		Expect(dst.RGBAAt(0, 0)).To(Equal(color.RGBA{R: 255, G: 255, B: 255, A: 255}))
+	})
+})
@@ -3,6 +3,7 @@ package utils
 import (
 	"log"
 	"os"
+	"strconv"
 )
 
 func RequiredEnv(key string) string {
@@ -11,4 +12,41 @@ func RequiredEnv(key string) string {
 		log.Fatalf("required env %s not set", key)
 	}
 	return env
-}
\ No newline at end of file
+}
+
+// StringEnv returns the value of an env or the fallback when it is not set
+func StringEnv(key string, fallback string) string {
+	env, ok := os.LookupEnv(key)
+	if !ok || env == "" {
+		return fallback
+	}
+	return env
+}
+
+// IntEnv returns the integer value of an env or the fallback when it is not set
+func IntEnv(key string, fallback int) int {
+	env, ok := os.LookupEnv(key)
+	if !ok || env == "" {
+		return fallback
+	}
+
+	value, err := strconv.Atoi(env)
+	if err != nil {
+		log.Fatalf("env %s must be an integer", key)
+	}
+	return value
+}
+
+// FloatEnv returns the float value of an env or the fallback when it is not set
+func FloatEnv(key string, fallback float64) float64 {
+	env, ok := os.LookupEnv(key)
+	if !ok || env == "" {
+		return fallback
+	}
+
+	value, err := strconv.ParseFloat(env, 64)
+	if err != nil {
+		log.Fatalf("env %s must be a number", key)
+	}
+	return value
+}
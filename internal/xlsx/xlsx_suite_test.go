@@ -0,0 +1,13 @@
+package xlsx_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestXLSX(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "XLSX Suite")
+}
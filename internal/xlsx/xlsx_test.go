@@ -0,0 +1,64 @@
+package xlsx_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"time"
+
+	"go-library-service/internal/xlsx"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// readPart reads a part of a written workbook
+func readPart(workbook []byte, name string) string {
+	archive, err := zip.NewReader(bytes.NewReader(workbook), int64(len(workbook)))
+	Expect(err).NotTo(HaveOccurred())
+
+	part, err := archive.Open(name)
+	Expect(err).NotTo(HaveOccurred())
+	defer part.Close()
+
+	content, err := io.ReadAll(part)
+	Expect(err).NotTo(HaveOccurred())
+	return string(content)
+}
+
+var _ = Describe("XLSX", func() {
+	It("should write the rows of a sheet", func() {
+		var workbook bytes.Buffer
+		w := xlsx.NewWriter(&workbook, "Books & copies")
+		Expect(w.Write("id", "title", "price")).To(Succeed())
+		Expect(w.Write(uint(1), "Dune <1965>", 12.5, nil, time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC))).To(Succeed())
+		Expect(w.Flush()).To(Succeed())
+		Expect(w.Close()).To(Succeed())
+
+		Expect(readPart(workbook.Bytes(), "xl/workbook.xml")).To(ContainSubstring(`<sheet name="Books &amp; copies" sheetId="1" r:id="rId1"/>`))
+		Expect(readPart(workbook.Bytes(), "[Content_Types].xml")).To(ContainSubstring(`PartName="/xl/worksheets/sheet1.xml"`))
+
+		sheet := readPart(workbook.Bytes(), "xl/worksheets/sheet1.xml")
+		Expect(sheet).To(ContainSubstring(`<row r="1"><c r="A1" t="inlineStr"><is><t xml:space="preserve">id</t></is></c>`))
+		Expect(sheet).To(ContainSubstring(`<row r="2"><c r="A2"><v>1</v></c>` +
+			`<c r="B2" t="inlineStr"><is><t xml:space="preserve">Dune &lt;1965&gt;</t></is></c>` +
+			`<c r="C2"><v>12.5</v></c>` +
+			`<c r="E2" t="inlineStr"><is><t>2026-01-01T08:00:00Z</t></is></c></row>`))
+		Expect(sheet).To(HaveSuffix(`</sheetData></worksheet>`))
+	})
+
+	It("should name columns past Z", func() {
+		values := make([]interface{}, 28)
+		for i := range values {
+			values[i] = i
+		}
+
+		var workbook bytes.Buffer
+		w := xlsx.NewWriter(&workbook, "Sheet1")
+		Expect(w.Write(values...)).To(Succeed())
+		Expect(w.Close()).To(Succeed())
+
+		sheet := readPart(workbook.Bytes(), "xl/worksheets/sheet1.xml")
+		Expect(sheet).To(ContainSubstring(`<c r="Z1"><v>25</v></c><c r="AA1"><v>26</v></c><c r="AB1"><v>27</v></c>`))
+	})
+})